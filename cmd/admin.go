@@ -24,6 +24,7 @@ to make automatic initialization process more smoothly`,
 		Subcommands: []cli.Command{
 			subcmdCreateUser,
 			subcmdChangePassword,
+			subcmdReindex,
 		},
 	}
 
@@ -76,6 +77,29 @@ to make automatic initialization process more smoothly`,
 			},
 		},
 	}
+
+	subcmdReindex = cli.Command{
+		Name:  "reindex",
+		Usage: "Rebuild the issue and code indexes",
+		Description: `Drop the issue and code indexes and index all issues and repositories again.
+When the bleve indexer type is used, Gitea must not be running while the indexes are rebuilt.`,
+		Action: runReindex,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "issues",
+				Usage: "Only rebuild the issue index",
+			},
+			cli.BoolFlag{
+				Name:  "repos",
+				Usage: "Only rebuild the code index",
+			},
+			cli.StringFlag{
+				Name:  "config, c",
+				Value: "custom/conf/app.ini",
+				Usage: "Custom configuration file path",
+			},
+		},
+	}
 )
 
 func runChangePassword(c *cli.Context) error {
@@ -145,3 +169,38 @@ func runCreateUser(c *cli.Context) error {
 	fmt.Printf("New user '%s' has been successfully created!\n", c.String("name"))
 	return nil
 }
+
+func runReindex(c *cli.Context) error {
+	if c.IsSet("config") {
+		setting.CustomConf = c.String("config")
+	}
+
+	setting.NewContext()
+	models.LoadConfigs()
+
+	setting.NewXORMLogService(false)
+	if err := models.SetEngine(); err != nil {
+		return fmt.Errorf("models.SetEngine: %v", err)
+	}
+
+	all := !c.Bool("issues") && !c.Bool("repos")
+	if all || c.Bool("issues") {
+		if err := models.RebuildIssueIndexer(); err != nil {
+			return fmt.Errorf("RebuildIssueIndexer: %v", err)
+		}
+		fmt.Println("Issue index has been successfully rebuilt!")
+	}
+	if all || c.Bool("repos") {
+		if !setting.Indexer.RepoIndexerEnabled {
+			if c.Bool("repos") {
+				return fmt.Errorf("Code indexer is not enabled")
+			}
+			return nil
+		}
+		if err := models.RebuildRepoIndexer(); err != nil {
+			return fmt.Errorf("RebuildRepoIndexer: %v", err)
+		}
+		fmt.Println("Code index has been successfully rebuilt!")
+	}
+	return nil
+}
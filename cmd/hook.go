@@ -62,6 +62,12 @@ var (
 	}
 )
 
+// isGiteaPush returns true if the hook runs for a push received by the SSH or
+// HTTP handlers of Gitea, which export the repository and pusher of the push.
+func isGiteaPush() bool {
+	return len(os.Getenv(models.EnvRepoUsername)) > 0 && len(os.Getenv(models.EnvPusherID)) > 0
+}
+
 func hookSetup(logPath string) {
 	setting.NewContext()
 	log.NewGitLogger(filepath.Join(setting.LogRootPath, logPath))
@@ -69,7 +75,7 @@ func hookSetup(logPath string) {
 }
 
 func runHookPreReceive(c *cli.Context) error {
-	if len(os.Getenv("SSH_ORIGINAL_COMMAND")) == 0 {
+	if !isGiteaPush() {
 		return nil
 	}
 
@@ -81,7 +87,7 @@ func runHookPreReceive(c *cli.Context) error {
 
 	hookSetup("hooks/pre-receive.log")
 
-	// the environment setted on serv command
+	// the environment set by the serv command or the HTTP handler
 	repoID, _ := strconv.ParseInt(os.Getenv(models.ProtectedBranchRepoID), 10, 64)
 	isWiki := (os.Getenv(models.EnvRepoIsWiki) == "true")
 	username := os.Getenv(models.EnvRepoUsername)
@@ -89,6 +95,13 @@ func runHookPreReceive(c *cli.Context) error {
 	userIDStr := os.Getenv(models.EnvPusherID)
 	repoPath := models.RepoPath(username, reponame)
 
+	repo, err := private.GetRepository(repoID)
+	if err != nil {
+		fail("Internal error", "Failed to retrieve repository information: %v", err)
+	} else if repo.IsArchived {
+		fail(fmt.Sprintf("repository %s/%s is archived and read-only", username, reponame), "")
+	}
+
 	buf := bytes.NewBuffer(nil)
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
@@ -109,13 +122,23 @@ func runHookPreReceive(c *cli.Context) error {
 		newCommitID := string(fields[1])
 		refFullName := string(fields[2])
 
+		// only branches can be protected
+		if !strings.HasPrefix(refFullName, git.BranchPrefix) {
+			continue
+		}
+
 		branchName := strings.TrimPrefix(refFullName, git.BranchPrefix)
 		protectBranch, err := private.GetProtectedBranchBy(repoID, branchName)
 		if err != nil {
-			log.GitLogger.Fatal(2, "retrieve protected branches information failed")
+			fail("Internal error", "Failed to retrieve protected branch information: %v", err)
 		}
 
 		if protectBranch != nil && protectBranch.IsProtected() {
+			// check deletion
+			if newCommitID == git.EmptySHA {
+				fail(fmt.Sprintf("branch %s is protected from deletion", branchName), "")
+			}
+
 			// detect force push
 			if git.EmptySHA != oldCommitID {
 				output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).RunInDir(repoPath)
@@ -126,17 +149,12 @@ func runHookPreReceive(c *cli.Context) error {
 				}
 			}
 
-			// check and deletion
-			if newCommitID == git.EmptySHA {
-				fail(fmt.Sprintf("branch %s is protected from deletion", branchName), "")
-			} else {
-				userID, _ := strconv.ParseInt(userIDStr, 10, 64)
-				canPush, err := private.CanUserPush(protectBranch.ID, userID)
-				if err != nil {
-					fail("Internal error", "Fail to detect user can push: %v", err)
-				} else if !canPush {
-					fail(fmt.Sprintf("protected branch %s can not be pushed to", branchName), "")
-				}
+			userID, _ := strconv.ParseInt(userIDStr, 10, 64)
+			canPush, err := private.CanUserPush(protectBranch.ID, userID)
+			if err != nil {
+				fail("Internal error", "Fail to detect user can push: %v", err)
+			} else if !canPush {
+				fail(fmt.Sprintf("protected branch %s can not be pushed to", branchName), "")
 			}
 		}
 	}
@@ -145,7 +163,7 @@ func runHookPreReceive(c *cli.Context) error {
 }
 
 func runHookUpdate(c *cli.Context) error {
-	if len(os.Getenv("SSH_ORIGINAL_COMMAND")) == 0 {
+	if !isGiteaPush() {
 		return nil
 	}
 
@@ -161,7 +179,7 @@ func runHookUpdate(c *cli.Context) error {
 }
 
 func runHookPostReceive(c *cli.Context) error {
-	if len(os.Getenv("SSH_ORIGINAL_COMMAND")) == 0 {
+	if !isGiteaPush() {
 		return nil
 	}
 
@@ -173,7 +191,7 @@ func runHookPostReceive(c *cli.Context) error {
 
 	hookSetup("hooks/post-receive.log")
 
-	// the environment setted on serv command
+	// the environment set by the serv command or the HTTP handler
 	repoUser := os.Getenv(models.EnvRepoUsername)
 	isWiki := (os.Getenv(models.EnvRepoIsWiki) == "true")
 	repoName := os.Getenv(models.EnvRepoName)
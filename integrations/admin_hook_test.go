@@ -0,0 +1,62 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminSystemHooks(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+
+	req := NewRequest(t, "GET", "/admin/hooks")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.NotZero(t, htmlDoc.doc.Find(`.ui.list .item a[href="/admin/hooks/4"]`).Length())
+
+	req = NewRequest(t, "GET", "/admin/hooks/4")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, "/admin/hooks/gitea/4", htmlDoc.doc.Find("form.ui.form").AttrOr("action", ""))
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(`input[name="user"][checked]`).Length())
+
+	csrf := GetCSRF(t, session, "/admin/hooks/gitea/new")
+	req = NewRequestWithValues(t, "POST", "/admin/hooks/gitea/new", map[string]string{
+		"_csrf":        csrf,
+		"payload_url":  "http://www.example.com/new-system-hook",
+		"content_type": "1",
+		"events":       "choose_events",
+		"user":         "on",
+		"member":       "on",
+		"active":       "on",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.EqualValues(t, "/admin/hooks", RedirectURL(t, resp))
+
+	hook := models.AssertExistsAndLoadBean(t, &models.Webhook{URL: "http://www.example.com/new-system-hook"}).(*models.Webhook)
+	assert.True(t, hook.IsSystemWebhook)
+	assert.True(t, hook.HasUserEvent())
+	assert.True(t, hook.HasMemberEvent())
+
+	// The webhook pages of repositories still work with the shared templates
+	session = loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/user2/repo1/settings/hooks")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.NotZero(t, htmlDoc.doc.Find(`.ui.list .item a[href="/user2/repo1/settings/hooks/1"]`).Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/settings/hooks/gitea/new")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, "/user2/repo1/settings/hooks/gitea/new", htmlDoc.doc.Find("form.ui.form").AttrOr("action", ""))
+	assert.EqualValues(t, 0, htmlDoc.doc.Find(`input[name="user"]`).Length())
+	assert.EqualValues(t, 0, htmlDoc.doc.Find(`input[name="member"]`).Length())
+}
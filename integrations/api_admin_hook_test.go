@@ -0,0 +1,70 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminHooks(t *testing.T) {
+	prepareTestEnv(t)
+
+	// Only site administrators can manage the system webhooks
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/api/v1/admin/hooks")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user1")
+	req = NewRequest(t, "GET", "/api/v1/admin/hooks")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var hooks []*api.Hook
+	DecodeJSON(t, resp, &hooks)
+	if assert.Len(t, hooks, 1) {
+		assert.EqualValues(t, 4, hooks[0].ID)
+		assert.Equal(t, []string{"push", "user", "member"}, hooks[0].Events)
+	}
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/hooks", &api.CreateHookOption{
+		Type: "gitea",
+		Config: map[string]string{
+			"url":          "http://www.example.com/system",
+			"content_type": "json",
+		},
+		Events: []string{"repository", "user"},
+		Active: true,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var hook api.Hook
+	DecodeJSON(t, resp, &hook)
+	assert.Equal(t, []string{"repository", "user"}, hook.Events)
+	models.AssertExistsAndLoadBean(t, &models.Webhook{ID: hook.ID, IsSystemWebhook: true, RepoID: 0, OrgID: 0})
+
+	active := false
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/hooks/%d", hook.ID), &api.EditHookOption{
+		Events: []string{"member"},
+		Active: &active,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &hook)
+	assert.Equal(t, []string{"member"}, hook.Events)
+	assert.False(t, hook.Active)
+
+	// The webhooks of repositories are not system webhooks
+	req = NewRequest(t, "GET", "/api/v1/admin/hooks/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "DELETE", "/api/v1/admin/hooks/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/admin/hooks/%d", hook.ID)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Webhook{ID: hook.ID})
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +43,160 @@ func TestAPIGetBranch(t *testing.T) {
 		testAPIGetBranch(t, test.BranchName, test.Exists)
 	}
 }
+
+func TestAPICreateAndDeleteBranch(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "release/v1.1",
+		OldRefName: "v1.1",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var branch api.Branch
+	DecodeJSON(t, resp, &branch)
+	assert.Equal(t, "release/v1.1", branch.Name)
+	assert.Equal(t, repo1CommitID, branch.Commit.ID)
+
+	// the branch exists already
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "release/v1.1",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "feature/2",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &branch)
+	assert.Equal(t, "feature/2", branch.Name)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "feature/3",
+		OldRefName: "missing",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/release/v1.1")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.DeletedBranch{RepoID: 1, Name: "release/v1.1"})
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/release/v1.1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/release/v1.1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/master")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// protected branch
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, models.UpdateProtectBranch(repo, &models.ProtectedBranch{
+		RepoID:     repo.ID,
+		BranchName: "feature/2",
+	}, models.WhitelistOptions{}))
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/feature/2")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	// not a collaborator
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/develop")
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIBranchProtection(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName:             "master",
+		EnablePushWhitelist:    true,
+		PushWhitelistUsernames: []string{"user2"},
+		RequiredApprovals:      1,
+		EnableStatusCheck:      true,
+		StatusCheckContexts:    []string{"ci/build", " "},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var bp api.BranchProtection
+	DecodeJSON(t, resp, &bp)
+	assert.Equal(t, "master", bp.BranchName)
+	assert.True(t, bp.EnablePushWhitelist)
+	assert.Equal(t, []string{"user2"}, bp.PushWhitelistUsernames)
+	assert.EqualValues(t, 1, bp.RequiredApprovals)
+	assert.Equal(t, []string{"ci/build"}, bp.StatusCheckContexts)
+	assert.False(t, bp.Created.IsZero())
+
+	// the branch is protected already
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName: "master",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	for status, opt := range map[int]*api.CreateBranchProtectionOption{
+		http.StatusNotFound:            {BranchName: "missing"},
+		http.StatusUnprocessableEntity: {BranchName: "develop", PushWhitelistUsernames: []string{"missing"}},
+	} {
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", opt)
+		session.MakeRequest(t, req, status)
+	}
+	// teams can not be whitelisted in a repository of a user
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName:         "develop",
+		PushWhitelistTeams: []string{"team1"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var bps []*api.BranchProtection
+	DecodeJSON(t, resp, &bps)
+	if assert.Len(t, bps, 1) {
+		assert.Equal(t, "master", bps[0].BranchName)
+	}
+
+	// edit
+	approvals := int64(2)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/branch_protections/master", &api.EditBranchProtectionOption{
+		RequiredApprovals: &approvals,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &bp)
+	assert.EqualValues(t, 2, bp.RequiredApprovals)
+	assert.Equal(t, []string{"user2"}, bp.PushWhitelistUsernames)
+	assert.Equal(t, []string{"ci/build"}, bp.StatusCheckContexts)
+
+	approvals = -1
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/branch_protections/master", &api.EditBranchProtectionOption{
+		RequiredApprovals: &approvals,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// not an admin of the repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections")
+	loginUser(t, "user4").MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections")
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// delete
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branch_protections/master")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections/master")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// team whitelists in a repository of an organization
+	session = loginUser(t, "user1")
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName:              "master",
+		EnablePushWhitelist:     true,
+		PushWhitelistTeams:      []string{"team1"},
+		ApprovalsWhitelistTeams: []string{"team1"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &bp)
+	assert.Equal(t, []string{"team1"}, bp.PushWhitelistTeams)
+	assert.Equal(t, []string{"team1"}, bp.ApprovalsWhitelistTeams)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/repo3/branch_protections/master", &api.EditBranchProtectionOption{
+		PushWhitelistTeams: []string{"missing"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -61,3 +61,20 @@ func TestAPICreateIssue(t *testing.T) {
 		Title:      title,
 	})
 }
+
+func TestAPISearchIssues(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/issues/search?state=all&labels=label1&created_by=user2")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	for _, apiIssue := range apiIssues {
+		issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: apiIssue.ID}).(*models.Issue)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: issue.RepoID}).(*models.Repository)
+		assert.False(t, repo.IsPrivate)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/issues/search?created_by=unknown")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
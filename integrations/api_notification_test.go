@@ -0,0 +1,116 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPINotifications(t *testing.T) {
+	prepareTestEnv(t)
+
+	// the token needs the notification scope
+	req := NewRequest(t, "GET", "/api/v1/notifications?token=hash2")
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/notifications")
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications/new?token=hash1")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var count api.NotificationCount
+	DecodeJSON(t, resp, &count)
+	assert.EqualValues(t, 1, count.New)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?token=hash1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var threads []*api.NotificationThread
+	DecodeJSON(t, resp, &threads)
+	if assert.Len(t, threads, 1) {
+		assert.EqualValues(t, 1, threads[0].ID)
+		assert.True(t, threads[0].Unread)
+		assert.False(t, threads[0].Pinned)
+		assert.EqualValues(t, 1, threads[0].Repository.ID)
+		assert.Equal(t, "Issue", threads[0].Subject.Type)
+		assert.Equal(t, "issue1", threads[0].Subject.Title)
+		assert.Equal(t, api.StateOpen, threads[0].Subject.State)
+		assert.Contains(t, threads[0].Subject.URL, "/api/v1/repos/user2/repo1/issues/1")
+		assert.Contains(t, threads[0].Subject.HTMLURL, "/user2/repo1/issues/1")
+		assert.Contains(t, threads[0].URL, "/api/v1/notifications/threads/1")
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?token=hash1&status=read")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 0)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?token=hash1&since=2000-01-02T00:00:00Z")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 0)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/notifications?token=hash1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 1)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/notifications?token=hash1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 0)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?token=hash1&status=archived")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "GET", "/api/v1/notifications?token=hash1&since=yesterday")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// notification of another user
+	req = NewRequest(t, "GET", "/api/v1/notifications/threads/2?token=hash1")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "PATCH", "/api/v1/notifications/threads/2?token=hash1")
+	MakeRequest(t, req, http.StatusNotFound)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 2, Status: models.NotificationStatusRead})
+
+	req = NewRequest(t, "PATCH", "/api/v1/notifications/threads/1?token=hash1&status=pinned")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var thread api.NotificationThread
+	DecodeJSON(t, resp, &thread)
+	assert.True(t, thread.Pinned)
+	assert.False(t, thread.Unread)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 1, Status: models.NotificationStatusPinned})
+
+	req = NewRequest(t, "PATCH", "/api/v1/notifications/threads/1?token=hash1&status=archived")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "PATCH", "/api/v1/notifications/threads/1?token=hash1&status=unread")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &thread)
+	assert.True(t, thread.Unread)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications/threads/1?token=hash1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &thread)
+	assert.EqualValues(t, 1, thread.ID)
+	assert.True(t, thread.Unread)
+
+	// notifications updated after last_read_at stay unread
+	req = NewRequest(t, "PUT", "/api/v1/notifications?token=hash1&last_read_at=2000-01-01T00:00:00Z")
+	MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 1, Status: models.NotificationStatusUnread})
+
+	req = NewRequest(t, "PUT", "/api/v1/repos/user2/repo1/notifications?token=hash1")
+	MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 1, Status: models.NotificationStatusRead})
+
+	req = NewRequest(t, "GET", "/api/v1/notifications/new?token=hash1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &count)
+	assert.EqualValues(t, 0, count.New)
+}
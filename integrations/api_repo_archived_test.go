@@ -0,0 +1,45 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIArchivedRepoDeleteIssueLabel(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.SetArchiveRepoState(true))
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/issues/1/labels/1")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: 1, LabelID: 1})
+}
+
+func TestAPIArchivedRepoDeleteProjectCard(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	models.AssertSuccessfulInsert(t, &models.RepoUnit{
+		RepoID: repo.ID,
+		Type:   models.UnitTypeProjects,
+		Config: new(models.UnitConfig),
+	})
+	assert.NoError(t, repo.SetArchiveRepoState(true))
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/projects/1/cards/1")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{ProjectID: 1, IssueID: 1})
+}
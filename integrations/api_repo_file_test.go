@@ -0,0 +1,158 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetContents(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var contents api.ContentsResponse
+	DecodeJSON(t, resp, &contents)
+	assert.Equal(t, "file", contents.Type)
+	assert.Equal(t, "README.md", contents.Path)
+	assert.Equal(t, "4b4851ad51df6a7d9f25c979345979eaeb5b349f", contents.SHA)
+	assert.Equal(t, "base64", contents.Encoding)
+	content, err := base64.StdEncoding.DecodeString(contents.Content)
+	assert.NoError(t, err)
+	assert.Equal(t, "# repo1\n\nDescription for repo1", string(content))
+
+	for _, ref := range []string{"master", "v1.1", "65f1bf27bc3bf70f64657658635e66094edbcb4d"} {
+		req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=%s", ref)
+		MakeRequest(t, req, http.StatusOK)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var list []*api.ContentsResponse
+	DecodeJSON(t, resp, &list)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "README.md", list[0].Name)
+		assert.Empty(t, list[0].Content)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/missing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=missing")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// private repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/contents/README.md")
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIChangeFiles(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	// create
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/docs/VERSION", &api.CreateFileOptions{
+		FileOptions: api.FileOptions{
+			Message: "Add version file",
+			Author: api.Identity{
+				Name:  "Release Bot",
+				Email: "bot@example.com",
+			},
+		},
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.0\n")),
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var created api.FileResponse
+	DecodeJSON(t, resp, &created)
+	assert.Equal(t, "docs/VERSION", created.Content.Path)
+	assert.Equal(t, "Add version file\n", created.Commit.Message)
+	assert.Equal(t, "Release Bot", created.Commit.Author.Name)
+	assert.Equal(t, "bot@example.com", created.Commit.Author.Email)
+	assert.Equal(t, "user2@example.com", created.Commit.Committer.Email)
+
+	// the file exists already
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// update with an outdated sha
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/docs/VERSION", &api.UpdateFileOptions{
+		SHA:     "4b4851ad51df6a7d9f25c979345979eaeb5b349f",
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.1\n")),
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	// update to a new branch
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/docs/VERSION", &api.UpdateFileOptions{
+		FileOptions: api.FileOptions{
+			NewBranchName: "release/1.0.1",
+		},
+		SHA:     created.Content.SHA,
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.1\n")),
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var updated api.FileResponse
+	DecodeJSON(t, resp, &updated)
+	assert.Equal(t, "Update 'docs/VERSION'\n", updated.Commit.Message)
+	assert.NotEqual(t, created.Content.SHA, updated.Content.SHA)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/docs/VERSION?ref=release/1.0.1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var contents api.ContentsResponse
+	DecodeJSON(t, resp, &contents)
+	content, err := base64.StdEncoding.DecodeString(contents.Content)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1\n", string(content))
+
+	// the default branch is unchanged
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/docs/VERSION")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &contents)
+	assert.Equal(t, created.Content.SHA, contents.SHA)
+
+	// delete
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/user2/repo1/contents/docs/VERSION", &api.DeleteFileOptions{
+		SHA: created.Content.SHA,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var deleted api.FileResponse
+	DecodeJSON(t, resp, &deleted)
+	assert.Nil(t, deleted.Content)
+	assert.Equal(t, "Delete 'docs/VERSION'\n", deleted.Commit.Message)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/docs/VERSION")
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIChangeFilesPermissions(t *testing.T) {
+	prepareTestEnv(t)
+
+	options := &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("content")),
+	}
+
+	// not signed in
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", options)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// not a collaborator
+	session := loginUser(t, "user4")
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", options)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	// protected branch
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, models.UpdateProtectBranch(repo, &models.ProtectedBranch{
+		RepoID:     repo.ID,
+		BranchName: "master",
+	}, models.WhitelistOptions{}))
+	session = loginUser(t, "user2")
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", options)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
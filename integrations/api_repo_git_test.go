@@ -0,0 +1,165 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const repo1CommitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+func TestAPIGitRefs(t *testing.T) {
+	prepareTestEnv(t)
+
+	for prefix, expected := range map[string][]string{
+		"": {
+			"refs/heads/DefaultBranch",
+			"refs/heads/develop",
+			"refs/heads/feature/1",
+			"refs/heads/master",
+			"refs/tags/v1.1",
+		},
+		"/heads/feat": {"refs/heads/feature/1"},
+		"/tags/":      {"refs/tags/v1.1"},
+		"/notes/":     {},
+	} {
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs"+prefix)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var refs []*api.Reference
+		DecodeJSON(t, resp, &refs)
+		if assert.Len(t, refs, len(expected), prefix) {
+			for i, ref := range refs {
+				assert.Equal(t, expected[i], ref.Ref)
+				assert.Equal(t, "commit", ref.Object.Type)
+				assert.Equal(t, repo1CommitID, ref.Object.SHA)
+			}
+		}
+	}
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo2/git/refs")
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIGitObjects(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/docs/a.txt", &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("a\nb\n")),
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var file api.FileResponse
+	DecodeJSON(t, resp, &file)
+	commitID := file.Commit.SHA
+
+	// commits
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+commitID)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var commit api.GitCommit
+	DecodeJSON(t, resp, &commit)
+	assert.Equal(t, commitID, commit.SHA)
+	assert.Equal(t, "Add 'docs/a.txt'\n", commit.Message)
+	if assert.Len(t, commit.Parents, 1) {
+		assert.Equal(t, repo1CommitID, commit.Parents[0].SHA)
+	}
+	if assert.Len(t, commit.Files, 1) {
+		assert.Equal(t, "docs/a.txt", commit.Files[0].Filename)
+		assert.Equal(t, "added", commit.Files[0].Status)
+		assert.Equal(t, 2, commit.Files[0].Additions)
+	}
+	assert.Equal(t, 2, commit.Stats.Total)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+repo1CommitID)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &commit)
+	assert.Len(t, commit.Parents, 0)
+	assert.Equal(t, "2a2f1d4670728a2e10049e345bd7a276468beab6", commit.Tree.SHA)
+	if assert.Len(t, commit.Files, 1) {
+		assert.Equal(t, "README.md", commit.Files[0].Filename)
+	}
+
+	// trees
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/master")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var tree api.GitTreeResponse
+	DecodeJSON(t, resp, &tree)
+	assert.Equal(t, 2, tree.TotalCount)
+	assert.False(t, tree.Truncated)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/master?recursive=1&limit=2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tree)
+	assert.Equal(t, 3, tree.TotalCount)
+	assert.True(t, tree.Truncated)
+	if assert.Len(t, tree.Entries, 2) {
+		assert.Equal(t, "README.md", tree.Entries[0].Path)
+		assert.Equal(t, "100644", tree.Entries[0].Mode)
+		assert.Equal(t, "docs", tree.Entries[1].Path)
+		assert.Equal(t, "tree", tree.Entries[1].Type)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/"+commitID+"?recursive=1&limit=2&page=2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tree)
+	assert.False(t, tree.Truncated)
+	if assert.Len(t, tree.Entries, 1) {
+		assert.Equal(t, "docs/a.txt", tree.Entries[0].Path)
+		assert.EqualValues(t, 4, tree.Entries[0].Size)
+	}
+
+	// blobs
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/blobs/"+tree.Entries[0].SHA)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var blob api.GitBlobResponse
+	DecodeJSON(t, resp, &blob)
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(content))
+
+	// tags
+	_, err = git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+		"tag", "-a", "-m", "Release 2.0", "v2.0", commitID).RunInDir(models.RepoPath("user2", "repo1"))
+	assert.NoError(t, err)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs/tags/v2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var refs []*api.Reference
+	DecodeJSON(t, resp, &refs)
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, "tag", refs[0].Object.Type)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/tags/"+refs[0].Object.SHA)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var tag api.AnnotatedTag
+		DecodeJSON(t, resp, &tag)
+		assert.Equal(t, "v2.0", tag.Tag)
+		assert.Equal(t, "Release 2.0\n", tag.Message)
+		assert.Equal(t, "user2", tag.Tagger.Name)
+		assert.Equal(t, "commit", tag.Object.Type)
+		assert.Equal(t, commitID, tag.Object.SHA)
+	}
+
+	// objects of another type or which do not exist
+	for _, url := range []string{
+		"/api/v1/repos/user2/repo1/git/blobs/" + commitID,
+		"/api/v1/repos/user2/repo1/git/commits/" + blob.SHA,
+		"/api/v1/repos/user2/repo1/git/tags/" + commitID,
+		"/api/v1/repos/user2/repo1/git/trees/" + blob.SHA,
+		"/api/v1/repos/user2/repo1/git/trees/missing",
+		"/api/v1/repos/user2/repo1/git/commits/0000000000000000000000000000000000000000",
+		"/api/v1/repos/user2/repo1/git/commits/master",
+		"/api/v1/repos/user2/repo2/git/commits/" + commitID,
+	} {
+		req = NewRequest(t, "GET", url)
+		MakeRequest(t, req, http.StatusNotFound)
+	}
+}
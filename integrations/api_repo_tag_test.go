@@ -0,0 +1,85 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoTags(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var tags []*api.Tag
+	DecodeJSON(t, resp, &tags)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "v1.1", tags[0].Name)
+		assert.Equal(t, repo1CommitID, tags[0].ID)
+		assert.Equal(t, repo1CommitID, tags[0].Commit.SHA)
+		assert.Empty(t, tags[0].Message)
+	}
+
+	// annotated tag
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v2.0",
+		Message: "Release 2.0",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var tag api.Tag
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, "v2.0", tag.Name)
+	assert.Equal(t, "Release 2.0\n", tag.Message)
+	assert.Equal(t, repo1CommitID, tag.Commit.SHA)
+	assert.NotEqual(t, repo1CommitID, tag.ID)
+	models.AssertExistsAndLoadBean(t, &models.Release{RepoID: 1, TagName: "v2.0", IsTag: true})
+
+	// the tag exists already
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v2.0",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	// lightweight tag
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v2.1",
+		Target:  "develop",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, repo1CommitID, tag.ID)
+	assert.Empty(t, tag.Message)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v2.2",
+		Target:  "missing",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags/v2.0")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, "Release 2.0\n", tag.Message)
+
+	// delete
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/v2.0")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Release{RepoID: 1, TagName: "v2.0"})
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags/v2.0")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/v2.0")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// not a collaborator
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/v2.1")
+	loginUser(t, "user4").MakeRequest(t, req, http.StatusForbidden)
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -235,3 +236,72 @@ func TestAPIGetRepoByIDUnauthorized(t *testing.T) {
 	req := NewRequestf(t, "GET", "/api/v1/repositories/2")
 	sess.MakeRequest(t, req, http.StatusNotFound)
 }
+
+func TestAPIGenerateRepo(t *testing.T) {
+	prepareTestEnv(t)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+
+	opts := &api.GenerateRepoOption{
+		Owner:      user.Name,
+		Name:       "generated",
+		GitContent: true,
+		Labels:     true,
+	}
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/generate", opts)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	templateRepo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	templateRepo.IsTemplate = true
+	assert.NoError(t, models.UpdateRepository(templateRepo, false))
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/generate", opts)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.EqualValues(t, "generated", repo.Name)
+	assert.False(t, repo.Template)
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID, TemplateID: templateRepo.ID})
+
+	req = NewRequest(t, "GET", "/user2/generated/raw/branch/master/README.md")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// user3 is an organization user2 owns
+	opts.Owner = "user3"
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/generate", opts)
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	// user5 is not an organization
+	opts.Owner = "user5"
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/generate", opts)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// generated repositories are private when it is forced by the settings
+	defer func(forcePrivate bool) {
+		setting.Repository.ForcePrivate = forcePrivate
+	}(setting.Repository.ForcePrivate)
+	setting.Repository.ForcePrivate = true
+	opts.Owner = user.Name
+	opts.Name = "generated-private"
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/generate", opts)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &repo)
+	assert.True(t, repo.Private)
+}
+
+func TestAPISearchCode(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/code/search?q=repo1&language=markdown&path=README")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var results []*api.CodeSearchResult
+	DecodeJSON(t, resp, &results)
+	for _, result := range results {
+		assert.False(t, result.Repository.Private)
+		assert.Equal(t, "markdown", result.Language)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/code/search?q=repo1&owner=unknown")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoTopics(t *testing.T) {
+	prepareTestEnv(t)
+
+	// Anybody can read the topics of a public repository
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/topics")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var topics api.TopicName
+	DecodeJSON(t, resp, &topics)
+	assert.EqualValues(t, []string{"database", "golang"}, topics.TopicNames)
+
+	// Only admins of the repository can change them
+	session := loginUser(t, "user4")
+	req = NewRequest(t, "PUT", "/api/v1/repos/user2/repo1/topics/sql")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user2")
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/topics", &api.RepoTopicOptions{
+		Topics: []string{"Golang", "web"},
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "PUT", "/api/v1/repos/user2/repo1/topics/sql")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "PUT", "/api/v1/repos/user2/repo1/topics/not%20valid")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/topics/web")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/topics/web")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.EqualValues(t, []string{"golang", "sql"}, repo.Topics)
+
+	req = NewRequest(t, "GET", "/api/v1/topics/search?q=sq")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var results []*api.TopicResponse
+	DecodeJSON(t, resp, &results)
+	if assert.Len(t, results, 1) {
+		assert.EqualValues(t, "sql", results[0].Name)
+		assert.EqualValues(t, 1, results[0].RepoCount)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/search?topic=sql")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var body api.SearchResults
+	DecodeJSON(t, resp, &body)
+	if assert.Len(t, body.Data, 1) {
+		assert.EqualValues(t, repo.ID, body.Data[0].ID)
+		assert.EqualValues(t, []string{"golang", "sql"}, body.Data[0].Topics)
+	}
+}
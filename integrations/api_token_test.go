@@ -0,0 +1,90 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenScopes(t *testing.T) {
+	prepareTestEnv(t)
+
+	// token with all scopes
+	req := NewRequest(t, "GET", "/api/v1/user?token=hash1")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/admin/hooks?token=hash1")
+	MakeRequest(t, req, http.StatusOK)
+
+	// token with the repo:read scope
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1?token=hash2")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/user/repos?token=hash2")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos?token=hash2", &api.CreateRepoOption{
+		Name: "scoped-repo",
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/labels?token=hash2", &api.CreateLabelOption{
+		Name:  "scoped",
+		Color: "#abcdef",
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1?token=hash2")
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/user?token=hash2")
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/admin/hooks?token=hash2")
+	MakeRequest(t, req, http.StatusForbidden)
+	models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 2, Name: "repo1"})
+}
+
+func TestAPITokenExpired(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/api/v1/user?token=hash4")
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
+
+func TestAPICreateToken(t *testing.T) {
+	prepareTestEnv(t)
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", &api.CreateAccessTokenOption{
+		Name:      "ci",
+		Scopes:    []string{"repo", "repo:read"},
+		ExpiresAt: &expires,
+	})
+	req.SetBasicAuth("user2", userPassword)
+	resp := MakeRequest(t, req, http.StatusCreated)
+
+	var apiToken api.AccessToken
+	DecodeJSON(t, resp, &apiToken)
+	assert.Equal(t, "ci", apiToken.Name)
+	assert.EqualValues(t, []string{"repo:read", "repo"}, apiToken.Scopes)
+	if assert.NotNil(t, apiToken.ExpiresAt) {
+		assert.EqualValues(t, expires.Unix(), apiToken.ExpiresAt.Unix())
+	}
+	assert.Nil(t, apiToken.LastUsedAt)
+	models.AssertExistsAndLoadBean(t, &models.AccessToken{
+		UID:         2,
+		Name:        "ci",
+		Scope:       "repo:read,repo",
+		ExpiresUnix: expires.Unix(),
+	})
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", &api.CreateAccessTokenOption{
+		Name:   "invalid",
+		Scopes: []string{"delete_everything"},
+	})
+	req.SetBasicAuth("user2", userPassword)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
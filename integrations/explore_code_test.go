@@ -0,0 +1,24 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestExploreCode(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/explore/code?q=repo1")
+	MakeRequest(t, req, http.StatusOK)
+
+	session := loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/explore/code?q=repo1&owner=user2&language=markdown&path=README")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/explore/code?q=repo1&owner=unknown")
+	session.MakeRequest(t, req, http.StatusOK)
+}
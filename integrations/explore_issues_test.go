@@ -0,0 +1,21 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestExploreIssues(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/explore/issues")
+	MakeRequest(t, req, http.StatusOK)
+
+	session := loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/explore/issues?q=issue&state=closed&labels=label1,label2&poster=user2")
+	session.MakeRequest(t, req, http.StatusOK)
+}
@@ -7,6 +7,8 @@ package integrations
 import (
 	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestExploreRepos(t *testing.T) {
@@ -15,3 +17,20 @@ func TestExploreRepos(t *testing.T) {
 	req := NewRequest(t, "GET", "/explore/repos")
 	MakeRequest(t, req, http.StatusOK)
 }
+
+func TestExploreReposByTopic(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/explore/repos?topic=golang")
+	resp := MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 2, htmlDoc.doc.Find(".repository.list .item a.name").Length())
+
+	// repo1 is searched by one of its topics
+	req = NewRequest(t, "GET", "/explore/repos?q=database")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	names := htmlDoc.doc.Find(".repository.list .item a.name")
+	assert.EqualValues(t, 1, names.Length())
+	assert.Contains(t, names.Text(), "repo1")
+}
@@ -10,12 +10,15 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/Unknwon/com"
 	"github.com/stretchr/testify/assert"
@@ -26,7 +29,10 @@ func onGiteaWebRun(t *testing.T, callback func(*testing.T, string)) {
 		Handler: mac,
 	}
 
-	listener, err := net.Listen("tcp", "")
+	// listen on the configured address, the git hooks reach back to it
+	u, err := url.Parse(setting.AppURL)
+	assert.NoError(t, err)
+	listener, err := net.Listen("tcp", u.Host)
 	assert.NoError(t, err)
 
 	defer func() {
@@ -58,3 +64,83 @@ func TestClone_ViaHTTP_NoLogin(t *testing.T) {
 		assert.True(t, com.IsExist(filepath.Join(dstPath, "README.md")))
 	})
 }
+
+func TestPush_ProtectedBranch_ViaHTTP(t *testing.T) {
+	prepareTestEnv(t)
+
+	onGiteaWebRun(t, func(t *testing.T, urlPrefix string) {
+		u, err := url.Parse(urlPrefix)
+		assert.NoError(t, err)
+		u.User = url.UserPassword("user2", userPassword)
+		u.Path = "user2/repo1.git"
+
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		commit := func(content string) {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "README.md"), []byte(content), 0644))
+			_, err := git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+				"commit", "-am", content).RunInDir(dstPath)
+			assert.NoError(t, err)
+		}
+		push := func(args ...string) error {
+			_, err := git.NewCommand(append([]string{"push", "origin"}, args...)...).RunInDir(dstPath)
+			return err
+		}
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		protectBranch := &models.ProtectedBranch{RepoID: repo.ID, BranchName: "master"}
+		assert.NoError(t, models.UpdateProtectBranch(repo, protectBranch, models.WhitelistOptions{}))
+
+		commit("not whitelisted")
+		err = push("master")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "protected branch master can not be pushed to")
+		}
+
+		protectBranch.EnableWhitelist = true
+		assert.NoError(t, models.UpdateProtectBranch(repo, protectBranch, models.WhitelistOptions{UserIDs: []int64{2}}))
+		assert.NoError(t, push("master"))
+
+		_, err = git.NewCommand("reset", "--hard", "HEAD~1").RunInDir(dstPath)
+		assert.NoError(t, err)
+		commit("force pushed")
+		err = push("--force", "master")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "branch master is protected from force push")
+		}
+
+		err = push("--delete", "master")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "branch master is protected from deletion")
+		}
+	})
+}
+
+func TestPush_ArchivedRepo_ViaHTTP(t *testing.T) {
+	prepareTestEnv(t)
+
+	onGiteaWebRun(t, func(t *testing.T, urlPrefix string) {
+		u, err := url.Parse(urlPrefix)
+		assert.NoError(t, err)
+		u.User = url.UserPassword("user2", userPassword)
+		u.Path = "user2/repo1.git"
+
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		assert.NoError(t, repo.SetArchiveRepoState(true))
+
+		_, err = git.NewCommand("push", "origin", "master:archived").RunInDir(dstPath)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "repository user2/repo1 is archived and read-only")
+		}
+	})
+}
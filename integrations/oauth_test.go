@@ -0,0 +1,401 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/user"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oauth2ClientID     = "da7da3ba-9a13-4167-856f-3899de0b0138"
+	oauth2ClientSecret = "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA"
+	oauth2CodeVerifier = "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"
+)
+
+func TestNoClientID(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/login/oauth/authorize")
+	ctx := loginUser(t, "user2")
+	ctx.MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestLoginRedirect(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/login/oauth/authorize")
+	assert.Contains(t, MakeRequest(t, req, http.StatusFound).Headers.Get("Location"), "/user/login")
+}
+
+func TestShowAuthorize(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestf(t, "GET", "/login/oauth/authorize?client_id=%s&redirect_uri=a&response_type=code&state=thestate&scope=openid+email", oauth2ClientID)
+	ctx := loginUser(t, "user4")
+	resp := ctx.MakeRequest(t, req, http.StatusOK)
+
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, "input[name='client_id']", true)
+	htmlDoc.AssertElement(t, "input[name='redirect_uri']", true)
+	assert.EqualValues(t, "openid email", htmlDoc.GetInputValueByName("scope"))
+}
+
+func TestRedirectWithExistingGrant(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestf(t, "GET", "/login/oauth/authorize?client_id=%s&redirect_uri=https%%3A%%2F%%2Fexample.com%%2Fxyzzy&response_type=code&state=thestate&scope=openid+profile", oauth2ClientID)
+	ctx := loginUser(t, "user1")
+	resp := ctx.MakeRequest(t, req, http.StatusFound)
+	u, err := url.Parse(resp.Headers.Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", u.Host)
+	assert.Equal(t, "thestate", u.Query().Get("state"))
+	assert.NotEmpty(t, u.Query().Get("code"))
+}
+
+func TestAuthorizeUnregisteredRedirect(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestf(t, "GET", "/login/oauth/authorize?client_id=%s&redirect_uri=https%%3A%%2F%%2Fevil.com&response_type=code", oauth2ClientID)
+	ctx := loginUser(t, "user1")
+	ctx.MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestGrantApplication(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user4")
+	csrf := GetCSRF(t, session, "/user/settings/applications")
+	req := NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        csrf,
+		"client_id":    oauth2ClientID,
+		"redirect_uri": "https://example.com/xyzzy",
+		"state":        "thestate",
+		"scope":        "openid",
+		"granted":      "true",
+	})
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	assert.True(t, strings.HasPrefix(resp.Headers.Get("Location"), "https://example.com/xyzzy?code="))
+
+	// denying the access redirects with an error
+	req = NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        csrf,
+		"client_id":    oauth2ClientID,
+		"redirect_uri": "https://example.com/xyzzy",
+		"state":        "thestate",
+		"granted":      "false",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	u, err := url.Parse(resp.Headers.Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "access_denied", u.Query().Get("error"))
+}
+
+func exchangeAuthorizationCode(t *testing.T, values map[string]string, expectedStatus int) *TestResponse {
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", values)
+	return MakeRequest(t, req, expectedStatus)
+}
+
+func TestAccessTokenExchange(t *testing.T) {
+	prepareTestEnv(t)
+
+	// the redirect uri of the authorization request is required
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusBadRequest)
+
+	resp := exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusOK)
+	var token user.AccessTokenResponse
+	DecodeJSON(t, resp, &token)
+	assert.Equal(t, "bearer", token.TokenType)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.NotEmpty(t, token.RefreshToken)
+	assert.NotEmpty(t, token.IDToken)
+
+	// the authorization code can only be used once
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusBadRequest)
+}
+
+func TestAccessTokenExchangeWithBasicAuth(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	})
+	req.SetBasicAuth(oauth2ClientID, oauth2ClientSecret)
+	MakeRequest(t, req, http.StatusOK)
+}
+
+func TestAccessTokenExchangePublicClient(t *testing.T) {
+	prepareTestEnv(t)
+
+	// a wrong code verifier is rejected
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "wrong",
+	}, http.StatusBadRequest)
+
+	// the code challenge authenticates a client without secret
+	resp := exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusOK)
+	var token user.AccessTokenResponse
+	DecodeJSON(t, resp, &token)
+
+	// the refresh token can be used without secret as well, but only by the
+	// same client
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     "unknown",
+		"refresh_token": token.RefreshToken,
+	}, http.StatusUnauthorized)
+	resp = exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauth2ClientID,
+		"refresh_token": token.RefreshToken,
+	}, http.StatusOK)
+	var refreshed user.AccessTokenResponse
+	DecodeJSON(t, resp, &refreshed)
+	assert.NotEmpty(t, refreshed.AccessToken)
+}
+
+func TestAccessTokenExchangeWrongSecret(t *testing.T) {
+	prepareTestEnv(t)
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": "wrong",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusUnauthorized)
+}
+
+func TestAccessTokenUsage(t *testing.T) {
+	prepareTestEnv(t)
+
+	// grant the user scope in addition to the scope of the fixture
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/user/settings/applications")
+	req := NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        csrf,
+		"client_id":    oauth2ClientID,
+		"redirect_uri": "https://example.com/xyzzy",
+		"scope":        "openid profile user",
+		"granted":      "true",
+	})
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	u, err := url.Parse(resp.Headers.Get("Location"))
+	assert.NoError(t, err)
+
+	resp = exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"redirect_uri":  "https://example.com/xyzzy",
+		"code":          u.Query().Get("code"),
+	}, http.StatusOK)
+	var token user.AccessTokenResponse
+	DecodeJSON(t, resp, &token)
+
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiUser api.User
+	DecodeJSON(t, resp, &apiUser)
+	assert.EqualValues(t, "user1", apiUser.UserName)
+
+	// the grant does not have the repo scope
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos", &api.CreateRepoOption{
+		Name: "oauth2-repo",
+	})
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// the refresh token is not an access token
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.Header.Set("Authorization", "Bearer "+token.RefreshToken)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var info map[string]interface{}
+	DecodeJSON(t, resp, &info)
+	assert.EqualValues(t, "1", info["sub"])
+	assert.EqualValues(t, "user1", info["preferred_username"])
+	// the email scope has not been granted
+	assert.Nil(t, info["email"])
+
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
+
+func TestAccessTokenWithoutScope(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/user/settings/applications")
+	req := NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        csrf,
+		"client_id":    oauth2ClientID,
+		"redirect_uri": "https://example.com/xyzzy",
+		"scope":        "",
+		"granted":      "true",
+	})
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	u, err := url.Parse(resp.Headers.Get("Location"))
+	assert.NoError(t, err)
+
+	resp = exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"redirect_uri":  "https://example.com/xyzzy",
+		"code":          u.Query().Get("code"),
+	}, http.StatusOK)
+	var token user.AccessTokenResponse
+	DecodeJSON(t, resp, &token)
+
+	// an application which did not request any scopes has no access
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos", &api.CreateRepoOption{
+		Name: "oauth2-repo",
+	})
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestRefreshTokenInvalidation(t *testing.T) {
+	prepareTestEnv(t)
+	resp := exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": oauth2CodeVerifier,
+	}, http.StatusOK)
+	var token user.AccessTokenResponse
+	DecodeJSON(t, resp, &token)
+
+	// refresh tokens of confidential clients require the secret
+	exchangeAuthorizationCode(t, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauth2ClientID,
+		"refresh_token": token.RefreshToken,
+	}, http.StatusUnauthorized)
+
+	refresh := map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauth2ClientID,
+		"client_secret": oauth2ClientSecret,
+		"refresh_token": token.RefreshToken,
+	}
+	resp = exchangeAuthorizationCode(t, refresh, http.StatusOK)
+	var refreshed user.AccessTokenResponse
+	DecodeJSON(t, resp, &refreshed)
+	assert.NotEmpty(t, refreshed.AccessToken)
+	assert.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+
+	// a refresh token can only be used once, reusing it revokes the grant
+	exchangeAuthorizationCode(t, refresh, http.StatusBadRequest)
+	models.AssertNotExistsBean(t, &models.OAuth2Grant{ID: 1})
+
+	refresh["refresh_token"] = refreshed.RefreshToken
+	exchangeAuthorizationCode(t, refresh, http.StatusBadRequest)
+}
+
+func TestOIDCDiscovery(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/.well-known/openid-configuration")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var config map[string]interface{}
+	DecodeJSON(t, resp, &config)
+	assert.EqualValues(t, "http://localhost:3003/login/oauth/authorize", config["authorization_endpoint"])
+	assert.EqualValues(t, "http://localhost:3003/login/oauth/keys", config["jwks_uri"])
+
+	req = NewRequest(t, "GET", "/login/oauth/keys")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var keys struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	DecodeJSON(t, resp, &keys)
+	if assert.Len(t, keys.Keys, 1) {
+		assert.Equal(t, "RSA", keys.Keys[0]["kty"])
+		assert.Equal(t, "RS256", keys.Keys[0]["alg"])
+		assert.NotEmpty(t, keys.Keys[0]["kid"])
+	}
+}
+
+func TestOAuth2ApplicationSettings(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	csrf := GetCSRF(t, session, "/user/settings/applications")
+	req := NewRequestWithValues(t, "POST", "/user/settings/applications/oauth2", map[string]string{
+		"_csrf":            csrf,
+		"application_name": "CI",
+		"redirect_uris":    "https://ci.example.com/callback",
+	})
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Headers.Get("Location"), "/user/settings/applications/oauth2/")
+
+	// relative redirect uris are rejected
+	req = NewRequestWithValues(t, "POST", "/user/settings/applications/oauth2", map[string]string{
+		"_csrf":            csrf,
+		"application_name": "CI",
+		"redirect_uris":    "/callback",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// the application of user1 is not accessible
+	req = NewRequest(t, "GET", "/user/settings/applications/oauth2/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// applications of organizations
+	csrf = GetCSRF(t, session, "/org/user3/settings/applications")
+	req = NewRequestWithValues(t, "POST", "/org/user3/settings/applications/oauth2", map[string]string{
+		"_csrf":            csrf,
+		"application_name": "Chat",
+		"redirect_uris":    "https://chat.example.com/callback",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Headers.Get("Location"), "/org/user3/settings/applications/oauth2/")
+	req = NewRequest(t, "GET", resp.Headers.Get("Location"))
+	session.MakeRequest(t, req, http.StatusOK)
+}
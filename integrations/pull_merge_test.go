@@ -10,10 +10,12 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
-func testPullMerge(t *testing.T, session *TestSession, user, repo, pullnum string) *TestResponse {
+func testPullMerge(t *testing.T, session *TestSession, user, repo, pullnum string, mergeStyle models.MergeStyle) *TestResponse {
 	req := NewRequest(t, "GET", path.Join(user, repo, "pulls", pullnum))
 	resp := session.MakeRequest(t, req, http.StatusOK)
 
@@ -23,6 +25,7 @@ func testPullMerge(t *testing.T, session *TestSession, user, repo, pullnum strin
 	assert.True(t, exists, "The template has changed")
 	req = NewRequestWithValues(t, "POST", link, map[string]string{
 		"_csrf": htmlDoc.GetCSRF(),
+		"do":    string(mergeStyle),
 	})
 	resp = session.MakeRequest(t, req, http.StatusFound)
 
@@ -55,7 +58,47 @@ func TestPullMerge(t *testing.T) {
 
 	elem := strings.Split(RedirectURL(t, resp), "/")
 	assert.EqualValues(t, "pulls", elem[3])
-	testPullMerge(t, session, elem[1], elem[2], elem[4])
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleMerge)
+}
+
+func TestPullRebase(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+	testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+
+	resp := testPullCreate(t, session, "user1", "repo1", "master")
+
+	elem := strings.Split(RedirectURL(t, resp), "/")
+	assert.EqualValues(t, "pulls", elem[3])
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleRebase)
+}
+
+func TestPullRebaseMerge(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+	testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+
+	resp := testPullCreate(t, session, "user1", "repo1", "master")
+
+	elem := strings.Split(RedirectURL(t, resp), "/")
+	assert.EqualValues(t, "pulls", elem[3])
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleRebaseMerge)
+}
+
+func TestPullSquash(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+	testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+	testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited!)\n")
+
+	resp := testPullCreate(t, session, "user1", "repo1", "master")
+
+	elem := strings.Split(RedirectURL(t, resp), "/")
+	assert.EqualValues(t, "pulls", elem[3])
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleSquash)
 }
 
 func TestPullCleanUpAfterMerge(t *testing.T) {
@@ -68,7 +111,7 @@ func TestPullCleanUpAfterMerge(t *testing.T) {
 
 	elem := strings.Split(RedirectURL(t, resp), "/")
 	assert.EqualValues(t, "pulls", elem[3])
-	testPullMerge(t, session, elem[1], elem[2], elem[4])
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleMerge)
 
 	// Check PR branch deletion
 	resp = testPullCleanUp(t, session, elem[1], elem[2], elem[4])
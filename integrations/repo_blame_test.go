@@ -0,0 +1,91 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoBlame(t *testing.T) {
+	prepareTestEnv(t)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/README.md")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var ranges []*api.BlameRange
+	DecodeJSON(t, resp, &ranges)
+	if assert.Len(t, ranges, 1) {
+		assert.Equal(t, 1, ranges[0].StartLine)
+		assert.Equal(t, 3, ranges[0].EndLine)
+		assert.Equal(t, []string{"# repo1", "", "Description for repo1"}, ranges[0].Lines)
+		assert.Equal(t, repo1CommitID, ranges[0].Commit.SHA)
+		assert.Equal(t, "Initial commit", ranges[0].Commit.Summary)
+		assert.Equal(t, "address1@example.com", ranges[0].Commit.Author.Email)
+		assert.Empty(t, ranges[0].Commit.PreviousSHA)
+	}
+
+	session := loginUser(t, "user2")
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/README.md", &api.UpdateFileOptions{
+		FileOptions: api.FileOptions{Message: "Blame me"},
+		SHA:         "4b4851ad51df6a7d9f25c979345979eaeb5b349f",
+		Content:     base64.StdEncoding.EncodeToString([]byte("# repo1\n\nDescription for repo1\nBlame me\n")),
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/README.md?ref=master")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &ranges)
+	if assert.Len(t, ranges, 2) {
+		assert.Equal(t, repo1CommitID, ranges[0].Commit.SHA)
+		assert.Equal(t, 2, ranges[0].EndLine)
+		assert.Equal(t, 3, ranges[1].StartLine)
+		assert.Equal(t, 4, ranges[1].EndLine)
+		assert.Equal(t, []string{"Description for repo1", "Blame me"}, ranges[1].Lines)
+		assert.Equal(t, "Blame me", ranges[1].Commit.Summary)
+		assert.Equal(t, repo1CommitID, ranges[1].Commit.PreviousSHA)
+		assert.Equal(t, "README.md", ranges[1].Commit.PreviousPath)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/README.md?ref="+repo1CommitID)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &ranges)
+	assert.Len(t, ranges, 1)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/missing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/README.md?ref=missing")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/user2/repo1/blame/branch/master/README.md")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	parts := htmlDoc.doc.Find(".blame-part")
+	assert.Equal(t, 2, parts.Length())
+	assert.Equal(t, "Blame me", parts.Eq(1).Find(".blame-summary").Text())
+	assert.Equal(t, 2, parts.Eq(1).Find(".lines-code li").Length())
+	prior, exists := parts.Eq(1).Find(".blame-prior").Attr("href")
+	assert.True(t, exists)
+	assert.Equal(t, "/user2/repo1/blame/commit/"+repo1CommitID+"/README.md#L3", prior)
+	assert.Equal(t, 0, parts.Eq(0).Find(".blame-prior").Length())
+
+	req = NewRequest(t, "GET", prior)
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 1, htmlDoc.doc.Find(".blame-part").Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/blame/branch/master/missing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the file view links to the blame
+	req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/README.md")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, `a[href="/user2/repo1/blame/branch/master/README.md"]`, true)
+}
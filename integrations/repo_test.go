@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
@@ -74,3 +75,34 @@ func TestViewRepo1CloneLinkAuthorized(t *testing.T) {
 	sshURL := fmt.Sprintf("%s@%s:user2/repo1.git", setting.RunUser, setting.SSH.Domain)
 	assert.Equal(t, sshURL, link)
 }
+
+func TestRepoTopics(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithValues(t, "POST", "/user2/repo1/topics", map[string]string{
+		"_csrf":  GetCSRF(t, session, "/user2/repo1/settings"),
+		"topics": "golang, web  sql",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.EqualValues(t, []string{"golang", "sql", "web"}, repo.Topics)
+
+	// Invalid topics are not saved
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/topics", map[string]string{
+		"_csrf":  GetCSRF(t, session, "/user2/repo1/settings"),
+		"topics": "golang -web",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.EqualValues(t, []string{"golang", "sql", "web"}, repo.Topics)
+
+	// Only admins can change the topics
+	session = loginUser(t, "user4")
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/topics", map[string]string{
+		"_csrf":  GetCSRF(t, session, "/user/settings"),
+		"topics": "golang",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -156,6 +156,30 @@ func (user *User) GetAccessibleRepositories(limit int) (repos []*Repository, _ e
 		Find(&repos)
 }
 
+// getReadableRepoIDs returns the IDs of all repositories the user can read,
+// which are all repositories for site administrators. A nil user can only
+// read public repositories. Only repositories of the owner are returned if
+// ownerID is not zero.
+func getReadableRepoIDs(e Engine, user *User, ownerID int64) ([]int64, error) {
+	sess := e.Table("repository").Cols("id")
+	if ownerID > 0 {
+		sess.And("owner_id = ?", ownerID)
+	}
+	if user == nil {
+		sess.And("is_private = ?", false)
+	} else if !user.IsAdmin {
+		sess.And("(is_private = ? OR owner_id = ? OR id IN (SELECT repo_id FROM `access` WHERE access.user_id = ?))",
+			false, user.ID, user.ID)
+	}
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, sess.Find(&repoIDs)
+}
+
+// GetReadableRepoIDs returns the IDs of all repositories the user can read
+func GetReadableRepoIDs(user *User, ownerID int64) ([]int64, error) {
+	return getReadableRepoIDs(x, user, ownerID)
+}
+
 func maxAccessMode(modes ...AccessMode) AccessMode {
 	max := AccessModeNone
 	for _, mode := range modes {
@@ -123,3 +123,26 @@ func TestRepository_RecalculateAccesses2(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, has)
 }
+
+func TestGetReadableRepoIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repoIDs, err := GetReadableRepoIDs(nil, 2)
+	assert.NoError(t, err)
+	assert.Contains(t, repoIDs, int64(1))
+	assert.NotContains(t, repoIDs, int64(2))
+	assert.NotContains(t, repoIDs, int64(4))
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repoIDs, err = GetReadableRepoIDs(user, 2)
+	assert.NoError(t, err)
+	assert.Contains(t, repoIDs, int64(1))
+	assert.Contains(t, repoIDs, int64(2))
+	assert.NotContains(t, repoIDs, int64(3))
+
+	repoIDs, err = GetReadableRepoIDs(user, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, repoIDs, int64(3))
+	assert.Contains(t, repoIDs, int64(4))
+	assert.NotContains(t, repoIDs, int64(5))
+}
@@ -476,6 +476,10 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 			}
 
 			if err = issue.ChangeStatus(doer, repo, true); err != nil {
+				// Don't return an error when dependencies are open as this would let the push fail
+				if IsErrDependenciesLeft(err) {
+					continue
+				}
 				return err
 			}
 		}
@@ -619,6 +623,10 @@ func CommitRepoAction(opts CommitRepoActionOptions) error {
 	case ActionDeleteBranch: // Delete Branch
 		isHookEventPush = true
 
+		if err = PrepareDeleteWebhooks(pusher, repo, "branch", refName); err != nil {
+			return err
+		}
+
 	case ActionPushTag: // Create
 		isHookEventPush = true
 
@@ -641,6 +649,10 @@ func CommitRepoAction(opts CommitRepoActionOptions) error {
 		}
 	case ActionDeleteTag: // Delete Tag
 		isHookEventPush = true
+
+		if err = PrepareDeleteWebhooks(pusher, repo, "tag", refName); err != nil {
+			return err
+		}
 	}
 
 	if isHookEventPush {
@@ -661,6 +673,21 @@ func CommitRepoAction(opts CommitRepoActionOptions) error {
 	return nil
 }
 
+// PrepareDeleteWebhooks adds the delete event of the branch or tag (refType)
+// refName of repo, deleted by doer, to the hook task queue.
+func PrepareDeleteWebhooks(doer *User, repo *Repository, refType, refName string) error {
+	if err := PrepareWebhooks(repo, HookEventDelete, &api.DeletePayload{
+		Ref:        refName,
+		RefType:    refType,
+		PusherType: api.PusherTypeUser,
+		Repo:       repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		return fmt.Errorf("PrepareWebhooks: %v", err)
+	}
+	return nil
+}
+
 func transferRepoAction(e Engine, doer, oldOwner *User, repo *Repository) (err error) {
 	if err = notifyWatchers(e, &Action{
 		ActUserID: doer.ID,
@@ -6,7 +6,6 @@ package models
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
@@ -24,17 +23,22 @@ const (
 
 // ProtectedBranch struct
 type ProtectedBranch struct {
-	ID               int64  `xorm:"pk autoincr"`
-	RepoID           int64  `xorm:"UNIQUE(s)"`
-	BranchName       string `xorm:"UNIQUE(s)"`
-	CanPush          bool   `xorm:"NOT NULL DEFAULT false"`
-	EnableWhitelist  bool
-	WhitelistUserIDs []int64   `xorm:"JSON TEXT"`
-	WhitelistTeamIDs []int64   `xorm:"JSON TEXT"`
-	Created          time.Time `xorm:"-"`
-	CreatedUnix      int64     `xorm:"created"`
-	Updated          time.Time `xorm:"-"`
-	UpdatedUnix      int64     `xorm:"updated"`
+	ID                        int64  `xorm:"pk autoincr"`
+	RepoID                    int64  `xorm:"UNIQUE(s)"`
+	BranchName                string `xorm:"UNIQUE(s)"`
+	CanPush                   bool   `xorm:"NOT NULL DEFAULT false"`
+	EnableWhitelist           bool
+	WhitelistUserIDs          []int64   `xorm:"JSON TEXT"`
+	WhitelistTeamIDs          []int64   `xorm:"JSON TEXT"`
+	RequiredApprovals         int64     `xorm:"NOT NULL DEFAULT 0"`
+	ApprovalsWhitelistUserIDs []int64   `xorm:"JSON TEXT"`
+	ApprovalsWhitelistTeamIDs []int64   `xorm:"JSON TEXT"`
+	EnableStatusCheck         bool      `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts       []string  `xorm:"JSON TEXT"`
+	Created                   time.Time `xorm:"-"`
+	CreatedUnix               int64     `xorm:"created"`
+	Updated                   time.Time `xorm:"-"`
+	UpdatedUnix               int64     `xorm:"updated"`
 }
 
 // IsProtected returns if the branch is protected
@@ -64,6 +68,87 @@ func (protectBranch *ProtectedBranch) CanUserPush(userID int64) bool {
 	return in
 }
 
+// IsUserOfficialReviewer returns if the approvals of the given user count towards the required approvals.
+// Without an approvals whitelist every user with write access to the repository is an official reviewer.
+func (protectBranch *ProtectedBranch) IsUserOfficialReviewer(user *User) (bool, error) {
+	return protectBranch.isUserOfficialReviewer(x, user)
+}
+
+func (protectBranch *ProtectedBranch) isUserOfficialReviewer(e Engine, user *User) (bool, error) {
+	if len(protectBranch.ApprovalsWhitelistUserIDs) == 0 && len(protectBranch.ApprovalsWhitelistTeamIDs) == 0 {
+		repo, err := getRepositoryByID(e, protectBranch.RepoID)
+		if err != nil {
+			return false, err
+		}
+		return hasAccess(e, user.ID, repo, AccessModeWrite)
+	}
+
+	if base.Int64sContains(protectBranch.ApprovalsWhitelistUserIDs, user.ID) {
+		return true, nil
+	}
+
+	if len(protectBranch.ApprovalsWhitelistTeamIDs) == 0 {
+		return false, nil
+	}
+	return isUserInTeams(e, user.ID, protectBranch.ApprovalsWhitelistTeamIDs)
+}
+
+// GetGrantedApprovalsCount returns the number of official reviewers whose latest review approves the pull request
+func (protectBranch *ProtectedBranch) GetGrantedApprovalsCount(pr *PullRequest) (int64, error) {
+	reviews, err := GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return 0, err
+	}
+
+	var approvals int64
+	for _, review := range reviews {
+		if review.Type != ReviewTypeApprove {
+			continue
+		}
+		official, err := protectBranch.IsUserOfficialReviewer(review.Reviewer)
+		if err != nil {
+			return 0, err
+		} else if official {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
+
+// HasEnoughApprovals returns true if the pull request has the required number of official approvals
+func (protectBranch *ProtectedBranch) HasEnoughApprovals(pr *PullRequest) (bool, error) {
+	if protectBranch.RequiredApprovals == 0 {
+		return true, nil
+	}
+	approvals, err := protectBranch.GetGrantedApprovalsCount(pr)
+	if err != nil {
+		return false, err
+	}
+	return approvals >= protectBranch.RequiredApprovals, nil
+}
+
+// GetUnmetStatusChecks returns the required status check contexts whose latest status
+// for the given commit of the repository is not a success.
+func (protectBranch *ProtectedBranch) GetUnmetStatusChecks(repo *Repository, sha string) ([]string, error) {
+	if !protectBranch.EnableStatusCheck {
+		return nil, nil
+	}
+
+	unmet := make([]string, 0, len(protectBranch.StatusCheckContexts))
+	for _, context := range protectBranch.StatusCheckContexts {
+		status := new(CommitStatus)
+		has, err := x.Where("repo_id = ? AND sha = ? AND context = ?", repo.ID, sha, context).
+			Desc("id").
+			Get(status)
+		if err != nil {
+			return nil, err
+		} else if !has || status.State != CommitStatusSuccess {
+			unmet = append(unmet, context)
+		}
+	}
+	return unmet, nil
+}
+
 // GetProtectedBranchByRepoID getting protected branch by repo ID
 func GetProtectedBranchByRepoID(RepoID int64) ([]*ProtectedBranch, error) {
 	protectedBranches := make([]*ProtectedBranch, 0)
@@ -72,7 +157,7 @@ func GetProtectedBranchByRepoID(RepoID int64) ([]*ProtectedBranch, error) {
 
 // GetProtectedBranchBy getting protected branch by ID/Name
 func GetProtectedBranchBy(repoID int64, BranchName string) (*ProtectedBranch, error) {
-	rel := &ProtectedBranch{RepoID: repoID, BranchName: strings.ToLower(BranchName)}
+	rel := &ProtectedBranch{RepoID: repoID, BranchName: BranchName}
 	has, err := x.Get(rel)
 	if err != nil {
 		return nil, err
@@ -96,44 +181,48 @@ func GetProtectedBranchByID(id int64) (*ProtectedBranch, error) {
 	return rel, nil
 }
 
+// WhitelistOptions represent all sorts of whitelists used for protected branches
+type WhitelistOptions struct {
+	UserIDs []int64
+	TeamIDs []int64
+
+	ApprovalsUserIDs []int64
+	ApprovalsTeamIDs []int64
+}
+
 // UpdateProtectBranch saves branch protection options of repository.
 // If ID is 0, it creates a new record. Otherwise, updates existing record.
 // This function also performs check if whitelist user and team's IDs have been changed
 // to avoid unnecessary whitelist delete and regenerate.
-func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, whitelistUserIDs, whitelistTeamIDs []int64) (err error) {
+func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, opts WhitelistOptions) (err error) {
 	if err = repo.GetOwner(); err != nil {
 		return fmt.Errorf("GetOwner: %v", err)
 	}
 
-	hasUsersChanged := !util.IsSliceInt64Eq(protectBranch.WhitelistUserIDs, whitelistUserIDs)
-	if hasUsersChanged {
-		protectBranch.WhitelistUserIDs = make([]int64, 0, len(whitelistUserIDs))
-		for _, userID := range whitelistUserIDs {
-			has, err := hasAccess(x, userID, repo, AccessModeWrite)
-			if err != nil {
-				return fmt.Errorf("HasAccess [user_id: %d, repo_id: %d]: %v", userID, protectBranch.RepoID, err)
-			} else if !has {
-				continue // Drop invalid user ID
-			}
+	whitelist, err := updateUserWhitelist(repo, protectBranch.WhitelistUserIDs, opts.UserIDs)
+	if err != nil {
+		return err
+	}
+	protectBranch.WhitelistUserIDs = whitelist
 
-			protectBranch.WhitelistUserIDs = append(protectBranch.WhitelistUserIDs, userID)
-		}
+	whitelist, err = updateUserWhitelist(repo, protectBranch.ApprovalsWhitelistUserIDs, opts.ApprovalsUserIDs)
+	if err != nil {
+		return err
 	}
+	protectBranch.ApprovalsWhitelistUserIDs = whitelist
 
 	// if the repo is in an orgniziation
-	hasTeamsChanged := !util.IsSliceInt64Eq(protectBranch.WhitelistTeamIDs, whitelistTeamIDs)
-	if hasTeamsChanged {
-		teams, err := GetTeamsWithAccessToRepo(repo.OwnerID, repo.ID, AccessModeWrite)
-		if err != nil {
-			return fmt.Errorf("GetTeamsWithAccessToRepo [org_id: %d, repo_id: %d]: %v", repo.OwnerID, repo.ID, err)
-		}
-		protectBranch.WhitelistTeamIDs = make([]int64, 0, len(teams))
-		for i := range teams {
-			if teams[i].HasWriteAccess() && com.IsSliceContainsInt64(whitelistTeamIDs, teams[i].ID) {
-				protectBranch.WhitelistTeamIDs = append(protectBranch.WhitelistTeamIDs, teams[i].ID)
-			}
-		}
+	whitelist, err = updateTeamWhitelist(repo, protectBranch.WhitelistTeamIDs, opts.TeamIDs)
+	if err != nil {
+		return err
+	}
+	protectBranch.WhitelistTeamIDs = whitelist
+
+	whitelist, err = updateTeamWhitelist(repo, protectBranch.ApprovalsWhitelistTeamIDs, opts.ApprovalsTeamIDs)
+	if err != nil {
+		return err
 	}
+	protectBranch.ApprovalsWhitelistTeamIDs = whitelist
 
 	// Make sure protectBranch.ID is not 0 for whitelists
 	if protectBranch.ID == 0 {
@@ -150,6 +239,48 @@ func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, white
 	return nil
 }
 
+// updateUserWhitelist checks whether the user whitelist changed and returns a whitelist with
+// the users from newWhitelist which have write access to the repo.
+func updateUserWhitelist(repo *Repository, currentWhitelist, newWhitelist []int64) (whitelist []int64, err error) {
+	if util.IsSliceInt64Eq(currentWhitelist, newWhitelist) {
+		return currentWhitelist, nil
+	}
+
+	whitelist = make([]int64, 0, len(newWhitelist))
+	for _, userID := range newWhitelist {
+		has, err := hasAccess(x, userID, repo, AccessModeWrite)
+		if err != nil {
+			return nil, fmt.Errorf("HasAccess [user_id: %d, repo_id: %d]: %v", userID, repo.ID, err)
+		} else if !has {
+			continue // Drop invalid user ID
+		}
+
+		whitelist = append(whitelist, userID)
+	}
+	return whitelist, nil
+}
+
+// updateTeamWhitelist checks whether the team whitelist changed and returns a whitelist with
+// the teams from newWhitelist which have write access to the repo.
+func updateTeamWhitelist(repo *Repository, currentWhitelist, newWhitelist []int64) (whitelist []int64, err error) {
+	if util.IsSliceInt64Eq(currentWhitelist, newWhitelist) {
+		return currentWhitelist, nil
+	}
+
+	teams, err := GetTeamsWithAccessToRepo(repo.OwnerID, repo.ID, AccessModeWrite)
+	if err != nil {
+		return nil, fmt.Errorf("GetTeamsWithAccessToRepo [org_id: %d, repo_id: %d]: %v", repo.OwnerID, repo.ID, err)
+	}
+
+	whitelist = make([]int64, 0, len(teams))
+	for i := range teams {
+		if teams[i].HasWriteAccess() && com.IsSliceContainsInt64(newWhitelist, teams[i].ID) {
+			whitelist = append(whitelist, teams[i].ID)
+		}
+	}
+	return whitelist, nil
+}
+
 // GetProtectedBranches get all protected branches
 func (repo *Repository) GetProtectedBranches() ([]*ProtectedBranch, error) {
 	protectedBranches := make([]*ProtectedBranch, 0)
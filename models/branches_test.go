@@ -78,3 +78,72 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestProtectedBranch_IsUserOfficialReviewer(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	owner := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// without a whitelist every writer is an official reviewer
+	protectBranch := &ProtectedBranch{RepoID: 1, BranchName: "master"}
+	official, err := protectBranch.IsUserOfficialReviewer(owner)
+	assert.NoError(t, err)
+	assert.True(t, official)
+	official, err = protectBranch.IsUserOfficialReviewer(user)
+	assert.NoError(t, err)
+	assert.False(t, official)
+
+	protectBranch.ApprovalsWhitelistUserIDs = []int64{3}
+	official, err = protectBranch.IsUserOfficialReviewer(owner)
+	assert.NoError(t, err)
+	assert.False(t, official)
+	official, err = protectBranch.IsUserOfficialReviewer(user)
+	assert.NoError(t, err)
+	assert.True(t, official)
+}
+
+func TestProtectedBranch_HasEnoughApprovals(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+
+	protectBranch := &ProtectedBranch{RepoID: 1, BranchName: "master"}
+	approvals, err := protectBranch.GetGrantedApprovalsCount(pr)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, approvals)
+
+	enough, err := protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.True(t, enough)
+
+	protectBranch.RequiredApprovals = 2
+	enough, err = protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.False(t, enough)
+
+	// the approval of user 2 does not count if only user 3 is whitelisted
+	protectBranch.RequiredApprovals = 1
+	protectBranch.ApprovalsWhitelistUserIDs = []int64{3}
+	enough, err = protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.False(t, enough)
+}
+
+func TestProtectedBranch_GetUnmetStatusChecks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	sha := "1234123412341234123412341234123412341234"
+
+	protectBranch := &ProtectedBranch{
+		RepoID:              1,
+		BranchName:          "master",
+		StatusCheckContexts: []string{"cov/awesomeness", "ci/awesomeness", "not/reported"},
+	}
+	unmet, err := protectBranch.GetUnmetStatusChecks(repo, sha)
+	assert.NoError(t, err)
+	assert.Empty(t, unmet)
+
+	protectBranch.EnableStatusCheck = true
+	unmet, err = protectBranch.GetUnmetStatusChecks(repo, sha)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ci/awesomeness", "not/reported"}, unmet)
+}
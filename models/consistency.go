@@ -27,7 +27,8 @@ func CheckConsistencyForAll(t *testing.T) {
 		&Milestone{},
 		&Label{},
 		&Team{},
-		&Action{})
+		&Action{},
+		&Topic{})
 }
 
 // CheckConsistencyFor test that all matching database entries are consistent
@@ -87,6 +88,7 @@ func (repo *Repository) checkForConsistency(t *testing.T) {
 	assertCount(t, &Watch{RepoID: repo.ID}, repo.NumWatches)
 	assertCount(t, &Milestone{RepoID: repo.ID}, repo.NumMilestones)
 	assertCount(t, &Repository{ForkID: repo.ID}, repo.NumForks)
+	assertCount(t, &RepoTopic{RepoID: repo.ID}, len(repo.Topics))
 	if repo.IsFork {
 		AssertExistsAndLoadBean(t, &Repository{ID: repo.ForkID})
 	}
@@ -164,3 +166,7 @@ func (action *Action) checkForConsistency(t *testing.T) {
 	repo := AssertExistsAndLoadBean(t, &Repository{ID: action.RepoID}).(*Repository)
 	assert.Equal(t, repo.IsPrivate, action.IsPrivate, "action: %+v", action)
 }
+
+func (topic *Topic) checkForConsistency(t *testing.T) {
+	assertCount(t, &RepoTopic{TopicID: topic.ID}, topic.RepoCount)
+}
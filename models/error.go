@@ -468,6 +468,36 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return fmt.Sprintf("access token is empty")
 }
 
+// ErrAccessTokenExpired represents a "AccessTokenExpired" kind of error.
+type ErrAccessTokenExpired struct {
+	ID int64
+}
+
+// IsErrAccessTokenExpired checks if an error is a ErrAccessTokenExpired.
+func IsErrAccessTokenExpired(err error) bool {
+	_, ok := err.(ErrAccessTokenExpired)
+	return ok
+}
+
+func (err ErrAccessTokenExpired) Error() string {
+	return fmt.Sprintf("access token has expired [id: %d]", err.ID)
+}
+
+// ErrAccessTokenScopeInvalid represents a "AccessTokenScopeInvalid" kind of error.
+type ErrAccessTokenScopeInvalid struct {
+	Scope string
+}
+
+// IsErrAccessTokenScopeInvalid checks if an error is a ErrAccessTokenScopeInvalid.
+func IsErrAccessTokenScopeInvalid(err error) bool {
+	_, ok := err.(ErrAccessTokenScopeInvalid)
+	return ok
+}
+
+func (err ErrAccessTokenScopeInvalid) Error() string {
+	return fmt.Sprintf("access token scope is invalid [scope: %s]", err.Scope)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -589,6 +619,83 @@ func (err ErrRepoNotExist) Error() string {
 		err.ID, err.UID, err.OwnerName, err.Name)
 }
 
+// ErrRepoArchived represents a "RepoArchived" kind of error.
+type ErrRepoArchived struct {
+	ID        int64
+	OwnerName string
+	Name      string
+}
+
+// IsErrRepoArchived checks if an error is a ErrRepoArchived.
+func IsErrRepoArchived(err error) bool {
+	_, ok := err.(ErrRepoArchived)
+	return ok
+}
+
+func (err ErrRepoArchived) Error() string {
+	return fmt.Sprintf("repository is archived [id: %d, owner_name: %s, name: %s]", err.ID, err.OwnerName, err.Name)
+}
+
+// ErrRepoNotTemplate represents a "RepoNotTemplate" kind of error.
+type ErrRepoNotTemplate struct {
+	ID int64
+}
+
+// IsErrRepoNotTemplate checks if an error is a ErrRepoNotTemplate.
+func IsErrRepoNotTemplate(err error) bool {
+	_, ok := err.(ErrRepoNotTemplate)
+	return ok
+}
+
+func (err ErrRepoNotTemplate) Error() string {
+	return fmt.Sprintf("repository is not a template [id: %d]", err.ID)
+}
+
+// ErrTopicNotExist represents a "TopicNotExist" kind of error.
+type ErrTopicNotExist struct {
+	Name string
+}
+
+// IsErrTopicNotExist checks if an error is an ErrTopicNotExist.
+func IsErrTopicNotExist(err error) bool {
+	_, ok := err.(ErrTopicNotExist)
+	return ok
+}
+
+func (err ErrTopicNotExist) Error() string {
+	return fmt.Sprintf("topic does not exist [name: %s]", err.Name)
+}
+
+// ErrTopicNotValid represents a "TopicNotValid" kind of error.
+type ErrTopicNotValid struct {
+	Name string
+}
+
+// IsErrTopicNotValid checks if an error is an ErrTopicNotValid.
+func IsErrTopicNotValid(err error) bool {
+	_, ok := err.(ErrTopicNotValid)
+	return ok
+}
+
+func (err ErrTopicNotValid) Error() string {
+	return fmt.Sprintf("topic is not valid [name: %s]", err.Name)
+}
+
+// ErrTooManyTopics represents a "TooManyTopics" kind of error.
+type ErrTooManyTopics struct {
+	RepoID int64
+}
+
+// IsErrTooManyTopics checks if an error is an ErrTooManyTopics.
+func IsErrTooManyTopics(err error) bool {
+	_, ok := err.(ErrTooManyTopics)
+	return ok
+}
+
+func (err ErrTooManyTopics) Error() string {
+	return fmt.Sprintf("repository has too many topics [repo_id: %d]", err.RepoID)
+}
+
 // ErrRepoAlreadyExist represents a "RepoAlreadyExist" kind of error.
 type ErrRepoAlreadyExist struct {
 	Uname string
@@ -639,6 +746,22 @@ func (err ErrInvalidCloneAddr) Error() string {
 		err.IsURLError, err.IsInvalidPath, err.IsPermissionDenied)
 }
 
+// ErrPushMirrorNotExist represents a "PushMirrorNotExist" kind of error.
+type ErrPushMirrorNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrPushMirrorNotExist checks if an error is a ErrPushMirrorNotExist.
+func IsErrPushMirrorNotExist(err error) bool {
+	_, ok := err.(ErrPushMirrorNotExist)
+	return ok
+}
+
+func (err ErrPushMirrorNotExist) Error() string {
+	return fmt.Sprintf("push mirror does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
 // ErrUpdateTaskNotExist represents a "UpdateTaskNotExist" kind of error.
 type ErrUpdateTaskNotExist struct {
 	UUID string
@@ -715,6 +838,38 @@ func (err ErrRepoFileAlreadyExist) Error() string {
 	return fmt.Sprintf("repository file already exists [file_name: %s]", err.FileName)
 }
 
+// ErrRepoFileDoesNotExist represents a "RepoFileDoesNotExist" kind of error.
+type ErrRepoFileDoesNotExist struct {
+	Path string
+}
+
+// IsErrRepoFileDoesNotExist checks if an error is a ErrRepoFileDoesNotExist.
+func IsErrRepoFileDoesNotExist(err error) bool {
+	_, ok := err.(ErrRepoFileDoesNotExist)
+	return ok
+}
+
+func (err ErrRepoFileDoesNotExist) Error() string {
+	return fmt.Sprintf("repository file does not exist [path: %s]", err.Path)
+}
+
+// ErrSHADoesNotMatch represents a "SHADoesNotMatch" kind of error.
+type ErrSHADoesNotMatch struct {
+	Path       string
+	GivenSHA   string
+	CurrentSHA string
+}
+
+// IsErrSHADoesNotMatch checks if an error is a ErrSHADoesNotMatch.
+func IsErrSHADoesNotMatch(err error) bool {
+	_, ok := err.(ErrSHADoesNotMatch)
+	return ok
+}
+
+func (err ErrSHADoesNotMatch) Error() string {
+	return fmt.Sprintf("sha does not match [path: %s, given: %s, current: %s]", err.Path, err.GivenSHA, err.CurrentSHA)
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -782,6 +937,21 @@ func (err ErrTagAlreadyExists) Error() string {
 	return fmt.Sprintf("tag already exists [name: %s]", err.TagName)
 }
 
+// ErrTagNotExist represents a "TagNotExist" kind of error.
+type ErrTagNotExist struct {
+	TagName string
+}
+
+// IsErrTagNotExist checks if an error is a ErrTagNotExist.
+func IsErrTagNotExist(err error) bool {
+	_, ok := err.(ErrTagNotExist)
+	return ok
+}
+
+func (err ErrTagNotExist) Error() string {
+	return fmt.Sprintf("tag does not exist [name: %s]", err.TagName)
+}
+
 //  __      __      ___.   .__                   __
 // /  \    /  \ ____\_ |__ |  |__   ____   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \ /  _ \ /  _ \|  |/ /
@@ -804,6 +974,22 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrHookTaskNotExist represents a "HookTaskNotExist" kind of error.
+type ErrHookTaskNotExist struct {
+	HookID int64
+	UUID   string
+}
+
+// IsErrHookTaskNotExist checks if an error is a ErrHookTaskNotExist.
+func IsErrHookTaskNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskNotExist)
+	return ok
+}
+
+func (err ErrHookTaskNotExist) Error() string {
+	return fmt.Sprintf("hook task does not exist [hook_id: %d, uuid: %s]", err.HookID, err.UUID)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -878,6 +1064,38 @@ func (err ErrPullRequestAlreadyExists) Error() string {
 		err.ID, err.IssueID, err.HeadRepoID, err.BaseRepoID, err.HeadBranch, err.BaseBranch)
 }
 
+// ErrInvalidMergeStyle represents an error if merging with disabled merge strategy
+type ErrInvalidMergeStyle struct {
+	ID    int64
+	Style MergeStyle
+}
+
+// IsErrInvalidMergeStyle checks if an error is a ErrInvalidMergeStyle.
+func IsErrInvalidMergeStyle(err error) bool {
+	_, ok := err.(ErrInvalidMergeStyle)
+	return ok
+}
+
+func (err ErrInvalidMergeStyle) Error() string {
+	return fmt.Sprintf("merge strategy is not allowed or is invalid [repo_id: %d, strategy: %s]",
+		err.ID, err.Style)
+}
+
+// ErrNotAllowedToMerge represents an error that a pull request does not meet the rules of its protected base branch.
+type ErrNotAllowedToMerge struct {
+	Reason string
+}
+
+// IsErrNotAllowedToMerge checks if an error is an ErrNotAllowedToMerge.
+func IsErrNotAllowedToMerge(err error) bool {
+	_, ok := err.(ErrNotAllowedToMerge)
+	return ok
+}
+
+func (err ErrNotAllowedToMerge) Error() string {
+	return fmt.Sprintf("not allowed to merge [reason: %s]", err.Reason)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -901,6 +1119,134 @@ func (err ErrCommentNotExist) Error() string {
 	return fmt.Sprintf("comment does not exist [id: %d, issue_id: %d]", err.ID, err.IssueID)
 }
 
+// ErrForbiddenReaction represents a "ForbiddenReaction" kind of error.
+type ErrForbiddenReaction struct {
+	Reaction string
+}
+
+// IsErrForbiddenReaction checks if an error is a ErrForbiddenReaction.
+func IsErrForbiddenReaction(err error) bool {
+	_, ok := err.(ErrForbiddenReaction)
+	return ok
+}
+
+func (err ErrForbiddenReaction) Error() string {
+	return fmt.Sprintf("reaction is not allowed [reaction: %s]", err.Reaction)
+}
+
+// ErrDependencyExists represents a "DependencyExists" kind of error.
+type ErrDependencyExists struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyExists checks if an error is a ErrDependencyExists.
+func IsErrDependencyExists(err error) bool {
+	_, ok := err.(ErrDependencyExists)
+	return ok
+}
+
+func (err ErrDependencyExists) Error() string {
+	return fmt.Sprintf("issue dependency does already exist [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependencyNotExists represents a "DependencyNotExists" kind of error.
+type ErrDependencyNotExists struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyNotExists checks if an error is a ErrDependencyNotExists.
+func IsErrDependencyNotExists(err error) bool {
+	_, ok := err.(ErrDependencyNotExists)
+	return ok
+}
+
+func (err ErrDependencyNotExists) Error() string {
+	return fmt.Sprintf("issue dependency does not exist [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrCircularDependency represents a "CircularDependency" kind of error.
+type ErrCircularDependency struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrCircularDependency checks if an error is a ErrCircularDependency.
+func IsErrCircularDependency(err error) bool {
+	_, ok := err.(ErrCircularDependency)
+	return ok
+}
+
+func (err ErrCircularDependency) Error() string {
+	return fmt.Sprintf("circular dependencies exists (two issues blocking each other) [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependenciesLeft represents a "DependenciesLeft" kind of error.
+type ErrDependenciesLeft struct {
+	IssueID int64
+}
+
+// IsErrDependenciesLeft checks if an error is a ErrDependenciesLeft.
+func IsErrDependenciesLeft(err error) bool {
+	_, ok := err.(ErrDependenciesLeft)
+	return ok
+}
+
+func (err ErrDependenciesLeft) Error() string {
+	return fmt.Sprintf("issue has open dependencies [issue_id: %d]", err.IssueID)
+}
+
+// __________            .__
+// \______   \ _______  _|__| ______  _  __
+//  |       _// __ \  \/ /  |/ __ \ \/ \/ /
+//  |    |   \  ___/\   /|  \  ___/\     /
+//  |____|_  /\___  >\_/ |__|\___  >\/\_/
+//         \/     \/             \/
+
+// ErrReviewNotExist represents a "ReviewNotExist" kind of error.
+type ErrReviewNotExist struct {
+	ID int64
+}
+
+// IsErrReviewNotExist checks if an error is a ErrReviewNotExist.
+func IsErrReviewNotExist(err error) bool {
+	_, ok := err.(ErrReviewNotExist)
+	return ok
+}
+
+func (err ErrReviewNotExist) Error() string {
+	return fmt.Sprintf("review does not exist [id: %d]", err.ID)
+}
+
+// ErrInvalidReviewType represents a "InvalidReviewType" kind of error.
+type ErrInvalidReviewType struct {
+	Type ReviewType
+}
+
+// IsErrInvalidReviewType checks if an error is a ErrInvalidReviewType.
+func IsErrInvalidReviewType(err error) bool {
+	_, ok := err.(ErrInvalidReviewType)
+	return ok
+}
+
+func (err ErrInvalidReviewType) Error() string {
+	return fmt.Sprintf("review type is invalid [type: %d]", err.Type)
+}
+
+// ErrReviewEmpty represents a "ReviewEmpty" kind of error.
+type ErrReviewEmpty struct{}
+
+// IsErrReviewEmpty checks if an error is a ErrReviewEmpty.
+func IsErrReviewEmpty(err error) bool {
+	_, ok := err.(ErrReviewEmpty)
+	return ok
+}
+
+func (err ErrReviewEmpty) Error() string {
+	return "review has neither content nor code comments"
+}
+
 //  _________ __                                __         .__
 //  /   _____//  |_  ____ ________  _  _______ _/  |_  ____ |  |__
 //  \_____  \\   __\/  _ \\____ \ \/ \/ /\__  \\   __\/ ___\|  |  \
@@ -945,6 +1291,21 @@ func (err ErrTrackedTimeNotExist) Error() string {
 	return fmt.Sprintf("tracked time does not exist [id: %d]", err.ID)
 }
 
+// ErrNotificationNotExist represents a "NotificationNotExist" kind of error.
+type ErrNotificationNotExist struct {
+	ID int64
+}
+
+// IsErrNotificationNotExist checks if an error is a ErrNotificationNotExist.
+func IsErrNotificationNotExist(err error) bool {
+	_, ok := err.(ErrNotificationNotExist)
+	return ok
+}
+
+func (err ErrNotificationNotExist) Error() string {
+	return fmt.Sprintf("notification does not exist [id: %d]", err.ID)
+}
+
 // .____          ___.          .__
 // |    |   _____ \_ |__   ____ |  |
 // |    |   \__  \ | __ \_/ __ \|  |
@@ -991,6 +1352,61 @@ func (err ErrMilestoneNotExist) Error() string {
 	return fmt.Sprintf("milestone does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
 }
 
+// __________                   __               __
+// \______   \_______  ____    |__| ____   _____/  |_
+//  |     ___/\_  __ \/  _ \   |  |/ __ \_/ ___\   __\
+//  |    |     |  | \(  <_> )  |  \  ___/\  \___|  |
+//  |____|     |__|   \____/\__|  |\___  >\___  >__|
+//                         \______|    \/     \/
+
+// ErrProjectNotExist represents a "ProjectNotExist" kind of error.
+type ErrProjectNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrProjectNotExist checks if an error is a ErrProjectNotExist.
+func IsErrProjectNotExist(err error) bool {
+	_, ok := err.(ErrProjectNotExist)
+	return ok
+}
+
+func (err ErrProjectNotExist) Error() string {
+	return fmt.Sprintf("project does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrProjectColumnNotExist represents a "ProjectColumnNotExist" kind of error.
+type ErrProjectColumnNotExist struct {
+	ID        int64
+	ProjectID int64
+}
+
+// IsErrProjectColumnNotExist checks if an error is a ErrProjectColumnNotExist.
+func IsErrProjectColumnNotExist(err error) bool {
+	_, ok := err.(ErrProjectColumnNotExist)
+	return ok
+}
+
+func (err ErrProjectColumnNotExist) Error() string {
+	return fmt.Sprintf("project column does not exist [id: %d, project_id: %d]", err.ID, err.ProjectID)
+}
+
+// ErrProjectIssueNotExist represents a "ProjectIssueNotExist" kind of error.
+type ErrProjectIssueNotExist struct {
+	ProjectID int64
+	IssueID   int64
+}
+
+// IsErrProjectIssueNotExist checks if an error is a ErrProjectIssueNotExist.
+func IsErrProjectIssueNotExist(err error) bool {
+	_, ok := err.(ErrProjectIssueNotExist)
+	return ok
+}
+
+func (err ErrProjectIssueNotExist) Error() string {
+	return fmt.Sprintf("issue is not on the project board [project_id: %d, issue_id: %d]", err.ProjectID, err.IssueID)
+}
+
 //    _____   __    __                .__                           __
 //   /  _  \_/  |__/  |______    ____ |  |__   _____   ____   _____/  |_
 //  /  /_\  \   __\   __\__  \ _/ ___\|  |  \ /     \_/ __ \ /    \   __\
@@ -56,6 +56,7 @@ type DiffLine struct {
 	RightIdx int
 	Type     DiffLineType
 	Content  string
+	Comments []*Comment
 }
 
 // GetType returns the type of a DiffLine.
@@ -63,6 +64,19 @@ func (d *DiffLine) GetType() int {
 	return int(d.Type)
 }
 
+// CanComment returns whether or not a line can get commented
+func (d *DiffLine) CanComment() bool {
+	return len(d.Comments) == 0 && d.Type != DiffLineSection
+}
+
+// GetCommentSide returns the comment side of the first comment, if not set returns empty string
+func (d *DiffLine) GetCommentSide() string {
+	if len(d.Comments) == 0 {
+		return ""
+	}
+	return d.Comments[0].DiffSide()
+}
+
 // DiffSection represents a section of a DiffFile.
 type DiffSection struct {
 	Name  string
@@ -229,6 +243,36 @@ func (diff *Diff) NumFiles() int {
 	return len(diff.Files)
 }
 
+// LoadComments loads the code comments of a pull request into the lines of the diff.
+// Comments on the previous version of a file are attached by their (negative) old line number,
+// comments on the proposed version by their new line number.
+func (diff *Diff) LoadComments(issue *Issue, currentUser *User) error {
+	allComments, err := FetchCodeComments(issue, currentUser)
+	if err != nil {
+		return err
+	}
+	for _, file := range diff.Files {
+		lineComments, ok := allComments[file.Name]
+		if !ok {
+			continue
+		}
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.Type == DiffLineSection {
+					continue
+				}
+				if comments, ok := lineComments[int64(line.LeftIdx*-1)]; ok && line.LeftIdx > 0 {
+					line.Comments = append(line.Comments, comments...)
+				}
+				if comments, ok := lineComments[int64(line.RightIdx)]; ok && line.RightIdx > 0 {
+					line.Comments = append(line.Comments, comments...)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 const cmdDiffHead = "diff --git "
 
 // ParsePatch builds a Diff object from a io.Reader and some
@@ -45,6 +45,10 @@ type Issue struct {
 	NumComments     int
 	Ref             string
 
+	// Name and ID of the poster on the host the issue was migrated from.
+	OriginalAuthor   string
+	OriginalAuthorID int64 `xorm:"INDEX"`
+
 	Deadline     time.Time `xorm:"-"`
 	DeadlineUnix int64     `xorm:"INDEX"`
 	Created      time.Time `xorm:"-"`
@@ -54,6 +58,7 @@ type Issue struct {
 
 	Attachments []*Attachment `xorm:"-"`
 	Comments    []*Comment    `xorm:"-"`
+	Reactions   ReactionList  `xorm:"-"`
 }
 
 // BeforeUpdate is invoked from XORM before updating this object.
@@ -79,6 +84,11 @@ func (issue *Issue) loadRepo(e Engine) (err error) {
 	return nil
 }
 
+// LoadRepo loads the repository of the issue
+func (issue *Issue) LoadRepo() error {
+	return issue.loadRepo(x)
+}
+
 // GetPullRequest returns the issue pull request
 func (issue *Issue) GetPullRequest() (pr *PullRequest, err error) {
 	if !issue.IsPull {
@@ -260,6 +270,10 @@ func (issue *Issue) State() api.StateType {
 // Required - Poster, Labels,
 // Optional - Milestone, Assignee, PullRequest
 func (issue *Issue) APIFormat() *api.Issue {
+	if err := issue.loadPoster(x); err != nil {
+		log.Error(4, "loadPoster: %v", err)
+	}
+
 	apiLabels := make([]*api.Label, len(issue.Labels))
 	for i := range issue.Labels {
 		apiLabels[i] = issue.Labels[i].APIFormat()
@@ -318,11 +332,11 @@ func (issue *Issue) HasLabel(labelID int64) bool {
 
 func (issue *Issue) sendLabelUpdatedWebhook(doer *User) {
 	var err error
+	if err = issue.loadRepo(x); err != nil {
+		log.Error(4, "loadRepo: %v", err)
+		return
+	}
 	if issue.IsPull {
-		if err = issue.loadRepo(x); err != nil {
-			log.Error(4, "loadRepo: %v", err)
-			return
-		}
 		if err = issue.loadPullRequest(x); err != nil {
 			log.Error(4, "loadPullRequest: %v", err)
 			return
@@ -338,6 +352,18 @@ func (issue *Issue) sendLabelUpdatedWebhook(doer *User) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		if issue.Labels, err = getLabelsByIssueID(x, issue.ID); err != nil {
+			log.Error(4, "getLabelsByIssueID: %v", err)
+			return
+		}
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action:     api.HookIssueLabelUpdated,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -356,6 +382,7 @@ func (issue *Issue) AddLabel(doer *User, label *Label) error {
 		return err
 	}
 
+	UpdateIssueIndexer(issue.ID)
 	issue.sendLabelUpdatedWebhook(doer)
 	return nil
 }
@@ -370,6 +397,7 @@ func (issue *Issue) AddLabels(doer *User, labels []*Label) error {
 		return err
 	}
 
+	UpdateIssueIndexer(issue.ID)
 	issue.sendLabelUpdatedWebhook(doer)
 	return nil
 }
@@ -406,6 +434,7 @@ func (issue *Issue) RemoveLabel(doer *User, label *Label) error {
 		return err
 	}
 
+	UpdateIssueIndexer(issue.ID)
 	issue.sendLabelUpdatedWebhook(doer)
 	return nil
 }
@@ -452,6 +481,7 @@ func (issue *Issue) ClearLabels(doer *User) (err error) {
 	if err = sess.Commit(); err != nil {
 		return fmt.Errorf("Commit: %v", err)
 	}
+	UpdateIssueIndexer(issue.ID)
 
 	if issue.IsPull {
 		err = issue.PullRequest.LoadIssue()
@@ -466,6 +496,15 @@ func (issue *Issue) ClearLabels(doer *User) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		issue.Labels = nil
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action:     api.HookIssueLabelCleared,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -538,7 +577,11 @@ func (issue *Issue) ReplaceLabels(labels []*Label, doer *User) (err error) {
 		}
 	}
 
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+	UpdateIssueIndexer(issue.ID)
+	return nil
 }
 
 // GetAssignee sets the Assignee attribute of this issue.
@@ -623,6 +666,13 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 		return err
 	}
 
+	// An issue can only be closed once all of its dependencies have been closed.
+	if isClosed && !issue.IsClosed {
+		if err = issue.checkDependenciesLeft(sess); err != nil {
+			return err
+		}
+	}
+
 	if err = issue.changeStatus(sess, doer, repo, isClosed); err != nil {
 		return err
 	}
@@ -646,6 +696,19 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 			apiPullRequest.Action = api.HookIssueReOpened
 		}
 		err = PrepareWebhooks(repo, HookEventPullRequest, apiPullRequest)
+	} else {
+		apiIssue := &api.IssuePayload{
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		}
+		if isClosed {
+			apiIssue.Action = api.HookIssueClosed
+		} else {
+			apiIssue.Action = api.HookIssueReOpened
+		}
+		err = PrepareWebhooks(repo, HookEventIssues, apiIssue)
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v, is_closed: %v]: %v", issue.IsPull, isClosed, err)
@@ -693,6 +756,19 @@ func (issue *Issue) ChangeTitle(doer *User, title string) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action: api.HookIssueEdited,
+			Index:  issue.Index,
+			Changes: &api.ChangesPayload{
+				Title: &api.ChangesFromPayload{
+					From: oldTitle,
+				},
+			},
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -743,6 +819,19 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action: api.HookIssueEdited,
+			Index:  issue.Index,
+			Changes: &api.ChangesPayload{
+				Body: &api.ChangesFromPayload{
+					From: oldContent,
+				},
+			},
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -760,6 +849,7 @@ func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
 	if err = UpdateIssueUserByAssignee(issue); err != nil {
 		return fmt.Errorf("UpdateIssueUserByAssignee: %v", err)
 	}
+	UpdateIssueIndexer(issue.ID)
 
 	sess := x.NewSession()
 	defer sess.Close()
@@ -797,6 +887,22 @@ func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
 			log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, isRemoveAssignee, err)
 			return nil
 		}
+	} else {
+		apiIssue := &api.IssuePayload{
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		}
+		if isRemoveAssignee {
+			apiIssue.Action = api.HookIssueUnassigned
+		} else {
+			apiIssue.Action = api.HookIssueAssigned
+		}
+		if err := PrepareWebhooks(issue.Repo, HookEventIssues, apiIssue); err != nil {
+			log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, isRemoveAssignee, err)
+			return nil
+		}
 	}
 	go HookQueue.Add(issue.RepoID)
 	return nil
@@ -811,10 +917,27 @@ type NewIssueOptions struct {
 	IsPull      bool
 }
 
+// getMaxIssueIndex returns the highest index of the issues and pull requests in a repository.
+func getMaxIssueIndex(e Engine, repoID int64) (int64, error) {
+	var maxIndex int64
+	if _, err := e.Table("issue").Select("COALESCE(MAX(`index`), 0)").Where("repo_id = ?", repoID).Get(&maxIndex); err != nil {
+		return 0, err
+	}
+	return maxIndex, nil
+}
+
 func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
 	opts.Issue.Index = opts.Repo.NextIssueIndex()
 
+	// Migrated repositories may have gaps in their issue indexes.
+	maxIndex, err := getMaxIssueIndex(e, opts.Issue.RepoID)
+	if err != nil {
+		return fmt.Errorf("getMaxIssueIndex: %v", err)
+	} else if maxIndex >= opts.Issue.Index {
+		opts.Issue.Index = maxIndex + 1
+	}
+
 	if opts.Issue.MilestoneID > 0 {
 		milestone, err := getMilestoneByRepoID(e, opts.Issue.RepoID, opts.Issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
@@ -952,6 +1075,18 @@ func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string)
 		log.Error(4, "MailParticipants: %v", err)
 	}
 
+	if err = PrepareWebhooks(repo, HookEventIssues, &api.IssuePayload{
+		Action:     api.HookIssueOpened,
+		Index:      issue.Index,
+		Issue:      issue.APIFormat(),
+		Repository: repo.APIFormat(AccessModeNone),
+		Sender:     issue.Poster.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks: %v", err)
+	} else {
+		go HookQueue.Add(repo.ID)
+	}
+
 	return nil
 }
 
@@ -60,6 +60,14 @@ const (
 	CommentTypeAddTimeManual
 	// Cancel a stopwatch for time tracking
 	CommentTypeCancelTracking
+	// Submitted a review of a pull request
+	CommentTypeReview
+	// Comment on a line of the pull request diff
+	CommentTypeCode
+	// Dependency added
+	CommentTypeAddDependency
+	// Dependency removed
+	CommentTypeRemoveDependency
 )
 
 // CommentTag defines comment tag type
@@ -93,8 +101,12 @@ type Comment struct {
 	OldTitle       string
 	NewTitle       string
 
+	DependentIssueID int64
+	DependentIssue   *Issue `xorm:"-"`
+
 	CommitID        int64
-	Line            int64
+	Line            int64 // - previous line / + proposed line
+	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
 
@@ -107,6 +119,14 @@ type Comment struct {
 	CommitSHA string `xorm:"VARCHAR(40)"`
 
 	Attachments []*Attachment `xorm:"-"`
+	Reactions   ReactionList  `xorm:"-"`
+
+	ReviewID int64   `xorm:"INDEX"`
+	Review   *Review `xorm:"-"`
+
+	// Name and ID of the poster on the host the comment was migrated from.
+	OriginalAuthor   string
+	OriginalAuthorID int64 `xorm:"INDEX"`
 
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
@@ -245,6 +265,27 @@ func (c *Comment) LoadMilestone() error {
 	return nil
 }
 
+// LoadDependentIssue if comment.Type is CommentTypeAddDependency or CommentTypeRemoveDependency, then load the dependent issue
+func (c *Comment) LoadDependentIssue() error {
+	if c.DependentIssueID == 0 || c.DependentIssue != nil {
+		return nil
+	}
+
+	issue, err := getIssueByID(x, c.DependentIssueID)
+	if err != nil {
+		if IsErrIssueNotExist(err) {
+			// The issue may have been deleted together with its repository.
+			return nil
+		}
+		return err
+	}
+	if err = issue.loadRepo(x); err != nil {
+		return err
+	}
+	c.DependentIssue = issue
+	return nil
+}
+
 // LoadAssignees if comment.Type is CommentTypeAssignees, then load assignees
 func (c *Comment) LoadAssignees() error {
 	var err error
@@ -264,6 +305,56 @@ func (c *Comment) LoadAssignees() error {
 	return nil
 }
 
+// LoadReview if comment.Type is CommentTypeReview or CommentTypeCode, then load review
+func (c *Comment) LoadReview() (err error) {
+	if c.Review != nil || c.ReviewID == 0 {
+		return nil
+	}
+	c.Review, err = getReviewByID(x, c.ReviewID)
+	if err != nil {
+		return err
+	}
+	return c.Review.loadAttributes(x)
+}
+
+// UnsignedLine returns the line number of a code comment without the side marker
+func (c *Comment) UnsignedLine() uint64 {
+	if c.Line < 0 {
+		return uint64(c.Line * -1)
+	}
+	return uint64(c.Line)
+}
+
+// DiffSide returns "previous" if the code comment refers to the old version of the file, otherwise "proposed"
+func (c *Comment) DiffSide() string {
+	if c.Line < 0 {
+		return "previous"
+	}
+	return "proposed"
+}
+
+// APIFormatCodeComment converts a code Comment to the api.PullReviewComment format
+func (c *Comment) APIFormatCodeComment() *api.PullReviewComment {
+	apiComment := &api.PullReviewComment{
+		ID:          c.ID,
+		Body:        c.Content,
+		Reviewer:    c.Poster.APIFormat(),
+		ReviewID:    c.ReviewID,
+		Created:     c.Created,
+		Updated:     c.Updated,
+		Path:        c.TreePath,
+		CommitID:    c.CommitSHA,
+		HTMLURL:     c.HTMLURL(),
+		HTMLPullURL: c.PRURL(),
+	}
+	if c.Line < 0 {
+		apiComment.OldLineNum = c.UnsignedLine()
+	} else {
+		apiComment.LineNum = c.UnsignedLine()
+	}
+	return apiComment
+}
+
 // MailParticipants sends new comment emails to repository watchers
 // and mentioned people.
 func (c *Comment) MailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
@@ -305,9 +396,13 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		CommitID:       opts.CommitID,
 		CommitSHA:      opts.CommitSHA,
 		Line:           opts.LineNum,
+		TreePath:       opts.TreePath,
+		ReviewID:       opts.ReviewID,
 		Content:        opts.Content,
 		OldTitle:       opts.OldTitle,
 		NewTitle:       opts.NewTitle,
+
+		DependentIssueID: opts.DependentIssueID,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -449,6 +544,25 @@ func createMilestoneComment(e *xorm.Session, doer *User, repo *Repository, issue
 	})
 }
 
+func createIssueDependencyComment(e *xorm.Session, doer *User, issue, dependency *Issue, add bool) error {
+	commentType := CommentTypeAddDependency
+	if !add {
+		commentType = CommentTypeRemoveDependency
+	}
+
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	_, err := createComment(e, &CreateCommentOptions{
+		Type:             commentType,
+		Doer:             doer,
+		Repo:             issue.Repo,
+		Issue:            issue,
+		DependentIssueID: dependency.ID,
+	})
+	return err
+}
+
 func createAssigneeComment(e *xorm.Session, doer *User, repo *Repository, issue *Issue, oldAssigneeID, assigneeID int64) (*Comment, error) {
 	return createComment(e, &CreateCommentOptions{
 		Type:          CommentTypeAssignees,
@@ -498,8 +612,12 @@ type CreateCommentOptions struct {
 	CommitID       int64
 	CommitSHA      string
 	LineNum        int64
+	TreePath       string
+	ReviewID       int64
 	Content        string
 	Attachments    []string // UUIDs of attachments
+
+	DependentIssueID int64
 }
 
 // CreateComment creates comment of issue or commit.
@@ -525,9 +643,31 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 	return comment, nil
 }
 
+// sendIssueCommentWebhook adds the issue comment event of the given action on c to the hook queue.
+func sendIssueCommentWebhook(doer *User, c *Comment, action api.HookIssueCommentAction, changes *api.ChangesPayload) {
+	issue, err := GetIssueByID(c.IssueID)
+	if err != nil {
+		log.Error(4, "GetIssueByID [%d]: %v", c.IssueID, err)
+		return
+	}
+
+	if err = PrepareWebhooks(issue.Repo, HookEventIssueComment, &api.IssueCommentPayload{
+		Action:     action,
+		Issue:      issue.APIFormat(),
+		Comment:    c.APIFormat(),
+		Changes:    changes,
+		Repository: issue.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [comment_id: %d]: %v", c.ID, err)
+	} else {
+		go HookQueue.Add(issue.RepoID)
+	}
+}
+
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content string, attachments []string) (*Comment, error) {
-	return CreateComment(&CreateCommentOptions{
+	comment, err := CreateComment(&CreateCommentOptions{
 		Type:        CommentTypeComment,
 		Doer:        doer,
 		Repo:        repo,
@@ -535,6 +675,12 @@ func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content stri
 		Content:     content,
 		Attachments: attachments,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	sendIssueCommentWebhook(doer, comment, api.HookIssueCommentCreated, nil)
+	return comment, nil
 }
 
 // CreateRefComment creates a commit reference comment to issue.
@@ -580,10 +726,11 @@ func GetCommentByID(id int64) (*Comment, error) {
 
 // FindCommentsOptions describes the conditions to Find comments
 type FindCommentsOptions struct {
-	RepoID  int64
-	IssueID int64
-	Since   int64
-	Type    CommentType
+	RepoID   int64
+	IssueID  int64
+	ReviewID int64
+	Since    int64
+	Type     CommentType
 }
 
 func (opts *FindCommentsOptions) toConds() builder.Cond {
@@ -594,6 +741,9 @@ func (opts *FindCommentsOptions) toConds() builder.Cond {
 	if opts.IssueID > 0 {
 		cond = cond.And(builder.Eq{"comment.issue_id": opts.IssueID})
 	}
+	if opts.ReviewID > 0 {
+		cond = cond.And(builder.Eq{"comment.review_id": opts.ReviewID})
+	}
 	if opts.Since > 0 {
 		cond = cond.And(builder.Gte{"comment.updated_unix": opts.Since})
 	}
@@ -646,18 +796,23 @@ func GetCommentsByRepoIDSince(repoID, since int64) ([]*Comment, error) {
 	})
 }
 
-// UpdateComment updates information of comment.
-func UpdateComment(c *Comment) error {
+// UpdateComment updates information of comment, as the given user.
+func UpdateComment(doer *User, c *Comment, oldContent string) error {
 	if _, err := x.ID(c.ID).AllCols().Update(c); err != nil {
 		return err
 	} else if c.Type == CommentTypeComment {
 		UpdateIssueIndexer(c.IssueID)
+		sendIssueCommentWebhook(doer, c, api.HookIssueCommentEdited, &api.ChangesPayload{
+			Body: &api.ChangesFromPayload{
+				From: oldContent,
+			},
+		})
 	}
 	return nil
 }
 
-// DeleteComment deletes the comment
-func DeleteComment(comment *Comment) error {
+// DeleteComment deletes the comment, as the given user.
+func DeleteComment(doer *User, comment *Comment) error {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -678,11 +833,15 @@ func DeleteComment(comment *Comment) error {
 	if _, err := sess.Where("comment_id = ?", comment.ID).Cols("is_deleted").Update(&Action{IsDeleted: true}); err != nil {
 		return err
 	}
+	if _, err := sess.Where("comment_id = ?", comment.ID).Delete(new(Reaction)); err != nil {
+		return err
+	}
 
 	if err := sess.Commit(); err != nil {
 		return err
 	} else if comment.Type == CommentTypeComment {
 		UpdateIssueIndexer(comment.IssueID)
+		sendIssueCommentWebhook(doer, comment, api.HookIssueCommentDeleted, nil)
 	}
 	return nil
 }
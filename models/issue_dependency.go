@@ -0,0 +1,186 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+)
+
+// IssueDependency represents an issue which can only be closed once
+// the issue it depends on has been closed.
+type IssueDependency struct {
+	ID           int64     `xorm:"pk autoincr"`
+	UserID       int64     `xorm:"NOT NULL"`
+	IssueID      int64     `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+	DependencyID int64     `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+	Created      time.Time `xorm:"-"`
+	CreatedUnix  int64     `xorm:"INDEX created"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (d *IssueDependency) AfterLoad() {
+	d.Created = time.Unix(d.CreatedUnix, 0).Local()
+}
+
+// DependencyType describes the direction of an issue dependency
+type DependencyType int
+
+// Define dependency types
+const (
+	// DependencyTypeBlockedBy means the issue depends on the other issue
+	DependencyTypeBlockedBy DependencyType = iota
+	// DependencyTypeBlocking means the other issue depends on the issue
+	DependencyTypeBlocking
+)
+
+func issueDependencyExists(e Engine, issueID, depID int64) (bool, error) {
+	return e.Where("issue_id = ? AND dependency_id = ?", issueID, depID).Exist(new(IssueDependency))
+}
+
+// dependsOn returns true if the issue depends on the other issue, directly or through other issues.
+func dependsOn(e Engine, issueID, otherID int64) (bool, error) {
+	visited := map[int64]bool{issueID: true}
+	queue := []int64{issueID}
+	for len(queue) > 0 {
+		deps := make([]*IssueDependency, 0, 10)
+		if err := e.In("issue_id", queue).Find(&deps); err != nil {
+			return false, err
+		}
+
+		queue = queue[:0]
+		for _, dep := range deps {
+			if dep.DependencyID == otherID {
+				return true, nil
+			}
+			if !visited[dep.DependencyID] {
+				visited[dep.DependencyID] = true
+				queue = append(queue, dep.DependencyID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// CreateIssueDependency makes issue depend on dep, so issue can only be closed once dep has been closed.
+func CreateIssueDependency(doer *User, issue, dep *Issue) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	exists, err := issueDependencyExists(sess, issue.ID, dep.ID)
+	if err != nil {
+		return err
+	} else if exists {
+		return ErrDependencyExists{issue.ID, dep.ID}
+	}
+
+	// Two issues can't block each other, neither directly nor through other issues.
+	if issue.ID == dep.ID {
+		return ErrCircularDependency{issue.ID, dep.ID}
+	}
+	circular, err := dependsOn(sess, dep.ID, issue.ID)
+	if err != nil {
+		return err
+	} else if circular {
+		return ErrCircularDependency{issue.ID, dep.ID}
+	}
+
+	if _, err = sess.Insert(&IssueDependency{
+		UserID:       doer.ID,
+		IssueID:      issue.ID,
+		DependencyID: dep.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err = createIssueDependencyComment(sess, doer, issue, dep, true); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// RemoveIssueDependency removes the dependency of issue on dep.
+func RemoveIssueDependency(doer *User, issue, dep *Issue) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	affected, err := sess.Delete(&IssueDependency{IssueID: issue.ID, DependencyID: dep.ID})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrDependencyNotExists{issue.ID, dep.ID}
+	}
+
+	if err = createIssueDependencyComment(sess, doer, issue, dep, false); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func issueNoDependenciesLeft(e Engine, issue *Issue) (bool, error) {
+	exists, err := e.
+		Table("issue_dependency").
+		Join("INNER", "issue", "issue.id = issue_dependency.dependency_id").
+		Where("issue_dependency.issue_id = ?", issue.ID).
+		And("issue.is_closed = ?", false).
+		Exist(new(IssueDependency))
+	return !exists, err
+}
+
+// IssueNoDependenciesLeft returns true if all issues the issue depends on have been closed.
+func IssueNoDependenciesLeft(issue *Issue) (bool, error) {
+	return issueNoDependenciesLeft(x, issue)
+}
+
+func (issue *Issue) checkDependenciesLeft(e Engine) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	if !issue.Repo.isDependenciesEnabled(e) {
+		return nil
+	}
+
+	noDeps, err := issueNoDependenciesLeft(e, issue)
+	if err != nil {
+		return err
+	} else if !noDeps {
+		return ErrDependenciesLeft{issue.ID}
+	}
+	return nil
+}
+
+func getDependencyIssues(e Engine, joinCond, whereCond string, issueID int64) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 5)
+	if err := e.
+		Table("issue").
+		Select("issue.*").
+		Join("INNER", "issue_dependency", joinCond).
+		Where(whereCond, issueID).
+		Asc("issue.repo_id", "issue.index").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		if err := issue.loadRepo(e); err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
+
+// BlockedByDependencies returns the issues the issue depends on
+func (issue *Issue) BlockedByDependencies() ([]*Issue, error) {
+	return getDependencyIssues(x, "issue.id = issue_dependency.dependency_id", "issue_dependency.issue_id = ?", issue.ID)
+}
+
+// BlockingDependencies returns the issues which depend on the issue
+func (issue *Issue) BlockingDependencies() ([]*Issue, error) {
+	return getDependencyIssues(x, "issue.id = issue_dependency.issue_id", "issue_dependency.dependency_id = ?", issue.ID)
+}
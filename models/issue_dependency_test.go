@@ -0,0 +1,106 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(user1, issue1, issue2))
+	AssertExistsAndLoadBean(t, &IssueDependency{UserID: user1.ID, IssueID: issue1.ID, DependencyID: issue2.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAddDependency, IssueID: issue1.ID, DependentIssueID: issue2.ID})
+
+	err := CreateIssueDependency(user1, issue1, issue2)
+	assert.True(t, IsErrDependencyExists(err))
+
+	err = CreateIssueDependency(user1, issue1, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+
+	err = CreateIssueDependency(user1, issue2, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+
+	// Cycles through other issues are refused as well.
+	assert.NoError(t, CreateIssueDependency(user1, issue2, issue3))
+	err = CreateIssueDependency(user1, issue3, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+
+	blockedBy, err := issue1.BlockedByDependencies()
+	assert.NoError(t, err)
+	if assert.Len(t, blockedBy, 1) {
+		assert.EqualValues(t, issue2.ID, blockedBy[0].ID)
+		assert.NotNil(t, blockedBy[0].Repo)
+	}
+
+	blocking, err := issue2.BlockingDependencies()
+	assert.NoError(t, err)
+	if assert.Len(t, blocking, 1) {
+		assert.EqualValues(t, issue1.ID, blocking[0].ID)
+	}
+}
+
+func TestRemoveIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	err := RemoveIssueDependency(user1, issue1, issue2)
+	assert.True(t, IsErrDependencyNotExists(err))
+
+	assert.NoError(t, CreateIssueDependency(user1, issue1, issue2))
+	assert.NoError(t, RemoveIssueDependency(user1, issue1, issue2))
+	AssertNotExistsBean(t, &IssueDependency{IssueID: issue1.ID, DependencyID: issue2.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeRemoveDependency, IssueID: issue1.ID, DependentIssueID: issue2.ID})
+}
+
+func TestIssueNoDependenciesLeft(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	closedIssue := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	// Closed dependencies don't block.
+	assert.NoError(t, CreateIssueDependency(user1, issue1, closedIssue))
+	noDeps, err := IssueNoDependenciesLeft(issue1)
+	assert.NoError(t, err)
+	assert.True(t, noDeps)
+
+	assert.NoError(t, CreateIssueDependency(user1, issue1, issue2))
+	noDeps, err = IssueNoDependenciesLeft(issue1)
+	assert.NoError(t, err)
+	assert.False(t, noDeps)
+}
+
+func TestChangeStatusWithDependencies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, issue1.LoadRepo())
+
+	assert.NoError(t, CreateIssueDependency(user1, issue1, issue2))
+	err := issue1.ChangeStatus(user1, issue1.Repo, true)
+	assert.True(t, IsErrDependenciesLeft(err))
+	assert.False(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+
+	// Issues can be closed once their dependencies are gone.
+	assert.NoError(t, RemoveIssueDependency(user1, issue1, issue2))
+	assert.NoError(t, issue1.ChangeStatus(user1, issue1.Repo, true))
+	assert.True(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+}
@@ -6,6 +6,8 @@ package models
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"code.gitea.io/gitea/modules/indexer"
 	"code.gitea.io/gitea/modules/log"
@@ -16,11 +18,26 @@ import (
 // issueIndexerUpdateQueue queue of issue ids to be updated
 var issueIndexerUpdateQueue chan int64
 
+// issueIndexerLock is held while an issue of the update queue is read from
+// the database, so that test fixtures are not reloaded at the same time
+var issueIndexerLock sync.Mutex
+
 // InitIssueIndexer initialize issue indexer
 func InitIssueIndexer() {
+	initIssueIndexer()
+	go processIssueIndexerUpdateQueue()
+}
+
+// initIssueIndexer initializes the issue indexer and its update queue, without
+// starting to process the queue
+func initIssueIndexer() {
 	indexer.InitIssueIndexer(populateIssueIndexer)
 	issueIndexerUpdateQueue = make(chan int64, setting.Indexer.UpdateQueueLength)
-	go processIssueIndexerUpdateQueue()
+}
+
+// RebuildIssueIndexer drops the issue indexer and indexes all issues again
+func RebuildIssueIndexer() error {
+	return indexer.RebuildIssueIndexer(populateIssueIndexer)
 }
 
 // populateIssueIndexer populate the issue indexer with issue data
@@ -49,7 +66,7 @@ func populateIssueIndexer() error {
 			if err != nil {
 				return err
 			}
-			if err = IssueList(issues).LoadComments(); err != nil {
+			if err = IssueList(issues).loadIndexedAttributes(x); err != nil {
 				return err
 			}
 			for _, issue := range issues {
@@ -75,15 +92,35 @@ func processIssueIndexerUpdateQueue() {
 			}
 			issueID = <-issueIndexerUpdateQueue
 		}
-		issue, err := GetIssueByID(issueID)
+		issueIndexerLock.Lock()
+		err := addIssueIndexerUpdate(batch, issueID)
+		issueIndexerLock.Unlock()
 		if err != nil {
-			log.Error(4, "GetIssueByID: %v", err)
-		} else if err = batch.Add(issue.update()); err != nil {
 			log.Error(4, "IssueIndexer: %v", err)
 		}
 	}
 }
 
+// addIssueIndexerUpdate adds the current data of an issue to the batch
+func addIssueIndexerUpdate(batch *indexer.Batch, issueID int64) error {
+	issue, err := GetIssueByID(issueID)
+	if err != nil {
+		return fmt.Errorf("GetIssueByID: %v", err)
+	} else if err = IssueList([]*Issue{issue}).loadIndexedAttributes(x); err != nil {
+		return fmt.Errorf("loadIndexedAttributes: %v", err)
+	}
+	return batch.Add(issue.update())
+}
+
+// loadIndexedAttributes loads the attributes of the issues which are stored
+// in the issue indexer
+func (issues IssueList) loadIndexedAttributes(e Engine) error {
+	if err := issues.loadComments(e); err != nil {
+		return err
+	}
+	return issues.loadLabels(e)
+}
+
 func (issue *Issue) update() indexer.IssueIndexerUpdate {
 	comments := make([]string, 0, 5)
 	for _, comment := range issue.Comments {
@@ -91,19 +128,115 @@ func (issue *Issue) update() indexer.IssueIndexerUpdate {
 			comments = append(comments, comment.Content)
 		}
 	}
+	labelIDs := make([]int64, len(issue.Labels))
+	for i, label := range issue.Labels {
+		labelIDs[i] = label.ID
+	}
 	return indexer.IssueIndexerUpdate{
 		IssueID: issue.ID,
 		Data: &indexer.IssueIndexerData{
-			RepoID:   issue.RepoID,
-			Title:    issue.Title,
-			Content:  issue.Content,
-			Comments: comments,
+			RepoID:      issue.RepoID,
+			Title:       issue.Title,
+			Content:     issue.Content,
+			Comments:    comments,
+			LabelIDs:    labelIDs,
+			MilestoneID: issue.MilestoneID,
+			PosterID:    issue.PosterID,
+			AssigneeID:  issue.AssigneeID,
+			IsClosed:    issue.IsClosed,
+			IsPull:      issue.IsPull,
+			UpdatedUnix: issue.UpdatedUnix,
 		},
 	}
 }
 
+// SearchIssuesOptions represents the options of a search for issues across
+// repositories
+type SearchIssuesOptions struct {
+	Keyword  string
+	Doer     *User // Only issues of repositories the doer can read are returned
+	IsClosed util.OptionalBool
+	IsPull   util.OptionalBool
+	Labels   []string // Names of labels the issues must have
+	PosterID int64
+	Page     int
+	PageSize int
+}
+
+// SearchIssues searches the issue indexer for issues across all repositories
+// readable by the doer. Returns the matching issues of the requested page and
+// the total number of matching issues.
+func SearchIssues(opts *SearchIssuesOptions) (IssueList, int64, error) {
+	repoIDs, err := getReadableRepoIDs(x, opts.Doer, 0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getReadableRepoIDs: %v", err)
+	} else if len(repoIDs) == 0 {
+		return IssueList{}, 0, nil
+	}
+
+	labelIDs := make([][]int64, 0, len(opts.Labels))
+	for _, name := range opts.Labels {
+		ids := make([]int64, 0, 10)
+		if err = x.Table("label").Cols("id").
+			Where("LOWER(name) = ?", strings.ToLower(name)).
+			In("repo_id", repoIDs).
+			Find(&ids); err != nil {
+			return nil, 0, fmt.Errorf("find labels: %v", err)
+		} else if len(ids) == 0 {
+			return IssueList{}, 0, nil
+		}
+		labelIDs = append(labelIDs, ids)
+	}
+
+	total, issueIDs, err := indexer.SearchIssues(&indexer.IssueSearchOptions{
+		Keyword:  opts.Keyword,
+		RepoIDs:  repoIDs,
+		IsClosed: opts.IsClosed,
+		IsPull:   opts.IsPull,
+		LabelIDs: labelIDs,
+		PosterID: opts.PosterID,
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("SearchIssues: %v", err)
+	} else if len(issueIDs) == 0 {
+		return IssueList{}, total, nil
+	}
+
+	issuesMap := make(map[int64]*Issue, len(issueIDs))
+	if err = x.In("id", issueIDs).Find(&issuesMap); err != nil {
+		return nil, 0, fmt.Errorf("find issues: %v", err)
+	}
+
+	// keep the order of the search results
+	issues := make(IssueList, 0, len(issueIDs))
+	for _, id := range issueIDs {
+		if issue, ok := issuesMap[id]; ok {
+			issues = append(issues, issue)
+		}
+	}
+	if err = issues.loadAttributes(x); err != nil {
+		return nil, 0, fmt.Errorf("loadAttributes: %v", err)
+	}
+
+	// issues of other repositories are listed, so their owners are needed
+	reposMap := make(map[int64]*Repository, len(issues))
+	for _, issue := range issues {
+		reposMap[issue.RepoID] = issue.Repo
+	}
+	if err = RepositoryList(valuesRepository(reposMap)).loadAttributes(x); err != nil {
+		return nil, 0, fmt.Errorf("load repositories: %v", err)
+	}
+	return issues, total, nil
+}
+
 // UpdateIssueIndexer add/update an issue to the issue indexer
 func UpdateIssueIndexer(issueID int64) {
+	if issueIndexerUpdateQueue == nil {
+		// the issue indexer is not initialized, e.g. in unit tests
+		return
+	}
 	select {
 	case issueIndexerUpdateQueue <- issueID:
 	default:
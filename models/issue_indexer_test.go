@@ -0,0 +1,117 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"code.gitea.io/gitea/modules/indexer"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchIssues(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	tmpDir, err := ioutil.TempDir("", "issues.bleve")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	setting.Indexer.IssuePath = filepath.Join(tmpDir, "issues.bleve")
+	setting.Indexer.UpdateQueueLength = 20
+	// the update queue is processed by processIssueIndexerUpdates instead of
+	// in the background, which would access the database concurrently
+	initIssueIndexer()
+	defer func() {
+		issueIndexerUpdateQueue = nil
+	}()
+
+	issueIDs := func(issues IssueList) []int64 {
+		ids := make([]int64, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+
+	// anonymous users only find issues of public repositories
+	issues, count, err := SearchIssues(&SearchIssuesOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	assert.EqualValues(t, []int64{1, 2, 3, 5}, issueIDs(issues))
+	for _, issue := range issues {
+		assert.NotNil(t, issue.Repo.Owner)
+	}
+
+	// the owner of a private repository also finds its issues
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issues, _, err = SearchIssues(&SearchIssuesOptions{
+		Doer:     user2,
+		PosterID: 2,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{4, 5}, issueIDs(issues))
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{
+		Doer:     user2,
+		IsClosed: util.OptionalBoolFalse,
+		IsPull:   util.OptionalBoolFalse,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{1, 6}, issueIDs(issues))
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Keyword: "first"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{1}, issueIDs(issues))
+
+	// labels are matched by name, case insensitively
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Labels: []string{"Label1"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{1, 2}, issueIDs(issues))
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Labels: []string{"label1", "label2"}})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Labels: []string{"unknown"}})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+
+	// pagination
+	issues, count, err = SearchIssues(&SearchIssuesOptions{Page: 2, PageSize: 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	assert.Len(t, issues, 1)
+
+	// changed labels are indexed
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	label := AssertExistsAndLoadBean(t, &Label{ID: 2}).(*Label)
+	assert.NoError(t, issue.ReplaceLabels([]*Label{label}, user2))
+	assert.NotEmpty(t, issueIndexerUpdateQueue)
+	processIssueIndexerUpdates(t)
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Labels: []string{"label1"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{1}, issueIDs(issues))
+
+	issues, _, err = SearchIssues(&SearchIssuesOptions{Doer: user2, Labels: []string{"label2"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{2, 5}, issueIDs(issues))
+}
+
+// processIssueIndexerUpdates indexes the issues of the update queue
+func processIssueIndexerUpdates(t *testing.T) {
+	batch := indexer.IssueIndexerBatch()
+	for len(issueIndexerUpdateQueue) > 0 {
+		assert.NoError(t, addIssueIndexerUpdate(batch, <-issueIndexerUpdateQueue))
+	}
+	assert.NoError(t, batch.Flush())
+}
@@ -305,7 +305,11 @@ func ChangeMilestoneAssign(issue *Issue, doer *User, oldMilestoneID int64) (err
 	if err = changeMilestoneAssign(sess, doer, issue, oldMilestoneID); err != nil {
 		return err
 	}
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+	UpdateIssueIndexer(issue.ID)
+	return nil
 }
 
 // DeleteMilestoneByRepoID deletes a milestone from a repository.
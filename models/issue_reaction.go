@@ -0,0 +1,267 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-xorm/builder"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// AllowedReactions contains the reactions users can add to issues and comments,
+// named after the emoji they are shown with.
+var AllowedReactions = []string{"+1", "-1", "laughing", "confused", "heart", "tada"}
+
+// IsAllowedReaction returns true if the reaction can be added to issues and comments.
+func IsAllowedReaction(content string) bool {
+	for _, reaction := range AllowedReactions {
+		if reaction == content {
+			return true
+		}
+	}
+	return false
+}
+
+// Reaction represents a reaction of a user to an issue or a comment.
+type Reaction struct {
+	ID          int64     `xorm:"pk autoincr"`
+	Type        string    `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	IssueID     int64     `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	CommentID   int64     `xorm:"INDEX UNIQUE(s)"`
+	UserID      int64     `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	User        *User     `xorm:"-"`
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (r *Reaction) AfterLoad() {
+	r.Created = time.Unix(r.CreatedUnix, 0).Local()
+}
+
+// APIFormat converts a Reaction to the api.Reaction format
+func (r *Reaction) APIFormat() *api.Reaction {
+	return &api.Reaction{
+		User:    r.User.APIFormat(),
+		Content: r.Type,
+		Created: r.Created,
+	}
+}
+
+// FindReactionsOptions describes the conditions to find reactions
+type FindReactionsOptions struct {
+	IssueID int64
+	// CommentID is zero for the reactions to the issue itself,
+	// use -1 for the reactions to the issue and all of its comments.
+	CommentID int64
+	UserID    int64
+}
+
+func (opts *FindReactionsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"reaction.issue_id": opts.IssueID})
+	}
+	if opts.CommentID >= 0 {
+		cond = cond.And(builder.Eq{"reaction.comment_id": opts.CommentID})
+	}
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"reaction.user_id": opts.UserID})
+	}
+	return cond
+}
+
+func findReactions(e Engine, opts FindReactionsOptions) (ReactionList, error) {
+	reactions := make(ReactionList, 0, 10)
+	if err := e.Where(opts.toConds()).Asc("reaction.created_unix", "reaction.id").Find(&reactions); err != nil {
+		return nil, err
+	}
+	return reactions, reactions.loadUsers(e)
+}
+
+// FindIssueReactions returns the reactions to an issue
+func FindIssueReactions(issue *Issue) (ReactionList, error) {
+	return findReactions(x, FindReactionsOptions{IssueID: issue.ID})
+}
+
+// FindCommentReactions returns the reactions to a comment
+func FindCommentReactions(comment *Comment) (ReactionList, error) {
+	return findReactions(x, FindReactionsOptions{IssueID: comment.IssueID, CommentID: comment.ID})
+}
+
+func createReaction(e Engine, doer *User, issueID, commentID int64, content string) (*Reaction, error) {
+	if !IsAllowedReaction(content) {
+		return nil, ErrForbiddenReaction{Reaction: content}
+	}
+
+	reaction := &Reaction{
+		Type:      content,
+		IssueID:   issueID,
+		CommentID: commentID,
+		UserID:    doer.ID,
+	}
+	// Reacting twice the same way is no error, the existing reaction is returned.
+	// The comment ID is always part of the condition as 0 means the issue.
+	has, err := e.Where("comment_id = ?", commentID).Get(reaction)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		if _, err = e.Insert(reaction); err != nil {
+			return nil, err
+		}
+	}
+	reaction.User = doer
+	return reaction, nil
+}
+
+// CreateIssueReaction adds a reaction of the doer to an issue
+func CreateIssueReaction(doer *User, issue *Issue, content string) (*Reaction, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	reaction, err := createReaction(sess, doer, issue.ID, 0, content)
+	if err != nil {
+		return nil, err
+	}
+	return reaction, sess.Commit()
+}
+
+// CreateCommentReaction adds a reaction of the doer to a comment
+func CreateCommentReaction(doer *User, comment *Comment, content string) (*Reaction, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	reaction, err := createReaction(sess, doer, comment.IssueID, comment.ID, content)
+	if err != nil {
+		return nil, err
+	}
+	return reaction, sess.Commit()
+}
+
+func deleteReaction(e Engine, doer *User, issueID, commentID int64, content string) error {
+	_, err := e.Where("comment_id = ?", commentID).Delete(&Reaction{
+		Type:      content,
+		IssueID:   issueID,
+		CommentID: commentID,
+		UserID:    doer.ID,
+	})
+	return err
+}
+
+// DeleteIssueReaction removes a reaction of the doer from an issue
+func DeleteIssueReaction(doer *User, issue *Issue, content string) error {
+	return deleteReaction(x, doer, issue.ID, 0, content)
+}
+
+// DeleteCommentReaction removes a reaction of the doer from a comment
+func DeleteCommentReaction(doer *User, comment *Comment, content string) error {
+	return deleteReaction(x, doer, comment.IssueID, comment.ID, content)
+}
+
+// ReactionList represents a list of reactions
+type ReactionList []*Reaction
+
+// HasUser returns true if the user is among the reactions
+func (list ReactionList) HasUser(userID int64) bool {
+	if userID == 0 {
+		return false
+	}
+	for _, reaction := range list {
+		if reaction.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupByType returns the reactions grouped by their type
+func (list ReactionList) GroupByType() map[string]ReactionList {
+	reactions := make(map[string]ReactionList)
+	for _, reaction := range list {
+		reactions[reaction.Type] = append(reactions[reaction.Type], reaction)
+	}
+	return reactions
+}
+
+func (list ReactionList) loadUsers(e Engine) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(list))
+	userMaps := make(map[int64]*User, len(list))
+	for _, reaction := range list {
+		if _, ok := userMaps[reaction.UserID]; !ok {
+			userIDs = append(userIDs, reaction.UserID)
+			userMaps[reaction.UserID] = nil
+		}
+	}
+
+	users := make([]*User, 0, len(userIDs))
+	if err := e.In("id", userIDs).Find(&users); err != nil {
+		return fmt.Errorf("find users: %v", err)
+	}
+	for _, user := range users {
+		userMaps[user.ID] = user
+	}
+
+	for _, reaction := range list {
+		if reaction.User = userMaps[reaction.UserID]; reaction.User == nil {
+			reaction.User = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// UserNames returns the comma separated names of the users who reacted,
+// the names of at most limit users are included.
+func (list ReactionList) UserNames(limit int) string {
+	names := make([]string, 0, limit)
+	for _, reaction := range list {
+		if len(names) == limit {
+			break
+		}
+		if reaction.User != nil {
+			names = append(names, reaction.User.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (issue *Issue) loadReactions(e Engine) error {
+	reactions, err := findReactions(e, FindReactionsOptions{IssueID: issue.ID, CommentID: -1})
+	if err != nil {
+		return err
+	}
+
+	commentReactions := make(map[int64]ReactionList)
+	issue.Reactions = make(ReactionList, 0, len(reactions))
+	for _, reaction := range reactions {
+		if reaction.CommentID == 0 {
+			issue.Reactions = append(issue.Reactions, reaction)
+		} else {
+			commentReactions[reaction.CommentID] = append(commentReactions[reaction.CommentID], reaction)
+		}
+	}
+	for _, comment := range issue.Comments {
+		comment.Reactions = commentReactions[comment.ID]
+	}
+	return nil
+}
+
+// LoadReactions loads the reactions to the issue and to its loaded comments
+func (issue *Issue) LoadReactions() error {
+	return issue.loadReactions(x)
+}
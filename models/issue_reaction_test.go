@@ -0,0 +1,121 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIssueReaction(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	reaction, err := CreateIssueReaction(user2, issue, "heart")
+	assert.NoError(t, err)
+	assert.Equal(t, user2, reaction.User)
+	AssertExistsAndLoadBean(t, &Reaction{ID: reaction.ID, Type: "heart", IssueID: issue.ID, UserID: user2.ID})
+
+	// Reacting twice returns the existing reaction.
+	again, err := CreateIssueReaction(user2, issue, "heart")
+	assert.NoError(t, err)
+	assert.Equal(t, reaction.ID, again.ID)
+	AssertCount(t, &Reaction{IssueID: issue.ID}, 1)
+
+	_, err = CreateIssueReaction(user2, issue, "smile")
+	assert.True(t, IsErrForbiddenReaction(err))
+	AssertCount(t, &Reaction{IssueID: issue.ID}, 1)
+}
+
+func TestDeleteIssueReaction(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	_, err := CreateIssueReaction(user2, issue, "+1")
+	assert.NoError(t, err)
+	_, err = CreateIssueReaction(user3, issue, "+1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, DeleteIssueReaction(user2, issue, "+1"))
+	AssertNotExistsBean(t, &Reaction{Type: "+1", IssueID: issue.ID, UserID: user2.ID})
+	AssertExistsAndLoadBean(t, &Reaction{Type: "+1", IssueID: issue.ID, UserID: user3.ID})
+}
+
+func TestCommentReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: comment.IssueID}).(*Issue)
+
+	_, err := CreateIssueReaction(user2, issue, "tada")
+	assert.NoError(t, err)
+	_, err = CreateCommentReaction(user2, comment, "+1")
+	assert.NoError(t, err)
+	_, err = CreateCommentReaction(user3, comment, "+1")
+	assert.NoError(t, err)
+	_, err = CreateCommentReaction(user3, comment, "-1")
+	assert.NoError(t, err)
+
+	reactions, err := FindCommentReactions(comment)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 3)
+	assert.True(t, reactions.HasUser(user2.ID))
+	assert.False(t, reactions.HasUser(1))
+
+	groups := reactions.GroupByType()
+	assert.Len(t, groups["+1"], 2)
+	assert.Len(t, groups["-1"], 1)
+	assert.Equal(t, "user2, user3", groups["+1"].UserNames(10))
+	assert.Equal(t, "user2", groups["+1"].UserNames(1))
+
+	// The reactions to the comment are not reactions to the issue.
+	reactions, err = FindIssueReactions(issue)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 1)
+
+	assert.NoError(t, issue.loadComments(x))
+	assert.NoError(t, issue.LoadReactions())
+	assert.Len(t, issue.Reactions, 1)
+	for _, c := range issue.Comments {
+		if c.ID == comment.ID {
+			assert.Len(t, c.Reactions, 3)
+		}
+	}
+
+	assert.NoError(t, DeleteCommentReaction(user3, comment, "-1"))
+	AssertCount(t, &Reaction{CommentID: comment.ID}, 2)
+
+	assert.NoError(t, DeleteComment(user3, comment))
+	AssertNotExistsBean(t, &Reaction{CommentID: comment.ID})
+}
+
+func TestIssueReactionKeepsCommentReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: comment.IssueID}).(*Issue)
+
+	commentReaction, err := CreateCommentReaction(user2, comment, "heart")
+	assert.NoError(t, err)
+
+	// The same reaction to the issue is a new reaction.
+	issueReaction, err := CreateIssueReaction(user2, issue, "heart")
+	assert.NoError(t, err)
+	assert.NotEqual(t, commentReaction.ID, issueReaction.ID)
+	assert.EqualValues(t, 0, issueReaction.CommentID)
+
+	assert.NoError(t, DeleteIssueReaction(user2, issue, "heart"))
+	AssertNotExistsBean(t, &Reaction{ID: issueReaction.ID})
+	AssertExistsAndLoadBean(t, &Reaction{ID: commentReaction.ID})
+}
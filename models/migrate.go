@@ -0,0 +1,211 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-xorm/xorm"
+)
+
+// InsertMilestones inserts milestones of a migrated repository.
+func InsertMilestones(ms ...*Milestone) (err error) {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	// Insert one by one to keep the order of the milestones.
+	for _, m := range ms {
+		if _, err = sess.NoAutoTime().Insert(m); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+func insertIssue(sess *xorm.Session, issue *Issue) error {
+	if _, err := sess.NoAutoTime().Insert(issue); err != nil {
+		return err
+	}
+
+	issueLabels := make([]IssueLabel, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		issueLabels = append(issueLabels, IssueLabel{
+			IssueID: issue.ID,
+			LabelID: label.ID,
+		})
+	}
+	if len(issueLabels) > 0 {
+		if _, err := sess.Insert(issueLabels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertIssues inserts issues of a migrated repository together with their labels,
+// keeping their index and timestamps.
+func InsertIssues(issues ...*Issue) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		if err = insertIssue(sess, issue); err != nil {
+			return fmt.Errorf("insertIssue [index: %d]: %v", issue.Index, err)
+		}
+	}
+	return sess.Commit()
+}
+
+// InsertIssueComments inserts comments of migrated issues, keeping their timestamps.
+func InsertIssueComments(comments ...*Comment) (err error) {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	issueIDs := make(map[int64]struct{})
+	for _, comment := range comments {
+		if _, err = sess.NoAutoTime().Insert(comment); err != nil {
+			return err
+		}
+		issueIDs[comment.IssueID] = struct{}{}
+	}
+
+	for issueID := range issueIDs {
+		if _, err = sess.Exec("UPDATE `issue` SET num_comments = (SELECT COUNT(*) FROM `comment` WHERE issue_id = ? AND type = ?) WHERE id = ?",
+			issueID, CommentTypeComment, issueID); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// InsertPullRequests inserts pull requests of a migrated repository together with their issues.
+func InsertPullRequests(prs ...*PullRequest) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		if err = insertIssue(sess, pr.Issue); err != nil {
+			return fmt.Errorf("insertIssue [index: %d]: %v", pr.Issue.Index, err)
+		}
+		pr.IssueID = pr.Issue.ID
+		pr.Index = pr.Issue.Index
+		if _, err = sess.NoAutoTime().Insert(pr); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// InsertReleases inserts releases of a migrated repository together with their attachments.
+// Releases which were already created for the tags of the repository are updated instead.
+func InsertReleases(rels ...*Release) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, rel := range rels {
+		rel.LowerTagName = strings.ToLower(rel.TagName)
+
+		existing := new(Release)
+		has, err := sess.Where("repo_id = ? AND lower_tag_name = ?", rel.RepoID, rel.LowerTagName).Get(existing)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			rel.ID = existing.ID
+			rel.Sha1 = existing.Sha1
+			rel.NumCommits = existing.NumCommits
+			if len(rel.Target) == 0 {
+				rel.Target = existing.Target
+			}
+			if _, err = sess.ID(rel.ID).AllCols().Update(rel); err != nil {
+				return err
+			}
+		} else if _, err = sess.Insert(rel); err != nil {
+			return err
+		}
+
+		for _, attach := range rel.Attachments {
+			attach.ReleaseID = rel.ID
+			if _, err = sess.NoAutoTime().Insert(attach); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.Commit()
+}
+
+// UpdateRepoIssueStats recalculates the counters of the issues, pull requests,
+// labels and milestones of a repository, e.g. after they have been migrated.
+func UpdateRepoIssueStats(repoID int64) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	updates := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{
+			"UPDATE `repository` SET num_issues = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ?), " +
+				"num_closed_issues = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ? AND is_closed = ?), " +
+				"num_pulls = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ?), " +
+				"num_closed_pulls = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ? AND is_closed = ?), " +
+				"num_milestones = (SELECT COUNT(*) FROM `milestone` WHERE repo_id = ?), " +
+				"num_closed_milestones = (SELECT COUNT(*) FROM `milestone` WHERE repo_id = ? AND is_closed = ?) " +
+				"WHERE id = ?",
+			[]interface{}{repoID, false, repoID, false, true, repoID, true, repoID, true, true, repoID, repoID, true, repoID},
+		},
+		{
+			"UPDATE `label` SET num_issues = (SELECT COUNT(*) FROM `issue_label` WHERE label_id = `label`.id), " +
+				"num_closed_issues = (SELECT COUNT(*) FROM `issue_label` INNER JOIN `issue` ON `issue`.id = `issue_label`.issue_id " +
+				"WHERE `issue_label`.label_id = `label`.id AND `issue`.is_closed = ?) " +
+				"WHERE repo_id = ?",
+			[]interface{}{true, repoID},
+		},
+		{
+			"UPDATE `milestone` SET num_issues = (SELECT COUNT(*) FROM `issue` WHERE milestone_id = `milestone`.id), " +
+				"num_closed_issues = (SELECT COUNT(*) FROM `issue` WHERE milestone_id = `milestone`.id AND is_closed = ?) " +
+				"WHERE repo_id = ?",
+			[]interface{}{true, repoID},
+		},
+		{
+			"UPDATE `milestone` SET completeness = num_closed_issues * 100 / num_issues WHERE repo_id = ? AND num_issues > 0",
+			[]interface{}{repoID},
+		},
+	}
+	for _, update := range updates {
+		if _, err = sess.Exec(update.sql, update.args...); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
@@ -148,6 +148,36 @@ var migrations = []Migration{
 	NewMigration("add repo indexer status", addRepoIndexerStatus),
 	// v49 -> v50
 	NewMigration("add lfs lock table", addLFSLock),
+	// v50 -> v51
+	NewMigration("add merge styles to pull request units", addPullRequestMergeStyles),
+	// v51 -> v52
+	NewMigration("add review table and code comment columns", addReview),
+	// v52 -> v53
+	NewMigration("add required approvals and status checks to protected branches", addApprovalsAndStatusChecksToProtectedBranches),
+	// v53 -> v54
+	NewMigration("add push mirror table", addPushMirrors),
+	// v54 -> v55
+	NewMigration("add original author to issues and comments", addOriginalAuthorToIssuesAndComments),
+	// v55 -> v56
+	NewMigration("add reactions", addReactions),
+	// v56 -> v57
+	NewMigration("add issue dependencies", addIssueDependencies),
+	// v57 -> v58
+	NewMigration("add project boards", addProjects),
+	// v58 -> v59
+	NewMigration("add retries of webhook deliveries", addHookTaskRetries),
+	// v59 -> v60
+	NewMigration("add is_archived column to repository table", addRepoIsArchived),
+	// v60 -> v61
+	NewMigration("add template columns to repository table", addTemplateToRepository),
+	// v61 -> v62
+	NewMigration("add topic and repo_topic tables", addTopicTables),
+	// v62 -> v63
+	NewMigration("add is_system_webhook column to webhook table", addSystemWebhookColumn),
+	// v63 -> v64
+	NewMigration("add scope and expiry columns to access_token table", addAccessTokenScopeAndExpiry),
+	// v64 -> v65
+	NewMigration("add tables for Gitea as an OAuth2 provider", addOAuth2ProviderTables),
 }
 
 // Migrate database to current version
@@ -0,0 +1,25 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addPullRequestMergeStyles(x *xorm.Engine) error {
+	// Pull requests have always been merged with a merge commit,
+	// so keep that working and enable the new styles as well.
+	const (
+		UnitTypePullRequests = 3
+		pullRequestsConfig   = `{"AllowMerge":true,"AllowRebase":true,"AllowRebaseMerge":true,"AllowSquash":true}`
+	)
+
+	if _, err := x.Exec("UPDATE `repo_unit` SET `config` = ? WHERE `type` = ?", pullRequestsConfig, UnitTypePullRequests); err != nil {
+		return fmt.Errorf("update repo_unit: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addApprovalsAndStatusChecksToProtectedBranches(x *xorm.Engine) error {
+	// ProtectedBranch see models/branches.go
+	type ProtectedBranch struct {
+		RequiredApprovals         int64    `xorm:"NOT NULL DEFAULT 0"`
+		ApprovalsWhitelistUserIDs []int64  `xorm:"JSON TEXT"`
+		ApprovalsWhitelistTeamIDs []int64  `xorm:"JSON TEXT"`
+		EnableStatusCheck         bool     `xorm:"NOT NULL DEFAULT false"`
+		StatusCheckContexts       []string `xorm:"JSON TEXT"`
+	}
+
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addPushMirrors(x *xorm.Engine) error {
+	// PushMirror see models/repo_push_mirror.go
+	type PushMirror struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX"`
+		RemoteName     string `xorm:"NOT NULL"`
+		Interval       time.Duration
+		SyncOnPush     bool `xorm:"NOT NULL DEFAULT false"`
+		LastUpdateUnix int64
+		NextUpdateUnix int64  `xorm:"INDEX"`
+		LastError      string `xorm:"TEXT"`
+	}
+
+	if err := x.Sync2(new(PushMirror)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addOriginalAuthorToIssuesAndComments(x *xorm.Engine) error {
+	// Issue see models/issue.go
+	type Issue struct {
+		OriginalAuthor   string
+		OriginalAuthorID int64 `xorm:"INDEX"`
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		OriginalAuthor   string
+		OriginalAuthorID int64 `xorm:"INDEX"`
+	}
+
+	if err := x.Sync2(new(Issue), new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
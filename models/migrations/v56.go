@@ -0,0 +1,66 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addIssueDependencies(x *xorm.Engine) error {
+	// IssueDependency see models/issue_dependency.go
+	type IssueDependency struct {
+		ID           int64 `xorm:"pk autoincr"`
+		UserID       int64 `xorm:"NOT NULL"`
+		IssueID      int64 `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+		DependencyID int64 `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+		CreatedUnix  int64 `xorm:"INDEX created"`
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		DependentIssueID int64
+	}
+
+	// RepoUnit describes all units of a repository
+	type RepoUnit struct {
+		ID          int64
+		RepoID      int64 `xorm:"INDEX(s)"`
+		Type        int   `xorm:"INDEX(s)"`
+		Index       int
+		Config      map[string]interface{} `xorm:"JSON"`
+		CreatedUnix int64                  `xorm:"INDEX CREATED"`
+		Created     time.Time              `xorm:"-"`
+	}
+
+	if err := x.Sync2(new(IssueDependency)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Updating existing issue units
+	units := make([]*RepoUnit, 0, 100)
+	if err := x.Where("`type` = ?", V16UnitTypeIssues).Find(&units); err != nil {
+		return fmt.Errorf("Query repo units: %v", err)
+	}
+	for _, unit := range units {
+		if unit.Config == nil {
+			unit.Config = make(map[string]interface{})
+		}
+		if _, ok := unit.Config["EnableDependencies"]; !ok {
+			unit.Config["EnableDependencies"] = setting.Service.DefaultEnableDependencies
+		}
+		if _, err := x.ID(unit.ID).Cols("config").Update(unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
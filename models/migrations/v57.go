@@ -0,0 +1,49 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addProjects(x *xorm.Engine) error {
+	// Project see models/project.go
+	type Project struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX"`
+		CreatorID   int64  `xorm:"NOT NULL"`
+		Title       string `xorm:"NOT NULL"`
+		Description string `xorm:"TEXT"`
+		IsClosed    bool   `xorm:"INDEX"`
+		CreatedUnix int64  `xorm:"INDEX created"`
+		UpdatedUnix int64  `xorm:"INDEX updated"`
+	}
+
+	// ProjectColumn see models/project.go
+	type ProjectColumn struct {
+		ID          int64  `xorm:"pk autoincr"`
+		ProjectID   int64  `xorm:"INDEX NOT NULL"`
+		Title       string `xorm:"NOT NULL"`
+		Sorting     int    `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix int64  `xorm:"created"`
+		UpdatedUnix int64  `xorm:"updated"`
+	}
+
+	// ProjectIssue see models/project.go
+	type ProjectIssue struct {
+		ID              int64 `xorm:"pk autoincr"`
+		ProjectID       int64 `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID         int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		ProjectColumnID int64 `xorm:"INDEX NOT NULL"`
+		Sorting         int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(Project), new(ProjectColumn), new(ProjectIssue)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
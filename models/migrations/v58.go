@@ -0,0 +1,29 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addHookTaskRetries(x *xorm.Engine) error {
+	// HookTask see models/webhook.go
+	type HookTask struct {
+		Attempts        int   `xorm:"NOT NULL DEFAULT 0"`
+		NextAttemptUnix int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(HookTask)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Every delivery made so far was attempted exactly once.
+	if _, err := x.Exec("UPDATE hook_task SET attempts = 1 WHERE is_delivered = ?", true); err != nil {
+		return fmt.Errorf("update attempts: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addAccessTokenScopeAndExpiry(x *xorm.Engine) error {
+	// AccessToken see models/token.go
+	type AccessToken struct {
+		Scope        string `xorm:"VARCHAR(255)"`
+		ExpiresUnix  int64  `xorm:"INDEX"`
+		LastUsedUnix int64
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// existing tokens keep the full permissions of their user
+	if _, err := x.Exec("UPDATE access_token SET scope = ?", "all"); err != nil {
+		return fmt.Errorf("update scope: %v", err)
+	}
+	// tokens were updated whenever they were used
+	if _, err := x.Exec("UPDATE access_token SET last_used_unix = updated_unix WHERE updated_unix > created_unix"); err != nil {
+		return fmt.Errorf("update last_used_unix: %v", err)
+	}
+	return nil
+}
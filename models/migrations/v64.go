@@ -0,0 +1,68 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+// OAuth2ApplicationV64 see models/oauth2_application.go
+type OAuth2ApplicationV64 struct {
+	ID           int64 `xorm:"pk autoincr"`
+	UID          int64 `xorm:"INDEX"`
+	Name         string
+	ClientID     string `xorm:"UNIQUE"`
+	ClientSecret string
+	RedirectURIs []string `xorm:"redirect_uris JSON TEXT"`
+	CreatedUnix  int64    `xorm:"INDEX created"`
+	UpdatedUnix  int64    `xorm:"INDEX updated"`
+}
+
+// TableName will be invoked by XORM to customize the table name
+func (*OAuth2ApplicationV64) TableName() string {
+	return "oauth2_application"
+}
+
+// OAuth2AuthorizationCodeV64 see models/oauth2_application.go
+type OAuth2AuthorizationCodeV64 struct {
+	ID                  int64  `xorm:"pk autoincr"`
+	GrantID             int64  `xorm:"INDEX"`
+	Code                string `xorm:"INDEX UNIQUE"`
+	CodeChallenge       string
+	CodeChallengeMethod string
+	RedirectURI         string
+	ValidUntilUnix      int64 `xorm:"INDEX"`
+}
+
+// TableName will be invoked by XORM to customize the table name
+func (*OAuth2AuthorizationCodeV64) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// OAuth2GrantV64 see models/oauth2_application.go
+type OAuth2GrantV64 struct {
+	ID            int64  `xorm:"pk autoincr"`
+	UserID        int64  `xorm:"INDEX unique(user_application)"`
+	ApplicationID int64  `xorm:"INDEX unique(user_application)"`
+	Counter       int64  `xorm:"NOT NULL DEFAULT 1"`
+	Scope         string `xorm:"TEXT"`
+	Nonce         string `xorm:"TEXT"`
+	CreatedUnix   int64  `xorm:"INDEX created"`
+	UpdatedUnix   int64  `xorm:"INDEX updated"`
+}
+
+// TableName will be invoked by XORM to customize the table name
+func (*OAuth2GrantV64) TableName() string {
+	return "oauth2_grant"
+}
+
+func addOAuth2ProviderTables(x *xorm.Engine) error {
+	if err := x.Sync2(new(OAuth2ApplicationV64), new(OAuth2AuthorizationCodeV64), new(OAuth2GrantV64)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
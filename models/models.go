@@ -118,6 +118,18 @@ func init() {
 		new(DeletedBranch),
 		new(RepoIndexerStatus),
 		new(LFSLock),
+		new(Review),
+		new(PushMirror),
+		new(Reaction),
+		new(IssueDependency),
+		new(Project),
+		new(ProjectColumn),
+		new(ProjectIssue),
+		new(Topic),
+		new(RepoTopic),
+		new(OAuth2Application),
+		new(OAuth2AuthorizationCode),
+		new(OAuth2Grant),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -153,15 +165,23 @@ func LoadConfigs() {
 	DbCfg.Timeout = sec.Key("SQLITE_TIMEOUT").MustInt(500)
 
 	sec = setting.Cfg.Section("indexer")
+	setting.Indexer.IssueType = sec.Key("ISSUE_INDEXER_TYPE").In(setting.IndexerTypeBleve,
+		[]string{setting.IndexerTypeBleve, setting.IndexerTypeElasticsearch})
 	setting.Indexer.IssuePath = sec.Key("ISSUE_INDEXER_PATH").MustString(path.Join(setting.AppDataPath, "indexers/issues.bleve"))
 	if !filepath.IsAbs(setting.Indexer.IssuePath) {
 		setting.Indexer.IssuePath = path.Join(setting.AppWorkPath, setting.Indexer.IssuePath)
 	}
+	setting.Indexer.IssueConnStr = sec.Key("ISSUE_INDEXER_CONN_STR").MustString("http://localhost:9200")
+	setting.Indexer.IssueIndexerName = sec.Key("ISSUE_INDEXER_NAME").MustString("gitea_issues")
 	setting.Indexer.RepoIndexerEnabled = sec.Key("REPO_INDEXER_ENABLED").MustBool(false)
+	setting.Indexer.RepoType = sec.Key("REPO_INDEXER_TYPE").In(setting.IndexerTypeBleve,
+		[]string{setting.IndexerTypeBleve, setting.IndexerTypeElasticsearch})
 	setting.Indexer.RepoPath = sec.Key("REPO_INDEXER_PATH").MustString(path.Join(setting.AppDataPath, "indexers/repos.bleve"))
 	if !filepath.IsAbs(setting.Indexer.RepoPath) {
 		setting.Indexer.RepoPath = path.Join(setting.AppWorkPath, setting.Indexer.RepoPath)
 	}
+	setting.Indexer.RepoConnStr = sec.Key("REPO_INDEXER_CONN_STR").MustString("http://localhost:9200")
+	setting.Indexer.RepoIndexerName = sec.Key("REPO_INDEXER_NAME").MustString("gitea_codes")
 	setting.Indexer.UpdateQueueLength = sec.Key("UPDATE_BUFFER_LEN").MustInt(20)
 	setting.Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(512 * 1024 * 1024)
 }
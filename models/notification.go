@@ -5,8 +5,9 @@
 package models
 
 import (
-	"fmt"
 	"time"
+
+	"github.com/go-xorm/builder"
 )
 
 type (
@@ -286,7 +287,7 @@ func SetNotificationStatus(notificationID int64, user *User, status Notification
 	}
 
 	if notification.UserID != user.ID {
-		return fmt.Errorf("Can't change notification of another user: %d, %d", notification.UserID, user.ID)
+		return ErrNotificationNotExist{notificationID}
 	}
 
 	notification.Status = status
@@ -295,6 +296,11 @@ func SetNotificationStatus(notificationID int64, user *User, status Notification
 	return err
 }
 
+// GetNotificationByID returns the notification with the given ID
+func GetNotificationByID(notificationID int64) (*Notification, error) {
+	return getNotificationByID(notificationID)
+}
+
 func getNotificationByID(notificationID int64) (*Notification, error) {
 	notification := new(Notification)
 	ok, err := x.
@@ -306,8 +312,86 @@ func getNotificationByID(notificationID int64) (*Notification, error) {
 	}
 
 	if !ok {
-		return nil, fmt.Errorf("Notification %d does not exists", notificationID)
+		return nil, ErrNotificationNotExist{notificationID}
 	}
 
 	return notification, nil
 }
+
+// FindNotificationOptions represent the filters for notifications. If an
+// ID is 0 it will be ignored.
+type FindNotificationOptions struct {
+	UserID            int64
+	RepoID            int64
+	Status            []NotificationStatus
+	UpdatedAfterUnix  int64
+	UpdatedBeforeUnix int64
+	Page              int
+	PageSize          int
+}
+
+func (opts *FindNotificationOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if opts.UserID != 0 {
+		cond = cond.And(builder.Eq{"notification.user_id": opts.UserID})
+	}
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"notification.repo_id": opts.RepoID})
+	}
+	if len(opts.Status) > 0 {
+		cond = cond.And(builder.In("notification.status", opts.Status))
+	}
+	if opts.UpdatedAfterUnix != 0 {
+		cond = cond.And(builder.Gte{"notification.updated_unix": opts.UpdatedAfterUnix})
+	}
+	if opts.UpdatedBeforeUnix != 0 {
+		cond = cond.And(builder.Lte{"notification.updated_unix": opts.UpdatedBeforeUnix})
+	}
+	return cond
+}
+
+// GetNotifications returns the notifications matching the options, the most
+// recently updated first
+func GetNotifications(opts FindNotificationOptions) ([]*Notification, error) {
+	sess := x.
+		Where(opts.toCond()).
+		Desc("notification.updated_unix").
+		Desc("notification.id")
+	if opts.Page > 0 && opts.PageSize > 0 {
+		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+	}
+
+	notifications := make([]*Notification, 0, opts.PageSize)
+	return notifications, sess.Find(&notifications)
+}
+
+// CountNotifications returns the number of notifications matching the options
+func CountNotifications(opts FindNotificationOptions) (int64, error) {
+	return x.Where(opts.toCond()).Count(new(Notification))
+}
+
+// UpdateNotificationStatuses changes the status of the notifications matching
+// the options
+func UpdateNotificationStatuses(opts FindNotificationOptions, status NotificationStatus) error {
+	_, err := x.
+		Where(opts.toCond()).
+		Cols("status", "updated_unix").
+		Update(&Notification{Status: status})
+	return err
+}
+
+// LoadAttributes loads the repository and the issue of the notification
+func (n *Notification) LoadAttributes() (err error) {
+	if n.Repository == nil {
+		if n.Repository, err = getRepositoryByID(x, n.RepoID); err != nil {
+			return err
+		}
+	}
+	if n.Issue == nil {
+		if n.Issue, err = getIssueByID(x, n.IssueID); err != nil {
+			return err
+		}
+	}
+	n.Issue.Repo = n.Repository
+	return nil
+}
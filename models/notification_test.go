@@ -79,3 +79,71 @@ func TestSetNotificationStatus(t *testing.T) {
 	assert.Error(t, SetNotificationStatus(1, user, NotificationStatusRead))
 	assert.Error(t, SetNotificationStatus(NonexistentID, user, NotificationStatusRead))
 }
+
+func TestGetNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	notfs, err := GetNotifications(FindNotificationOptions{UserID: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 1, notfs[0].ID)
+	}
+
+	notfs, err = GetNotifications(FindNotificationOptions{
+		RepoID: 1,
+		Status: []NotificationStatus{NotificationStatusRead},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 2, notfs[0].ID)
+	}
+
+	notfs, err = GetNotifications(FindNotificationOptions{UserID: 1, UpdatedAfterUnix: 946684801})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 0)
+
+	notfs, err = GetNotifications(FindNotificationOptions{Page: 2, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 1)
+}
+
+func TestCountNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	cnt, err := CountNotifications(FindNotificationOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, cnt)
+
+	cnt, err = CountNotifications(FindNotificationOptions{UserID: 2, Status: []NotificationStatus{NotificationStatusUnread}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, cnt)
+}
+
+func TestUpdateNotificationStatuses(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	opts := FindNotificationOptions{UserID: 1, Status: []NotificationStatus{NotificationStatusUnread}}
+	assert.NoError(t, UpdateNotificationStatuses(opts, NotificationStatusRead))
+	AssertExistsAndLoadBean(t, &Notification{ID: 1, Status: NotificationStatusRead})
+	AssertExistsAndLoadBean(t, &Notification{ID: 2, Status: NotificationStatusRead})
+}
+
+func TestGetNotificationByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	notf, err := GetNotificationByID(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, notf.UserID)
+
+	_, err = GetNotificationByID(NonexistentID)
+	assert.True(t, IsErrNotificationNotExist(err))
+}
+
+func TestNotification_LoadAttributes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notf := AssertExistsAndLoadBean(t, &Notification{ID: 1}).(*Notification)
+	assert.NoError(t, notf.LoadAttributes())
+	assert.EqualValues(t, notf.RepoID, notf.Repository.ID)
+	assert.EqualValues(t, notf.IssueID, notf.Issue.ID)
+	assert.Equal(t, notf.Repository, notf.Issue.Repo)
+}
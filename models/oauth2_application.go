@@ -0,0 +1,613 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Unknwon/com"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-xorm/xorm"
+	gouuid "github.com/satori/go.uuid"
+
+	"code.gitea.io/gitea/modules/base"
+)
+
+// OAuth2Application an application which signs in users with their Gitea
+// account, Gitea acting as the OAuth2 provider
+type OAuth2Application struct {
+	ID           int64 `xorm:"pk autoincr"`
+	UID          int64 `xorm:"INDEX"`
+	Owner        *User `xorm:"-"`
+	Name         string
+	ClientID     string   `xorm:"UNIQUE"`
+	ClientSecret string   // SHA256 hash of the secret, which is only shown once
+	RedirectURIs []string `xorm:"redirect_uris JSON TEXT"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX updated"`
+}
+
+// TableName sets the table name to `oauth2_application`
+func (app *OAuth2Application) TableName() string {
+	return "oauth2_application"
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (app *OAuth2Application) AfterLoad() {
+	app.Created = time.Unix(app.CreatedUnix, 0).Local()
+	app.Updated = time.Unix(app.UpdatedUnix, 0).Local()
+}
+
+// PrimaryRedirectURI returns the first redirect uri of the application
+func (app *OAuth2Application) PrimaryRedirectURI() string {
+	if len(app.RedirectURIs) == 0 {
+		return ""
+	}
+	return app.RedirectURIs[0]
+}
+
+// ContainsRedirectURI returns true if the uri is one of the registered
+// redirect uris, which must match exactly
+func (app *OAuth2Application) ContainsRedirectURI(redirectURI string) bool {
+	for _, uri := range app.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func hashClientSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateClientSecret replaces the client secret of the application by a
+// new random secret, which is returned in plain text
+func (app *OAuth2Application) GenerateClientSecret() (string, error) {
+	secret, err := base.GetRandomString(44)
+	if err != nil {
+		return "", err
+	}
+	app.ClientSecret = hashClientSecret(secret)
+	if _, err = x.ID(app.ID).Cols("client_secret").Update(app); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ValidateClientSecret returns true if the secret is the client secret of
+// the application
+func (app *OAuth2Application) ValidateClientSecret(secret string) bool {
+	return len(secret) > 0 && len(app.ClientSecret) > 0 &&
+		subtle.ConstantTimeCompare([]byte(hashClientSecret(secret)), []byte(app.ClientSecret)) == 1
+}
+
+// LoadOwner loads the user or organization owning the application
+func (app *OAuth2Application) LoadOwner() (err error) {
+	if app.Owner == nil {
+		app.Owner, err = GetUserByID(app.UID)
+	}
+	return err
+}
+
+// GetGrantByUserID returns the grant of the user for the application, or
+// nil if the user has not authorized the application
+func (app *OAuth2Application) GetGrantByUserID(userID int64) (*OAuth2Grant, error) {
+	grant := &OAuth2Grant{}
+	has, err := x.Where("user_id = ? AND application_id = ?", userID, app.ID).Get(grant)
+	if err != nil || !has {
+		return nil, err
+	}
+	grant.Application = app
+	return grant, nil
+}
+
+// CreateGrant authorizes the application to access the account of the user
+// with the given scope
+func (app *OAuth2Application) CreateGrant(userID int64, scope string) (*OAuth2Grant, error) {
+	grant := &OAuth2Grant{
+		ApplicationID: app.ID,
+		Application:   app,
+		UserID:        userID,
+		Counter:       1,
+		Scope:         scope,
+	}
+	if _, err := x.Insert(grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// GetOAuth2ApplicationByClientID returns the application with the client id
+func GetOAuth2ApplicationByClientID(clientID string) (*OAuth2Application, error) {
+	app := &OAuth2Application{}
+	has, err := x.Where("client_id = ?", clientID).Get(app)
+	if err != nil {
+		return nil, err
+	} else if !has || len(clientID) == 0 {
+		return nil, ErrOAuthClientIDInvalid{ClientID: clientID}
+	}
+	return app, nil
+}
+
+// GetOAuth2ApplicationByID returns the application with the id
+func GetOAuth2ApplicationByID(id int64) (*OAuth2Application, error) {
+	app := &OAuth2Application{}
+	has, err := x.ID(id).Get(app)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuthApplicationNotFound{ID: id}
+	}
+	return app, nil
+}
+
+// GetOAuth2ApplicationsByUserID returns the applications owned by the user
+// or organization
+func GetOAuth2ApplicationsByUserID(userID int64) ([]*OAuth2Application, error) {
+	apps := make([]*OAuth2Application, 0, 5)
+	return apps, x.Where("uid = ?", userID).Asc("id").Find(&apps)
+}
+
+// CreateOAuth2ApplicationOptions holds options to create an oauth2 application
+type CreateOAuth2ApplicationOptions struct {
+	Name         string
+	UserID       int64
+	RedirectURIs []string
+}
+
+// CreateOAuth2Application registers a new application, its client secret
+// has to be generated afterwards
+func CreateOAuth2Application(opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error) {
+	app := &OAuth2Application{
+		UID:          opts.UserID,
+		Name:         opts.Name,
+		ClientID:     gouuid.NewV4().String(),
+		RedirectURIs: opts.RedirectURIs,
+	}
+	if _, err := x.Insert(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// UpdateOAuth2ApplicationOptions holds options to update an oauth2 application
+type UpdateOAuth2ApplicationOptions struct {
+	ID           int64
+	Name         string
+	UserID       int64
+	RedirectURIs []string
+}
+
+// UpdateOAuth2Application updates the name and the redirect uris of an
+// application owned by the user or organization
+func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) error {
+	app, err := GetOAuth2ApplicationByID(opts.ID)
+	if err != nil {
+		return err
+	} else if app.UID != opts.UserID {
+		return ErrOAuthApplicationNotFound{ID: opts.ID}
+	}
+	app.Name = opts.Name
+	app.RedirectURIs = opts.RedirectURIs
+	_, err = x.ID(app.ID).Cols("name", "redirect_uris").Update(app)
+	return err
+}
+
+func deleteOAuth2Application(sess *xorm.Session, id, userID int64) error {
+	if deleted, err := sess.Delete(&OAuth2Application{ID: id, UID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuthApplicationNotFound{ID: id}
+	}
+	if _, err := sess.
+		Where("grant_id IN (SELECT id FROM oauth2_grant WHERE application_id = ?)", id).
+		Delete(&OAuth2AuthorizationCode{}); err != nil {
+		return err
+	}
+	_, err := sess.Where("application_id = ?", id).Delete(&OAuth2Grant{})
+	return err
+}
+
+// DeleteOAuth2Application deletes an application owned by the user or
+// organization, together with all of its grants
+func DeleteOAuth2Application(id, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := deleteOAuth2Application(sess, id, userID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// deleteOAuth2ApplicationsByUserID deletes the applications owned by a user
+// and the grants of the user
+func deleteOAuth2ApplicationsByUserID(sess *xorm.Session, userID int64) error {
+	apps := make([]*OAuth2Application, 0, 5)
+	if err := sess.Where("uid = ?", userID).Find(&apps); err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if err := deleteOAuth2Application(sess, app.ID, userID); err != nil {
+			return err
+		}
+	}
+	if _, err := sess.
+		Where("grant_id IN (SELECT id FROM oauth2_grant WHERE user_id = ?)", userID).
+		Delete(&OAuth2AuthorizationCode{}); err != nil {
+		return err
+	}
+	_, err := sess.Where("user_id = ?", userID).Delete(&OAuth2Grant{})
+	return err
+}
+
+//////////////////////////////////////////////////////////////////////
+
+// Scopes of the OpenID Connect protocol, all other scopes of the OAuth2
+// flow are access token scopes which limit the access to the API
+const (
+	OAuth2ScopeOpenID  = "openid"
+	OAuth2ScopeProfile = "profile"
+	OAuth2ScopeEmail   = "email"
+)
+
+// OAuth2Scopes lists the scopes which can be requested by an application
+var OAuth2Scopes = []string{OAuth2ScopeOpenID, OAuth2ScopeProfile, OAuth2ScopeEmail}
+
+func init() {
+	for _, scope := range AccessTokenScopes {
+		OAuth2Scopes = append(OAuth2Scopes, string(scope))
+	}
+}
+
+// NormalizeOAuth2Scope validates the space separated scopes requested by an
+// application and returns them without duplicates
+func NormalizeOAuth2Scope(scope string) (string, error) {
+	requested := strings.Fields(scope)
+	normalized := make([]string, 0, len(requested))
+	for _, s := range requested {
+		if !com.IsSliceContainsStr(OAuth2Scopes, s) {
+			return "", ErrOAuthScopeInvalid{Scope: s}
+		} else if !com.IsSliceContainsStr(normalized, s) {
+			normalized = append(normalized, s)
+		}
+	}
+	return strings.Join(normalized, " "), nil
+}
+
+// OAuth2Grant the authorization of an application to access the account of
+// a user
+type OAuth2Grant struct {
+	ID            int64              `xorm:"pk autoincr"`
+	UserID        int64              `xorm:"INDEX unique(user_application)"`
+	Application   *OAuth2Application `xorm:"-"`
+	ApplicationID int64              `xorm:"INDEX unique(user_application)"`
+	// Counter is increased whenever a refresh token is used, which
+	// invalidates all refresh tokens issued before
+	Counter int64  `xorm:"NOT NULL DEFAULT 1"`
+	Scope   string `xorm:"TEXT"`
+	Nonce   string `xorm:"TEXT"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX updated"`
+}
+
+// TableName sets the table name to `oauth2_grant`
+func (grant *OAuth2Grant) TableName() string {
+	return "oauth2_grant"
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (grant *OAuth2Grant) AfterLoad() {
+	grant.Created = time.Unix(grant.CreatedUnix, 0).Local()
+	grant.Updated = time.Unix(grant.UpdatedUnix, 0).Local()
+}
+
+// ScopeList returns the scopes which have been granted
+func (grant *OAuth2Grant) ScopeList() []string {
+	return strings.Fields(grant.Scope)
+}
+
+// ScopeContains returns true if the scope has been granted
+func (grant *OAuth2Grant) ScopeContains(scope string) bool {
+	return com.IsSliceContainsStr(strings.Fields(grant.Scope), scope)
+}
+
+// AccessTokenScope returns the scope of the API requests made with access
+// tokens of the grant. No scopes are granted if the application did not
+// request any, full access requires the all scope.
+func (grant *OAuth2Grant) AccessTokenScope() AccessTokenScope {
+	scopes := make([]string, 0, 3)
+	for _, scope := range AccessTokenScopes {
+		if grant.ScopeContains(string(scope)) {
+			scopes = append(scopes, string(scope))
+		}
+	}
+	return AccessTokenScope(strings.Join(scopes, ","))
+}
+
+// UpdateScope replaces the scope of the grant
+func (grant *OAuth2Grant) UpdateScope(scope string) error {
+	grant.Scope = scope
+	_, err := x.ID(grant.ID).Cols("scope").Update(grant)
+	return err
+}
+
+// SetNonce sets the nonce of the OpenID Connect authentication request,
+// which is included in the next id token
+func (grant *OAuth2Grant) SetNonce(nonce string) error {
+	grant.Nonce = nonce
+	_, err := x.ID(grant.ID).Cols("nonce").Update(grant)
+	return err
+}
+
+// IncreaseCounter increases the counter of the grant, so that refresh
+// tokens issued before are no longer accepted. The counter is only
+// increased if it still equals the counter of the refresh token, otherwise
+// ErrOAuthRefreshTokenReused is returned.
+func (grant *OAuth2Grant) IncreaseCounter(counter int64) error {
+	affected, err := x.ID(grant.ID).Where("counter = ?", counter).Incr("counter").Update(new(OAuth2Grant))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrOAuthRefreshTokenReused{GrantID: grant.ID}
+	}
+	grant.Counter = counter + 1
+	return nil
+}
+
+// GenerateNewAuthorizationCode generates an authorization code of the grant,
+// which can be exchanged for the tokens once within the next few minutes
+func (grant *OAuth2Grant) GenerateNewAuthorizationCode(redirectURI, codeChallenge, codeChallengeMethod string) (*OAuth2AuthorizationCode, error) {
+	code, err := base.GetRandomString(44)
+	if err != nil {
+		return nil, err
+	}
+	authCode := &OAuth2AuthorizationCode{
+		Grant:               grant,
+		GrantID:             grant.ID,
+		Code:                code,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+		RedirectURI:         redirectURI,
+		ValidUntilUnix:      time.Now().Add(oauth2AuthorizationCodeLifetime).Unix(),
+	}
+	if _, err = x.Insert(authCode); err != nil {
+		return nil, err
+	}
+	return authCode, nil
+}
+
+// GetOAuth2GrantByID returns the grant with the id
+func GetOAuth2GrantByID(id int64) (*OAuth2Grant, error) {
+	grant := &OAuth2Grant{}
+	has, err := x.ID(id).Get(grant)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuthGrantNotFound{ID: id}
+	}
+	return grant, nil
+}
+
+// GetOAuth2GrantsByUserID returns the grants of the user, with their
+// applications
+func GetOAuth2GrantsByUserID(userID int64) ([]*OAuth2Grant, error) {
+	grants := make([]*OAuth2Grant, 0, 5)
+	if err := x.Where("user_id = ?", userID).Asc("id").Find(&grants); err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		app, err := GetOAuth2ApplicationByID(grant.ApplicationID)
+		if err != nil {
+			return nil, err
+		}
+		grant.Application = app
+	}
+	return grants, nil
+}
+
+// RevokeOAuth2Grant revokes a grant of the user, all tokens issued for the
+// grant are rejected afterwards
+func RevokeOAuth2Grant(grantID, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if deleted, err := sess.Delete(&OAuth2Grant{ID: grantID, UserID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuthGrantNotFound{ID: grantID}
+	}
+	if _, err := sess.Where("grant_id = ?", grantID).Delete(&OAuth2AuthorizationCode{}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+//////////////////////////////////////////////////////////////////////
+
+// oauth2AuthorizationCodeLifetime how long an authorization code is valid
+const oauth2AuthorizationCodeLifetime = 5 * time.Minute
+
+// OAuth2AuthorizationCode a code which an application exchanges for the
+// tokens of a grant
+type OAuth2AuthorizationCode struct {
+	ID                  int64        `xorm:"pk autoincr"`
+	Grant               *OAuth2Grant `xorm:"-"`
+	GrantID             int64        `xorm:"INDEX"`
+	Code                string       `xorm:"INDEX UNIQUE"`
+	CodeChallenge       string
+	CodeChallengeMethod string
+	RedirectURI         string
+	ValidUntilUnix      int64 `xorm:"INDEX"`
+}
+
+// TableName sets the table name to `oauth2_authorization_code`
+func (code *OAuth2AuthorizationCode) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// IsExpired returns true if the code can no longer be exchanged
+func (code *OAuth2AuthorizationCode) IsExpired() bool {
+	return code.ValidUntilUnix <= time.Now().Unix()
+}
+
+// GenerateRedirectURI returns the redirect uri with the code and the state
+// of the authorization request
+func (code *OAuth2AuthorizationCode) GenerateRedirectURI(state string) (*url.URL, error) {
+	redirect, err := url.Parse(code.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	q := redirect.Query()
+	if len(state) > 0 {
+		q.Set("state", state)
+	}
+	q.Set("code", code.Code)
+	redirect.RawQuery = q.Encode()
+	return redirect, nil
+}
+
+// ValidateCodeChallenge returns true if the verifier matches the challenge
+// of the authorization request, see RFC 7636 (PKCE)
+func (code *OAuth2AuthorizationCode) ValidateCodeChallenge(verifier string) bool {
+	switch code.CodeChallengeMethod {
+	case "S256":
+		hash := sha256.Sum256([]byte(verifier))
+		challenge := base64.RawURLEncoding.EncodeToString(hash[:])
+		return subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) == 1
+	case "plain":
+		return subtle.ConstantTimeCompare([]byte(verifier), []byte(code.CodeChallenge)) == 1
+	case "":
+		return true
+	default:
+		return false
+	}
+}
+
+// Invalidate deletes the code, so that it cannot be used again. Returns
+// ErrOAuthAuthorizationCodeInvalid if the code has been deleted already, e.g.
+// by a concurrent exchange of the same code.
+func (code *OAuth2AuthorizationCode) Invalidate() error {
+	count, err := x.ID(code.ID).Delete(&OAuth2AuthorizationCode{})
+	if err != nil {
+		return err
+	} else if count != 1 {
+		return ErrOAuthAuthorizationCodeInvalid{}
+	}
+	return nil
+}
+
+// GetOAuth2AuthorizationByCode returns the authorization code together with
+// its grant and application
+func GetOAuth2AuthorizationByCode(code string) (*OAuth2AuthorizationCode, error) {
+	authCode := &OAuth2AuthorizationCode{}
+	has, err := x.Where("code = ?", code).Get(authCode)
+	if err != nil {
+		return nil, err
+	} else if !has || len(code) == 0 {
+		return nil, ErrOAuthAuthorizationCodeInvalid{}
+	}
+	if authCode.Grant, err = GetOAuth2GrantByID(authCode.GrantID); err != nil {
+		return nil, err
+	}
+	if authCode.Grant.Application, err = GetOAuth2ApplicationByID(authCode.Grant.ApplicationID); err != nil {
+		return nil, err
+	}
+	return authCode, nil
+}
+
+//////////////////////////////////////////////////////////////////////
+
+// OAuth2TokenType the type of a token issued by Gitea as an OAuth2 provider
+type OAuth2TokenType int
+
+const (
+	// TypeAccessToken a token used to access the API
+	TypeAccessToken OAuth2TokenType = iota
+	// TypeRefreshToken a token used to obtain new tokens
+	TypeRefreshToken
+)
+
+// OAuth2Token the claims of an access or refresh token, which is signed as
+// a JSON Web Token
+type OAuth2Token struct {
+	GrantID int64           `json:"gnt"`
+	Type    OAuth2TokenType `json:"tt"`
+	Counter int64           `json:"cnt,omitempty"`
+	// PublicClient is set on refresh tokens issued to clients without a
+	// secret, which can use them without authenticating
+	PublicClient bool `json:"pub,omitempty"`
+	jwt.StandardClaims
+}
+
+// SignToken signs the token with the private key
+func (token *OAuth2Token) SignToken(key *rsa.PrivateKey) (string, error) {
+	token.IssuedAt = time.Now().Unix()
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, token)
+	return jwtToken.SignedString(key)
+}
+
+// ParseOAuth2Token parses and validates a token signed by the private key
+func ParseOAuth2Token(jwtToken string, key *rsa.PrivateKey) (*OAuth2Token, error) {
+	parsedToken, err := jwt.ParseWithClaims(jwtToken, &OAuth2Token{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method == nil || token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Header["alg"])
+		}
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	token, ok := parsedToken.Claims.(*OAuth2Token)
+	if !ok || !parsedToken.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return token, nil
+}
+
+// OIDCToken the claims of an OpenID Connect id token
+type OIDCToken struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+
+	// Scope profile
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Website           string `json:"website,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+
+	// Scope email
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+}
+
+// SignToken signs the id token with the private key
+func (token *OIDCToken) SignToken(key *rsa.PrivateKey, keyID string) (string, error) {
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, token)
+	jwtToken.Header["kid"] = keyID
+	return jwtToken.SignedString(key)
+}
@@ -0,0 +1,250 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2Application_GenerateClientSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	secret, err := app.GenerateClientSecret()
+	assert.NoError(t, err)
+	assert.True(t, len(secret) > 0)
+	assert.True(t, app.ValidateClientSecret(secret))
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1, ClientSecret: app.ClientSecret})
+}
+
+func TestOAuth2Application_ValidateClientSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	assert.True(t, app.ValidateClientSecret("4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA"))
+	assert.False(t, app.ValidateClientSecret("4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiB"))
+	assert.False(t, app.ValidateClientSecret(""))
+}
+
+func TestOAuth2Application_ContainsRedirectURI(t *testing.T) {
+	app := &OAuth2Application{RedirectURIs: []string{"a", "b", "c"}}
+	assert.True(t, app.ContainsRedirectURI("a"))
+	assert.True(t, app.ContainsRedirectURI("b"))
+	assert.False(t, app.ContainsRedirectURI("d"))
+	assert.False(t, app.ContainsRedirectURI("A"))
+}
+
+func TestGetOAuth2ApplicationByClientID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := GetOAuth2ApplicationByClientID("da7da3ba-9a13-4167-856f-3899de0b0138")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, app.ID)
+
+	_, err = GetOAuth2ApplicationByClientID("invalid")
+	assert.True(t, IsErrOAuthClientIDInvalid(err))
+	_, err = GetOAuth2ApplicationByClientID("")
+	assert.True(t, IsErrOAuthClientIDInvalid(err))
+}
+
+func TestCreateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := CreateOAuth2Application(CreateOAuth2ApplicationOptions{
+		Name:         "newapp",
+		UserID:       2,
+		RedirectURIs: []string{"https://example.com/callback"},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, app.ClientID)
+	app = AssertExistsAndLoadBean(t, &OAuth2Application{ID: app.ID, Name: "newapp"}).(*OAuth2Application)
+	assert.Equal(t, []string{"https://example.com/callback"}, app.RedirectURIs)
+}
+
+func TestUpdateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.NoError(t, UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{
+		ID:           1,
+		Name:         "renamed",
+		UserID:       1,
+		RedirectURIs: []string{"b"},
+	}))
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1, Name: "renamed"}).(*OAuth2Application)
+	assert.Equal(t, []string{"b"}, app.RedirectURIs)
+
+	err := UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{ID: 1, Name: "stolen", UserID: 2})
+	assert.True(t, IsErrOAuthApplicationNotFound(err))
+}
+
+func TestDeleteOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuthApplicationNotFound(DeleteOAuth2Application(1, 2)))
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1})
+
+	assert.NoError(t, DeleteOAuth2Application(1, 1))
+	AssertNotExistsBean(t, &OAuth2Application{ID: 1})
+	AssertNotExistsBean(t, &OAuth2Grant{ApplicationID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{GrantID: 1})
+}
+
+func TestOAuth2Application_GetGrantByUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	grant, err := app.GetGrantByUserID(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, grant.ID)
+
+	grant, err = app.GetGrantByUserID(2)
+	assert.NoError(t, err)
+	assert.Nil(t, grant)
+}
+
+func TestOAuth2Application_CreateGrant(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	grant, err := app.CreateGrant(2, "repo:read")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: grant.ID, UserID: 2, ApplicationID: 1, Counter: 1})
+}
+
+func TestNormalizeOAuth2Scope(t *testing.T) {
+	scope, err := NormalizeOAuth2Scope(" openid  repo openid ")
+	assert.NoError(t, err)
+	assert.Equal(t, "openid repo", scope)
+
+	scope, err = NormalizeOAuth2Scope("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", scope)
+
+	_, err = NormalizeOAuth2Scope("openid delete_repo")
+	assert.True(t, IsErrOAuthScopeInvalid(err))
+}
+
+func TestOAuth2Grant_AccessTokenScope(t *testing.T) {
+	assert.Equal(t, AccessTokenScope(""), (&OAuth2Grant{}).AccessTokenScope())
+	assert.Equal(t, AccessTokenScopeAll, (&OAuth2Grant{Scope: "all"}).AccessTokenScope())
+	assert.Equal(t, AccessTokenScope(""), (&OAuth2Grant{Scope: "openid email"}).AccessTokenScope())
+	assert.Equal(t, AccessTokenScope("repo:read,user"), (&OAuth2Grant{Scope: "user openid repo:read"}).AccessTokenScope())
+}
+
+func TestOAuth2Grant_IncreaseCounter(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 1}).(*OAuth2Grant)
+	assert.NoError(t, grant.IncreaseCounter(1))
+	assert.EqualValues(t, 2, grant.Counter)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 2})
+
+	// the counter of a reused refresh token is outdated
+	err := grant.IncreaseCounter(1)
+	assert.True(t, IsErrOAuthRefreshTokenReused(err))
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 2})
+}
+
+func TestOAuth2Grant_GenerateNewAuthorizationCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
+	code, err := grant.GenerateNewAuthorizationCode("https://example.com/callback", "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg", "S256")
+	assert.NoError(t, err)
+	assert.NotNil(t, code)
+	assert.True(t, len(code.Code) > 32)
+	assert.False(t, code.IsExpired())
+	AssertExistsAndLoadBean(t, &OAuth2AuthorizationCode{Code: code.Code, GrantID: 1})
+}
+
+func TestRevokeOAuth2Grant(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuthGrantNotFound(RevokeOAuth2Grant(1, 2)))
+	assert.NoError(t, RevokeOAuth2Grant(1, 1))
+	AssertNotExistsBean(t, &OAuth2Grant{ID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{GrantID: 1})
+}
+
+func TestGetOAuth2AuthorizationByCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	code, err := GetOAuth2AuthorizationByCode("authcode")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, code.Grant.ID)
+	assert.EqualValues(t, 1, code.Grant.Application.ID)
+
+	_, err = GetOAuth2AuthorizationByCode("does not exist")
+	assert.True(t, IsErrOAuthAuthorizationCodeInvalid(err))
+}
+
+func TestOAuth2AuthorizationCode_Invalidate(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	code := AssertExistsAndLoadBean(t, &OAuth2AuthorizationCode{Code: "authcode"}).(*OAuth2AuthorizationCode)
+	assert.NoError(t, code.Invalidate())
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{Code: "authcode"})
+
+	// a code can only be invalidated once
+	assert.True(t, IsErrOAuthAuthorizationCodeInvalid(code.Invalidate()))
+}
+
+func TestOAuth2AuthorizationCode_ValidateCodeChallenge(t *testing.T) {
+	code := &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "S256",
+		CodeChallenge:       "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg",
+	}
+	assert.True(t, code.ValidateCodeChallenge("N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"))
+	assert.False(t, code.ValidateCodeChallenge("CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg"))
+
+	code = &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "plain",
+		CodeChallenge:       "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	}
+	assert.True(t, code.ValidateCodeChallenge("N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"))
+	assert.False(t, code.ValidateCodeChallenge("other"))
+
+	code = &OAuth2AuthorizationCode{}
+	assert.True(t, code.ValidateCodeChallenge(""))
+
+	code = &OAuth2AuthorizationCode{CodeChallengeMethod: "unknown"}
+	assert.False(t, code.ValidateCodeChallenge(""))
+}
+
+func TestOAuth2AuthorizationCode_GenerateRedirectURI(t *testing.T) {
+	code := &OAuth2AuthorizationCode{
+		RedirectURI: "https://example.com/callback?x=1",
+		Code:        "thecode",
+	}
+	redirect, err := code.GenerateRedirectURI("thestate")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/callback?code=thecode&state=thestate&x=1", redirect.String())
+}
+
+func TestOAuth2Token_SignToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	token := &OAuth2Token{
+		GrantID: 1,
+		Type:    TypeRefreshToken,
+		Counter: 2,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	signed, err := token.SignToken(key)
+	assert.NoError(t, err)
+
+	parsed, err := ParseOAuth2Token(signed, key)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, parsed.GrantID)
+	assert.Equal(t, TypeRefreshToken, parsed.Type)
+	assert.EqualValues(t, 2, parsed.Counter)
+
+	_, err = ParseOAuth2Token(signed, otherKey)
+	assert.Error(t, err)
+
+	token.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	signed, err = token.SignToken(key)
+	assert.NoError(t, err)
+	_, err = ParseOAuth2Token(signed, key)
+	assert.Error(t, err)
+}
@@ -10,6 +10,8 @@ import (
 	"os"
 	"strings"
 
+	api "code.gitea.io/sdk/gitea"
+
 	"github.com/Unknwon/com"
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
@@ -242,6 +244,10 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deleteOAuth2ApplicationsByUserID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteOAuth2ApplicationsByUserID: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
@@ -422,9 +428,17 @@ func AddOrgUser(orgID, uid int64) error {
 	} else if _, err = sess.Exec("UPDATE `user` SET num_members = num_members + 1 WHERE id = ?", orgID); err != nil {
 		sess.Rollback()
 		return err
+	} else if err = prepareMemberWebhooks(sess, orgID, uid, api.HookMemberAdded); err != nil {
+		sess.Rollback()
+		return err
 	}
 
-	return sess.Commit()
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+
+	go HookQueue.Add(0)
+	return nil
 }
 
 // RemoveOrgUser removes user from given organization.
@@ -504,7 +518,39 @@ func RemoveOrgUser(orgID, userID int64) error {
 		}
 	}
 
-	return sess.Commit()
+	if err = prepareMemberWebhooks(sess, orgID, userID, api.HookMemberRemoved); err != nil {
+		sess.Rollback()
+		return err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	go HookQueue.Add(0)
+	return nil
+}
+
+// prepareMemberWebhooks adds the webhooks of the organization and the system webhooks
+// to task queue for the membership change of the given user.
+func prepareMemberWebhooks(e Engine, orgID, userID int64, action api.HookMemberAction) error {
+	org, err := getUserByID(e, orgID)
+	if err != nil {
+		return fmt.Errorf("getUserByID [%d]: %v", orgID, err)
+	}
+	member, err := getUserByID(e, userID)
+	if err != nil {
+		return fmt.Errorf("getUserByID [%d]: %v", userID, err)
+	}
+
+	if err = prepareOrgWebhooks(e, org, HookEventMember, &api.MemberPayload{
+		Action:       action,
+		Member:       member.APIFormat(),
+		Organization: org.APIFormat(),
+	}); err != nil {
+		return fmt.Errorf("prepareOrgWebhooks: %v", err)
+	}
+	return nil
 }
 
 func removeOrgRepo(e Engine, orgID, repoID int64) error {
@@ -0,0 +1,445 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/xorm"
+)
+
+// Project represents a project board of a repository. Issues and pull requests
+// of the repository are placed as cards in the ordered columns of the board.
+type Project struct {
+	ID                  int64  `xorm:"pk autoincr"`
+	RepoID              int64  `xorm:"INDEX"`
+	CreatorID           int64  `xorm:"NOT NULL"`
+	Creator             *User  `xorm:"-"`
+	Title               string `xorm:"NOT NULL"`
+	Description         string `xorm:"TEXT"`
+	RenderedDescription string `xorm:"-"`
+	IsClosed            bool   `xorm:"INDEX"`
+
+	Columns []*ProjectColumn `xorm:"-"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX updated"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (p *Project) AfterLoad() {
+	p.Created = time.Unix(p.CreatedUnix, 0).Local()
+	p.Updated = time.Unix(p.UpdatedUnix, 0).Local()
+}
+
+// State returns string representation of project status.
+func (p *Project) State() api.StateType {
+	if p.IsClosed {
+		return api.StateClosed
+	}
+	return api.StateOpen
+}
+
+func (p *Project) loadCreator(e Engine) (err error) {
+	if p.Creator != nil {
+		return nil
+	}
+	p.Creator, err = getUserByID(e, p.CreatorID)
+	if IsErrUserNotExist(err) {
+		p.Creator = NewGhostUser()
+		err = nil
+	}
+	return err
+}
+
+// LoadCreator loads the user who created the project
+func (p *Project) LoadCreator() error {
+	return p.loadCreator(x)
+}
+
+// APIFormat returns this Project in API format. The creator has to be loaded.
+func (p *Project) APIFormat() *api.Project {
+	return &api.Project{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		State:       p.State(),
+		Creator:     p.Creator.APIFormat(),
+		Created:     p.Created,
+		Updated:     p.Updated,
+	}
+}
+
+// ProjectColumn represents a column of a project board
+type ProjectColumn struct {
+	ID        int64  `xorm:"pk autoincr"`
+	ProjectID int64  `xorm:"INDEX NOT NULL"`
+	Title     string `xorm:"NOT NULL"`
+	Sorting   int    `xorm:"NOT NULL DEFAULT 0"`
+
+	Cards []*ProjectIssue `xorm:"-"`
+
+	CreatedUnix int64 `xorm:"created"`
+	UpdatedUnix int64 `xorm:"updated"`
+}
+
+// APIFormat returns this ProjectColumn in API format.
+func (c *ProjectColumn) APIFormat() *api.ProjectColumn {
+	return &api.ProjectColumn{
+		ID:      c.ID,
+		Title:   c.Title,
+		Sorting: c.Sorting,
+	}
+}
+
+// ProjectIssue represents an issue or pull request placed as a card in a column of a project board
+type ProjectIssue struct {
+	ID              int64  `xorm:"pk autoincr"`
+	ProjectID       int64  `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID         int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Issue           *Issue `xorm:"-"`
+	ProjectColumnID int64  `xorm:"INDEX NOT NULL"`
+	Sorting         int    `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// APIFormat returns this ProjectIssue in API format. The issue has to be loaded.
+func (pi *ProjectIssue) APIFormat() *api.ProjectCard {
+	return &api.ProjectCard{
+		ColumnID: pi.ProjectColumnID,
+		Sorting:  pi.Sorting,
+		Issue:    pi.Issue.APIFormat(),
+	}
+}
+
+// NewProject creates a new project board of a repository.
+func NewProject(p *Project) error {
+	_, err := x.Insert(p)
+	return err
+}
+
+func getProjectByRepoID(e Engine, repoID, id int64) (*Project, error) {
+	p := &Project{
+		ID:     id,
+		RepoID: repoID,
+	}
+	has, err := e.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectNotExist{id, repoID}
+	}
+	return p, nil
+}
+
+// GetProjectByRepoID returns the project board in a repository.
+func GetProjectByRepoID(repoID, id int64) (*Project, error) {
+	return getProjectByRepoID(x, repoID, id)
+}
+
+// GetProjects returns a list of the project boards of a repository, newest first.
+// All of them are returned if page is zero.
+func GetProjects(repoID int64, page int, isClosed bool) ([]*Project, error) {
+	projects := make([]*Project, 0, setting.UI.IssuePagingNum)
+	sess := x.Where("repo_id = ? AND is_closed = ?", repoID, isClosed)
+	if page > 0 {
+		sess = sess.Limit(setting.UI.IssuePagingNum, (page-1)*setting.UI.IssuePagingNum)
+	}
+	return projects, sess.Desc("id").Find(&projects)
+}
+
+// ProjectStats returns the number of open and closed project boards of a repository.
+func ProjectStats(repoID int64) (open int64, closed int64) {
+	open, _ = x.Where("repo_id = ? AND is_closed = ?", repoID, false).Count(new(Project))
+	closed, _ = x.Where("repo_id = ? AND is_closed = ?", repoID, true).Count(new(Project))
+	return open, closed
+}
+
+// UpdateProject updates the title and description of a project board.
+func UpdateProject(p *Project) error {
+	_, err := x.ID(p.ID).Cols("title", "description").Update(p)
+	return err
+}
+
+// ChangeProjectStatus changes the status of a project board to open or closed.
+func ChangeProjectStatus(p *Project, isClosed bool) error {
+	p.IsClosed = isClosed
+	_, err := x.ID(p.ID).Cols("is_closed").Update(p)
+	return err
+}
+
+// DeleteProjectByRepoID deletes a project board of a repository together with its columns and cards.
+func DeleteProjectByRepoID(repoID, id int64) error {
+	p, err := GetProjectByRepoID(repoID, id)
+	if err != nil {
+		if IsErrProjectNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = deleteProjects(sess, p.ID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func deleteProjects(e Engine, projectIDs ...int64) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	if _, err := e.In("project_id", projectIDs).Delete(new(ProjectIssue)); err != nil {
+		return err
+	}
+	if _, err := e.In("project_id", projectIDs).Delete(new(ProjectColumn)); err != nil {
+		return err
+	}
+	_, err := e.In("id", projectIDs).Delete(new(Project))
+	return err
+}
+
+func deleteRepoProjects(e Engine, repoID int64) error {
+	projectIDs := make([]int64, 0, 5)
+	if err := e.Table("project").Cols("id").Where("repo_id = ?", repoID).Find(&projectIDs); err != nil {
+		return err
+	}
+	return deleteProjects(e, projectIDs...)
+}
+
+func (p *Project) getColumns(e Engine) ([]*ProjectColumn, error) {
+	columns := make([]*ProjectColumn, 0, 5)
+	return columns, e.Where("project_id = ?", p.ID).Asc("sorting", "id").Find(&columns)
+}
+
+// GetColumns returns the columns of the project board in their order.
+func (p *Project) GetColumns() ([]*ProjectColumn, error) {
+	return p.getColumns(x)
+}
+
+func (p *Project) getCards(e Engine) ([]*ProjectIssue, error) {
+	cards := make([]*ProjectIssue, 0, 10)
+	if err := e.Where("project_id = ?", p.ID).Asc("sorting", "id").Find(&cards); err != nil {
+		return nil, err
+	} else if len(cards) == 0 {
+		return cards, nil
+	}
+
+	issueIDs := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		issueIDs = append(issueIDs, card.IssueID)
+	}
+	issues := make(map[int64]*Issue, len(cards))
+	if err := e.In("id", issueIDs).Find(&issues); err != nil {
+		return nil, err
+	}
+
+	issueList := make(IssueList, 0, len(issues))
+	for _, card := range cards {
+		if issue, ok := issues[card.IssueID]; ok {
+			card.Issue = issue
+			issueList = append(issueList, issue)
+		}
+	}
+	return cards, issueList.loadAttributes(e)
+}
+
+// GetCards returns the cards of the project board with their issues loaded,
+// in the order they are shown in their columns.
+func (p *Project) GetCards() ([]*ProjectIssue, error) {
+	return p.getCards(x)
+}
+
+// LoadColumns loads the columns of the project board together with their cards.
+func (p *Project) LoadColumns() error {
+	columns, err := p.getColumns(x)
+	if err != nil {
+		return err
+	}
+	cards, err := p.getCards(x)
+	if err != nil {
+		return err
+	}
+
+	columnsByID := make(map[int64]*ProjectColumn, len(columns))
+	for _, column := range columns {
+		columnsByID[column.ID] = column
+	}
+	for _, card := range cards {
+		if column, ok := columnsByID[card.ProjectColumnID]; ok && card.Issue != nil {
+			column.Cards = append(column.Cards, card)
+		}
+	}
+	p.Columns = columns
+	return nil
+}
+
+// NewProjectColumn adds a new column at the end of a project board.
+func NewProjectColumn(c *ProjectColumn) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := sess.Where("project_id = ?", c.ProjectID).Count(new(ProjectColumn))
+	if err != nil {
+		return err
+	}
+	c.Sorting = int(count)
+	if _, err = sess.Insert(c); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetProjectColumn returns the column of a project board.
+func GetProjectColumn(projectID, id int64) (*ProjectColumn, error) {
+	c := &ProjectColumn{
+		ID:        id,
+		ProjectID: projectID,
+	}
+	has, err := x.Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectColumnNotExist{id, projectID}
+	}
+	return c, nil
+}
+
+// UpdateProjectColumn updates the title of a column of a project board.
+func UpdateProjectColumn(c *ProjectColumn) error {
+	_, err := x.ID(c.ID).Cols("title").Update(c)
+	return err
+}
+
+// clampPosition limits a position in a list of the given length.
+func clampPosition(position, length int) int {
+	if position < 0 {
+		return 0
+	} else if position > length {
+		return length
+	}
+	return position
+}
+
+// MoveProjectColumn moves a column of a project board to the given position,
+// the positions of the other columns are updated accordingly.
+func MoveProjectColumn(c *ProjectColumn, position int) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	columns := make([]*ProjectColumn, 0, 5)
+	if err = sess.Where("project_id = ? AND id != ?", c.ProjectID, c.ID).Asc("sorting", "id").Find(&columns); err != nil {
+		return err
+	}
+
+	position = clampPosition(position, len(columns))
+	columns = append(columns[:position], append([]*ProjectColumn{c}, columns[position:]...)...)
+	for i, column := range columns {
+		column.Sorting = i
+		if _, err = sess.ID(column.ID).Cols("sorting").Update(column); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// DeleteProjectColumn deletes a column of a project board together with its cards.
+func DeleteProjectColumn(c *ProjectColumn) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err = sess.Where("project_column_id = ?", c.ID).Delete(new(ProjectIssue)); err != nil {
+		return err
+	}
+	if _, err = sess.ID(c.ID).Delete(new(ProjectColumn)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func moveIssueToProjectColumn(e *xorm.Session, issue *Issue, c *ProjectColumn, position int) (*ProjectIssue, error) {
+	card := &ProjectIssue{
+		ProjectID: c.ProjectID,
+		IssueID:   issue.ID,
+	}
+	has, err := e.Get(card)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*ProjectIssue, 0, 10)
+	if err = e.Where("project_column_id = ? AND issue_id != ?", c.ID, issue.ID).Asc("sorting", "id").Find(&cards); err != nil {
+		return nil, err
+	}
+
+	position = clampPosition(position, len(cards))
+	card.ProjectColumnID = c.ID
+	card.Sorting = position
+	if has {
+		if _, err = e.ID(card.ID).Cols("project_column_id", "sorting").Update(card); err != nil {
+			return nil, err
+		}
+	} else if _, err = e.Insert(card); err != nil {
+		return nil, err
+	}
+
+	cards = append(cards[:position], append([]*ProjectIssue{card}, cards[position:]...)...)
+	for i, other := range cards {
+		if other.Sorting == i {
+			continue
+		}
+		other.Sorting = i
+		if _, err = e.ID(other.ID).Cols("sorting").Update(other); err != nil {
+			return nil, err
+		}
+	}
+	return card, nil
+}
+
+// MoveIssueToProjectColumn places an issue at the given position of a column of a project board.
+// If the issue is already on the board, its card is moved there.
+func MoveIssueToProjectColumn(issue *Issue, c *ProjectColumn, position int) (*ProjectIssue, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	card, err := moveIssueToProjectColumn(sess, issue, c, position)
+	if err != nil {
+		return nil, err
+	}
+	card.Issue = issue
+	return card, sess.Commit()
+}
+
+// RemoveIssueFromProject removes the card of an issue from a project board.
+func RemoveIssueFromProject(p *Project, issue *Issue) error {
+	affected, err := x.Delete(&ProjectIssue{ProjectID: p.ID, IssueID: issue.ID})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrProjectIssueNotExist{p.ID, issue.ID}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProject(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	p := &Project{
+		RepoID:    1,
+		CreatorID: 2,
+		Title:     "newProject",
+	}
+	assert.NoError(t, NewProject(p))
+	AssertExistsAndLoadBean(t, p)
+}
+
+func TestGetProjects(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	projects, err := GetProjects(1, 0, false)
+	assert.NoError(t, err)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, 1, projects[0].ID)
+	}
+
+	projects, err = GetProjects(1, 0, true)
+	assert.NoError(t, err)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, 2, projects[0].ID)
+	}
+
+	open, closed := ProjectStats(1)
+	assert.EqualValues(t, 1, open)
+	assert.EqualValues(t, 1, closed)
+
+	_, err = GetProjectByRepoID(2, 1)
+	assert.True(t, IsErrProjectNotExist(err))
+}
+
+func TestProject_LoadColumns(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	p := AssertExistsAndLoadBean(t, &Project{ID: 1}).(*Project)
+	assert.NoError(t, p.LoadColumns())
+	if assert.Len(t, p.Columns, 2) {
+		assert.EqualValues(t, 1, p.Columns[0].ID)
+		if assert.Len(t, p.Columns[0].Cards, 2) {
+			assert.EqualValues(t, 1, p.Columns[0].Cards[0].Issue.ID)
+			assert.EqualValues(t, 2, p.Columns[0].Cards[1].Issue.ID)
+		}
+		assert.Len(t, p.Columns[1].Cards, 0)
+	}
+}
+
+func TestMoveProjectColumn(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, NewProjectColumn(&ProjectColumn{ProjectID: 1, Title: "In Progress"}))
+	c := AssertExistsAndLoadBean(t, &ProjectColumn{ProjectID: 1, Title: "In Progress"}).(*ProjectColumn)
+	assert.EqualValues(t, 2, c.Sorting)
+
+	assert.NoError(t, MoveProjectColumn(c, 1))
+	columns, err := (&Project{ID: 1}).GetColumns()
+	assert.NoError(t, err)
+	if assert.Len(t, columns, 3) {
+		assert.EqualValues(t, 1, columns[0].ID)
+		assert.EqualValues(t, c.ID, columns[1].ID)
+		assert.EqualValues(t, 2, columns[2].ID)
+	}
+}
+
+func TestMoveIssueToProjectColumn(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	todo := AssertExistsAndLoadBean(t, &ProjectColumn{ID: 1}).(*ProjectColumn)
+	done := AssertExistsAndLoadBean(t, &ProjectColumn{ID: 2}).(*ProjectColumn)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	// Placing a new issue at the top shifts the other cards down.
+	_, err := MoveIssueToProjectColumn(issue3, todo, 0)
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &ProjectIssue{ProjectID: 1, IssueID: 3, ProjectColumnID: 1, Sorting: 0})
+	AssertExistsAndLoadBean(t, &ProjectIssue{ProjectID: 1, IssueID: 1, ProjectColumnID: 1, Sorting: 1})
+	AssertExistsAndLoadBean(t, &ProjectIssue{ProjectID: 1, IssueID: 2, ProjectColumnID: 1, Sorting: 2})
+
+	// Moving a card to another column keeps a single card per issue.
+	card, err := MoveIssueToProjectColumn(issue1, done, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, card.Sorting)
+	AssertExistsAndLoadBean(t, &ProjectIssue{ProjectID: 1, IssueID: 1, ProjectColumnID: 2})
+	AssertCount(t, &ProjectIssue{ProjectID: 1, IssueID: 1}, 1)
+
+	assert.NoError(t, RemoveIssueFromProject(&Project{ID: 1}, issue1))
+	AssertNotExistsBean(t, &ProjectIssue{ProjectID: 1, IssueID: 1})
+	err = RemoveIssueFromProject(&Project{ID: 1}, issue1)
+	assert.True(t, IsErrProjectIssueNotExist(err))
+}
+
+func TestDeleteProjectByRepoID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, DeleteProjectByRepoID(1, 1))
+	AssertNotExistsBean(t, &Project{ID: 1})
+	AssertNotExistsBean(t, &ProjectColumn{ProjectID: 1})
+	AssertNotExistsBean(t, &ProjectIssue{ProjectID: 1})
+
+	// Deleting a project that doesn't exist is not an error.
+	assert.NoError(t, DeleteProjectByRepoID(1, 1))
+}
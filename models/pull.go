@@ -5,6 +5,7 @@
 package models
 
 import (
+	"container/list"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -224,6 +225,69 @@ func (pr *PullRequest) GetHeadRepo() error {
 	return pr.getHeadRepo(x)
 }
 
+// GetHeadCommitID returns the SHA of the head branch's latest commit
+func (pr *PullRequest) GetHeadCommitID() (string, error) {
+	if pr.HeadRepo == nil {
+		if err := pr.GetHeadRepo(); err != nil {
+			return "", err
+		} else if pr.HeadRepo == nil {
+			return "", ErrRepoNotExist{ID: pr.HeadRepoID}
+		}
+	}
+
+	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return "", fmt.Errorf("OpenRepository: %v", err)
+	}
+	return headGitRepo.GetBranchCommitID(pr.HeadBranch)
+}
+
+// GetProtectedBranch returns the protection rules of the base branch, or nil if it is not protected
+func (pr *PullRequest) GetProtectedBranch() (*ProtectedBranch, error) {
+	return GetProtectedBranchBy(pr.BaseRepoID, pr.BaseBranch)
+}
+
+// CheckProtectedBranchRules returns ErrNotAllowedToMerge if the pull request does not have
+// the required approvals or successful status checks of its protected base branch.
+func (pr *PullRequest) CheckProtectedBranchRules() error {
+	protectBranch, err := pr.GetProtectedBranch()
+	if err != nil {
+		return fmt.Errorf("GetProtectedBranch: %v", err)
+	} else if protectBranch == nil {
+		return nil
+	}
+
+	enough, err := protectBranch.HasEnoughApprovals(pr)
+	if err != nil {
+		return fmt.Errorf("HasEnoughApprovals: %v", err)
+	} else if !enough {
+		return ErrNotAllowedToMerge{
+			Reason: fmt.Sprintf("at least %d approvals are required", protectBranch.RequiredApprovals),
+		}
+	}
+
+	if !protectBranch.EnableStatusCheck {
+		return nil
+	}
+
+	if err = pr.GetBaseRepo(); err != nil {
+		return err
+	}
+	headCommitID, err := pr.GetHeadCommitID()
+	if err != nil {
+		return fmt.Errorf("GetHeadCommitID: %v", err)
+	}
+	unmet, err := protectBranch.GetUnmetStatusChecks(pr.BaseRepo, headCommitID)
+	if err != nil {
+		return fmt.Errorf("GetUnmetStatusChecks: %v", err)
+	} else if len(unmet) > 0 {
+		return ErrNotAllowedToMerge{
+			Reason: fmt.Sprintf("required status checks have not succeeded: %s", strings.Join(unmet, ", ")),
+		}
+	}
+	return nil
+}
+
 // GetBaseRepo loads the target repository
 func (pr *PullRequest) GetBaseRepo() (err error) {
 	if pr.BaseRepo != nil {
@@ -247,15 +311,74 @@ func (pr *PullRequest) CanAutoMerge() bool {
 	return pr.Status == PullRequestStatusMergeable
 }
 
+// MergeStyle represents the approach to merge commits into base branch.
+type MergeStyle string
+
+const (
+	// MergeStyleMerge create merge commit
+	MergeStyleMerge MergeStyle = "merge"
+	// MergeStyleRebase rebase before merging
+	MergeStyleRebase MergeStyle = "rebase"
+	// MergeStyleRebaseMerge rebase before merging with merge commit (--no-ff)
+	MergeStyleRebaseMerge MergeStyle = "rebase-merge"
+	// MergeStyleSquash squash commits into single commit before merging
+	MergeStyleSquash MergeStyle = "squash"
+)
+
+// GetDefaultMergeMessage returns default message used when merging pull request
+func (pr *PullRequest) GetDefaultMergeMessage() string {
+	if pr.HeadRepo == nil {
+		var err error
+		pr.HeadRepo, err = GetRepositoryByID(pr.HeadRepoID)
+		if err != nil {
+			log.Error(4, "GetRepositoryById[%d]: %v", pr.HeadRepoID, err)
+			return ""
+		}
+	}
+	return fmt.Sprintf("Merge branch '%s' of %s/%s into %s", pr.HeadBranch, pr.HeadUserName, pr.HeadRepo.Name, pr.BaseBranch)
+}
+
+// GetDefaultSquashMessage returns default message used when squash and merging pull request
+func (pr *PullRequest) GetDefaultSquashMessage() string {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error(4, "LoadIssue: %v", err)
+		return ""
+	}
+	return fmt.Sprintf("%s (#%d)", pr.Issue.Title, pr.Issue.Index)
+}
+
 // Merge merges pull request to base repository.
 // FIXME: add repoWorkingPull make sure two merges does not happen at same time.
-func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error) {
+func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle MergeStyle, message string) (err error) {
 	if err = pr.GetHeadRepo(); err != nil {
 		return fmt.Errorf("GetHeadRepo: %v", err)
 	} else if err = pr.GetBaseRepo(); err != nil {
 		return fmt.Errorf("GetBaseRepo: %v", err)
 	}
 
+	prUnit, err := pr.BaseRepo.GetUnit(UnitTypePullRequests)
+	if err != nil {
+		return err
+	}
+	prConfig := prUnit.PullRequestsConfig()
+
+	// Check if merge style is correct and allowed
+	if !prConfig.IsMergeStyleAllowed(mergeStyle) {
+		return ErrInvalidMergeStyle{pr.BaseRepo.ID, mergeStyle}
+	}
+
+	if err = pr.CheckProtectedBranchRules(); err != nil {
+		return err
+	}
+
+	// A pull request can only be merged once all of its dependencies have been closed.
+	if err = pr.loadIssue(x); err != nil {
+		return err
+	}
+	if err = pr.Issue.checkDependenciesLeft(x); err != nil {
+		return err
+	}
+
 	defer func() {
 		go HookQueue.Add(pr.BaseRepo.ID)
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false)
@@ -267,6 +390,12 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error
 		return fmt.Errorf("OpenRepository: %v", err)
 	}
 
+	// Remember the base branch head, so pushed commits can be listed for non-merge-commit styles.
+	baseCommitID, err := baseGitRepo.GetBranchCommitID(pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommitID: %v", err)
+	}
+
 	// Clone base repo.
 	tmpBasePath := path.Join(setting.AppDataPath, "tmp/repos", com.ToStr(time.Now().Nanosecond())+".git")
 
@@ -304,18 +433,80 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error
 		return fmt.Errorf("git fetch [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
 	}
 
-	if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
-		fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
-		"git", "merge", "--no-ff", "--no-commit", "head_repo/"+pr.HeadBranch); err != nil {
-		return fmt.Errorf("git merge --no-ff --no-commit [%s]: %v - %s", tmpBasePath, err, stderr)
-	}
-
 	sig := doer.NewGitSig()
-	if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
-		fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
-		"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
-		"-m", fmt.Sprintf("Merge branch '%s' of %s/%s into %s", pr.HeadBranch, pr.HeadUserName, pr.HeadRepo.Name, pr.BaseBranch)); err != nil {
-		return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+	switch mergeStyle {
+	case MergeStyleMerge:
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
+			"git", "merge", "--no-ff", "--no-commit", "head_repo/"+pr.HeadBranch); err != nil {
+			return fmt.Errorf("git merge --no-ff --no-commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
+			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+			"-m", message); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	case MergeStyleRebase, MergeStyleRebaseMerge:
+		// Checkout head branch
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git checkout): %s", tmpBasePath),
+			"git", "checkout", "-b", "head_repo_"+pr.HeadBranch, "head_repo/"+pr.HeadBranch); err != nil {
+			return fmt.Errorf("git checkout: %s", stderr)
+		}
+		// Rebase before merging
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git rebase): %s", tmpBasePath),
+			"git", "rebase", "-q", pr.BaseBranch); err != nil {
+			return fmt.Errorf("git rebase [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
+		}
+		// Checkout base branch again
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git checkout): %s", tmpBasePath),
+			"git", "checkout", pr.BaseBranch); err != nil {
+			return fmt.Errorf("git checkout: %s", stderr)
+		}
+
+		if mergeStyle == MergeStyleRebase {
+			// Merge fast forward
+			if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+				fmt.Sprintf("PullRequest.Merge (git merge --ff-only): %s", tmpBasePath),
+				"git", "merge", "--ff-only", "-q", "head_repo_"+pr.HeadBranch); err != nil {
+				return fmt.Errorf("git merge --ff-only [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
+			}
+			break
+		}
+
+		// Prepare merge with commit
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
+			"git", "merge", "--no-ff", "--no-commit", "-q", "head_repo_"+pr.HeadBranch); err != nil {
+			return fmt.Errorf("git merge --no-ff --no-commit [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
+		}
+
+		// Make merge commit
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
+			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+			"-m", message); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	case MergeStyleSquash:
+		// Merge with squash
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git squash): %s", tmpBasePath),
+			"git", "merge", "-q", "--squash", "head_repo/"+pr.HeadBranch); err != nil {
+			return fmt.Errorf("git merge --squash [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
+		}
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git squash): %s", tmpBasePath),
+			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+			"-m", message); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	default:
+		return ErrInvalidMergeStyle{pr.BaseRepo.ID, mergeStyle}
 	}
 
 	// Push back to upstream.
@@ -358,28 +549,40 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error
 		return nil
 	}
 
-	l, err := headGitRepo.CommitsBetweenIDs(pr.MergedCommitID, pr.MergeBase)
-	if err != nil {
-		log.Error(4, "CommitsBetweenIDs: %v", err)
-		return nil
-	}
+	before := pr.MergeBase
+	var l *list.List
+	if mergeStyle == MergeStyleMerge {
+		l, err = headGitRepo.CommitsBetweenIDs(pr.MergedCommitID, pr.MergeBase)
+		if err != nil {
+			log.Error(4, "CommitsBetweenIDs: %v", err)
+			return nil
+		}
 
-	// TODO: when squash commits, no need to append merge commit.
-	// It is possible that head branch is not fully sync with base branch for merge commits,
-	// so we need to get latest head commit and append merge commit manually
-	// to avoid strange diff commits produced.
-	mergeCommit, err := baseGitRepo.GetBranchCommit(pr.BaseBranch)
-	if err != nil {
-		log.Error(4, "GetBranchCommit: %v", err)
-		return nil
+		// It is possible that head branch is not fully sync with base branch for merge commits,
+		// so we need to get latest head commit and append merge commit manually
+		// to avoid strange diff commits produced.
+		mergeCommit, err := baseGitRepo.GetBranchCommit(pr.BaseBranch)
+		if err != nil {
+			log.Error(4, "GetBranchCommit: %v", err)
+			return nil
+		}
+		l.PushFront(mergeCommit)
+	} else {
+		// Rebased and squashed commits only exist in the base repository,
+		// so list everything that has been pushed on top of the old base head.
+		before = baseCommitID
+		l, err = baseGitRepo.CommitsBetweenIDs(pr.MergedCommitID, baseCommitID)
+		if err != nil {
+			log.Error(4, "CommitsBetweenIDs: %v", err)
+			return nil
+		}
 	}
-	l.PushFront(mergeCommit)
 
 	p := &api.PushPayload{
 		Ref:        git.BranchPrefix + pr.BaseBranch,
-		Before:     pr.MergeBase,
+		Before:     before,
 		After:      pr.MergedCommitID,
-		CompareURL: setting.AppURL + pr.BaseRepo.ComposeCompareURL(pr.MergeBase, pr.MergedCommitID),
+		CompareURL: setting.AppURL + pr.BaseRepo.ComposeCompareURL(before, pr.MergedCommitID),
 		Commits:    ListToPushCommits(l).ToAPIPayloadCommits(pr.BaseRepo.HTMLURL()),
 		Repo:       pr.BaseRepo.APIFormat(AccessModeNone),
 		Pusher:     pr.HeadRepo.MustOwner().APIFormat(),
@@ -52,6 +52,32 @@ func TestPullRequest_GetHeadRepo(t *testing.T) {
 	assert.Equal(t, pr.HeadRepoID, pr.HeadRepo.ID)
 }
 
+func TestPullRequest_GetDefaultMergeMessage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.Equal(t, "Merge branch 'branch2' of user1/repo1 into master", pr.GetDefaultMergeMessage())
+}
+
+func TestPullRequest_GetDefaultSquashMessage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.Equal(t, "issue3 (#3)", pr.GetDefaultSquashMessage())
+}
+
+func TestPullRequestsConfig_IsMergeStyleAllowed(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	prUnit, err := repo.GetUnit(UnitTypePullRequests)
+	assert.NoError(t, err)
+	prConfig := prUnit.PullRequestsConfig()
+	assert.True(t, prConfig.IsMergeStyleAllowed(MergeStyleMerge))
+	assert.True(t, prConfig.IsMergeStyleAllowed(MergeStyleSquash))
+
+	prConfig.AllowSquash = false
+	assert.False(t, prConfig.IsMergeStyleAllowed(MergeStyleSquash))
+	assert.False(t, prConfig.IsMergeStyleAllowed(MergeStyle("octopus")))
+}
+
 // TODO TestMerge
 
 // TODO TestNewPullRequest
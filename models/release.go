@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/sdk/gitea"
@@ -98,6 +99,7 @@ func (r *Release) APIFormat() *api.Release {
 		ID:           r.ID,
 		TagName:      r.TagName,
 		Target:       r.Target,
+		Title:        r.Title,
 		Note:         r.Note,
 		URL:          r.APIURL(),
 		TarURL:       r.TarURL(),
@@ -110,6 +112,29 @@ func (r *Release) APIFormat() *api.Release {
 	}
 }
 
+// sendWebhook adds the release event of the given action to the hook queue.
+// Drafts and plain tags are not announced.
+func (r *Release) sendWebhook(doer *User, action api.HookReleaseAction) {
+	if r.IsDraft || r.IsTag {
+		return
+	}
+	if err := r.LoadAttributes(); err != nil {
+		log.Error(4, "LoadAttributes: %v", err)
+		return
+	}
+
+	if err := PrepareWebhooks(r.Repo, HookEventRelease, &api.ReleasePayload{
+		Action:     action,
+		Release:    r.APIFormat(),
+		Repository: r.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [release_id: %d]: %v", r.ID, err)
+	} else {
+		go HookQueue.Add(r.RepoID)
+	}
+}
+
 // IsReleaseExist returns true if release with given tag name already exists.
 func IsReleaseExist(repoID int64, tagName string) (bool, error) {
 	if len(tagName) == 0 {
@@ -197,9 +222,15 @@ func CreateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	if err = addReleaseAttachments(rel.ID, attachmentUUIDs); err != nil {
+		return err
+	}
 
-	return err
+	if err = rel.LoadAttributes(); err != nil {
+		return err
+	}
+	rel.sendWebhook(rel.Publisher, api.HookReleasePublished)
+	return nil
 }
 
 // GetRelease returns release by given ID.
@@ -358,8 +389,13 @@ func SortReleases(rels []*Release) {
 	sort.Sort(sorter)
 }
 
-// UpdateRelease updates information of a release.
-func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
+// UpdateRelease updates information of a release, as the given user.
+func UpdateRelease(doer *User, gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
+	old, err := GetReleaseByID(rel.ID)
+	if err != nil {
+		return err
+	}
+
 	if err = createTag(gitRepo, rel); err != nil {
 		return err
 	}
@@ -370,9 +406,17 @@ func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	if err = addReleaseAttachments(rel.ID, attachmentUUIDs); err != nil {
+		return err
+	}
 
-	return err
+	// A draft or a plain tag turning into a release is a publication.
+	if old.IsDraft || old.IsTag {
+		rel.sendWebhook(doer, api.HookReleasePublished)
+	} else {
+		rel.sendWebhook(doer, api.HookReleaseUpdated)
+	}
+	return nil
 }
 
 // DeleteReleaseByID deletes a release and corresponding Git tag by given ID.
@@ -394,6 +438,9 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		return fmt.Errorf("DeleteReleaseByID: permission denied")
 	}
 
+	deleted := *rel
+	deleted.Repo = repo
+
 	if delTag {
 		_, stderr, err := process.GetManager().ExecDir(-1, repo.RepoPath(),
 			fmt.Sprintf("DeleteReleaseByID (git tag -d): %d", rel.ID),
@@ -417,6 +464,7 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		}
 	}
 
+	deleted.sendWebhook(u, api.HookReleaseDeleted)
 	return nil
 }
 
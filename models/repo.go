@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/indexer"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/options"
@@ -202,6 +203,13 @@ type Repository struct {
 	IsMirror bool `xorm:"INDEX"`
 	*Mirror  `xorm:"-"`
 
+	IsArchived bool `xorm:"INDEX NOT NULL DEFAULT false"`
+
+	IsTemplate bool  `xorm:"INDEX NOT NULL DEFAULT false"`
+	TemplateID int64 `xorm:"INDEX"`
+
+	Topics []string `xorm:"TEXT JSON"`
+
 	ExternalMetas map[string]string `xorm:"-"`
 	Units         []*RepoUnit       `xorm:"-"`
 
@@ -300,6 +308,9 @@ func (repo *Repository) innerAPIFormat(mode AccessMode, isParent bool) *api.Repo
 		Fork:          repo.IsFork,
 		Parent:        parent,
 		Mirror:        repo.IsMirror,
+		Archived:      repo.IsArchived,
+		Template:      repo.IsTemplate,
+		Topics:        repo.Topics,
 		HTMLURL:       repo.HTMLURL(),
 		SSHURL:        cloneLink.SSH,
 		CloneURL:      cloneLink.HTTPS,
@@ -443,6 +454,11 @@ func (repo *Repository) MustGetUnit(tp UnitType) *RepoUnit {
 			Type:   tp,
 			Config: new(ExternalTrackerConfig),
 		}
+	} else if tp == UnitTypePullRequests {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(PullRequestsConfig),
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -699,7 +715,7 @@ func (repo *Repository) AllowsPulls() bool {
 
 // CanEnableEditor returns true if repository meets the requirements of web editor.
 func (repo *Repository) CanEnableEditor() bool {
-	return !repo.IsMirror
+	return !repo.IsMirror && !repo.IsArchived
 }
 
 // GetWriters returns all users that have write access to the repository.
@@ -1311,7 +1327,17 @@ func createRepository(e *xorm.Session, doer, u *User, repo *Repository) (err err
 			units = append(units, RepoUnit{
 				RepoID: repo.ID,
 				Type:   tp,
-				Config: &IssuesConfig{EnableTimetracker: setting.Service.DefaultEnableTimetracking, AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime},
+				Config: &IssuesConfig{
+					EnableTimetracker:                setting.Service.DefaultEnableTimetracking,
+					AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime,
+					EnableDependencies:               setting.Service.DefaultEnableDependencies,
+				},
+			})
+		} else if tp == UnitTypePullRequests {
+			units = append(units, RepoUnit{
+				RepoID: repo.ID,
+				Type:   tp,
+				Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true},
 			})
 		} else {
 			units = append(units, RepoUnit{
@@ -1702,6 +1728,15 @@ func UpdateRepository(repo *Repository, visibilityChanged bool) (err error) {
 	return sess.Commit()
 }
 
+// SetArchiveRepoState sets if a repo is archived. An archived repository
+// stays browsable and cloneable, but accepts no pushes, issues, pull requests,
+// comments or releases.
+func (repo *Repository) SetArchiveRepoState(isArchived bool) (err error) {
+	repo.IsArchived = isArchived
+	_, err = x.ID(repo.ID).Cols("is_archived").Update(repo)
+	return
+}
+
 // UpdateRepositoryUnits updates a repository's units
 func UpdateRepositoryUnits(repo *Repository, units []RepoUnit) (err error) {
 	sess := x.NewSession()
@@ -1770,6 +1805,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&Watch{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Mirror{RepoID: repoID},
+		&PushMirror{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Release{RepoID: repoID},
 		&Collaboration{RepoID: repoID},
@@ -1780,6 +1816,14 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = removeRepoTopics(sess, repoID); err != nil {
+		return fmt.Errorf("removeRepoTopics: %v", err)
+	}
+
+	if err = deleteRepoProjects(sess, repoID); err != nil {
+		return fmt.Errorf("deleteRepoProjects: %v", err)
+	}
+
 	// Delete comments and attachments.
 	issueIDs := make([]int64, 0, 25)
 	attachmentPaths := make([]string, 0, len(issueIDs))
@@ -1795,9 +1839,21 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		if _, err = sess.In("issue_id", issueIDs).Delete(&Comment{}); err != nil {
 			return err
 		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&Review{}); err != nil {
+			return err
+		}
 		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueUser{}); err != nil {
 			return err
 		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&Reaction{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueDependency{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("dependency_id", issueIDs).Delete(&IssueDependency{}); err != nil {
+			return err
+		}
 
 		attachments := make([]*Attachment, 0, 5)
 		if err = sess.
@@ -1892,6 +1948,9 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		go HookQueue.Add(repo.ID)
 	}
 
+	if err = indexer.DeleteIssuesFromIndexer(issueIDs...); err != nil {
+		log.Error(4, "DeleteIssuesFromIndexer: %v", err)
+	}
 	DeleteRepoFromIndexer(repo)
 	return nil
 }
@@ -2425,7 +2484,21 @@ func ForkRepository(doer, u *User, oldRepo *Repository, name, desc string) (_ *R
 		}
 	}
 
-	return repo, sess2.Commit()
+	if err = sess2.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = PrepareWebhooks(oldRepo, HookEventFork, &api.ForkPayload{
+		Forkee: repo.APIFormat(AccessModeNone),
+		Repo:   oldRepo.APIFormat(AccessModeNone),
+		Sender: doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [repo_id: %d]: %v", oldRepo.ID, err)
+	} else {
+		go HookQueue.Add(oldRepo.ID)
+	}
+
+	return repo, nil
 }
 
 // GetForks returns all the forks of the repository
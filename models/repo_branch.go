@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/Unknwon/com"
@@ -44,7 +45,7 @@ func GetBranchesByPath(path string) ([]*Branch, error) {
 
 // CanCreateBranch returns true if repository meets the requirements for creating new branches.
 func (repo *Repository) CanCreateBranch() bool {
-	return !repo.IsMirror
+	return !repo.IsMirror && !repo.IsArchived
 }
 
 // GetBranch returns a branch by it's name
@@ -185,6 +186,54 @@ func (repo *Repository) CreateNewBranchFromCommit(doer *User, commit, branchName
 	return nil
 }
 
+// DeleteBranch deletes a branch of the repository and records it as a
+// deleted branch so that it can be restored later.
+func (repo *Repository) DeleteBranch(doer *User, branchName string) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !gitRepo.IsBranchExist(branchName) {
+		return ErrBranchNotExist{branchName}
+	}
+
+	commit, err := gitRepo.GetBranchCommit(branchName)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommit: %v", err)
+	}
+
+	if err = gitRepo.DeleteBranch(branchName, git.DeleteBranchOptions{
+		Force: true,
+	}); err != nil {
+		return fmt.Errorf("DeleteBranch: %v", err)
+	}
+
+	if err = repo.AddDeletedBranch(branchName, commit.ID.String(), doer.ID); err != nil {
+		log.Warn("AddDeletedBranch: %v", err)
+	}
+
+	// Simulate push event.
+	if err = repo.GetOwner(); err != nil {
+		return fmt.Errorf("GetOwner: %v", err)
+	}
+	if err = PushUpdate(
+		branchName,
+		PushUpdateOptions{
+			PusherID:     doer.ID,
+			PusherName:   doer.Name,
+			RepoUserName: repo.Owner.Name,
+			RepoName:     repo.Name,
+			RefFullName:  git.BranchPrefix + branchName,
+			OldCommitID:  commit.ID.String(),
+			NewCommitID:  git.EmptySHA,
+		},
+	); err != nil {
+		return fmt.Errorf("PushUpdate: %v", err)
+	}
+
+	return nil
+}
+
 // GetCommit returns all the commits of a branch
 func (branch *Branch) GetCommit() (*git.Commit, error) {
 	gitRepo, err := git.OpenRepository(branch.Path)
@@ -55,6 +55,34 @@ func (repo *Repository) DiscardLocalRepoBranchChanges(branch string) error {
 	return discardLocalRepoBranchChanges(repo.LocalCopyPath(), branch)
 }
 
+// checkFileSHA returns ErrSHADoesNotMatch if the blob of the file on the
+// branch does not have the given SHA.
+func checkFileSHA(repoPath, branch, treePath, sha string) error {
+	gitRepo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommit [branch: %s]: %v", branch, err)
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return ErrRepoFileDoesNotExist{treePath}
+		}
+		return fmt.Errorf("GetTreeEntryByPath [path: %s]: %v", treePath, err)
+	}
+	if entry.ID.String() != sha {
+		return ErrSHADoesNotMatch{
+			Path:       treePath,
+			GivenSHA:   sha,
+			CurrentSHA: entry.ID.String(),
+		}
+	}
+	return nil
+}
+
 // checkoutNewBranch checks out to a new branch from the a branch name.
 func checkoutNewBranch(repoPath, localPath, oldBranch, newBranch string) error {
 	if err := git.Checkout(localPath, git.CheckoutOptions{
@@ -82,10 +110,19 @@ type UpdateRepoFileOptions struct {
 	Message      string
 	Content      string
 	IsNewFile    bool
+	// SHA of the blob of the file before the update. If given, the update
+	// fails if the file has been changed in the meantime.
+	SHA string
+	// Author of the commit, the doer is used if nil
+	Author *git.Signature
 }
 
 // UpdateRepoFile adds or updates a file in repository.
 func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (err error) {
+	if repo.IsArchived {
+		return ErrRepoArchived{ID: repo.ID, OwnerName: repo.MustOwner().Name, Name: repo.Name}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -95,6 +132,12 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
 	}
 
+	if len(opts.SHA) > 0 && !opts.IsNewFile {
+		if err = checkFileSHA(repo.RepoPath(), opts.OldBranch, opts.OldTreeName, opts.SHA); err != nil {
+			return err
+		}
+	}
+
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
 			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
@@ -133,6 +176,7 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 		return fmt.Errorf("git add --all: %v", err)
 	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
 		Committer: doer.NewGitSig(),
+		Author:    opts.Author,
 		Message:   opts.Message,
 	}); err != nil {
 		return fmt.Errorf("CommitChanges: %v", err)
@@ -249,10 +293,19 @@ type DeleteRepoFileOptions struct {
 	NewBranch    string
 	TreePath     string
 	Message      string
+	// SHA of the blob of the file. If given, the deletion fails if the file
+	// has been changed in the meantime.
+	SHA string
+	// Author of the commit, the doer is used if nil
+	Author *git.Signature
 }
 
 // DeleteRepoFile deletes a repository file
 func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (err error) {
+	if repo.IsArchived {
+		return ErrRepoArchived{ID: repo.ID, OwnerName: repo.MustOwner().Name, Name: repo.Name}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -262,6 +315,12 @@ func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (
 		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
 	}
 
+	if len(opts.SHA) > 0 {
+		if err = checkFileSHA(repo.RepoPath(), opts.OldBranch, opts.TreePath, opts.SHA); err != nil {
+			return err
+		}
+	}
+
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
 			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
@@ -277,6 +336,7 @@ func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (
 		return fmt.Errorf("git add --all: %v", err)
 	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
 		Committer: doer.NewGitSig(),
+		Author:    opts.Author,
 		Message:   opts.Message,
 	}); err != nil {
 		return fmt.Errorf("CommitChanges: %v", err)
@@ -476,6 +536,8 @@ type UploadRepoFileOptions struct {
 func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions) (err error) {
 	if len(opts.Files) == 0 {
 		return nil
+	} else if repo.IsArchived {
+		return ErrRepoArchived{ID: repo.ID, OwnerName: repo.MustOwner().Name, Name: repo.Name}
 	}
 
 	uploads, err := GetUploadsByUUIDs(opts.Files)
@@ -0,0 +1,434 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/Unknwon/com"
+)
+
+// templateFilesPath is the file of a template repository listing the glob
+// patterns of the files whose placeholders are expanded on generation.
+const templateFilesPath = ".gitea/template"
+
+// maxTemplateRepositories is the number of template repositories offered
+// when creating a new repository.
+const maxTemplateRepositories = 50
+
+var templateVarPattern = regexp.MustCompile(`\$\{([A-Z_]+)\}|\$([A-Z_]+)`)
+
+// GenerateRepoOptions contains the options to generate a repository from a template
+type GenerateRepoOptions struct {
+	Name        string
+	Description string
+	IsPrivate   bool
+	GitContent  bool
+	Labels      bool
+	// Webhooks are only copied if the doer is an admin of the template, as
+	// they contain the secrets of the template
+	Webhooks bool
+	Units    bool
+	Topics   bool
+}
+
+// IsValid returns true if at least one item of the template is chosen
+func (opts GenerateRepoOptions) IsValid() bool {
+	return opts.GitContent || opts.Labels || opts.Webhooks || opts.Units || opts.Topics
+}
+
+// GetTemplateRepositories returns the template repositories the user can see
+func GetTemplateRepositories(user *User) (RepositoryList, error) {
+	repos, _, err := SearchRepositoryByName(&SearchRepoOptions{
+		OwnerID:   user.ID,
+		Private:   true,
+		AllPublic: true,
+		Template:  util.OptionalBoolTrue,
+		OrderBy:   SearchOrderByAlphabetically,
+		Page:      1,
+		PageSize:  maxTemplateRepositories,
+	})
+	return repos, err
+}
+
+// templateGlob compiles a glob pattern of the template file into a regular
+// expression, where "**" matches any path and "*" any part of a path segment.
+func templateGlob(pattern string) (*regexp.Regexp, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				buf.WriteString(".*")
+				i++
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteByte('$')
+	return regexp.Compile(buf.String())
+}
+
+// readTemplateGlobs reads the glob patterns listed in the template file, if any.
+func readTemplateGlobs(tmpDir string) ([]*regexp.Regexp, error) {
+	f, err := os.Open(filepath.Join(tmpDir, templateFilesPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var globs []*regexp.Regexp
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		g, err := templateGlob(strings.TrimPrefix(line, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", line, err)
+		}
+		globs = append(globs, g)
+	}
+	return globs, scanner.Err()
+}
+
+// expandTemplateVars replaces the known $VAR and ${VAR} placeholders of content,
+// other variables are left untouched.
+func expandTemplateVars(content []byte, vars map[string]string) []byte {
+	return templateVarPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		name := strings.Trim(string(match), "${}")
+		if value, ok := vars[name]; ok {
+			return []byte(value)
+		}
+		return match
+	})
+}
+
+func templateVars(repo, templateRepo *Repository) map[string]string {
+	cloneLink := repo.CloneLink()
+	return map[string]string{
+		"REPO_NAME":            repo.Name,
+		"REPO_OWNER":           repo.MustOwner().Name,
+		"REPO_DESCRIPTION":     repo.Description,
+		"REPO_LINK":            repo.HTMLURL(),
+		"REPO_HTTPS_URL":       cloneLink.HTTPS,
+		"REPO_SSH_URL":         cloneLink.SSH,
+		"TEMPLATE_NAME":        templateRepo.Name,
+		"TEMPLATE_OWNER":       templateRepo.MustOwner().Name,
+		"TEMPLATE_DESCRIPTION": templateRepo.Description,
+		"TEMPLATE_LINK":        templateRepo.HTMLURL(),
+	}
+}
+
+// expandTemplateFiles expands the placeholders in the text files matched by
+// the template file, and removes the template file itself.
+func expandTemplateFiles(tmpDir string, repo, templateRepo *Repository) error {
+	globs, err := readTemplateGlobs(tmpDir)
+	if err != nil {
+		return fmt.Errorf("readTemplateGlobs: %v", err)
+	} else if globs == nil {
+		return nil
+	}
+
+	if err = os.Remove(filepath.Join(tmpDir, templateFilesPath)); err != nil {
+		return err
+	}
+
+	vars := templateVars(repo, templateRepo)
+	return filepath.Walk(tmpDir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !info.Mode().IsRegular() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(tmpDir, fpath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		for _, g := range globs {
+			if !g.MatchString(relPath) {
+				continue
+			}
+
+			content, err := ioutil.ReadFile(fpath)
+			if err != nil {
+				return err
+			} else if !base.IsTextFile(content) {
+				return nil
+			}
+			return ioutil.WriteFile(fpath, expandTemplateVars(content, vars), info.Mode())
+		}
+		return nil
+	})
+}
+
+// generateRepoCommit copies the files of the template repository into the
+// new repository as a single initial commit.
+func generateRepoCommit(repo, templateRepo *Repository, doer *User, repoPath, tmpDir string) error {
+	_, stderr, err := process.GetManager().Exec(
+		fmt.Sprintf("generateRepoCommit(git clone): %s", templateRepo.RepoPath()),
+		"git", "clone", "--depth", "1", "file://"+templateRepo.RepoPath(), tmpDir,
+	)
+	if err != nil {
+		return fmt.Errorf("git clone: %v - %s", err, stderr)
+	}
+
+	// Drop the history of the template, the new repository starts afresh.
+	if err = os.RemoveAll(path.Join(tmpDir, ".git")); err != nil {
+		return err
+	}
+
+	if err = expandTemplateFiles(tmpDir, repo, templateRepo); err != nil {
+		return fmt.Errorf("expandTemplateFiles: %v", err)
+	}
+
+	if err = git.InitRepository(tmpDir, false); err != nil {
+		return fmt.Errorf("InitRepository: %v", err)
+	}
+
+	if _, stderr, err = process.GetManager().ExecDir(-1,
+		tmpDir, fmt.Sprintf("generateRepoCommit(git remote add): %s", repoPath),
+		"git", "remote", "add", "origin", repoPath); err != nil {
+		return fmt.Errorf("git remote add: %v - %s", err, stderr)
+	}
+
+	if _, stderr, err = process.GetManager().ExecDir(-1,
+		tmpDir, fmt.Sprintf("generateRepoCommit(git add): %s", tmpDir),
+		"git", "add", "--all"); err != nil {
+		return fmt.Errorf("git add: %s", stderr)
+	}
+
+	// The doer is both author and committer of the generated commit.
+	sig := doer.NewGitSig()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME="+sig.Name,
+		"GIT_AUTHOR_EMAIL="+sig.Email,
+		"GIT_COMMITTER_NAME="+sig.Name,
+		"GIT_COMMITTER_EMAIL="+sig.Email,
+	)
+	if _, stderr, err = process.GetManager().ExecDirEnv(-1,
+		tmpDir, fmt.Sprintf("generateRepoCommit(git commit): %s", tmpDir), env,
+		"git", "commit", "-m", "Initial commit"); err != nil {
+		return fmt.Errorf("git commit: %s", stderr)
+	}
+
+	if _, stderr, err = process.GetManager().ExecDir(-1,
+		tmpDir, fmt.Sprintf("generateRepoCommit(git push): %s", tmpDir),
+		"git", "push", "origin", "master"); err != nil {
+		return fmt.Errorf("git push: %s", stderr)
+	}
+	return nil
+}
+
+func copyTemplateLabels(e Engine, repo, templateRepo *Repository) error {
+	var labels []*Label
+	if err := e.Where("repo_id = ?", templateRepo.ID).Asc("name").Find(&labels); err != nil {
+		return err
+	}
+
+	for _, l := range labels {
+		if _, err := e.Insert(&Label{
+			RepoID: repo.ID,
+			Name:   l.Name,
+			Color:  l.Color,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyTemplateWebhooks(e Engine, repo, templateRepo *Repository) error {
+	var hooks []*Webhook
+	if err := e.Where("repo_id = ?", templateRepo.ID).Find(&hooks); err != nil {
+		return err
+	}
+
+	for _, w := range hooks {
+		w.ID = 0
+		w.RepoID = repo.ID
+		w.LastStatus = HookStatusNone
+		if _, err := e.Insert(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyTemplateUnits(e Engine, repo, templateRepo *Repository) error {
+	units, err := getUnitsByRepoID(e, templateRepo.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = e.Delete(&RepoUnit{RepoID: repo.ID}); err != nil {
+		return err
+	}
+
+	for _, u := range units {
+		if _, err = e.Insert(&RepoUnit{
+			RepoID: repo.ID,
+			Type:   u.Type,
+			Config: u.Config,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyTemplateTopics(e Engine, repo, templateRepo *Repository) error {
+	for _, name := range templateRepo.Topics {
+		if err := addTopicByNameToRepo(e, repo.ID, name); err != nil {
+			return err
+		}
+	}
+	repo.Topics = templateRepo.Topics
+	return nil
+}
+
+func generateRepository(e Engine, doer *User, repo, templateRepo *Repository, repoPath string, opts GenerateRepoOptions) (err error) {
+	// Somehow the directory could exist.
+	if com.IsExist(repoPath) {
+		return fmt.Errorf("generateRepository: path already exists: %s", repoPath)
+	}
+
+	if err = git.InitRepository(repoPath, true); err != nil {
+		return fmt.Errorf("InitRepository: %v", err)
+	} else if err = createDelegateHooks(repoPath); err != nil {
+		return fmt.Errorf("createDelegateHooks: %v", err)
+	}
+
+	hasContent := opts.GitContent && !templateRepo.IsBare
+	if hasContent {
+		tmpDir := filepath.Join(os.TempDir(), "gitea-"+repo.Name+"-"+com.ToStr(time.Now().Nanosecond()))
+		if err = os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+			return fmt.Errorf("Failed to create dir %s: %v", tmpDir, err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if err = generateRepoCommit(repo, templateRepo, doer, repoPath, tmpDir); err != nil {
+			return fmt.Errorf("generateRepoCommit: %v", err)
+		}
+	}
+
+	if opts.Labels {
+		if err = copyTemplateLabels(e, repo, templateRepo); err != nil {
+			return fmt.Errorf("copyTemplateLabels: %v", err)
+		}
+	}
+	if opts.Webhooks {
+		mode, err := accessLevel(e, doer.ID, templateRepo)
+		if err != nil {
+			return fmt.Errorf("accessLevel: %v", err)
+		}
+		if doer.IsAdmin || mode >= AccessModeAdmin {
+			if err = copyTemplateWebhooks(e, repo, templateRepo); err != nil {
+				return fmt.Errorf("copyTemplateWebhooks: %v", err)
+			}
+		}
+	}
+	if opts.Units {
+		if err = copyTemplateUnits(e, repo, templateRepo); err != nil {
+			return fmt.Errorf("copyTemplateUnits: %v", err)
+		}
+	}
+	if opts.Topics {
+		if err = copyTemplateTopics(e, repo, templateRepo); err != nil {
+			return fmt.Errorf("copyTemplateTopics: %v", err)
+		}
+	}
+
+	// Only update the changed columns, the counters have been updated in
+	// the database while creating the repository.
+	repo.IsBare = !hasContent
+	repo.DefaultBranch = "master"
+	_, err = e.ID(repo.ID).Cols("is_bare", "default_branch", "topics").Update(repo)
+	return err
+}
+
+// GenerateRepository creates a repository for the user/organization owner
+// from the template repository templateRepo.
+func GenerateRepository(doer, owner *User, templateRepo *Repository, opts GenerateRepoOptions) (_ *Repository, err error) {
+	if !templateRepo.IsTemplate {
+		return nil, ErrRepoNotTemplate{templateRepo.ID}
+	} else if !owner.CanCreateRepo() {
+		return nil, ErrReachLimitOfRepo{owner.MaxRepoCreation}
+	} else if err = templateRepo.GetOwner(); err != nil {
+		return nil, err
+	}
+
+	repo := &Repository{
+		OwnerID:     owner.ID,
+		Owner:       owner,
+		Name:        opts.Name,
+		LowerName:   strings.ToLower(opts.Name),
+		Description: opts.Description,
+		IsPrivate:   opts.IsPrivate,
+		TemplateID:  templateRepo.ID,
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	if err = createRepository(sess, doer, owner, repo); err != nil {
+		return nil, err
+	}
+
+	repoPath := RepoPath(owner.Name, repo.Name)
+	if err = generateRepository(sess, doer, repo, templateRepo, repoPath, opts); err != nil {
+		if err2 := os.RemoveAll(repoPath); err2 != nil {
+			log.Error(4, "generateRepository: %v", err)
+			return nil, fmt.Errorf(
+				"delete repo directory %s/%s failed(2): %v", owner.Name, repo.Name, err2)
+		}
+		return nil, fmt.Errorf("generateRepository: %v", err)
+	}
+
+	_, stderr, err := process.GetManager().ExecDir(-1,
+		repoPath, fmt.Sprintf("GenerateRepository(git update-server-info): %s", repoPath),
+		"git", "update-server-info")
+	if err != nil {
+		return nil, fmt.Errorf("git update-server-info: %s", stderr)
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = repo.UpdateSize(); err != nil {
+		log.Error(4, "Failed to update size for repository: %v", err)
+	}
+	return repo, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateGlob(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"README.md", "README.md", true},
+		{"README.md", "docs/README.md", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"**.md", "docs/README.md", true},
+		{"docs/**", "docs/a/b/c.txt", true},
+		{"docs/*", "docs/a/b/c.txt", false},
+		{"file?.go", "file1.go", true},
+		{"a.b", "axb", false},
+	} {
+		g, err := templateGlob(tc.pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.match, g.MatchString(tc.path), "%s ~ %s", tc.pattern, tc.path)
+	}
+}
+
+func TestExpandTemplateVars(t *testing.T) {
+	vars := map[string]string{
+		"REPO_NAME":  "repo",
+		"REPO_OWNER": "owner",
+	}
+	assert.Equal(t, "# owner/repo", string(expandTemplateVars([]byte("# $REPO_OWNER/${REPO_NAME}"), vars)))
+	assert.Equal(t, "$HOME ${UNKNOWN}", string(expandTemplateVars([]byte("$HOME ${UNKNOWN}"), vars)))
+}
+
+func TestExpandTemplateFiles(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	templateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	tmpDir, err := ioutil.TempDir("", "template")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		templateFilesPath: "# expanded files\n*.md\ndocs/**\n",
+		"README.md":       "# $REPO_NAME from ${TEMPLATE_OWNER}/$TEMPLATE_NAME",
+		"docs/a/b.txt":    "$REPO_OWNER",
+		"main.go":         "$REPO_NAME",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	assert.NoError(t, expandTemplateFiles(tmpDir, repo, templateRepo))
+
+	assertContent := func(name, expected string) {
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+	assertContent("README.md", "# repo2 from user2/repo1")
+	assertContent("docs/a/b.txt", "user2")
+	assertContent("main.go", "$REPO_NAME")
+	_, err = os.Stat(filepath.Join(tmpDir, templateFilesPath))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateRepository(t *testing.T) {
+	PrepareTestEnv(t)
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	templateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	opts := GenerateRepoOptions{
+		Name:     "generated",
+		Labels:   true,
+		Webhooks: true,
+		Topics:   true,
+	}
+
+	_, err := GenerateRepository(doer, doer, templateRepo, opts)
+	assert.Error(t, err)
+	assert.True(t, IsErrRepoNotTemplate(err))
+
+	templateRepo.IsTemplate = true
+	assert.NoError(t, UpdateRepository(templateRepo, false))
+
+	repo, err := GenerateRepository(doer, doer, templateRepo, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, templateRepo.ID, repo.TemplateID)
+	assert.True(t, repo.IsBare)
+
+	labels, err := GetLabelsByRepoID(repo.ID, "")
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	hooks, err := GetWebhooksByRepoID(repo.ID)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 2)
+
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository)
+	assert.EqualValues(t, []string{"database", "golang"}, repo.Topics)
+	CheckConsistencyFor(t, &Repository{}, &Topic{})
+
+	// The webhooks of the template are not copied for a mere reader.
+	reader := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	repo, err = GenerateRepository(reader, reader, templateRepo, opts)
+	assert.NoError(t, err)
+	hooks, err = GetWebhooksByRepoID(repo.ID)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 0)
+	labels, err = GetLabelsByRepoID(repo.ID, "")
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	_, err = GenerateRepository(doer, doer, templateRepo, opts)
+	assert.True(t, IsErrRepoAlreadyExist(err))
+}
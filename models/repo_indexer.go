@@ -75,9 +75,18 @@ func InitRepoIndexer() {
 	go processRepoIndexerOperationQueue()
 }
 
+// RebuildRepoIndexer drops the repo indexer and indexes all repositories again
+func RebuildRepoIndexer() error {
+	return indexer.RebuildRepoIndexer(populateRepoIndexer)
+}
+
 // populateRepoIndexer populate the repo indexer with data
 func populateRepoIndexer() error {
 	log.Info("Populating repository indexer (this may take a while)")
+	// the index is empty, so the statuses of previous indexing are obsolete
+	if _, err := x.Where("1=1").Delete(new(RepoIndexerStatus)); err != nil {
+		return err
+	}
 	for page := 1; ; page++ {
 		repos, _, err := SearchRepositoryByName(&SearchRepoOptions{
 			Page:     page,
@@ -174,8 +183,10 @@ func addUpdate(filename string, repo *Repository, batch *indexer.Batch) error {
 		Filepath: filename,
 		Op:       indexer.RepoIndexerOpUpdate,
 		Data: &indexer.RepoIndexerData{
-			RepoID:  repo.ID,
-			Content: string(fileContents),
+			RepoID:   repo.ID,
+			Filepath: filename,
+			Language: indexer.FileLanguage(filename),
+			Content:  string(fileContents),
 		},
 	})
 }
@@ -32,3 +32,20 @@ func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	}
 	return u.IssuesConfig().AllowOnlyContributorsToTrackTime
 }
+
+// IsDependenciesEnabled returns whether or not dependencies between issues are enabled. It returns the default value from config if an error occurs.
+func (repo *Repository) IsDependenciesEnabled() bool {
+	return repo.isDependenciesEnabled(x)
+}
+
+func (repo *Repository) isDependenciesEnabled(e Engine) bool {
+	if err := repo.getUnits(e); err != nil {
+		return setting.Service.DefaultEnableDependencies
+	}
+	for _, unit := range repo.Units {
+		if unit.Type == UnitTypeIssues {
+			return unit.IssuesConfig().EnableDependencies
+		}
+	}
+	return false
+}
@@ -49,6 +49,19 @@ func (repos RepositoryList) LoadAttributes() error {
 	return repos.loadAttributes(x)
 }
 
+// GetRepositoriesMapByIDs returns the repositories of the given IDs with
+// their owners, by ID
+func GetRepositoriesMapByIDs(ids []int64) (map[int64]*Repository, error) {
+	repos := make(map[int64]*Repository, len(ids))
+	if len(ids) == 0 {
+		return repos, nil
+	}
+	if err := x.In("id", ids).Find(&repos); err != nil {
+		return nil, err
+	}
+	return repos, RepositoryListOfMap(repos).loadAttributes(x)
+}
+
 // MirrorRepositoryList contains the mirror repositories
 type MirrorRepositoryList []*Repository
 
@@ -112,6 +125,16 @@ type SearchRepoOptions struct {
 	// True -> include just mirrors
 	// False -> include just non-mirrors
 	Mirror util.OptionalBool
+	// None -> include archived AND non-archived
+	// True -> include just archived
+	// False -> include just non-archived
+	Archived util.OptionalBool
+	// None -> include templates AND non-templates
+	// True -> include just templates
+	// False -> include just non-templates
+	Template util.OptionalBool
+	// Topic includes just the repositories having the topic of this name
+	Topic string
 }
 
 //SearchOrderBy is used to sort the result
@@ -179,7 +202,20 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (RepositoryList, int64, err
 	}
 
 	if opts.Keyword != "" {
-		cond = cond.And(builder.Like{"lower_name", strings.ToLower(opts.Keyword)})
+		// A keyword matches the name or one of the topics of the repository
+		keyword := strings.ToLower(opts.Keyword)
+		cond = cond.And(builder.Or(
+			builder.Like{"lower_name", keyword},
+			builder.In("repository.id", builder.Select("repo_topic.repo_id").From("repo_topic").
+				Join("INNER", "topic", "topic.id = repo_topic.topic_id").
+				Where(builder.Eq{"topic.name": keyword})),
+		))
+	}
+
+	if opts.Topic != "" {
+		cond = cond.And(builder.In("repository.id", builder.Select("repo_topic.repo_id").From("repo_topic").
+			Join("INNER", "topic", "topic.id = repo_topic.topic_id").
+			Where(builder.Eq{"topic.name": strings.ToLower(opts.Topic)})))
 	}
 
 	if opts.Fork != util.OptionalBoolNone {
@@ -190,6 +226,14 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (RepositoryList, int64, err
 		cond = cond.And(builder.Eq{"is_mirror": opts.Mirror == util.OptionalBoolTrue})
 	}
 
+	if opts.Archived != util.OptionalBoolNone {
+		cond = cond.And(builder.Eq{"is_archived": opts.Archived == util.OptionalBoolTrue})
+	}
+
+	if opts.Template != util.OptionalBoolNone {
+		cond = cond.And(builder.Eq{"is_template": opts.Template == util.OptionalBoolTrue})
+	}
+
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = SearchOrderByAlphabetically
 	}
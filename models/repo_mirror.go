@@ -77,11 +77,15 @@ func (m *Mirror) ScheduleNextUpdate() {
 }
 
 func remoteAddress(repoPath string) (string, error) {
+	return remoteAddressByName(repoPath, "origin")
+}
+
+func remoteAddressByName(repoPath, remoteName string) (string, error) {
 	cfg, err := ini.Load(GitConfigPath(repoPath))
 	if err != nil {
 		return "", err
 	}
-	return cfg.Section("remote \"origin\"").Key("url").Value(), nil
+	return cfg.Section("remote \"" + remoteName + "\"").Key("url").Value(), nil
 }
 
 func (m *Mirror) readAddress() {
@@ -271,6 +275,16 @@ func MirrorUpdate() {
 		}); err != nil {
 		log.Error(4, "MirrorUpdate: %v", err)
 	}
+
+	// Push mirrors without an interval are only synced on push and never scheduled.
+	if err := x.
+		Where("next_update_unix>0 AND next_update_unix<=?", time.Now().Unix()).
+		Iterate(new(PushMirror), func(idx int, bean interface{}) error {
+			PushMirrorQueue.Add(bean.(*PushMirror).ID)
+			return nil
+		}); err != nil {
+		log.Error(4, "MirrorUpdate [push mirrors]: %v", err)
+	}
 }
 
 // SyncMirrors checks and syncs mirrors.
@@ -299,7 +313,8 @@ func SyncMirrors() {
 	}
 }
 
-// InitSyncMirrors initializes a go routine to sync the mirrors
+// InitSyncMirrors initializes go routines to sync the pull and push mirrors
 func InitSyncMirrors() {
 	go SyncMirrors()
+	go SyncPushMirrors()
 }
@@ -0,0 +1,274 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Unknwon/com"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+)
+
+// PushMirrorQueue holds an UniqueQueue object of the push mirrors to sync
+var PushMirrorQueue = sync.NewUniqueQueue(setting.Repository.MirrorQueueLength)
+
+// PushMirror represents a remote repository which is kept in sync with a repository
+// by pushing all of its refs to it.
+type PushMirror struct {
+	ID         int64       `xorm:"pk autoincr"`
+	RepoID     int64       `xorm:"INDEX"`
+	Repo       *Repository `xorm:"-"`
+	RemoteName string      `xorm:"NOT NULL"`
+
+	// Interval is zero if the mirror is only synced on push.
+	Interval   time.Duration
+	SyncOnPush bool `xorm:"NOT NULL DEFAULT false"`
+
+	LastUpdate     time.Time `xorm:"-"`
+	LastUpdateUnix int64
+	NextUpdate     time.Time `xorm:"-"`
+	NextUpdateUnix int64     `xorm:"INDEX"`
+	LastError      string    `xorm:"TEXT"`
+
+	address string `xorm:"-"`
+}
+
+// BeforeInsert will be invoked by XORM before inserting a record
+func (m *PushMirror) BeforeInsert() {
+	m.setUnixTimes()
+}
+
+// BeforeUpdate is invoked from XORM before updating this object.
+func (m *PushMirror) BeforeUpdate() {
+	m.setUnixTimes()
+}
+
+func (m *PushMirror) setUnixTimes() {
+	if !m.LastUpdate.IsZero() {
+		m.LastUpdateUnix = m.LastUpdate.Unix()
+	}
+	if m.NextUpdate.IsZero() {
+		m.NextUpdateUnix = 0
+	} else {
+		m.NextUpdateUnix = m.NextUpdate.Unix()
+	}
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (m *PushMirror) AfterLoad() {
+	if m.LastUpdateUnix > 0 {
+		m.LastUpdate = time.Unix(m.LastUpdateUnix, 0).Local()
+	}
+	if m.NextUpdateUnix > 0 {
+		m.NextUpdate = time.Unix(m.NextUpdateUnix, 0).Local()
+	}
+}
+
+// ScheduleNextUpdate calculates and sets next update time, push mirrors without an interval are never scheduled.
+func (m *PushMirror) ScheduleNextUpdate() {
+	if m.Interval > 0 {
+		m.NextUpdate = time.Now().Add(m.Interval)
+	} else {
+		m.NextUpdate = time.Time{}
+	}
+}
+
+func (m *PushMirror) loadRepo(e Engine) (err error) {
+	if m.Repo != nil {
+		return nil
+	}
+	m.Repo, err = getRepositoryByID(e, m.RepoID)
+	return err
+}
+
+// LoadRepo loads the repository of the push mirror
+func (m *PushMirror) LoadRepo() error {
+	return m.loadRepo(x)
+}
+
+func (m *PushMirror) readAddress() {
+	if len(m.address) > 0 {
+		return
+	}
+	if err := m.loadRepo(x); err != nil {
+		log.Error(4, "loadRepo: %v", err)
+		return
+	}
+	var err error
+	m.address, err = remoteAddressByName(m.Repo.RepoPath(), m.RemoteName)
+	if err != nil {
+		log.Error(4, "remoteAddressByName: %v", err)
+	}
+}
+
+// Address returns the remote address with user credentials replaced by a placeholder.
+func (m *PushMirror) Address() string {
+	m.readAddress()
+	return HandleCloneUserCredentials(m.address, true)
+}
+
+// sanitize replaces all occurrences of the remote address in output with its sanitized version.
+func (m *PushMirror) sanitize(output string) string {
+	m.readAddress()
+	if len(m.address) == 0 {
+		return output
+	}
+	return strings.Replace(output, m.address, HandleCloneUserCredentials(m.address, true), -1)
+}
+
+// runSync pushes all refs of the repository to the remote and records the outcome.
+func (m *PushMirror) runSync() {
+	repoPath := m.Repo.RepoPath()
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+
+	m.LastError = ""
+	if _, stderr, err := process.GetManager().ExecDir(
+		timeout, repoPath, fmt.Sprintf("PushMirror.runSync: %s", repoPath),
+		"git", "push", "--mirror", m.RemoteName); err != nil {
+		// sanitize the output, since it may contain the remote address, which may
+		// contain a password
+		m.LastError = m.sanitize(fmt.Sprintf("%v - %s", err, stderr))
+		desc := fmt.Sprintf("Failed to push mirror repository '%s' to '%s': %s", repoPath, m.Address(), m.LastError)
+		log.Error(4, desc)
+		if err = CreateRepositoryNotice(desc); err != nil {
+			log.Error(4, "CreateRepositoryNotice: %v", err)
+		}
+	}
+	m.LastUpdate = time.Now()
+}
+
+// CreatePushMirror adds a remote to the repository and starts mirroring the repository to it.
+// The address may contain user credentials, they are only stored in the git config of the repository.
+func CreatePushMirror(repo *Repository, address string, interval time.Duration, syncOnPush bool) (*PushMirror, error) {
+	suffix, err := base.GetRandomString(10)
+	if err != nil {
+		return nil, fmt.Errorf("GetRandomString: %v", err)
+	}
+
+	m := &PushMirror{
+		RepoID:     repo.ID,
+		Repo:       repo,
+		RemoteName: "push_mirror_" + suffix,
+		Interval:   interval,
+		SyncOnPush: syncOnPush,
+	}
+	m.ScheduleNextUpdate()
+
+	repoPath := repo.RepoPath()
+	if _, stderr, err := process.GetManager().ExecDir(-1, repoPath,
+		fmt.Sprintf("CreatePushMirror (git remote add): %s", repoPath),
+		"git", "remote", "add", "--mirror=push", m.RemoteName, address); err != nil {
+		return nil, fmt.Errorf("git remote add: %s", strings.Replace(stderr, address, HandleCloneUserCredentials(address, true), -1))
+	}
+
+	if _, err = x.Insert(m); err != nil {
+		if err := m.removeRemote(); err != nil {
+			log.Error(4, "removeRemote: %v", err)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *PushMirror) removeRemote() error {
+	repoPath := m.Repo.RepoPath()
+	if _, stderr, err := process.GetManager().ExecDir(-1, repoPath,
+		fmt.Sprintf("PushMirror.removeRemote (git remote remove): %s", repoPath),
+		"git", "remote", "remove", m.RemoteName); err != nil {
+		return fmt.Errorf("git remote remove: %s", m.sanitize(stderr))
+	}
+	return nil
+}
+
+// GetPushMirrorsByRepoID returns all push mirrors of a repository.
+func GetPushMirrorsByRepoID(repoID int64) ([]*PushMirror, error) {
+	mirrors := make([]*PushMirror, 0, 5)
+	return mirrors, x.Where("repo_id = ?", repoID).Asc("id").Find(&mirrors)
+}
+
+// GetPushMirrorByID returns the push mirror with the given ID.
+func GetPushMirrorByID(id int64) (*PushMirror, error) {
+	m := new(PushMirror)
+	has, err := x.ID(id).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushMirrorNotExist{ID: id}
+	}
+	return m, nil
+}
+
+// GetPushMirrorByRepoID returns the push mirror with the given ID of a repository.
+func GetPushMirrorByRepoID(repoID, id int64) (*PushMirror, error) {
+	m, err := GetPushMirrorByID(id)
+	if err != nil {
+		return nil, err
+	} else if m.RepoID != repoID {
+		return nil, ErrPushMirrorNotExist{ID: id, RepoID: repoID}
+	}
+	return m, nil
+}
+
+// UpdatePushMirror updates the push mirror
+func UpdatePushMirror(m *PushMirror) error {
+	_, err := x.ID(m.ID).AllCols().Update(m)
+	return err
+}
+
+// DeletePushMirror removes the push mirror and its remote from the repository.
+func DeletePushMirror(m *PushMirror) error {
+	if err := m.loadRepo(x); err != nil {
+		return err
+	}
+	if err := m.removeRemote(); err != nil {
+		return err
+	}
+	_, err := x.ID(m.ID).Delete(new(PushMirror))
+	return err
+}
+
+// AddPushMirrorsToQueue adds all push mirrors of a repository which sync on push to the queue.
+func AddPushMirrorsToQueue(repoID int64) {
+	mirrors := make([]*PushMirror, 0, 5)
+	if err := x.Where("repo_id = ? AND sync_on_push = ?", repoID, true).Find(&mirrors); err != nil {
+		log.Error(4, "AddPushMirrorsToQueue [repo_id: %d]: %v", repoID, err)
+		return
+	}
+	for _, m := range mirrors {
+		PushMirrorQueue.Add(m.ID)
+	}
+}
+
+// SyncPushMirrors syncs the push mirrors added to the queue.
+func SyncPushMirrors() {
+	// Start listening on new sync requests.
+	for id := range PushMirrorQueue.Queue() {
+		log.Trace("SyncPushMirrors [push_mirror_id: %v]", id)
+		PushMirrorQueue.Remove(id)
+
+		m, err := GetPushMirrorByID(com.StrTo(id).MustInt64())
+		if err != nil {
+			log.Error(4, "GetPushMirrorByID [%s]: %v", id, err)
+			continue
+		}
+		if err = m.LoadRepo(); err != nil {
+			log.Error(4, "LoadRepo [%s]: %v", id, err)
+			continue
+		}
+
+		m.runSync()
+		m.ScheduleNextUpdate()
+		if err = UpdatePushMirror(m); err != nil {
+			log.Error(4, "UpdatePushMirror [%s]: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// CreateTag creates a tag of the given commit in the repository. The tag is
+// annotated and tagged by doer if message is not empty, else it is a
+// lightweight tag.
+func (repo *Repository) CreateTag(doer *User, tagName, commitID, message string) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	if gitRepo.IsTagExist(tagName) {
+		return ErrTagAlreadyExists{tagName}
+	}
+
+	cmd := git.NewCommand()
+	if len(message) > 0 {
+		sig := doer.NewGitSig()
+		cmd.AddArguments("-c", "user.name="+sig.Name, "-c", "user.email="+sig.Email,
+			"tag", "-a", "-m", message)
+	} else {
+		cmd.AddArguments("tag")
+	}
+	if _, err = cmd.AddArguments("--", tagName, commitID).RunInDir(repo.RepoPath()); err != nil {
+		if strings.Contains(err.Error(), "is not a valid tag name") {
+			return ErrInvalidTagName{tagName}
+		}
+		return fmt.Errorf("CreateTag: %v", err)
+	}
+
+	tagID, err := gitRepo.GetTagCommitID(tagName)
+	if err != nil {
+		return fmt.Errorf("GetTagCommitID: %v", err)
+	}
+
+	return repo.pushUpdateTag(doer, tagName, git.EmptySHA, tagID)
+}
+
+// DeleteTag deletes a tag of the repository.
+func (repo *Repository) DeleteTag(doer *User, tagName string) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !gitRepo.IsTagExist(tagName) {
+		return ErrTagNotExist{tagName}
+	}
+
+	tagID, err := gitRepo.GetTagCommitID(tagName)
+	if err != nil {
+		return fmt.Errorf("GetTagCommitID: %v", err)
+	}
+
+	if _, err = git.NewCommand("tag", "-d", "--", tagName).RunInDir(repo.RepoPath()); err != nil {
+		return fmt.Errorf("DeleteTag: %v", err)
+	}
+
+	return repo.pushUpdateTag(doer, tagName, tagID, git.EmptySHA)
+}
+
+// pushUpdateTag simulates the push event of a tag which has been changed
+// directly in the repository.
+func (repo *Repository) pushUpdateTag(doer *User, tagName, oldCommitID, newCommitID string) error {
+	if err := repo.GetOwner(); err != nil {
+		return fmt.Errorf("GetOwner: %v", err)
+	}
+	if err := PushUpdate(
+		git.TagPrefix+tagName,
+		PushUpdateOptions{
+			PusherID:     doer.ID,
+			PusherName:   doer.Name,
+			RepoUserName: repo.Owner.Name,
+			RepoName:     repo.Name,
+			RefFullName:  git.TagPrefix + tagName,
+			OldCommitID:  oldCommitID,
+			NewCommitID:  newCommitID,
+		},
+	); err != nil {
+		return fmt.Errorf("PushUpdate: %v", err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/Unknwon/com"
 	"github.com/stretchr/testify/assert"
@@ -153,3 +154,42 @@ func TestRepoLocalCopyPath(t *testing.T) {
 	setting.Repository.Local.LocalCopyPath = tempPath
 	assert.Equal(t, expected, repo.LocalCopyPath())
 }
+
+func TestRepository_SetArchiveRepoState(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, repo.SetArchiveRepoState(true))
+	assert.True(t, AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository).IsArchived)
+	assert.False(t, repo.CanEnableEditor())
+	assert.True(t, repo.APIFormat(AccessModeRead).Archived)
+
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:  "repo1",
+		Page:     1,
+		PageSize: 10,
+		Archived: util.OptionalBoolTrue,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	repos, _, err = SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:  "repo1",
+		Page:     1,
+		PageSize: 10,
+		Archived: util.OptionalBoolFalse,
+	})
+	assert.NoError(t, err)
+	for _, r := range repos {
+		assert.False(t, r.IsArchived)
+	}
+
+	err = repo.DeleteRepoFile(AssertExistsAndLoadBean(t, &User{ID: 2}).(*User), DeleteRepoFileOptions{})
+	assert.True(t, IsErrRepoArchived(err))
+
+	assert.NoError(t, repo.SetArchiveRepoState(false))
+	assert.False(t, AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository).IsArchived)
+}
@@ -0,0 +1,543 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/builder"
+)
+
+// ReviewType defines the sort of feedback a review gives
+type ReviewType int
+
+// ReviewTypeUnknown unknown review type
+const ReviewTypeUnknown ReviewType = -1
+
+const (
+	// ReviewTypePending is a review which is not published yet
+	ReviewTypePending ReviewType = iota
+	// ReviewTypeApprove approves changes
+	ReviewTypeApprove
+	// ReviewTypeComment gives general feedback
+	ReviewTypeComment
+	// ReviewTypeReject gives feedback blocking merge
+	ReviewTypeReject
+)
+
+// Icon returns the corresponding icon for the review type
+func (rt ReviewType) Icon() string {
+	switch rt {
+	case ReviewTypeApprove:
+		return "eye"
+	case ReviewTypeReject:
+		return "x"
+	default:
+		return "comment"
+	}
+}
+
+// APIState returns the corresponding API state for the review type
+func (rt ReviewType) APIState() api.ReviewStateType {
+	switch rt {
+	case ReviewTypePending:
+		return api.ReviewStatePending
+	case ReviewTypeApprove:
+		return api.ReviewStateApproved
+	case ReviewTypeReject:
+		return api.ReviewStateRequestChanges
+	case ReviewTypeComment:
+		return api.ReviewStateComment
+	default:
+		return api.ReviewStateUnknown
+	}
+}
+
+// ReviewTypeFromAPIState returns the review type for the given API state
+func ReviewTypeFromAPIState(state api.ReviewStateType) ReviewType {
+	switch state {
+	case api.ReviewStatePending:
+		return ReviewTypePending
+	case api.ReviewStateApproved:
+		return ReviewTypeApprove
+	case api.ReviewStateRequestChanges:
+		return ReviewTypeReject
+	case api.ReviewStateComment:
+		return ReviewTypeComment
+	default:
+		return ReviewTypeUnknown
+	}
+}
+
+// Review represents collection of code comments giving feedback for a PR
+type Review struct {
+	ID         int64 `xorm:"pk autoincr"`
+	Type       ReviewType
+	Reviewer   *User  `xorm:"-"`
+	ReviewerID int64  `xorm:"index"`
+	Issue      *Issue `xorm:"-"`
+	IssueID    int64  `xorm:"index"`
+	Content    string `xorm:"TEXT"`
+	// CommitID is the head commit of the pull request the review was given on
+	CommitID string `xorm:"VARCHAR(40)"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX updated"`
+
+	// CodeComments are the initial code comments of the review
+	CodeComments []*Comment `xorm:"-"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (r *Review) AfterLoad() {
+	r.Created = time.Unix(r.CreatedUnix, 0).Local()
+	r.Updated = time.Unix(r.UpdatedUnix, 0).Local()
+}
+
+func (r *Review) loadCodeComments(e Engine) (err error) {
+	if r.CodeComments != nil {
+		return nil
+	}
+	r.CodeComments, err = findComments(e, FindCommentsOptions{
+		IssueID:  r.IssueID,
+		ReviewID: r.ID,
+		Type:     CommentTypeCode,
+	})
+	return err
+}
+
+// LoadCodeComments loads the code comments of the review
+func (r *Review) LoadCodeComments() error {
+	return r.loadCodeComments(x)
+}
+
+func (r *Review) loadIssue(e Engine) (err error) {
+	if r.Issue != nil {
+		return nil
+	}
+	r.Issue, err = getIssueByID(e, r.IssueID)
+	return err
+}
+
+func (r *Review) loadReviewer(e Engine) (err error) {
+	if r.Reviewer != nil || r.ReviewerID == 0 {
+		return nil
+	}
+	r.Reviewer, err = getUserByID(e, r.ReviewerID)
+	if IsErrUserNotExist(err) {
+		r.ReviewerID = -1
+		r.Reviewer = NewGhostUser()
+		return nil
+	}
+	return err
+}
+
+func (r *Review) loadAttributes(e Engine) (err error) {
+	if err = r.loadReviewer(e); err != nil {
+		return
+	}
+	if err = r.loadIssue(e); err != nil {
+		return
+	}
+	return r.loadCodeComments(e)
+}
+
+// LoadAttributes loads the reviewer, the issue and the code comments of the review
+func (r *Review) LoadAttributes() error {
+	return r.loadAttributes(x)
+}
+
+// HTMLURL formats a URL-string to the review
+func (r *Review) HTMLURL() string {
+	if err := r.loadIssue(x); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s#%s", r.Issue.HTMLURL(), r.HashTag())
+}
+
+// HashTag returns unique hash tag for review.
+func (r *Review) HashTag() string {
+	return fmt.Sprintf("pullrequestreview-%d", r.ID)
+}
+
+// APIFormat converts a Review to the api.PullReview format
+func (r *Review) APIFormat() *api.PullReview {
+	apiReview := &api.PullReview{
+		ID:                r.ID,
+		State:             r.Type.APIState(),
+		Body:              r.Content,
+		CommitID:          r.CommitID,
+		CodeCommentsCount: len(r.CodeComments),
+		Submitted:         r.Updated,
+		HTMLURL:           r.HTMLURL(),
+	}
+	if r.Reviewer != nil {
+		apiReview.Reviewer = r.Reviewer.APIFormat()
+	}
+	if r.Issue != nil {
+		apiReview.HTMLPullURL = r.Issue.HTMLURL()
+	}
+	return apiReview
+}
+
+func getReviewByID(e Engine, id int64) (*Review, error) {
+	review := new(Review)
+	if has, err := e.ID(id).Get(review); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReviewNotExist{ID: id}
+	}
+	return review, nil
+}
+
+// GetReviewByID returns the review by the given ID
+func GetReviewByID(id int64) (*Review, error) {
+	return getReviewByID(x, id)
+}
+
+// FindReviewOptions represent possible filters to find reviews
+type FindReviewOptions struct {
+	Type       ReviewType
+	IssueID    int64
+	ReviewerID int64
+}
+
+func (opts *FindReviewOptions) toCond() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	if opts.ReviewerID > 0 {
+		cond = cond.And(builder.Eq{"reviewer_id": opts.ReviewerID})
+	}
+	if opts.Type != ReviewTypeUnknown {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	return cond
+}
+
+func findReviews(e Engine, opts FindReviewOptions) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	return reviews, e.Where(opts.toCond()).
+		Asc("created_unix").
+		Asc("id").
+		Find(&reviews)
+}
+
+// FindReviews returns reviews passing FindReviewOptions
+func FindReviews(opts FindReviewOptions) ([]*Review, error) {
+	return findReviews(x, opts)
+}
+
+// GetVisibleReviewsByIssueID returns all reviews of an issue which can be seen by the doer,
+// that is all submitted reviews and the doer's own pending review.
+func GetVisibleReviewsByIssueID(issueID int64, doer *User) ([]*Review, error) {
+	cond := builder.Eq{"issue_id": issueID}.And(builder.Neq{"type": ReviewTypePending})
+	if doer != nil {
+		cond = builder.Eq{"issue_id": issueID}.And(
+			builder.Neq{"type": ReviewTypePending}.Or(builder.Eq{"reviewer_id": doer.ID}))
+	}
+	reviews := make([]*Review, 0, 10)
+	if err := x.Where(cond).Asc("created_unix").Asc("id").Find(&reviews); err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		if err := review.loadAttributes(x); err != nil {
+			return nil, err
+		}
+	}
+	return reviews, nil
+}
+
+// CreateReviewOptions represent the options to create a review. Type, Issue and Reviewer are required.
+type CreateReviewOptions struct {
+	Content  string
+	Type     ReviewType
+	Issue    *Issue
+	Reviewer *User
+	CommitID string
+}
+
+func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
+	review := &Review{
+		Type:       opts.Type,
+		Issue:      opts.Issue,
+		IssueID:    opts.Issue.ID,
+		Reviewer:   opts.Reviewer,
+		ReviewerID: opts.Reviewer.ID,
+		Content:    opts.Content,
+		CommitID:   opts.CommitID,
+	}
+	if _, err := e.Insert(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// CreateReview creates a new review based on opts
+func CreateReview(opts CreateReviewOptions) (*Review, error) {
+	return createReview(x, opts)
+}
+
+func getCurrentReview(e Engine, reviewer *User, issue *Issue) (*Review, error) {
+	if reviewer == nil {
+		return nil, ErrReviewNotExist{}
+	}
+	reviews, err := findReviews(e, FindReviewOptions{
+		Type:       ReviewTypePending,
+		IssueID:    issue.ID,
+		ReviewerID: reviewer.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, ErrReviewNotExist{}
+	}
+	return reviews[0], nil
+}
+
+// GetCurrentReview returns the current pending review of reviewer for given issue
+func GetCurrentReview(reviewer *User, issue *Issue) (*Review, error) {
+	return getCurrentReview(x, reviewer, issue)
+}
+
+// GetOrCreatePendingReview returns the pending review of reviewer for given issue,
+// a new one is started if there is none yet.
+func GetOrCreatePendingReview(reviewer *User, issue *Issue, commitID string) (*Review, error) {
+	review, err := getCurrentReview(x, reviewer, issue)
+	if err == nil {
+		return review, nil
+	} else if !IsErrReviewNotExist(err) {
+		return nil, err
+	}
+	return createReview(x, CreateReviewOptions{
+		Type:     ReviewTypePending,
+		Issue:    issue,
+		Reviewer: reviewer,
+		CommitID: commitID,
+	})
+}
+
+// CreateCodeComment creates a comment on the given line of a file in the diff of a pull request.
+// Negative lines refer to the old version of the file, positive ones to the new version.
+func CreateCodeComment(doer *User, repo *Repository, issue *Issue, content, treePath string, line, reviewID int64, commitSHA string) (*Comment, error) {
+	return CreateComment(&CreateCommentOptions{
+		Type:      CommentTypeCode,
+		Doer:      doer,
+		Repo:      repo,
+		Issue:     issue,
+		Content:   content,
+		LineNum:   line,
+		TreePath:  treePath,
+		CommitSHA: commitSHA,
+		ReviewID:  reviewID,
+	})
+}
+
+// SubmitReview publishes the pending review of doer with the given type and content,
+// or creates and publishes a new review if doer has no pending one.
+func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, commitID string) (*Review, *Comment, error) {
+	if reviewType == ReviewTypePending || reviewType == ReviewTypeUnknown {
+		return nil, nil, ErrInvalidReviewType{Type: reviewType}
+	}
+
+	if err := issue.loadRepo(x); err != nil {
+		return nil, nil, err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, nil, err
+	}
+
+	review, err := getCurrentReview(sess, doer, issue)
+	if err != nil {
+		if !IsErrReviewNotExist(err) {
+			return nil, nil, err
+		}
+
+		// A review without code comments needs some content, unless it approves the changes.
+		if len(content) == 0 && reviewType != ReviewTypeApprove {
+			return nil, nil, ErrReviewEmpty{}
+		}
+
+		if review, err = createReview(sess, CreateReviewOptions{
+			Type:     reviewType,
+			Issue:    issue,
+			Reviewer: doer,
+			Content:  content,
+			CommitID: commitID,
+		}); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		review.Type = reviewType
+		review.Content = content
+		if len(commitID) > 0 {
+			review.CommitID = commitID
+		}
+		if _, err = sess.ID(review.ID).Cols("type", "content", "commit_id").Update(review); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	comm, err := createComment(sess, &CreateCommentOptions{
+		Type:     CommentTypeReview,
+		Doer:     doer,
+		Content:  review.Content,
+		Issue:    issue,
+		Repo:     issue.Repo,
+		ReviewID: review.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	review.Reviewer = doer
+	review.Issue = issue
+	review.sendSubmittedWebhook(doer)
+	return review, comm, nil
+}
+
+// sendSubmittedWebhook adds the pull request review event of a freshly submitted review to the hook queue.
+func (r *Review) sendSubmittedWebhook(doer *User) {
+	var err error
+	if err = r.loadCodeComments(x); err != nil {
+		log.Error(4, "loadCodeComments: %v", err)
+		return
+	}
+	if err = r.Issue.loadPullRequest(x); err != nil {
+		log.Error(4, "loadPullRequest: %v", err)
+		return
+	}
+	r.Issue.PullRequest.Issue = r.Issue
+
+	if err = PrepareWebhooks(r.Issue.Repo, HookEventPullRequestReview, &api.PullRequestReviewPayload{
+		Action:      api.HookPullRequestReviewSubmitted,
+		Index:       r.Issue.Index,
+		Review:      r.APIFormat(),
+		PullRequest: r.Issue.PullRequest.APIFormat(),
+		Repository:  r.Issue.Repo.APIFormat(AccessModeNone),
+		Sender:      doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [review_id: %d]: %v", r.ID, err)
+	} else {
+		go HookQueue.Add(r.Issue.RepoID)
+	}
+}
+
+// DeletePendingReview removes a pending review together with its code comments.
+func DeletePendingReview(review *Review) error {
+	if review.Type != ReviewTypePending {
+		return ErrInvalidReviewType{Type: review.Type}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&Comment{ReviewID: review.ID, Type: CommentTypeCode}); err != nil {
+		return err
+	}
+	if _, err := sess.ID(review.ID).Delete(new(Review)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetReviewersByIssueID returns the latest submitted review of each reviewer of a pull request
+func GetReviewersByIssueID(issueID int64) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	if err := x.Where("issue_id = ? AND type <> ? AND type <> ?", issueID, ReviewTypePending, ReviewTypeComment).
+		Desc("created_unix").
+		Desc("id").
+		Find(&reviews); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool, len(reviews))
+	latest := make([]*Review, 0, len(reviews))
+	for _, review := range reviews {
+		if seen[review.ReviewerID] {
+			continue
+		}
+		seen[review.ReviewerID] = true
+		if err := review.loadReviewer(x); err != nil {
+			return nil, err
+		}
+		latest = append(latest, review)
+	}
+	return latest, nil
+}
+
+// CodeComments represents comments on code by using this structure: FILENAME -> LINE (+ == proposed; - == previous) -> COMMENTS
+type CodeComments map[string]map[int64][]*Comment
+
+func fetchCodeComments(e Engine, issue *Issue, currentUser *User) (CodeComments, error) {
+	var pathToLineToComment = make(CodeComments)
+
+	// Comments of pending reviews are only visible to their author
+	cond := builder.Eq{
+		"comment.type":     CommentTypeCode,
+		"comment.issue_id": issue.ID,
+	}.And(builder.Neq{"review.type": ReviewTypePending})
+	if currentUser != nil {
+		cond = builder.Eq{
+			"comment.type":     CommentTypeCode,
+			"comment.issue_id": issue.ID,
+		}.And(builder.Neq{"review.type": ReviewTypePending}.Or(builder.Eq{"review.reviewer_id": currentUser.ID}))
+	}
+
+	var comments []*Comment
+	if err := e.Where(cond).
+		Join("INNER", "review", "review.id = comment.review_id").
+		Asc("comment.created_unix").
+		Asc("comment.id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+
+	if err := issue.loadRepo(e); err != nil {
+		return nil, err
+	}
+
+	reviews := make(map[int64]*Review)
+	for _, comment := range comments {
+		review, ok := reviews[comment.ReviewID]
+		if !ok {
+			var err error
+			if review, err = getReviewByID(e, comment.ReviewID); err != nil {
+				return nil, err
+			}
+			reviews[comment.ReviewID] = review
+		}
+		comment.Review = review
+
+		if pathToLineToComment[comment.TreePath] == nil {
+			pathToLineToComment[comment.TreePath] = make(map[int64][]*Comment)
+		}
+		pathToLineToComment[comment.TreePath][comment.Line] = append(pathToLineToComment[comment.TreePath][comment.Line], comment)
+	}
+	return pathToLineToComment, nil
+}
+
+// FetchCodeComments will return a 2d-map: ["Path"]["Line"] = Comments at line
+func FetchCodeComments(issue *Issue, currentUser *User) (CodeComments, error) {
+	return fetchCodeComments(x, issue, currentUser)
+}
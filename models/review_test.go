@@ -0,0 +1,162 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReviewByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review, err := GetReviewByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "LGTM", review.Content)
+	assert.Equal(t, ReviewTypeApprove, review.Type)
+
+	_, err = GetReviewByID(23892)
+	assert.Error(t, err)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestReview_LoadAttributes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review := AssertExistsAndLoadBean(t, &Review{ID: 4}).(*Review)
+	assert.NoError(t, review.LoadAttributes())
+	assert.EqualValues(t, 2, review.Reviewer.ID)
+	assert.EqualValues(t, 2, review.Issue.ID)
+	assert.Len(t, review.CodeComments, 1)
+	assert.EqualValues(t, 1, review.APIFormat().CodeCommentsCount)
+}
+
+func TestFindReviews(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviews, err := FindReviews(FindReviewOptions{
+		Type:       ReviewTypeApprove,
+		IssueID:    2,
+		ReviewerID: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, "LGTM", reviews[0].Content)
+
+	reviews, err = FindReviews(FindReviewOptions{
+		Type:    ReviewTypeUnknown,
+		IssueID: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 4)
+}
+
+func TestGetCurrentReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	review, err := GetCurrentReview(user, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, ReviewTypePending, review.Type)
+	assert.EqualValues(t, 3, review.ID)
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err = GetCurrentReview(user2, issue)
+	assert.Error(t, err)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestGetOrCreatePendingReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	review, err := GetOrCreatePendingReview(user, issue, "")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, review.ID)
+
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	review, err = GetOrCreatePendingReview(user5, issue, "abcdef")
+	assert.NoError(t, err)
+	assert.Equal(t, ReviewTypePending, review.Type)
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, ReviewerID: 5, IssueID: 2, CommitID: "abcdef"})
+}
+
+func TestSubmitReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	// the pending review of user 4 gets published
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	review, comment, err := SubmitReview(user, issue, ReviewTypeReject, "needs work", "")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, review.ID)
+	assert.Equal(t, ReviewTypeReject, review.Type)
+	assert.Equal(t, CommentTypeReview, comment.Type)
+	assert.EqualValues(t, review.ID, comment.ReviewID)
+	AssertExistsAndLoadBean(t, &Review{ID: 3, Type: ReviewTypeReject, Content: "needs work"})
+
+	// a new review is created for user 5
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	_, _, err = SubmitReview(user5, issue, ReviewTypeComment, "", "")
+	assert.True(t, IsErrReviewEmpty(err))
+	review, _, err = SubmitReview(user5, issue, ReviewTypeApprove, "", "")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, ReviewerID: 5, Type: ReviewTypeApprove})
+
+	_, _, err = SubmitReview(user5, issue, ReviewTypePending, "", "")
+	assert.True(t, IsErrInvalidReviewType(err))
+}
+
+func TestDeletePendingReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review := AssertExistsAndLoadBean(t, &Review{ID: 3}).(*Review)
+	assert.NoError(t, DeletePendingReview(review))
+	AssertNotExistsBean(t, &Review{ID: 3})
+	AssertNotExistsBean(t, &Comment{ID: 5})
+
+	review = AssertExistsAndLoadBean(t, &Review{ID: 1}).(*Review)
+	assert.True(t, IsErrInvalidReviewType(DeletePendingReview(review)))
+}
+
+func TestGetReviewersByIssueID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviews, err := GetReviewersByIssueID(2)
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 2) {
+		assert.EqualValues(t, 3, reviews[0].ReviewerID)
+		assert.Equal(t, ReviewTypeReject, reviews[0].Type)
+		assert.EqualValues(t, 2, reviews[1].ReviewerID)
+		assert.Equal(t, ReviewTypeApprove, reviews[1].Type)
+	}
+}
+
+func TestFetchCodeComments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	res, err := FetchCodeComments(issue, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, res, "README.md")
+	assert.Len(t, res["README.md"][4], 1)
+	assert.Len(t, res["README.md"][-4], 0)
+	assert.EqualValues(t, 4, res["README.md"][4][0].ID)
+
+	// pending comments are visible to their author
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	res, err = FetchCodeComments(issue, user)
+	assert.NoError(t, err)
+	assert.Len(t, res["README.md"][-4], 1)
+	assert.Equal(t, "previous", res["README.md"][-4][0].DiffSide())
+	assert.EqualValues(t, 4, res["README.md"][-4][0].UnsignedLine())
+}
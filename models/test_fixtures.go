@@ -19,5 +19,8 @@ func InitFixtures(helper testfixtures.Helper, dir string) (err error) {
 
 // LoadFixtures load fixtures for a test database
 func LoadFixtures() error {
+	// the issue indexer reads updated issues in the background
+	issueIndexerLock.Lock()
+	defer issueIndexerLock.Unlock()
 	return fixtures.Load()
 }
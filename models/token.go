@@ -5,6 +5,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	gouuid "github.com/satori/go.uuid"
@@ -12,27 +13,132 @@ import (
 	"code.gitea.io/gitea/modules/base"
 )
 
+// AccessTokenScope is a comma separated list of the scopes of an access token.
+type AccessTokenScope string
+
+// Scopes of access tokens
+const (
+	// AccessTokenScopeAll grants all permissions of the user
+	AccessTokenScopeAll AccessTokenScope = "all"
+	// AccessTokenScopeRepoRead grants read access to repositories
+	AccessTokenScopeRepoRead AccessTokenScope = "repo:read"
+	// AccessTokenScopeRepo grants read and write access to repositories
+	AccessTokenScopeRepo AccessTokenScope = "repo"
+	// AccessTokenScopeAdmin grants access to the site administration
+	AccessTokenScopeAdmin AccessTokenScope = "admin"
+	// AccessTokenScopeUser grants access to the user's profile and settings
+	AccessTokenScopeUser AccessTokenScope = "user"
+	// AccessTokenScopeOrg grants access to organizations and teams
+	AccessTokenScopeOrg AccessTokenScope = "org"
+	// AccessTokenScopeNotification grants access to the notifications of the user
+	AccessTokenScopeNotification AccessTokenScope = "notification"
+)
+
+// AccessTokenScopes lists all scopes of access tokens. There is no packages
+// scope yet, as there is no package registry whose routes it could limit.
+var AccessTokenScopes = []AccessTokenScope{
+	AccessTokenScopeAll,
+	AccessTokenScopeRepoRead,
+	AccessTokenScopeRepo,
+	AccessTokenScopeAdmin,
+	AccessTokenScopeUser,
+	AccessTokenScopeOrg,
+	AccessTokenScopeNotification,
+}
+
+// impliedAccessTokenScopes scopes which are granted by another scope
+var impliedAccessTokenScopes = map[AccessTokenScope][]AccessTokenScope{
+	AccessTokenScopeRepo: {AccessTokenScopeRepoRead},
+}
+
+// NewAccessTokenScope validates the given scopes and joins them to an
+// AccessTokenScope, in the order of AccessTokenScopes.
+func NewAccessTokenScope(scopes []string) (AccessTokenScope, error) {
+	set := make(map[AccessTokenScope]bool, len(scopes))
+	for _, scope := range scopes {
+		set[AccessTokenScope(strings.TrimSpace(scope))] = true
+	}
+
+	list := make([]string, 0, len(set))
+	for _, scope := range AccessTokenScopes {
+		if set[scope] {
+			list = append(list, string(scope))
+			delete(set, scope)
+		}
+	}
+	for scope := range set {
+		return "", ErrAccessTokenScopeInvalid{string(scope)}
+	}
+	return AccessTokenScope(strings.Join(list, ",")), nil
+}
+
+// List returns the scopes of the access token
+func (s AccessTokenScope) List() []AccessTokenScope {
+	if len(s) == 0 {
+		return nil
+	}
+	parts := strings.Split(string(s), ",")
+	scopes := make([]AccessTokenScope, len(parts))
+	for i, part := range parts {
+		scopes[i] = AccessTokenScope(part)
+	}
+	return scopes
+}
+
+// Has returns true if the scope is granted, either directly or implied by
+// another scope
+func (s AccessTokenScope) Has(scope AccessTokenScope) bool {
+	for _, granted := range s.List() {
+		if granted == AccessTokenScopeAll || granted == scope {
+			return true
+		}
+		for _, implied := range impliedAccessTokenScopes[granted] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // AccessToken represents a personal access token.
 type AccessToken struct {
-	ID   int64 `xorm:"pk autoincr"`
-	UID  int64 `xorm:"INDEX"`
-	Name string
-	Sha1 string `xorm:"UNIQUE VARCHAR(40)"`
+	ID    int64 `xorm:"pk autoincr"`
+	UID   int64 `xorm:"INDEX"`
+	Name  string
+	Sha1  string           `xorm:"UNIQUE VARCHAR(40)"`
+	Scope AccessTokenScope `xorm:"VARCHAR(255)"`
 
 	Created           time.Time `xorm:"-"`
 	CreatedUnix       int64     `xorm:"INDEX created"`
 	Updated           time.Time `xorm:"-"`
 	UpdatedUnix       int64     `xorm:"INDEX updated"`
-	HasRecentActivity bool      `xorm:"-"`
-	HasUsed           bool      `xorm:"-"`
+	Expires           time.Time `xorm:"-"`
+	ExpiresUnix       int64     `xorm:"INDEX"` // Zero means the token never expires
+	LastUsed          time.Time `xorm:"-"`
+	LastUsedUnix      int64
+	HasRecentActivity bool `xorm:"-"`
+	HasUsed           bool `xorm:"-"`
 }
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (t *AccessToken) AfterLoad() {
 	t.Created = time.Unix(t.CreatedUnix, 0).Local()
 	t.Updated = time.Unix(t.UpdatedUnix, 0).Local()
-	t.HasUsed = t.Updated.After(t.Created)
-	t.HasRecentActivity = t.Updated.Add(7 * 24 * time.Hour).After(time.Now())
+	t.Expires = time.Unix(t.ExpiresUnix, 0).Local()
+	t.LastUsed = time.Unix(t.LastUsedUnix, 0).Local()
+	t.HasUsed = t.LastUsedUnix > 0
+	t.HasRecentActivity = t.HasUsed && t.LastUsed.Add(7*24*time.Hour).After(time.Now())
+}
+
+// CanExpire returns true if the token has an expiry date
+func (t *AccessToken) CanExpire() bool {
+	return t.ExpiresUnix > 0
+}
+
+// IsExpired returns true if the token has expired
+func (t *AccessToken) IsExpired() bool {
+	return t.CanExpire() && t.ExpiresUnix <= time.Now().Unix()
 }
 
 // NewAccessToken creates new access token.
@@ -43,6 +149,7 @@ func NewAccessToken(t *AccessToken) error {
 }
 
 // GetAccessTokenBySHA returns access token by given sha1.
+// Expired tokens are returned with an ErrAccessTokenExpired error.
 func GetAccessTokenBySHA(sha string) (*AccessToken, error) {
 	if sha == "" {
 		return nil, ErrAccessTokenEmpty{}
@@ -53,6 +160,8 @@ func GetAccessTokenBySHA(sha string) (*AccessToken, error) {
 		return nil, err
 	} else if !has {
 		return nil, ErrAccessTokenNotExist{sha}
+	} else if t.IsExpired() {
+		return t, ErrAccessTokenExpired{t.ID}
 	}
 	return t, nil
 }
@@ -72,6 +181,14 @@ func UpdateAccessToken(t *AccessToken) error {
 	return err
 }
 
+// UpdateAccessTokenLastUsed records that the access token has just been used.
+func UpdateAccessTokenLastUsed(t *AccessToken) error {
+	t.LastUsed = time.Now()
+	t.LastUsedUnix = t.LastUsed.Unix()
+	_, err := x.ID(t.ID).Cols("last_used_unix").Update(t)
+	return err
+}
+
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
 	cnt, err := x.ID(id).Delete(&AccessToken{
@@ -91,3 +91,51 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, IsErrAccessTokenNotExist(err))
 }
+
+func TestGetAccessTokenBySHA_Expired(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	token, err := GetAccessTokenBySHA("hash4")
+	assert.Error(t, err)
+	assert.True(t, IsErrAccessTokenExpired(err))
+	assert.True(t, token.IsExpired())
+
+	token, err = GetAccessTokenBySHA("hash1")
+	assert.NoError(t, err)
+	assert.False(t, token.CanExpire())
+	assert.False(t, token.IsExpired())
+}
+
+func TestUpdateAccessTokenLastUsed(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	token, err := GetAccessTokenBySHA("hash1")
+	assert.NoError(t, err)
+	assert.False(t, token.HasUsed)
+
+	assert.NoError(t, UpdateAccessTokenLastUsed(token))
+	token, err = GetAccessTokenBySHA("hash1")
+	assert.NoError(t, err)
+	assert.True(t, token.HasUsed)
+	assert.True(t, token.HasRecentActivity)
+}
+
+func TestNewAccessTokenScope(t *testing.T) {
+	scope, err := NewAccessTokenScope([]string{"org", "repo", "org"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "repo,org", scope)
+
+	scope, err = NewAccessTokenScope(nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "", scope)
+
+	_, err = NewAccessTokenScope([]string{"repo", "delete_everything"})
+	assert.True(t, IsErrAccessTokenScopeInvalid(err))
+}
+
+func TestAccessTokenScope_Has(t *testing.T) {
+	assert.True(t, AccessTokenScopeAll.Has(AccessTokenScopeAdmin))
+	assert.True(t, AccessTokenScope("repo,org").Has(AccessTokenScopeRepoRead))
+	assert.True(t, AccessTokenScope("repo,org").Has(AccessTokenScopeOrg))
+	assert.False(t, AccessTokenScope("repo,org").Has(AccessTokenScopeUser))
+	assert.False(t, AccessTokenScopeRepoRead.Has(AccessTokenScopeRepo))
+	assert.False(t, AccessTokenScope("").Has(AccessTokenScopeRepoRead))
+}
@@ -0,0 +1,302 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/Unknwon/com"
+	"github.com/go-xorm/builder"
+)
+
+// MaxRepoTopics is the maximum number of topics of a repository
+const MaxRepoTopics = 25
+
+var topicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Topic represents a topic of repositories
+type Topic struct {
+	ID        int64  `xorm:"pk autoincr"`
+	Name      string `xorm:"UNIQUE VARCHAR(35)"`
+	RepoCount int
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX created"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX updated"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (t *Topic) AfterLoad() {
+	t.Created = time.Unix(t.CreatedUnix, 0).Local()
+	t.Updated = time.Unix(t.UpdatedUnix, 0).Local()
+}
+
+// APIFormat converts a Topic to its API format
+func (t *Topic) APIFormat() *api.TopicResponse {
+	return &api.TopicResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		RepoCount: t.RepoCount,
+		Created:   t.Created,
+		Updated:   t.Updated,
+	}
+}
+
+// RepoTopic represents the topics of a repository
+type RepoTopic struct {
+	RepoID  int64 `xorm:"UNIQUE(s)"`
+	TopicID int64 `xorm:"UNIQUE(s) INDEX"`
+}
+
+// ValidateTopic checks if a topic name is valid: lower case alphanumeric
+// characters and dashes, not longer than 35 characters.
+func ValidateTopic(topic string) bool {
+	return len(topic) <= 35 && topicPattern.MatchString(topic)
+}
+
+// SanitizeTopics lower-cases, trims and dedupes the given topic names, dropping
+// empty ones. It returns ErrTopicNotValid for the first invalid name.
+func SanitizeTopics(topicNames []string) ([]string, error) {
+	seen := make(map[string]bool, len(topicNames))
+	topics := make([]string, 0, len(topicNames))
+	for _, name := range topicNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) == 0 || seen[name] {
+			continue
+		} else if !ValidateTopic(name) {
+			return nil, ErrTopicNotValid{name}
+		}
+		seen[name] = true
+		topics = append(topics, name)
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+func getTopicByName(e Engine, name string) (*Topic, error) {
+	topic := new(Topic)
+	has, err := e.Where("name = ?", name).Get(topic)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTopicNotExist{name}
+	}
+	return topic, nil
+}
+
+// GetTopicByName returns the topic of given name
+func GetTopicByName(name string) (*Topic, error) {
+	return getTopicByName(x, name)
+}
+
+// FindTopicOptions represents the options to find topics
+type FindTopicOptions struct {
+	RepoID   int64
+	Keyword  string
+	Page     int
+	PageSize int
+}
+
+func (opts *FindTopicOptions) toConds() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_topic.repo_id": opts.RepoID})
+	}
+	if opts.Keyword != "" {
+		cond = cond.And(builder.Like{"topic.name", strings.ToLower(opts.Keyword)})
+	}
+	return cond
+}
+
+// FindTopics returns the topics matching the given options, the most used first
+func FindTopics(opts *FindTopicOptions) ([]*Topic, error) {
+	sess := x.Where(opts.toConds())
+	if opts.RepoID > 0 {
+		sess.Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id")
+		sess.Asc("topic.name")
+	} else {
+		sess.Desc("topic.repo_count").Asc("topic.name")
+	}
+	if opts.PageSize > 0 {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+	}
+
+	topics := make([]*Topic, 0, 10)
+	return topics, sess.Find(&topics)
+}
+
+func addTopicByNameToRepo(e Engine, repoID int64, name string) error {
+	topic, err := getTopicByName(e, name)
+	if err != nil {
+		if !IsErrTopicNotExist(err) {
+			return err
+		}
+		topic = &Topic{Name: name}
+		if _, err = e.Insert(topic); err != nil {
+			return err
+		}
+	}
+
+	if _, err = e.Insert(&RepoTopic{RepoID: repoID, TopicID: topic.ID}); err != nil {
+		return err
+	}
+	_, err = e.Exec("UPDATE `topic` SET repo_count = repo_count + 1 WHERE id = ?", topic.ID)
+	return err
+}
+
+func removeTopicFromRepo(e Engine, repoID int64, topic *Topic) error {
+	if _, err := e.Delete(&RepoTopic{RepoID: repoID, TopicID: topic.ID}); err != nil {
+		return err
+	}
+	_, err := e.Exec("UPDATE `topic` SET repo_count = repo_count - 1 WHERE id = ?", topic.ID)
+	return err
+}
+
+// removeRepoTopics removes all the topics of a repository being deleted.
+func removeRepoTopics(e Engine, repoID int64) error {
+	if _, err := e.Exec("UPDATE `topic` SET repo_count = repo_count - 1 WHERE id IN (SELECT topic_id FROM `repo_topic` WHERE repo_id = ?)", repoID); err != nil {
+		return err
+	}
+	_, err := e.Delete(&RepoTopic{RepoID: repoID})
+	return err
+}
+
+// syncRepoTopics updates the topic names cached on the repository.
+func syncRepoTopics(e Engine, repoID int64) error {
+	topicNames := make([]string, 0, MaxRepoTopics)
+	if err := e.Table("topic").Cols("name").
+		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ?", repoID).
+		Asc("topic.name").
+		Find(&topicNames); err != nil {
+		return err
+	}
+
+	_, err := e.ID(repoID).Cols("topics").Update(&Repository{Topics: topicNames})
+	return err
+}
+
+// SaveTopics replaces the topics of a repository with the given topic names
+func SaveTopics(repoID int64, topicNames ...string) error {
+	topicNames, err := SanitizeTopics(topicNames)
+	if err != nil {
+		return err
+	} else if len(topicNames) > MaxRepoTopics {
+		return ErrTooManyTopics{repoID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	currentTopics := make([]*Topic, 0, MaxRepoTopics)
+	if err = sess.Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ?", repoID).
+		Find(&currentTopics); err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(currentTopics))
+	for _, topic := range currentTopics {
+		if com.IsSliceContainsStr(topicNames, topic.Name) {
+			keep[topic.Name] = true
+		} else if err = removeTopicFromRepo(sess, repoID, topic); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range topicNames {
+		if keep[name] {
+			continue
+		} else if err = addTopicByNameToRepo(sess, repoID, name); err != nil {
+			return err
+		}
+	}
+
+	if err = syncRepoTopics(sess, repoID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// AddTopic adds a topic to a repository if it doesn't have it yet
+func AddTopic(repoID int64, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !ValidateTopic(name) {
+		return ErrTopicNotValid{name}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := sess.Where("repo_id = ?", repoID).Count(new(RepoTopic))
+	if err != nil {
+		return err
+	}
+
+	topic, err := getTopicByName(sess, name)
+	if err != nil && !IsErrTopicNotExist(err) {
+		return err
+	} else if topic != nil {
+		has, err := sess.Get(&RepoTopic{RepoID: repoID, TopicID: topic.ID})
+		if err != nil {
+			return err
+		} else if has {
+			return nil
+		}
+	}
+
+	if count >= MaxRepoTopics {
+		return ErrTooManyTopics{repoID}
+	}
+	if err = addTopicByNameToRepo(sess, repoID, name); err != nil {
+		return err
+	} else if err = syncRepoTopics(sess, repoID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteTopic removes a topic from a repository
+func DeleteTopic(repoID int64, name string) error {
+	topic, err := GetTopicByName(strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Get(&RepoTopic{RepoID: repoID, TopicID: topic.ID})
+	if err != nil {
+		return err
+	} else if !has {
+		return ErrTopicNotExist{topic.Name}
+	}
+
+	if err = removeTopicFromRepo(sess, repoID, topic); err != nil {
+		return err
+	} else if err = syncRepoTopics(sess, repoID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTopic(t *testing.T) {
+	assert.True(t, ValidateTopic("golang"))
+	assert.True(t, ValidateTopic("web-framework2"))
+	assert.False(t, ValidateTopic(""))
+	assert.False(t, ValidateTopic("-golang"))
+	assert.False(t, ValidateTopic("Golang"))
+	assert.False(t, ValidateTopic("go lang"))
+	assert.False(t, ValidateTopic("abcdefghijklmnopqrstuvwxyz0123456789"))
+}
+
+func TestFindTopics(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	topics, err := FindTopics(&FindTopicOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 3)
+	assert.EqualValues(t, "golang", topics[0].Name)
+
+	topics, err = FindTopics(&FindTopicOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 2)
+	assert.EqualValues(t, "database", topics[0].Name)
+
+	topics, err = FindTopics(&FindTopicOptions{Keyword: "go"})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 1)
+	assert.EqualValues(t, 1, topics[0].ID)
+}
+
+func TestSaveTopics(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, SaveTopics(1, "golang", "SQL", " web ", "sql", ""))
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.EqualValues(t, []string{"golang", "sql", "web"}, repo.Topics)
+
+	topic := AssertExistsAndLoadBean(t, &Topic{Name: "web"}).(*Topic)
+	assert.EqualValues(t, 1, topic.RepoCount)
+	AssertNotExistsBean(t, &RepoTopic{RepoID: 1, TopicID: 2})
+
+	err := SaveTopics(1, "not valid")
+	assert.True(t, IsErrTopicNotValid(err))
+
+	CheckConsistencyFor(t, &Repository{}, &Topic{})
+}
+
+func TestAddDeleteTopic(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, AddTopic(4, "SQL"))
+	assert.NoError(t, AddTopic(4, "sql"))
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	assert.EqualValues(t, []string{"golang", "sql"}, repo.Topics)
+
+	assert.NoError(t, DeleteTopic(4, "golang"))
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	assert.EqualValues(t, []string{"sql"}, repo.Topics)
+
+	assert.True(t, IsErrTopicNotExist(DeleteTopic(4, "golang")))
+	assert.True(t, IsErrTopicNotExist(DeleteTopic(4, "unknown")))
+
+	CheckConsistencyFor(t, &Repository{}, &Topic{})
+}
+
+func TestSearchRepositoryByTopic(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{Topic: "golang", PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, repos, 2)
+
+	repos, count, err = SearchRepositoryByName(&SearchRepoOptions{Keyword: "database", PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.EqualValues(t, 1, repos[0].ID)
+}
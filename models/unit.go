@@ -16,6 +16,7 @@ const (
 	UnitTypeWiki                                // 5 Wiki
 	UnitTypeExternalWiki                        // 6 ExternalWiki
 	UnitTypeExternalTracker                     // 7 ExternalTracker
+	UnitTypeProjects                            // 8 Projects
 )
 
 var (
@@ -28,6 +29,7 @@ var (
 		UnitTypeWiki,
 		UnitTypeExternalWiki,
 		UnitTypeExternalTracker,
+		UnitTypeProjects,
 	}
 
 	// defaultRepoUnits contains the default unit types
@@ -37,6 +39,7 @@ var (
 		UnitTypePullRequests,
 		UnitTypeReleases,
 		UnitTypeWiki,
+		UnitTypeProjects,
 	}
 
 	// MustRepoUnits contains the units could not be disabled currently
@@ -126,6 +129,14 @@ var (
 		4,
 	}
 
+	UnitProjects = Unit{
+		UnitTypeProjects,
+		"repo.projects",
+		"/projects",
+		"repo.projects.desc",
+		5,
+	}
+
 	// Units contains all the units
 	Units = map[UnitType]Unit{
 		UnitTypeCode:            UnitCode,
@@ -135,5 +146,6 @@ var (
 		UnitTypeReleases:        UnitReleases,
 		UnitTypeWiki:            UnitWiki,
 		UnitTypeExternalWiki:    UnitExternalWiki,
+		UnitTypeProjects:        UnitProjects,
 	}
 )
@@ -278,5 +278,7 @@ func pushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 	}); err != nil {
 		return nil, fmt.Errorf("CommitRepoAction: %v", err)
 	}
+
+	go AddPushMirrorsToQueue(repo.ID)
 	return repo, nil
 }
@@ -713,9 +713,19 @@ func CreateUser(u *User) (err error) {
 		return err
 	} else if err = os.MkdirAll(UserPath(u.Name), os.ModePerm); err != nil {
 		return err
+	} else if err = prepareSystemWebhooks(sess, HookEventUser, &api.UserPayload{
+		Action: api.HookUserCreated,
+		User:   u.APIFormat(),
+	}); err != nil {
+		return fmt.Errorf("prepareSystemWebhooks: %v", err)
 	}
 
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	go HookQueue.Add(0)
+	return nil
 }
 
 func countUsers(e Engine) int64 {
@@ -972,12 +982,17 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&Follow{FollowID: u.ID},
 		&Action{UserID: u.ID},
 		&IssueUser{UID: u.ID},
+		&Reaction{UserID: u.ID},
 		&EmailAddress{UID: u.ID},
 		&UserOpenID{UID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deleteOAuth2ApplicationsByUserID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteOAuth2ApplicationsByUserID: %v", err)
+	}
+
 	// ***** START: PublicKey *****
 	keys := make([]*PublicKey, 0, 10)
 	if err = e.Find(&keys, &PublicKey{OwnerID: u.ID}); err != nil {
@@ -1043,9 +1058,17 @@ func DeleteUser(u *User) (err error) {
 		return err
 	}
 
+	if err = prepareSystemWebhooks(sess, HookEventUser, &api.UserPayload{
+		Action: api.HookUserDeleted,
+		User:   u.APIFormat(),
+	}); err != nil {
+		return fmt.Errorf("prepareSystemWebhooks: %v", err)
+	}
+
 	if err = sess.Commit(); err != nil {
 		return err
 	}
+	go HookQueue.Add(0)
 
 	return RewriteAllPublicKeys()
 }
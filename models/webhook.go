@@ -6,7 +6,10 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -64,10 +67,18 @@ func IsValidHookContentType(name string) bool {
 
 // HookEvents is a set of web hook events
 type HookEvents struct {
-	Create      bool `json:"create"`
-	Push        bool `json:"push"`
-	PullRequest bool `json:"pull_request"`
-	Repository  bool `json:"repository"`
+	Create            bool `json:"create"`
+	Delete            bool `json:"delete"`
+	Fork              bool `json:"fork"`
+	Issues            bool `json:"issues"`
+	IssueComment      bool `json:"issue_comment"`
+	Push              bool `json:"push"`
+	PullRequest       bool `json:"pull_request"`
+	PullRequestReview bool `json:"pull_request_review"`
+	Release           bool `json:"release"`
+	Repository        bool `json:"repository"`
+	User              bool `json:"user"`
+	Member            bool `json:"member"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -105,6 +116,10 @@ type Webhook struct {
 	Meta         string     `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus   HookStatus // Last delivery status
 
+	// System webhooks are managed by site administrators and fire for all repositories,
+	// they are the only ones receiving user events.
+	IsSystemWebhook bool `xorm:"INDEX NOT NULL DEFAULT false"`
+
 	Created     time.Time `xorm:"-"`
 	CreatedUnix int64     `xorm:"INDEX created"`
 	Updated     time.Time `xorm:"-"`
@@ -158,6 +173,30 @@ func (w *Webhook) HasCreateEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Create)
 }
 
+// HasDeleteEvent returns true if hook enabled delete event.
+func (w *Webhook) HasDeleteEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Delete)
+}
+
+// HasForkEvent returns true if hook enabled fork event.
+func (w *Webhook) HasForkEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Fork)
+}
+
+// HasIssuesEvent returns true if hook enabled issues event.
+func (w *Webhook) HasIssuesEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Issues)
+}
+
+// HasIssueCommentEvent returns true if hook enabled issue comment event.
+func (w *Webhook) HasIssueCommentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.IssueComment)
+}
+
 // HasPushEvent returns true if hook enabled push event.
 func (w *Webhook) HasPushEvent() bool {
 	return w.PushOnly || w.SendEverything ||
@@ -170,23 +209,61 @@ func (w *Webhook) HasPullRequestEvent() bool {
 		(w.ChooseEvents && w.HookEvents.PullRequest)
 }
 
+// HasPullRequestReviewEvent returns true if hook enabled pull request review event.
+func (w *Webhook) HasPullRequestReviewEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.PullRequestReview)
+}
+
+// HasReleaseEvent returns true if hook enabled release event.
+func (w *Webhook) HasReleaseEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Release)
+}
+
 // HasRepositoryEvent returns if hook enabled repository event.
 func (w *Webhook) HasRepositoryEvent() bool {
 	return w.SendEverything ||
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasUserEvent returns if hook enabled user event,
+// which is only available to system webhooks.
+func (w *Webhook) HasUserEvent() bool {
+	return w.IsSystemWebhook && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.User))
+}
+
+// HasMemberEvent returns if hook enabled organization member event,
+// which is not available to the webhooks of a repository.
+func (w *Webhook) HasMemberEvent() bool {
+	return w.RepoID == 0 && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Member))
+}
+
 // EventsArray returns an array of hook events
 func (w *Webhook) EventsArray() []string {
-	events := make([]string, 0, 3)
-	if w.HasCreateEvent() {
-		events = append(events, "create")
-	}
-	if w.HasPushEvent() {
-		events = append(events, "push")
-	}
-	if w.HasPullRequestEvent() {
-		events = append(events, "pull_request")
+	events := make([]string, 0, 10)
+	for _, e := range []struct {
+		event HookEventType
+		has   func() bool
+	}{
+		{HookEventCreate, w.HasCreateEvent},
+		{HookEventDelete, w.HasDeleteEvent},
+		{HookEventFork, w.HasForkEvent},
+		{HookEventIssues, w.HasIssuesEvent},
+		{HookEventIssueComment, w.HasIssueCommentEvent},
+		{HookEventPush, w.HasPushEvent},
+		{HookEventPullRequest, w.HasPullRequestEvent},
+		{HookEventPullRequestReview, w.HasPullRequestReviewEvent},
+		{HookEventRelease, w.HasReleaseEvent},
+		{HookEventRepository, w.HasRepositoryEvent},
+		{HookEventUser, w.HasUserEvent},
+		{HookEventMember, w.HasMemberEvent},
+	} {
+		if e.has() {
+			events = append(events, string(e.event))
+		}
 	}
 	return events
 }
@@ -268,6 +345,37 @@ func GetWebhooksByOrgID(orgID int64) (ws []*Webhook, err error) {
 	return ws, err
 }
 
+// GetSystemWebhook returns system webhook by given ID.
+func GetSystemWebhook(id int64) (*Webhook, error) {
+	w := new(Webhook)
+	has, err := x.
+		Where("id=?", id).
+		And("is_system_webhook=?", true).
+		Get(w)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrWebhookNotExist{id}
+	}
+	return w, nil
+}
+
+// GetSystemWebhooks returns all system webhooks.
+func GetSystemWebhooks() ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, x.
+		Where("is_system_webhook=?", true).
+		Find(&webhooks)
+}
+
+func getActiveSystemWebhooks(e Engine) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, e.
+		Where("is_system_webhook=?", true).
+		And("is_active=?", true).
+		Find(&webhooks)
+}
+
 // UpdateWebhook updates information of webhook.
 func UpdateWebhook(w *Webhook) error {
 	_, err := x.ID(w.ID).AllCols().Update(w)
@@ -316,6 +424,14 @@ func DeleteWebhookByOrgID(orgID, id int64) error {
 	})
 }
 
+// DeleteSystemWebhook deletes system webhook by given ID.
+func DeleteSystemWebhook(id int64) error {
+	if _, err := GetSystemWebhook(id); err != nil {
+		return err
+	}
+	return deleteWebhook(&Webhook{ID: id})
+}
+
 //   ___ ___                __   ___________              __
 //  /   |   \  ____   ____ |  | _\__    ___/____    _____|  | __
 // /    ~    \/  _ \ /  _ \|  |/ / |    |  \__  \  /  ___/  |/ /
@@ -376,10 +492,18 @@ type HookEventType string
 
 // Types of hook events
 const (
-	HookEventCreate      HookEventType = "create"
-	HookEventPush        HookEventType = "push"
-	HookEventPullRequest HookEventType = "pull_request"
-	HookEventRepository  HookEventType = "repository"
+	HookEventCreate            HookEventType = "create"
+	HookEventDelete            HookEventType = "delete"
+	HookEventFork              HookEventType = "fork"
+	HookEventIssues            HookEventType = "issues"
+	HookEventIssueComment      HookEventType = "issue_comment"
+	HookEventPush              HookEventType = "push"
+	HookEventPullRequest       HookEventType = "pull_request"
+	HookEventPullRequestReview HookEventType = "pull_request_review"
+	HookEventRelease           HookEventType = "release"
+	HookEventRepository        HookEventType = "repository"
+	HookEventUser              HookEventType = "user"
+	HookEventMember            HookEventType = "member"
 )
 
 // HookRequest represents hook task request information.
@@ -411,6 +535,11 @@ type HookTask struct {
 	Delivered       int64
 	DeliveredString string `xorm:"-"`
 
+	// Failed deliveries are retried until MaxAttempts is reached,
+	// IsDelivered stays false while a retry is pending.
+	Attempts        int   `xorm:"NOT NULL DEFAULT 0"`
+	NextAttemptUnix int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+
 	// History info.
 	IsSucceed       bool
 	RequestContent  string        `xorm:"TEXT"`
@@ -479,13 +608,92 @@ func createHookTask(e Engine, t *HookTask) error {
 	return err
 }
 
+// GetHookTaskByUUID returns the hook task of a webhook with the given delivery UUID.
+func GetHookTaskByUUID(hookID int64, uuid string) (*HookTask, error) {
+	t := &HookTask{
+		HookID: hookID,
+		UUID:   uuid,
+	}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskNotExist{hookID, uuid}
+	}
+	return t, nil
+}
+
+// ReplayHookTask creates a new delivery of the payload of a hook task,
+// which is sent to the current URL of the webhook.
+func ReplayHookTask(w *Webhook, uuid string) (*HookTask, error) {
+	t, err := GetHookTaskByUUID(w.ID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payloads delivered before the secret was dropped from them still contain it.
+	content := t.PayloadContent
+	if (t.Type == GITEA || t.Type == GOGS) && !setting.Webhook.SendSecretInPayload {
+		data, err := removePayloadSecret([]byte(content))
+		if err != nil {
+			return nil, err
+		}
+		content = string(data)
+	}
+
+	replay := &HookTask{
+		RepoID:         t.RepoID,
+		HookID:         w.ID,
+		UUID:           gouuid.NewV4().String(),
+		Type:           w.HookTaskType,
+		URL:            w.URL,
+		PayloadContent: content,
+		ContentType:    w.ContentType,
+		EventType:      t.EventType,
+		IsSSL:          w.IsSSL,
+	}
+	if _, err = x.Insert(replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
 // UpdateHookTask updates information of hook task.
 func UpdateHookTask(t *HookTask) error {
 	_, err := x.ID(t.ID).AllCols().Update(t)
 	return err
 }
 
-// PrepareWebhook adds special webhook to task queue for given payload.
+// secretlessPayloader leaves the secret out of a Gitea or Gogs payload, the
+// signature headers of the delivery are used to authenticate it instead.
+type secretlessPayloader struct {
+	api.Payloader
+}
+
+// JSONPayload implements api.Payloader
+func (p secretlessPayloader) JSONPayload() ([]byte, error) {
+	data, err := p.Payloader.JSONPayload()
+	if err != nil {
+		return nil, err
+	}
+	return removePayloadSecret(data)
+}
+
+// removePayloadSecret removes the secret field from a JSON payload.
+func removePayloadSecret(payload []byte) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["secret"]; !ok {
+		return payload, nil
+	}
+	delete(fields, "secret")
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// PrepareWebhook adds special webhook to task queue for given payload,
+// repo is nil for events which are not about a repository.
 func PrepareWebhook(w *Webhook, repo *Repository, event HookEventType, p api.Payloader) error {
 	return prepareWebhook(x, w, repo, event, p)
 }
@@ -496,6 +704,22 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 		if !w.HasCreateEvent() {
 			return nil
 		}
+	case HookEventDelete:
+		if !w.HasDeleteEvent() {
+			return nil
+		}
+	case HookEventFork:
+		if !w.HasForkEvent() {
+			return nil
+		}
+	case HookEventIssues:
+		if !w.HasIssuesEvent() {
+			return nil
+		}
+	case HookEventIssueComment:
+		if !w.HasIssueCommentEvent() {
+			return nil
+		}
 	case HookEventPush:
 		if !w.HasPushEvent() {
 			return nil
@@ -504,10 +728,26 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 		if !w.HasPullRequestEvent() {
 			return nil
 		}
+	case HookEventPullRequestReview:
+		if !w.HasPullRequestReviewEvent() {
+			return nil
+		}
+	case HookEventRelease:
+		if !w.HasReleaseEvent() {
+			return nil
+		}
 	case HookEventRepository:
 		if !w.HasRepositoryEvent() {
 			return nil
 		}
+	case HookEventUser:
+		if !w.HasUserEvent() {
+			return nil
+		}
+	case HookEventMember:
+		if !w.HasMemberEvent() {
+			return nil
+		}
 	}
 
 	var payloader api.Payloader
@@ -530,12 +770,20 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 			return fmt.Errorf("GetDingtalkPayload: %v", err)
 		}
 	default:
-		p.SetSecret(w.Secret)
-		payloader = p
+		if setting.Webhook.SendSecretInPayload {
+			p.SetSecret(w.Secret)
+			payloader = p
+		} else {
+			payloader = secretlessPayloader{p}
+		}
 	}
 
+	var repoID int64
+	if repo != nil {
+		repoID = repo.ID
+	}
 	if err = createHookTask(e, &HookTask{
-		RepoID:      repo.ID,
+		RepoID:      repoID,
 		HookID:      w.ID,
 		Type:        w.HookTaskType,
 		URL:         w.URL,
@@ -570,9 +818,11 @@ func prepareWebhooks(e Engine, repo *Repository, event HookEventType, p api.Payl
 		ws = append(ws, orgHooks...)
 	}
 
-	if len(ws) == 0 {
-		return nil
+	systemHooks, err := getActiveSystemWebhooks(e)
+	if err != nil {
+		return fmt.Errorf("getActiveSystemWebhooks: %v", err)
 	}
+	ws = append(ws, systemHooks...)
 
 	for _, w := range ws {
 		if err = prepareWebhook(e, w, repo, event, p); err != nil {
@@ -582,8 +832,77 @@ func prepareWebhooks(e Engine, repo *Repository, event HookEventType, p api.Payl
 	return nil
 }
 
+// prepareOrgWebhooks adds new webhooks of the organization and the system webhooks
+// to task queue for given payload, which is not about a repository.
+// The hook tasks are queued with a zero repository ID.
+func prepareOrgWebhooks(e Engine, org *User, event HookEventType, p api.Payloader) error {
+	ws, err := getActiveWebhooksByOrgID(e, org.ID)
+	if err != nil {
+		return fmt.Errorf("GetActiveWebhooksByOrgID: %v", err)
+	}
+
+	systemHooks, err := getActiveSystemWebhooks(e)
+	if err != nil {
+		return fmt.Errorf("getActiveSystemWebhooks: %v", err)
+	}
+	ws = append(ws, systemHooks...)
+
+	for _, w := range ws {
+		if err = prepareWebhook(e, w, nil, event, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareSystemWebhooks adds the system webhooks to task queue for given payload,
+// which is not about a repository. The hook tasks are queued with a zero repository ID.
+func prepareSystemWebhooks(e Engine, event HookEventType, p api.Payloader) error {
+	ws, err := getActiveSystemWebhooks(e)
+	if err != nil {
+		return fmt.Errorf("getActiveSystemWebhooks: %v", err)
+	}
+
+	for _, w := range ws {
+		if err = prepareWebhook(e, w, nil, event, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signature returns the hex encoded HMAC-SHA256 of the payload keyed with the secret.
+func signature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// maxRetryDelay is the longest time to wait between two attempts of a delivery.
+const maxRetryDelay = 24 * time.Hour
+
+// retryDelay returns how long to wait before the next attempt after the given number of
+// failed ones, the delay doubles after every attempt.
+func retryDelay(attempts int) time.Duration {
+	delay := time.Duration(setting.Webhook.RetryBackoff) * time.Second
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
 func (t *HookTask) deliver() {
-	t.IsDelivered = true
+	t.Attempts++
+
+	w, err := GetWebhookByID(t.HookID)
+	if err != nil {
+		log.Error(5, "GetWebhookByID: %v", err)
+		t.IsDelivered = true
+		return
+	}
 
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 	req := httplib.Post(t.URL).SetTimeout(timeout, timeout).
@@ -602,6 +921,13 @@ func (t *HookTask) deliver() {
 		req.Param("payload", t.PayloadContent)
 	}
 
+	// Sign the payload with the secret of the webhook, so receivers can verify it was sent by us.
+	if len(w.Secret) > 0 {
+		sig := signature(w.Secret, t.PayloadContent)
+		req = req.Header("X-Gitea-Signature", sig).
+			Header("X-Gogs-Signature", sig)
+	}
+
 	// Record delivery information.
 	t.RequestInfo = &HookRequest{
 		Headers: map[string]string{},
@@ -616,18 +942,18 @@ func (t *HookTask) deliver() {
 
 	defer func() {
 		t.Delivered = time.Now().UnixNano()
+		t.IsDelivered = true
 		if t.IsSucceed {
 			log.Trace("Hook delivered: %s", t.UUID)
+		} else if t.Attempts < setting.Webhook.MaxAttempts {
+			t.IsDelivered = false
+			t.NextAttemptUnix = time.Now().Add(retryDelay(t.Attempts)).Unix()
+			log.Trace("Hook delivery failed, will retry: %s", t.UUID)
 		} else {
 			log.Trace("Hook delivery failed: %s", t.UUID)
 		}
 
 		// Update webhook last delivery status.
-		w, err := GetWebhookByID(t.HookID)
-		if err != nil {
-			log.Error(5, "GetWebhookByID: %v", err)
-			return
-		}
 		if t.IsSucceed {
 			w.LastStatus = HookStatusSucceed
 		} else {
@@ -661,41 +987,54 @@ func (t *HookTask) deliver() {
 	t.ResponseInfo.Body = string(p)
 }
 
-// DeliverHooks checks and delivers undelivered hooks.
-// TODO: shoot more hooks at same time.
-func DeliverHooks() {
+// deliverHookTasks delivers the given hook tasks one after another and saves the results.
+func deliverHookTasks(tasks []*HookTask) {
+	for _, t := range tasks {
+		t.deliver()
+		if err := UpdateHookTask(t); err != nil {
+			log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
+		}
+	}
+}
+
+// deliverDueHookTasks delivers all the undelivered hook tasks whose next attempt is due.
+func deliverDueHookTasks() {
 	tasks := make([]*HookTask, 0, 10)
-	err := x.Where("is_delivered=?", false).Find(&tasks)
-	if err != nil {
+	if err := x.Where("is_delivered=? AND next_attempt_unix<=?", false, time.Now().Unix()).Find(&tasks); err != nil {
 		log.Error(4, "DeliverHooks: %v", err)
 		return
 	}
+	deliverHookTasks(tasks)
+}
 
-	// Update hook task status.
-	for _, t := range tasks {
-		t.deliver()
+// DeliverHooks checks and delivers undelivered hooks,
+// failed deliveries are retried once their next attempt is due.
+// TODO: shoot more hooks at same time.
+func DeliverHooks() {
+	deliverDueHookTasks()
 
-		if err := UpdateHookTask(t); err != nil {
-			log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
-		}
+	retryInterval := time.Duration(setting.Webhook.RetryBackoff) * time.Second
+	if retryInterval <= 0 {
+		retryInterval = time.Minute
 	}
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
 
 	// Start listening on new hook requests.
-	for repoID := range HookQueue.Queue() {
-		log.Trace("DeliverHooks [repo_id: %v]", repoID)
-		HookQueue.Remove(repoID)
-
-		tasks = make([]*HookTask, 0, 5)
-		if err := x.Where("repo_id=? AND is_delivered=?", repoID, false).Find(&tasks); err != nil {
-			log.Error(4, "Get repository [%s] hook tasks: %v", repoID, err)
-			continue
-		}
-		for _, t := range tasks {
-			t.deliver()
-			if err := UpdateHookTask(t); err != nil {
-				log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
+	for {
+		select {
+		case <-ticker.C:
+			deliverDueHookTasks()
+		case repoID := <-HookQueue.Queue():
+			log.Trace("DeliverHooks [repo_id: %v]", repoID)
+			HookQueue.Remove(repoID)
+
+			tasks := make([]*HookTask, 0, 5)
+			if err := x.Where("repo_id=? AND is_delivered=? AND next_attempt_unix<=?", repoID, false, time.Now().Unix()).Find(&tasks); err != nil {
+				log.Error(4, "Get repository [%s] hook tasks: %v", repoID, err)
 				continue
 			}
+			deliverHookTasks(tasks)
 		}
 	}
 }
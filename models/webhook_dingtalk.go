@@ -49,6 +49,102 @@ func getDingtalkCreatePayload(p *api.CreatePayload) (*DingtalkPayload, error) {
 	}, nil
 }
 
+func getDingtalkDeletePayload(p *api.DeletePayload) (*DingtalkPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        title,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: "view repository",
+			SingleURL:   p.Repo.HTMLURL,
+		},
+	}, nil
+}
+
+func getDingtalkForkPayload(p *api.ForkPayload) (*DingtalkPayload, error) {
+	title := fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName)
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        title,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: fmt.Sprintf("view forked repo %s", p.Forkee.FullName),
+			SingleURL:   p.Forkee.HTMLURL,
+		},
+	}, nil
+}
+
+func getDingtalkIssuesPayload(p *api.IssuePayload) (*DingtalkPayload, error) {
+	var text, title string
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Issue opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueClosed:
+		title = fmt.Sprintf("[%s] Issue closed: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Issue re-opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Issue edited: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueAssigned:
+		title = fmt.Sprintf("[%s] Issue assigned to %s: #%d %s", p.Repository.FullName,
+			p.Issue.Assignee.UserName, p.Index, p.Issue.Title)
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Issue unassigned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Issue labels updated: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Issue labels cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	}
+	text = p.Issue.Body
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        text,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: "view issue",
+			SingleURL:   fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+		},
+	}, nil
+}
+
+func getDingtalkIssueCommentPayload(p *api.IssueCommentPayload) (*DingtalkPayload, error) {
+	var text, title, url string
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+	case api.HookIssueCommentEdited:
+		title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+	case api.HookIssueCommentDeleted:
+		title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = title
+		url = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+	}
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        text,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: "view comment",
+			SingleURL:   url,
+		},
+	}, nil
+}
+
 func getDingtalkPushPayload(p *api.PushPayload) (*DingtalkPayload, error) {
 	var (
 		branchName = git.RefEndName(p.Ref)
@@ -147,6 +243,64 @@ func getDingtalkPullRequestPayload(p *api.PullRequestPayload) (*DingtalkPayload,
 	}, nil
 }
 
+func getDingtalkPullRequestReviewPayload(p *api.PullRequestReviewPayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		title = fmt.Sprintf("[%s] Pull request approved: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+	case api.ReviewStateRequestChanges:
+		title = fmt.Sprintf("[%s] Pull request changes requested: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+	default:
+		title = fmt.Sprintf("[%s] Pull request reviewed: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+	}
+	text := p.Review.Body
+	if text == "" {
+		text = title
+	}
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        text,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: "view review",
+			SingleURL:   p.Review.HTMLURL,
+		},
+	}, nil
+}
+
+func getDingtalkReleasePayload(p *api.ReleasePayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case api.HookReleasePublished:
+		title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+	case api.HookReleaseUpdated:
+		title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+	case api.HookReleaseDeleted:
+		title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+		return &DingtalkPayload{
+			MsgType: "text",
+			Text: struct {
+				Content string `json:"content"`
+			}{
+				Content: title,
+			},
+		}, nil
+	}
+
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        title,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: "view releases",
+			SingleURL:   p.Repository.HTMLURL + "/releases",
+		},
+	}, nil
+}
+
 func getDingtalkRepositoryPayload(p *api.RepositoryPayload) (*DingtalkPayload, error) {
 	var title, url string
 	switch p.Action {
@@ -178,6 +332,44 @@ func getDingtalkRepositoryPayload(p *api.RepositoryPayload) (*DingtalkPayload, e
 	return nil, nil
 }
 
+func getDingtalkUserPayload(p *api.UserPayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case api.HookUserCreated:
+		title = fmt.Sprintf("User created: %s", p.User.UserName)
+	case api.HookUserDeleted:
+		title = fmt.Sprintf("User deleted: %s", p.User.UserName)
+	}
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: title,
+		},
+	}, nil
+}
+
+func getDingtalkMemberPayload(p *api.MemberPayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case api.HookMemberAdded:
+		title = fmt.Sprintf("[%s] Member added: %s", p.Organization.UserName, p.Member.UserName)
+	case api.HookMemberRemoved:
+		title = fmt.Sprintf("[%s] Member removed: %s", p.Organization.UserName, p.Member.UserName)
+	}
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: title,
+		},
+	}, nil
+}
+
 // GetDingtalkPayload converts a ding talk webhook into a DingtalkPayload
 func GetDingtalkPayload(p api.Payloader, event HookEventType, meta string) (*DingtalkPayload, error) {
 	s := new(DingtalkPayload)
@@ -185,12 +377,28 @@ func GetDingtalkPayload(p api.Payloader, event HookEventType, meta string) (*Din
 	switch event {
 	case HookEventCreate:
 		return getDingtalkCreatePayload(p.(*api.CreatePayload))
+	case HookEventDelete:
+		return getDingtalkDeletePayload(p.(*api.DeletePayload))
+	case HookEventFork:
+		return getDingtalkForkPayload(p.(*api.ForkPayload))
+	case HookEventIssues:
+		return getDingtalkIssuesPayload(p.(*api.IssuePayload))
+	case HookEventIssueComment:
+		return getDingtalkIssueCommentPayload(p.(*api.IssueCommentPayload))
 	case HookEventPush:
 		return getDingtalkPushPayload(p.(*api.PushPayload))
 	case HookEventPullRequest:
 		return getDingtalkPullRequestPayload(p.(*api.PullRequestPayload))
+	case HookEventPullRequestReview:
+		return getDingtalkPullRequestReviewPayload(p.(*api.PullRequestReviewPayload))
+	case HookEventRelease:
+		return getDingtalkReleasePayload(p.(*api.ReleasePayload))
 	case HookEventRepository:
 		return getDingtalkRepositoryPayload(p.(*api.RepositoryPayload))
+	case HookEventUser:
+		return getDingtalkUserPayload(p.(*api.UserPayload))
+	case HookEventMember:
+		return getDingtalkMemberPayload(p.(*api.MemberPayload))
 	}
 
 	return s, nil
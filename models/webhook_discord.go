@@ -115,6 +115,147 @@ func getDiscordCreatePayload(p *api.CreatePayload, meta *DiscordMeta) (*DiscordP
 	}, nil
 }
 
+func getDiscordDeletePayload(p *api.DeletePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: title,
+				URL:   p.Repo.HTMLURL,
+				Color: warnColor,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
+func getDiscordForkPayload(p *api.ForkPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	title := fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName)
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: title,
+				URL:   p.Forkee.HTMLURL,
+				Color: successColor,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
+func getDiscordIssuesPayload(p *api.IssuePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var text, title string
+	var color int
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Issue opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueClosed:
+		title = fmt.Sprintf("[%s] Issue closed: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = failedColor
+		text = p.Issue.Body
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Issue re-opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Issue edited: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueAssigned:
+		title = fmt.Sprintf("[%s] Issue assigned to %s: #%d %s", p.Repository.FullName,
+			p.Issue.Assignee.UserName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = successColor
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Issue unassigned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Issue labels updated: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Issue labels cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+				Color:       color,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
+func getDiscordIssueCommentPayload(p *api.IssueCommentPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var text, title, url string
+	var color int
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = successColor
+	case api.HookIssueCommentEdited:
+		title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = warnColor
+	case api.HookIssueCommentDeleted:
+		title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		url = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         url,
+				Color:       color,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
 func getDiscordPushPayload(p *api.PushPayload, meta *DiscordMeta) (*DiscordPayload, error) {
 	var (
 		branchName = git.RefEndName(p.Ref)
@@ -232,6 +373,76 @@ func getDiscordPullRequestPayload(p *api.PullRequestPayload, meta *DiscordMeta)
 	}, nil
 }
 
+func getDiscordPullRequestReviewPayload(p *api.PullRequestReviewPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title string
+	var color int
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		title = fmt.Sprintf("[%s] Pull request approved: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = successColor
+	case api.ReviewStateRequestChanges:
+		title = fmt.Sprintf("[%s] Pull request changes requested: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = failedColor
+	default:
+		title = fmt.Sprintf("[%s] Pull request reviewed: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: p.Review.Body,
+				URL:         p.Review.HTMLURL,
+				Color:       color,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
+func getDiscordReleasePayload(p *api.ReleasePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title, url string
+	var color int
+	switch p.Action {
+	case api.HookReleasePublished:
+		title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+		url = p.Repository.HTMLURL + "/releases"
+		color = successColor
+	case api.HookReleaseUpdated:
+		title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+		url = p.Repository.HTMLURL + "/releases"
+		color = warnColor
+	case api.HookReleaseDeleted:
+		title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: p.Release.Note,
+				URL:         url,
+				Color:       color,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Sender.UserName,
+					URL:     setting.AppURL + p.Sender.UserName,
+					IconURL: p.Sender.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
 func getDiscordRepositoryPayload(p *api.RepositoryPayload, meta *DiscordMeta) (*DiscordPayload, error) {
 	var title, url string
 	var color int
@@ -263,6 +474,62 @@ func getDiscordRepositoryPayload(p *api.RepositoryPayload, meta *DiscordMeta) (*
 	}, nil
 }
 
+func getDiscordUserPayload(p *api.UserPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title, url string
+	var color int
+	switch p.Action {
+	case api.HookUserCreated:
+		title = fmt.Sprintf("User created: %s", p.User.UserName)
+		url = setting.AppURL + p.User.UserName
+		color = successColor
+	case api.HookUserDeleted:
+		title = fmt.Sprintf("User deleted: %s", p.User.UserName)
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: title,
+				URL:   url,
+				Color: color,
+			},
+		},
+	}, nil
+}
+
+func getDiscordMemberPayload(p *api.MemberPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case api.HookMemberAdded:
+		title = fmt.Sprintf("[%s] Member added: %s", p.Organization.UserName, p.Member.UserName)
+		color = successColor
+	case api.HookMemberRemoved:
+		title = fmt.Sprintf("[%s] Member removed: %s", p.Organization.UserName, p.Member.UserName)
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: title,
+				URL:   setting.AppURL + p.Organization.UserName,
+				Color: color,
+				Author: DiscordEmbedAuthor{
+					Name:    p.Member.UserName,
+					URL:     setting.AppURL + p.Member.UserName,
+					IconURL: p.Member.AvatarURL,
+				},
+			},
+		},
+	}, nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (*DiscordPayload, error) {
 	s := new(DiscordPayload)
@@ -275,12 +542,28 @@ func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (*Disc
 	switch event {
 	case HookEventCreate:
 		return getDiscordCreatePayload(p.(*api.CreatePayload), discord)
+	case HookEventDelete:
+		return getDiscordDeletePayload(p.(*api.DeletePayload), discord)
+	case HookEventFork:
+		return getDiscordForkPayload(p.(*api.ForkPayload), discord)
+	case HookEventIssues:
+		return getDiscordIssuesPayload(p.(*api.IssuePayload), discord)
+	case HookEventIssueComment:
+		return getDiscordIssueCommentPayload(p.(*api.IssueCommentPayload), discord)
 	case HookEventPush:
 		return getDiscordPushPayload(p.(*api.PushPayload), discord)
 	case HookEventPullRequest:
 		return getDiscordPullRequestPayload(p.(*api.PullRequestPayload), discord)
+	case HookEventPullRequestReview:
+		return getDiscordPullRequestReviewPayload(p.(*api.PullRequestReviewPayload), discord)
+	case HookEventRelease:
+		return getDiscordReleasePayload(p.(*api.ReleasePayload), discord)
 	case HookEventRepository:
 		return getDiscordRepositoryPayload(p.(*api.RepositoryPayload), discord)
+	case HookEventUser:
+		return getDiscordUserPayload(p.(*api.UserPayload), discord)
+	case HookEventMember:
+		return getDiscordMemberPayload(p.(*api.MemberPayload), discord)
 	}
 
 	return s, nil
@@ -106,6 +106,106 @@ func getSlackCreatePayload(p *api.CreatePayload, slack *SlackMeta) (*SlackPayloa
 	}, nil
 }
 
+func getSlackDeletePayload(p *api.DeletePayload, slack *SlackMeta) (*SlackPayload, error) {
+	refName := git.RefEndName(p.Ref)
+	repoLink := SlackLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	text := fmt.Sprintf("[%s:%s] %s deleted by %s", repoLink, refName, p.RefType, p.Sender.UserName)
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackForkPayload(p *api.ForkPayload, slack *SlackMeta) (*SlackPayload, error) {
+	baseLink := SlackLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	forkLink := SlackLinkFormatter(p.Forkee.HTMLURL, p.Forkee.FullName)
+	text := fmt.Sprintf("%s is forked to %s", baseLink, forkLink)
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackIssuesPayload(p *api.IssuePayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+		fmt.Sprintf("#%d %s", p.Index, p.Issue.Title))
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueOpened:
+		text = fmt.Sprintf("[%s] Issue submitted by %s", p.Repository.FullName, senderLink)
+		title = titleLink
+		attachmentText = SlackTextFormatter(p.Issue.Body)
+	case api.HookIssueClosed:
+		text = fmt.Sprintf("[%s] Issue closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueReOpened:
+		text = fmt.Sprintf("[%s] Issue re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueEdited:
+		text = fmt.Sprintf("[%s] Issue edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		attachmentText = SlackTextFormatter(p.Issue.Body)
+	case api.HookIssueAssigned:
+		text = fmt.Sprintf("[%s] Issue assigned to %s: %s by %s", p.Repository.FullName,
+			SlackLinkFormatter(setting.AppURL+p.Issue.Assignee.UserName, p.Issue.Assignee.UserName),
+			titleLink, senderLink)
+	case api.HookIssueUnassigned:
+		text = fmt.Sprintf("[%s] Issue unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelUpdated:
+		text = fmt.Sprintf("[%s] Issue labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelCleared:
+		text = fmt.Sprintf("[%s] Issue labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Title: title,
+			Text:  attachmentText,
+		}},
+	}, nil
+}
+
+func getSlackIssueCommentPayload(p *api.IssueCommentPayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(p.Comment.HTMLURL, fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title))
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		text = fmt.Sprintf("[%s] New comment created by %s", p.Repository.FullName, senderLink)
+		title = titleLink
+		attachmentText = SlackTextFormatter(p.Comment.Body)
+	case api.HookIssueCommentEdited:
+		text = fmt.Sprintf("[%s] Comment edited by %s", p.Repository.FullName, senderLink)
+		title = titleLink
+		attachmentText = SlackTextFormatter(p.Comment.Body)
+	case api.HookIssueCommentDeleted:
+		text = fmt.Sprintf("[%s] Comment deleted by %s", p.Repository.FullName, senderLink)
+		title = SlackLinkFormatter(fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index),
+			fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title))
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Title: title,
+			Text:  attachmentText,
+		}},
+	}, nil
+}
+
 func getSlackPushPayload(p *api.PushPayload, slack *SlackMeta) (*SlackPayload, error) {
 	// n new commits
 	var (
@@ -198,6 +298,52 @@ func getSlackPullRequestPayload(p *api.PullRequestPayload, slack *SlackMeta) (*S
 	}, nil
 }
 
+func getSlackPullRequestReviewPayload(p *api.PullRequestReviewPayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(p.Review.HTMLURL, fmt.Sprintf("#%d %s", p.Index, p.PullRequest.Title))
+	var text string
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		text = fmt.Sprintf("[%s] Pull request approved: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.ReviewStateRequestChanges:
+		text = fmt.Sprintf("[%s] Pull request changes requested: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	default:
+		text = fmt.Sprintf("[%s] Pull request reviewed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Text:  SlackTextFormatter(p.Review.Body),
+		}},
+	}, nil
+}
+
+func getSlackReleasePayload(p *api.ReleasePayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	releaseLink := SlackLinkFormatter(p.Repository.HTMLURL+"/releases", p.Release.TagName)
+	var text string
+	switch p.Action {
+	case api.HookReleasePublished:
+		text = fmt.Sprintf("[%s] Release %s published by %s", p.Repository.FullName, releaseLink, senderLink)
+	case api.HookReleaseUpdated:
+		text = fmt.Sprintf("[%s] Release %s updated by %s", p.Repository.FullName, releaseLink, senderLink)
+	case api.HookReleaseDeleted:
+		text = fmt.Sprintf("[%s] Release %s deleted by %s", p.Repository.FullName, p.Release.TagName, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
 func getSlackRepositoryPayload(p *api.RepositoryPayload, slack *SlackMeta) (*SlackPayload, error) {
 	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
 	var text, title, attachmentText string
@@ -222,6 +368,43 @@ func getSlackRepositoryPayload(p *api.RepositoryPayload, slack *SlackMeta) (*Sla
 	}, nil
 }
 
+func getSlackUserPayload(p *api.UserPayload, slack *SlackMeta) (*SlackPayload, error) {
+	var text string
+	switch p.Action {
+	case api.HookUserCreated:
+		userLink := SlackLinkFormatter(setting.AppURL+p.User.UserName, p.User.UserName)
+		text = fmt.Sprintf("User %s created", userLink)
+	case api.HookUserDeleted:
+		text = fmt.Sprintf("User %s deleted", p.User.UserName)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackMemberPayload(p *api.MemberPayload, slack *SlackMeta) (*SlackPayload, error) {
+	memberLink := SlackLinkFormatter(setting.AppURL+p.Member.UserName, p.Member.UserName)
+	orgLink := SlackLinkFormatter(setting.AppURL+p.Organization.UserName, p.Organization.UserName)
+	var text string
+	switch p.Action {
+	case api.HookMemberAdded:
+		text = fmt.Sprintf("[%s] Member %s added", orgLink, memberLink)
+	case api.HookMemberRemoved:
+		text = fmt.Sprintf("[%s] Member %s removed", orgLink, memberLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
 // GetSlackPayload converts a slack webhook into a SlackPayload
 func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (*SlackPayload, error) {
 	s := new(SlackPayload)
@@ -234,12 +417,28 @@ func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (*SlackP
 	switch event {
 	case HookEventCreate:
 		return getSlackCreatePayload(p.(*api.CreatePayload), slack)
+	case HookEventDelete:
+		return getSlackDeletePayload(p.(*api.DeletePayload), slack)
+	case HookEventFork:
+		return getSlackForkPayload(p.(*api.ForkPayload), slack)
+	case HookEventIssues:
+		return getSlackIssuesPayload(p.(*api.IssuePayload), slack)
+	case HookEventIssueComment:
+		return getSlackIssueCommentPayload(p.(*api.IssueCommentPayload), slack)
 	case HookEventPush:
 		return getSlackPushPayload(p.(*api.PushPayload), slack)
 	case HookEventPullRequest:
 		return getSlackPullRequestPayload(p.(*api.PullRequestPayload), slack)
+	case HookEventPullRequestReview:
+		return getSlackPullRequestReviewPayload(p.(*api.PullRequestReviewPayload), slack)
+	case HookEventRelease:
+		return getSlackReleasePayload(p.(*api.ReleasePayload), slack)
 	case HookEventRepository:
 		return getSlackRepositoryPayload(p.(*api.RepositoryPayload), slack)
+	case HookEventUser:
+		return getSlackUserPayload(p.(*api.UserPayload), slack)
+	case HookEventMember:
+		return getSlackMemberPayload(p.(*api.MemberPayload), slack)
 	}
 
 	return s, nil
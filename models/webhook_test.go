@@ -5,9 +5,17 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -73,17 +81,36 @@ func TestWebhook_UpdateEvent(t *testing.T) {
 }
 
 func TestWebhook_EventsArray(t *testing.T) {
-	assert.Equal(t, []string{"create", "push", "pull_request"},
+	assert.Equal(t, []string{"create", "delete", "fork", "issues", "issue_comment",
+		"push", "pull_request", "pull_request_review", "release", "repository"},
 		(&Webhook{
+			RepoID:    1,
 			HookEvent: &HookEvent{SendEverything: true},
 		}).EventsArray(),
 	)
 
+	assert.Equal(t, []string{"create", "delete", "fork", "issues", "issue_comment",
+		"push", "pull_request", "pull_request_review", "release", "repository", "user", "member"},
+		(&Webhook{
+			IsSystemWebhook: true,
+			HookEvent:       &HookEvent{SendEverything: true},
+		}).EventsArray(),
+	)
+
 	assert.Equal(t, []string{"push"},
 		(&Webhook{
 			HookEvent: &HookEvent{PushOnly: true},
 		}).EventsArray(),
 	)
+
+	assert.Equal(t, []string{"issues", "release"},
+		(&Webhook{
+			HookEvent: &HookEvent{
+				ChooseEvents: true,
+				HookEvents:   HookEvents{Issues: true, Release: true},
+			},
+		}).EventsArray(),
+	)
 }
 
 func TestCreateWebhook(t *testing.T) {
@@ -193,6 +220,37 @@ func TestDeleteWebhookByOrgID(t *testing.T) {
 	assert.True(t, IsErrWebhookNotExist(err))
 }
 
+func TestGetSystemWebhook(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	hook, err := GetSystemWebhook(4)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), hook.ID)
+
+	_, err = GetSystemWebhook(1)
+	assert.Error(t, err)
+	assert.True(t, IsErrWebhookNotExist(err))
+}
+
+func TestGetSystemWebhooks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	hooks, err := GetSystemWebhooks()
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, int64(4), hooks[0].ID)
+		assert.True(t, hooks[0].IsSystemWebhook)
+	}
+}
+
+func TestDeleteSystemWebhook(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	err := DeleteSystemWebhook(1)
+	assert.True(t, IsErrWebhookNotExist(err))
+	AssertExistsAndLoadBean(t, &Webhook{ID: 1})
+
+	assert.NoError(t, DeleteSystemWebhook(4))
+	AssertNotExistsBean(t, &Webhook{ID: 4})
+}
+
 func TestToHookTaskType(t *testing.T) {
 	assert.Equal(t, GOGS, ToHookTaskType("gogs"))
 	assert.Equal(t, SLACK, ToHookTaskType("slack"))
@@ -257,6 +315,7 @@ func TestPrepareWebhooks(t *testing.T) {
 	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
 	hookTasks := []*HookTask{
 		{RepoID: repo.ID, HookID: 1, EventType: HookEventPush},
+		{RepoID: repo.ID, HookID: 4, EventType: HookEventPush},
 	}
 	for _, hookTask := range hookTasks {
 		AssertNotExistsBean(t, hookTask)
@@ -267,6 +326,257 @@ func TestPrepareWebhooks(t *testing.T) {
 	}
 }
 
-// TODO TestHookTask_deliver
+func TestPrepareWebhook_ChosenEvents(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.PushOnly = false
+	w.ChooseEvents = true
+	w.HookEvents = HookEvents{Issues: true}
+
+	assert.NoError(t, PrepareWebhook(w, repo, HookEventIssues, &api.IssuePayload{}))
+	AssertExistsAndLoadBean(t, &HookTask{RepoID: repo.ID, HookID: w.ID, EventType: HookEventIssues})
+
+	assert.NoError(t, PrepareWebhook(w, repo, HookEventRelease, &api.ReleasePayload{}))
+	AssertNotExistsBean(t, &HookTask{RepoID: repo.ID, HookID: w.ID, EventType: HookEventRelease})
+}
+
+func TestGetChatPayloads_Issues(t *testing.T) {
+	p := &api.IssuePayload{
+		Action: api.HookIssueClosed,
+		Index:  2,
+		Issue:  &api.Issue{Index: 2, Title: "crash on start"},
+		Repository: &api.Repository{
+			FullName: "user2/repo1",
+			HTMLURL:  "http://localhost:3000/user2/repo1",
+		},
+		Sender: &api.User{UserName: "user2"},
+	}
+
+	slack, err := GetSlackPayload(p, HookEventIssues, `{"channel":"#dev"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, slack.Text, "Issue closed")
+	assert.Contains(t, slack.Text, "http://localhost:3000/user2/repo1/issues/2")
+
+	discord, err := GetDiscordPayload(p, HookEventIssues, `{}`)
+	assert.NoError(t, err)
+	if assert.Len(t, discord.Embeds, 1) {
+		assert.Equal(t, "[user2/repo1] Issue closed: #2 crash on start", discord.Embeds[0].Title)
+		assert.Equal(t, failedColor, discord.Embeds[0].Color)
+	}
+
+	dingtalk, err := GetDingtalkPayload(p, HookEventIssues, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[user2/repo1] Issue closed: #2 crash on start", dingtalk.ActionCard.Title)
+	assert.Equal(t, "http://localhost:3000/user2/repo1/issues/2", dingtalk.ActionCard.SingleURL)
+}
+
+func TestPrepareWebhooks_UserEvents(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	u := &User{Name: "hooked", Email: "hooked@example.com", Passwd: "password"}
+	assert.NoError(t, CreateUser(u))
+	AssertExistsAndLoadBean(t, &HookTask{RepoID: 0, HookID: 4, EventType: HookEventUser})
+
+	// Only system webhooks receive user events.
+	AssertNotExistsBean(t, &HookTask{HookID: 1, EventType: HookEventUser})
+
+	assert.NoError(t, AddOrgUser(3, u.ID))
+	task := AssertExistsAndLoadBean(t, &HookTask{RepoID: 0, HookID: 4, EventType: HookEventMember}).(*HookTask)
+	assert.Contains(t, task.PayloadContent, `"action": "added"`)
+	assert.Contains(t, task.PayloadContent, `"username": "hooked"`)
+}
+
+func TestGetChatPayloads_Member(t *testing.T) {
+	p := &api.MemberPayload{
+		Action:       api.HookMemberAdded,
+		Member:       &api.User{UserName: "user5"},
+		Organization: &api.User{UserName: "user3"},
+	}
+
+	slack, err := GetSlackPayload(p, HookEventMember, `{"channel":"#dev"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, slack.Text, "Member")
+	assert.Contains(t, slack.Text, "user5")
+
+	discord, err := GetDiscordPayload(p, HookEventMember, `{}`)
+	assert.NoError(t, err)
+	if assert.Len(t, discord.Embeds, 1) {
+		assert.Equal(t, "[user3] Member added: user5", discord.Embeds[0].Title)
+		assert.Equal(t, successColor, discord.Embeds[0].Color)
+	}
+
+	dingtalk, err := GetDingtalkPayload(p, HookEventMember, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[user3] Member added: user5", dingtalk.Text.Content)
+}
+
+func TestReplayHookTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	task := AssertExistsAndLoadBean(t, &HookTask{UUID: "uuid1"}).(*HookTask)
+
+	replay, err := ReplayHookTask(w, task.UUID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, task.UUID, replay.UUID)
+	AssertExistsAndLoadBean(t, &HookTask{ID: replay.ID, HookID: w.ID, RepoID: task.RepoID, URL: w.URL})
+
+	_, err = ReplayHookTask(w, "no-such-uuid")
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
+
+func TestHookTask_deliver(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	var signature string
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Gitea-Signature")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.URL = server.URL
+	w.Secret = "secret"
+	assert.NoError(t, UpdateWebhook(w))
+
+	task := &HookTask{
+		RepoID:      1,
+		HookID:      w.ID,
+		URL:         w.URL,
+		Payloader:   &api.PushPayload{},
+		ContentType: ContentTypeJSON,
+		EventType:   HookEventPush,
+	}
+	assert.NoError(t, CreateHookTask(task))
+
+	// Failed deliveries are retried later.
+	task.deliver()
+	assert.False(t, task.IsSucceed)
+	assert.False(t, task.IsDelivered)
+	assert.EqualValues(t, 1, task.Attempts)
+	assert.True(t, task.NextAttemptUnix > time.Now().Unix())
+	// HMAC-SHA256 of the payload keyed with the secret.
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, task.RequestInfo.Headers["X-Gitea-Signature"])
+
+	status = http.StatusOK
+	task.deliver()
+	assert.True(t, task.IsSucceed)
+	assert.True(t, task.IsDelivered)
+	assert.EqualValues(t, 2, task.Attempts)
+}
+
+func TestHookTask_deliverWithoutSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get("X-Gitea-Signature")
+	}))
+	defer server.Close()
+
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.URL = server.URL
+	w.Secret = "secret"
+	w.HookTaskType = GITEA
+	assert.NoError(t, UpdateWebhook(w))
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, PrepareWebhook(w, repo, HookEventPush, &api.PushPayload{Ref: "refs/heads/master"}))
+	task := AssertExistsAndLoadBean(t, &HookTask{HookID: w.ID, Type: GITEA}).(*HookTask)
+
+	task.deliver()
+	assert.True(t, task.IsSucceed)
+
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "refs/heads/master", payload["ref"])
+	assert.NotContains(t, payload, "secret")
+
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(body)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+}
+
+func TestPrepareWebhook_SendSecretInPayload(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	setting.Webhook.SendSecretInPayload = true
+	defer func() {
+		setting.Webhook.SendSecretInPayload = false
+	}()
+
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.Secret = "secret"
+	w.HookTaskType = GITEA
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, PrepareWebhook(w, repo, HookEventPush, &api.PushPayload{}))
+	task := AssertExistsAndLoadBean(t, &HookTask{HookID: w.ID, Type: GITEA}).(*HookTask)
+
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(task.PayloadContent), &payload))
+	assert.Equal(t, "secret", payload["secret"])
+}
+
+func TestReplayHookTask_RemovesSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	task := &HookTask{
+		RepoID:      1,
+		HookID:      w.ID,
+		Type:        GITEA,
+		Payloader:   &api.PushPayload{Secret: "secret"},
+		ContentType: ContentTypeJSON,
+		EventType:   HookEventPush,
+	}
+	assert.NoError(t, CreateHookTask(task))
+
+	replay, err := ReplayHookTask(w, task.UUID)
+	assert.NoError(t, err)
+
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(replay.PayloadContent), &payload))
+	assert.NotContains(t, payload, "secret")
+}
+
+func TestHookTask_deliverGivesUp(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	task := &HookTask{
+		RepoID:      1,
+		HookID:      1,
+		URL:         server.URL,
+		Payloader:   &api.PushPayload{},
+		ContentType: ContentTypeJSON,
+		EventType:   HookEventPush,
+		Attempts:    setting.Webhook.MaxAttempts - 1,
+	}
+	assert.NoError(t, CreateHookTask(task))
+
+	task.deliver()
+	assert.False(t, task.IsSucceed)
+	assert.True(t, task.IsDelivered)
+}
+
+func TestRetryDelay(t *testing.T) {
+	backoff := time.Duration(setting.Webhook.RetryBackoff) * time.Second
+	assert.Equal(t, backoff, retryDelay(1))
+	assert.Equal(t, 2*backoff, retryDelay(2))
+	assert.Equal(t, 4*backoff, retryDelay(3))
+	assert.Equal(t, maxRetryDelay, retryDelay(100))
+}
 
 // TODO TestDeliverHooks
@@ -7,7 +7,6 @@ package auth
 import (
 	"reflect"
 	"strings"
-	"time"
 
 	"github.com/Unknwon/com"
 	"github.com/go-macaron/binding"
@@ -16,6 +15,7 @@ import (
 	"gopkg.in/macaron.v1"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -44,7 +44,7 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 			auHead := ctx.Req.Header.Get("Authorization")
 			if len(auHead) > 0 {
 				auths := strings.Fields(auHead)
-				if len(auths) == 2 && auths[0] == "token" {
+				if len(auths) == 2 && (auths[0] == "token" || strings.ToLower(auths[0]) == "bearer") {
 					tokenSHA = auths[1]
 				}
 			}
@@ -52,17 +52,23 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 
 		// Let's see if token is valid.
 		if len(tokenSHA) > 0 {
+			if grant := CheckOAuthAccessToken(tokenSHA); grant != nil {
+				ctx.Data["AccessTokenScope"] = grant.AccessTokenScope()
+				return grant.UserID
+			}
 			t, err := models.GetAccessTokenBySHA(tokenSHA)
 			if err != nil {
-				if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
+				if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) ||
+					models.IsErrAccessTokenExpired(err) {
 					log.Error(4, "GetAccessTokenBySHA: %v", err)
 				}
 				return 0
 			}
-			t.Updated = time.Now()
-			if err = models.UpdateAccessToken(t); err != nil {
-				log.Error(4, "UpdateAccessToken: %v", err)
+			if err = models.UpdateAccessTokenLastUsed(t); err != nil {
+				log.Error(4, "UpdateAccessTokenLastUsed: %v", err)
 			}
+			// the API checks the scope of the token for each request
+			ctx.Data["AccessTokenScope"] = t.Scope
 			return t.UID
 		}
 	}
@@ -76,6 +82,30 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 	return 0
 }
 
+// CheckOAuthAccessToken returns the grant of an OAuth2 access token issued
+// by Gitea, or nil if the token is not a valid access token
+func CheckOAuthAccessToken(accessToken string) *models.OAuth2Grant {
+	// JWT tokens require a "."
+	if !setting.OAuth2.Enable || oauth2.JWTSigningKey == nil || !strings.Contains(accessToken, ".") {
+		return nil
+	}
+	token, err := models.ParseOAuth2Token(accessToken, oauth2.JWTSigningKey)
+	if err != nil {
+		log.Trace("ParseOAuth2Token: %v", err)
+		return nil
+	} else if token.Type != models.TypeAccessToken {
+		return nil
+	}
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil {
+		if !models.IsErrOAuthGrantNotFound(err) {
+			log.Error(4, "GetOAuth2GrantByID: %v", err)
+		}
+		return nil
+	}
+	return grant
+}
+
 // SignedInUser returns the user object of signed user.
 // It returns a bool value to indicate whether user uses basic auth or not.
 func SignedInUser(ctx *macaron.Context, sess session.Store) (*models.User, bool) {
@@ -0,0 +1,84 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// JWTSigningKey the key which signs the tokens issued by Gitea as an OAuth2
+// provider, it is nil until InitSigningKey is called
+var JWTSigningKey *rsa.PrivateKey
+
+// InitSigningKey loads the RSA private key from the given PEM file, or
+// generates a new key and saves it to the file if it does not exist
+func InitSigningKey(keyPath string) error {
+	data, err := ioutil.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		log.Info("Generating OAuth2 JWT signing key: %s", keyPath)
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return err
+		}
+		if err = os.MkdirAll(filepath.Dir(keyPath), os.ModePerm); err != nil {
+			return err
+		}
+		data = pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})
+		if err = ioutil.WriteFile(keyPath, data, 0600); err != nil {
+			return err
+		}
+		JWTSigningKey = key
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		return fmt.Errorf("%s does not contain a PEM encoded RSA private key", keyPath)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	JWTSigningKey = key
+	return nil
+}
+
+// SigningKeyID returns the key ID of the public key, which is derived from
+// the key so that it changes whenever the key is replaced
+func SigningKeyID(key *rsa.PrivateKey) string {
+	hash := sha256.New()
+	hash.Write(key.N.Bytes())
+	hash.Write(big.NewInt(int64(key.E)).Bytes())
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+// SigningKeyJWK returns the public key in the JSON Web Key format
+func SigningKeyJWK(key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"alg": "RS256",
+		"use": "sig",
+		"kid": SigningKeyID(key),
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
@@ -8,6 +8,7 @@ package auth
 import (
 	"net/url"
 	"strings"
+	"unicode"
 
 	"code.gitea.io/gitea/models"
 	"github.com/Unknwon/com"
@@ -32,6 +33,13 @@ type CreateRepoForm struct {
 	Gitignores  string
 	License     string
 	Readme      string
+
+	RepoTemplate int64
+	GitContent   bool
+	Labels       bool
+	Webhooks     bool
+	Units        bool
+	Topics       bool
 }
 
 // Validate validates the fields
@@ -52,6 +60,13 @@ type MigrateRepoForm struct {
 	Mirror      bool   `json:"mirror"`
 	Private     bool   `json:"private"`
 	Description string `json:"description" binding:"MaxSize(255)"`
+
+	// Items migrated from GitHub and GitLab, ignored for mirrors.
+	Milestones   bool `json:"milestones"`
+	Labels       bool `json:"labels"`
+	Releases     bool `json:"releases"`
+	Issues       bool `json:"issues"`
+	PullRequests bool `json:"pull_requests"`
 }
 
 // Validate validates the fields
@@ -95,8 +110,17 @@ type RepoSettingForm struct {
 	Interval      string
 	MirrorAddress string
 	Private       bool
+	Template      bool
 	EnablePrune   bool
 
+	// Push mirror settings
+	PushMirrorID         int64
+	PushMirrorAddress    string
+	PushMirrorUsername   string
+	PushMirrorPassword   string
+	PushMirrorInterval   string
+	PushMirrorSyncOnPush bool
+
 	// Advanced settings
 	EnableWiki                       bool
 	EnableExternalWiki               bool
@@ -107,8 +131,33 @@ type RepoSettingForm struct {
 	TrackerURLFormat                 string
 	TrackerIssueStyle                string
 	EnablePulls                      bool
+	PullsAllowMerge                  bool
+	PullsAllowRebase                 bool
+	PullsAllowRebaseMerge            bool
+	PullsAllowSquash                 bool
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
+	EnableIssueDependencies          bool
+	EnableProjects                   bool
+}
+
+// ParsePushMirrorAddr checks if the given push mirror address is a valid
+// HTTP/HTTPS URL, and returns composed URL with needed username and password.
+func (f RepoSettingForm) ParsePushMirrorAddr() (string, error) {
+	remoteAddr := strings.TrimSpace(f.PushMirrorAddress)
+	if !strings.HasPrefix(remoteAddr, "http://") &&
+		!strings.HasPrefix(remoteAddr, "https://") {
+		return "", models.ErrInvalidCloneAddr{IsURLError: true}
+	}
+
+	u, err := url.Parse(remoteAddr)
+	if err != nil {
+		return "", models.ErrInvalidCloneAddr{IsURLError: true}
+	}
+	if len(f.PushMirrorUsername)+len(f.PushMirrorPassword) > 0 {
+		u.User = url.UserPassword(f.PushMirrorUsername, f.PushMirrorPassword)
+	}
+	return u.String(), nil
 }
 
 // Validate validates the fields
@@ -116,6 +165,23 @@ func (f *RepoSettingForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// RepoTopicsForm form for changing the topics of a repository
+type RepoTopicsForm struct {
+	Topics string
+}
+
+// TopicNames returns the topic names separated by spaces or commas
+func (f RepoTopicsForm) TopicNames() []string {
+	return strings.FieldsFunc(f.Topics, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// Validate validates the fields
+func (f *RepoTopicsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -125,10 +191,15 @@ func (f *RepoSettingForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 
 // ProtectBranchForm form for changing protected branch settings
 type ProtectBranchForm struct {
-	Protected       bool
-	EnableWhitelist bool
-	WhitelistUsers  string
-	WhitelistTeams  string
+	Protected               bool
+	EnableWhitelist         bool
+	WhitelistUsers          string
+	WhitelistTeams          string
+	RequiredApprovals       int64
+	ApprovalsWhitelistUsers string
+	ApprovalsWhitelistTeams string
+	EnableStatusCheck       bool
+	StatusCheckContexts     string
 }
 
 // Validate validates the fields
@@ -145,12 +216,20 @@ func (f *ProtectBranchForm) Validate(ctx *macaron.Context, errs binding.Errors)
 
 // WebhookForm form for changing web hook
 type WebhookForm struct {
-	Events      string
-	Create      bool
-	Push        bool
-	PullRequest bool
-	Repository  bool
-	Active      bool
+	Events            string
+	Create            bool
+	Delete            bool
+	Fork              bool
+	Issues            bool
+	IssueComment      bool
+	Release           bool
+	Push              bool
+	PullRequest       bool
+	PullRequestReview bool
+	Repository        bool
+	User              bool
+	Member            bool
+	Active            bool
 }
 
 // PushOnly if the hook will be triggered when push
@@ -268,6 +347,70 @@ func (f *CreateCommentForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ReactionForm form for adding and removing reaction
+type ReactionForm struct {
+	Content string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *ReactionForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// MergePullRequestForm form for merging Pull Request
+// swagger:model MergePullRequestOption
+type MergePullRequestForm struct {
+	// required: true
+	// enum: merge,rebase,rebase-merge,squash
+	Do                string `binding:"Required;In(merge,rebase,rebase-merge,squash)"`
+	MergeTitleField   string
+	MergeMessageField string
+}
+
+// Validate validates the fields
+func (f *MergePullRequestForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// CodeCommentForm form for adding code comments for PRs
+type CodeCommentForm struct {
+	Content  string `binding:"Required"`
+	Side     string `binding:"Required;In(previous,proposed)"`
+	Line     int64
+	TreePath string `form:"tree_path" binding:"Required"`
+	IsReview bool   `form:"is_review"`
+}
+
+// Validate validates the fields
+func (f *CodeCommentForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// SubmitReviewForm for submitting a finished code review
+type SubmitReviewForm struct {
+	Content string
+	Type    string `binding:"Required;In(approve,comment,reject)"`
+}
+
+// Validate validates the fields
+func (f *SubmitReviewForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ReviewType will return the corresponding reviewtype for type
+func (f SubmitReviewForm) ReviewType() models.ReviewType {
+	switch f.Type {
+	case "approve":
+		return models.ReviewTypeApprove
+	case "comment":
+		return models.ReviewTypeComment
+	case "reject":
+		return models.ReviewTypeReject
+	default:
+		return models.ReviewTypeUnknown
+	}
+}
+
 //    _____  .__.__                   __
 //   /     \ |__|  |   ____   _______/  |_  ____   ____   ____
 //  /  \ /  \|  |  | _/ __ \ /  ___/\   __\/  _ \ /    \_/ __ \
@@ -287,6 +430,27 @@ func (f *CreateMilestoneForm) Validate(ctx *macaron.Context, errs binding.Errors
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// CreateProjectForm form for creating and editing a project board
+type CreateProjectForm struct {
+	Title       string `binding:"Required;MaxSize(100)"`
+	Description string
+}
+
+// Validate validates the fields
+func (f *CreateProjectForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ProjectColumnForm form for creating and renaming a column of a project board
+type ProjectColumnForm struct {
+	Title string `binding:"Required;MaxSize(100)"`
+}
+
+// Validate validates the fields
+func (f *ProjectColumnForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // .____          ___.          .__
 // |    |   _____ \_ |__   ____ |  |
 // |    |   \__  \ | __ \_/ __ \|  |
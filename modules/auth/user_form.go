@@ -6,6 +6,7 @@ package auth
 
 import (
 	"mime/multipart"
+	"strings"
 
 	"github.com/go-macaron/binding"
 	"gopkg.in/macaron.v1"
@@ -181,7 +182,9 @@ func (f *AddKeyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding
 
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
-	Name string `binding:"Required"`
+	Name      string `binding:"Required"`
+	Scopes    []string
+	ExpiresAt string
 }
 
 // Validate valideates the fields
@@ -189,6 +192,83 @@ func (f *NewAccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// EditOAuth2ApplicationForm form for creating and editing oauth2 applications
+type EditOAuth2ApplicationForm struct {
+	Name         string `binding:"Required;MaxSize(255)" form:"application_name" locale:"settings.oauth2_application_name"`
+	RedirectURIs string `binding:"Required" form:"redirect_uris" locale:"settings.oauth2_redirect_uris"`
+}
+
+// Validate valideates the fields
+func (f *EditOAuth2ApplicationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// RedirectURIList returns the redirect uris, one per line
+func (f *EditOAuth2ApplicationForm) RedirectURIList() []string {
+	var uris []string
+	for _, uri := range strings.Split(f.RedirectURIs, "\n") {
+		if uri = strings.TrimSpace(uri); len(uri) > 0 {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// AuthorizationForm form for authorizing oauth2 clients
+type AuthorizationForm struct {
+	ResponseType string `form:"response_type"`
+	ClientID     string `form:"client_id"`
+	RedirectURI  string `form:"redirect_uri"`
+	State        string `form:"state"`
+	Scope        string `form:"scope"`
+	Nonce        string `form:"nonce"`
+
+	// PKCE support
+	CodeChallengeMethod string `form:"code_challenge_method"` // S256, plain
+	CodeChallenge       string `form:"code_challenge"`
+}
+
+// Validate valideates the fields
+func (f *AuthorizationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// GrantApplicationForm form for authorizing oauth2 clients
+type GrantApplicationForm struct {
+	ClientID    string `binding:"Required" form:"client_id"`
+	RedirectURI string `form:"redirect_uri"`
+	State       string `form:"state"`
+	Scope       string `form:"scope"`
+	Nonce       string `form:"nonce"`
+	Granted     bool   `form:"granted"`
+
+	CodeChallengeMethod string `form:"code_challenge_method"`
+	CodeChallenge       string `form:"code_challenge"`
+}
+
+// Validate valideates the fields
+func (f *GrantApplicationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// AccessTokenForm for issuing access tokens from authorization codes or refresh tokens
+type AccessTokenForm struct {
+	GrantType    string `form:"grant_type"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	RedirectURI  string `form:"redirect_uri"`
+	Code         string `form:"code"`
+	RefreshToken string `form:"refresh_token"`
+
+	// PKCE support
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// Validate valideates the fields
+func (f *AccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // TwoFactorAuthForm for logging in with 2FA token.
 type TwoFactorAuthForm struct {
 	Passcode string `binding:"Required"`
@@ -394,6 +394,28 @@ func RepoAssignment() macaron.Handler {
 			ctx.Data["IsStaringRepo"] = models.IsStaring(ctx.User.ID, repo.ID)
 		}
 
+		if repo.TemplateID > 0 {
+			templateRepo, err := models.GetRepositoryByID(repo.TemplateID)
+			if err != nil && !models.IsErrRepoNotExist(err) {
+				ctx.Handle(500, "GetRepositoryByID", err)
+				return
+			}
+			if templateRepo != nil {
+				var userID int64
+				if ctx.IsSigned {
+					userID = ctx.User.ID
+				}
+				has, err := models.HasAccess(userID, templateRepo, models.AccessModeRead)
+				if err != nil {
+					ctx.Handle(500, "HasAccess", err)
+					return
+				}
+				if has {
+					ctx.Data["TemplateRepo"] = templateRepo
+				}
+			}
+		}
+
 		// repo is bare and display enable
 		if ctx.Repo.Repository.IsBare {
 			ctx.Data["BranchName"] = ctx.Repo.Repository.DefaultBranch
@@ -433,14 +455,14 @@ func RepoAssignment() macaron.Handler {
 		if ctx.Repo.IsWriter() || (ctx.IsSigned && ctx.User.HasForkedRepo(ctx.Repo.Repository.ID)) {
 			// Pull request is allowed if this is a fork repository
 			// and base repository accepts pull requests.
-			if repo.BaseRepo != nil && repo.BaseRepo.AllowsPulls() {
+			if repo.BaseRepo != nil && repo.BaseRepo.AllowsPulls() && !repo.BaseRepo.IsArchived {
 				ctx.Data["BaseRepo"] = repo.BaseRepo
 				ctx.Repo.PullRequest.BaseRepo = repo.BaseRepo
 				ctx.Repo.PullRequest.Allowed = true
 				ctx.Repo.PullRequest.HeadInfo = ctx.Repo.Owner.Name + ":" + ctx.Repo.BranchName
 			} else {
 				// Or, this is repository accepts pull requests between branches.
-				if repo.AllowsPulls() {
+				if repo.AllowsPulls() && !repo.IsArchived {
 					ctx.Data["BaseRepo"] = repo
 					ctx.Repo.PullRequest.BaseRepo = repo
 					ctx.Repo.PullRequest.Allowed = true
@@ -661,6 +683,15 @@ func RequireRepoWriter() macaron.Handler {
 	}
 }
 
+// RepoMustNotBeArchived returns a macaron middleware for refusing changes to an archived repository
+func RepoMustNotBeArchived() macaron.Handler {
+	return func(ctx *Context) {
+		if ctx.Repo.Repository.IsArchived {
+			ctx.Handle(404, "RepoMustNotBeArchived", fmt.Errorf("%s", ctx.Tr("repo.archive.title")))
+		}
+	}
+}
+
 // LoadRepoUnits loads repsitory's units, it should be called after repository and user loaded
 func LoadRepoUnits() macaron.Handler {
 	return func(ctx *Context) {
@@ -719,5 +750,6 @@ func UnitTypes() macaron.Handler {
 		ctx.Data["UnitTypeWiki"] = models.UnitTypeWiki
 		ctx.Data["UnitTypeExternalWiki"] = models.UnitTypeExternalWiki
 		ctx.Data["UnitTypeExternalTracker"] = models.UnitTypeExternalTracker
+		ctx.Data["UnitTypeProjects"] = models.UnitTypeProjects
 	}
 }
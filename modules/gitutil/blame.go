@@ -0,0 +1,167 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+)
+
+// BlameCommit represents a commit which last changed some lines of a file
+type BlameCommit struct {
+	ID        git.SHA1
+	Author    *git.Signature
+	Committer *git.Signature
+	Summary   string
+	// Path of the file in the commit
+	Path string
+	// PreviousID and PreviousPath are the parent commit and the path of the
+	// file in it, they are empty if the file was created by the commit
+	PreviousID   string
+	PreviousPath string
+}
+
+// BlamePart represents consecutive lines of a file which were last changed
+// by the same commit
+type BlamePart struct {
+	Commit *BlameCommit
+	// StartLine is the 1-based number of the first line in the file
+	StartLine int
+	// OrigStartLine is the 1-based number of the first line in the file as of
+	// the commit
+	OrigStartLine int
+	Lines         []string
+}
+
+// EndLine returns the 1-based number of the last line of the part
+func (p *BlamePart) EndLine() int {
+	return p.StartLine + len(p.Lines) - 1
+}
+
+// GetBlame returns the blame of a file at the given revision, as consecutive
+// parts of lines last changed by the same commit
+func GetBlame(repo *git.Repository, revision, file string) ([]*BlamePart, error) {
+	stdout, err := git.NewCommand("blame", "--porcelain", revision, "--", file).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(stdout)
+}
+
+// parseBlameSignature sets the field of the signature given by the suffix of
+// an author or committer key of git blame --porcelain, e.g. "-mail"
+func parseBlameSignature(sig *git.Signature, key, value string) error {
+	switch key {
+	case "":
+		sig.Name = value
+	case "-mail":
+		sig.Email = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+	case "-time":
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		sig.When = time.Unix(seconds, 0)
+	case "-tz":
+		sig.When = sig.When.In(parseTimezone(value))
+	}
+	return nil
+}
+
+// parseBlamePorcelain parses the output of git blame --porcelain
+func parseBlamePorcelain(data []byte) ([]*BlamePart, error) {
+	var (
+		parts   []*BlamePart
+		commits = make(map[string]*BlameCommit)
+		commit  *BlameCommit
+		line    = struct{ orig, final int }{}
+	)
+
+	for _, l := range bytes.Split(data, []byte{'\n'}) {
+		if len(l) == 0 {
+			continue
+		}
+
+		// content of the line
+		if l[0] == '\t' {
+			if commit == nil {
+				return nil, fmt.Errorf("unexpected blame line: %s", l)
+			}
+			content := string(l[1:])
+			if last := len(parts) - 1; last >= 0 && parts[last].Commit == commit && parts[last].EndLine()+1 == line.final {
+				parts[last].Lines = append(parts[last].Lines, content)
+			} else {
+				parts = append(parts, &BlamePart{
+					Commit:        commit,
+					StartLine:     line.final,
+					OrigStartLine: line.orig,
+					Lines:         []string{content},
+				})
+			}
+			continue
+		}
+
+		fields := strings.SplitN(string(l), " ", 2)
+		key, value := fields[0], ""
+		if len(fields) > 1 {
+			value = fields[1]
+		}
+
+		// header of a line: <sha> <orig line> <final line> [<lines in group>]
+		if id, err := git.NewIDFromString(key); err == nil {
+			numbers := strings.Fields(value)
+			if len(numbers) < 2 {
+				return nil, fmt.Errorf("invalid blame header: %s", l)
+			}
+			if line.orig, err = strconv.Atoi(numbers[0]); err != nil {
+				return nil, fmt.Errorf("invalid blame header: %s", l)
+			}
+			if line.final, err = strconv.Atoi(numbers[1]); err != nil {
+				return nil, fmt.Errorf("invalid blame header: %s", l)
+			}
+
+			var ok bool
+			if commit, ok = commits[key]; !ok {
+				commit = &BlameCommit{
+					ID:        id,
+					Author:    new(git.Signature),
+					Committer: new(git.Signature),
+				}
+				commits[key] = commit
+			}
+			continue
+		}
+
+		if commit == nil {
+			return nil, fmt.Errorf("unexpected blame line: %s", l)
+		}
+		var err error
+		switch {
+		case strings.HasPrefix(key, "author"):
+			err = parseBlameSignature(commit.Author, strings.TrimPrefix(key, "author"), value)
+		case strings.HasPrefix(key, "committer"):
+			err = parseBlameSignature(commit.Committer, strings.TrimPrefix(key, "committer"), value)
+		case key == "summary":
+			commit.Summary = value
+		case key == "filename":
+			commit.Path = value
+		case key == "previous":
+			previous := strings.SplitN(value, " ", 2)
+			if len(previous) == 2 {
+				commit.PreviousID, commit.PreviousPath = previous[0], previous[1]
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid blame line %q: %v", l, err)
+		}
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlame(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	parts, err := GetBlame(repo, commitID, "docs/guide.md")
+	assert.NoError(t, err)
+	if assert.Len(t, parts, 1) {
+		part := parts[0]
+		assert.Equal(t, []string{"# guide", "", "steps"}, part.Lines)
+		assert.Equal(t, 1, part.StartLine)
+		assert.Equal(t, 3, part.EndLine())
+		assert.Equal(t, commitID, part.Commit.ID.String())
+		assert.Equal(t, "Gitea", part.Commit.Author.Name)
+		assert.Equal(t, "gitea@fake.local", part.Commit.Author.Email)
+		assert.Equal(t, "initial commit", part.Commit.Summary)
+		assert.Equal(t, "docs/guide.md", part.Commit.Path)
+		assert.Empty(t, part.Commit.PreviousID)
+	}
+
+	_, err = GetBlame(repo, commitID, "unknown.md")
+	assert.Error(t, err)
+}
+
+func TestParseBlamePorcelain(t *testing.T) {
+	parts, err := parseBlamePorcelain([]byte(`65f1bf27bc3bf70f64657658635e66094edbcb4d 1 1 2
+author user1
+author-mail <address1@example.com>
+author-time 1489956479
+author-tz +0100
+committer Ethan Koenig
+committer-mail <ethantkoenig@gmail.com>
+committer-time 1489956479
+committer-tz -0400
+summary Initial commit
+filename README.md
+	# repo1
+65f1bf27bc3bf70f64657658635e66094edbcb4d 2 2
+	
+8f1a3e3c3b4aaad1fd5b9e8c8e5a1a0f3b2c1d0e 1 3 1
+author user2
+author-mail <user2@example.com>
+author-time 1489956500
+author-tz +0000
+committer user2
+committer-mail <user2@example.com>
+committer-time 1489956500
+committer-tz +0000
+summary Add description
+previous 65f1bf27bc3bf70f64657658635e66094edbcb4d README.md
+filename README.md
+	Description for repo1
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, parts, 2) {
+		assert.Equal(t, []string{"# repo1", ""}, parts[0].Lines)
+		assert.Equal(t, 1, parts[0].StartLine)
+		assert.Equal(t, "user1", parts[0].Commit.Author.Name)
+		assert.Equal(t, "address1@example.com", parts[0].Commit.Author.Email)
+		assert.EqualValues(t, 1489956479, parts[0].Commit.Author.When.Unix())
+		_, offset := parts[0].Commit.Committer.When.Zone()
+		assert.Equal(t, -4*60*60, offset)
+
+		assert.Equal(t, []string{"Description for repo1"}, parts[1].Lines)
+		assert.Equal(t, 3, parts[1].StartLine)
+		assert.Equal(t, 1, parts[1].OrigStartLine)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", parts[1].Commit.PreviousID)
+		assert.Equal(t, "README.md", parts[1].Commit.PreviousPath)
+	}
+
+	_, err = parseBlamePorcelain([]byte("\tline without header\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// CommitFileStat represents the changes of a file in a commit
+type CommitFileStat struct {
+	Name    string
+	OldName string
+	// Status is the status letter of git diff-tree, e.g. 'A', 'D', 'M' or 'R'
+	Status    byte
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// diffTree returns the NUL separated fields of the output of git diff-tree
+// of the commit and its first parent in the given format
+func diffTree(repo *git.Repository, commit *git.Commit, format string) ([]string, error) {
+	cmd := git.NewCommand("diff-tree", "-r", "-M", "-z", "--no-commit-id", format)
+	if commit.ParentCount() == 0 {
+		cmd.AddArguments("--root", commit.ID.String())
+	} else {
+		parentID, err := commit.ParentID(0)
+		if err != nil {
+			return nil, err
+		}
+		cmd.AddArguments(parentID.String(), commit.ID.String())
+	}
+
+	stdout, err := cmd.RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	} else if len(stdout) == 0 {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSuffix(stdout, "\x00"), "\x00"), nil
+}
+
+// GetCommitFileStats returns the changes of every file of the commit compared
+// to its first parent
+func GetCommitFileStats(repo *git.Repository, commit *git.Commit) ([]*CommitFileStat, error) {
+	fields, err := diffTree(repo, commit, "--name-status")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*CommitFileStat, 0, len(fields)/2)
+	for i := 0; i < len(fields) && len(fields[i]) > 0; {
+		stat := &CommitFileStat{Status: fields[i][0]}
+		i++
+		if (stat.Status == 'R' || stat.Status == 'C') && i < len(fields) {
+			stat.OldName = fields[i]
+			i++
+		}
+		if i >= len(fields) {
+			break
+		}
+		stat.Name = fields[i]
+		i++
+		stats = append(stats, stat)
+	}
+
+	fields, err = diffTree(repo, commit, "--numstat")
+	if err != nil {
+		return nil, err
+	}
+
+	// --numstat lists the files in the same order as --name-status
+	for i, n := 0, 0; i < len(fields) && n < len(stats); i, n = i+1, n+1 {
+		counts := strings.SplitN(fields[i], "\t", 3)
+		if len(counts) != 3 {
+			continue
+		} else if len(counts[2]) == 0 {
+			// renamed or copied files are followed by both of their names
+			i += 2
+		}
+
+		if counts[0] == "-" {
+			stats[n].IsBinary = true
+			continue
+		}
+		stats[n].Additions, _ = strconv.Atoi(counts[0])
+		stats[n].Deletions, _ = strconv.Atoi(counts[1])
+	}
+	return stats, nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommitFileStats(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+	commit, err := repo.GetCommit(commitID)
+	assert.NoError(t, err)
+
+	// the files of a root commit are compared to an empty tree
+	stats, err := GetCommitFileStats(repo, commit)
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, &CommitFileStat{Name: "README.md", Status: 'A', Additions: 1}, stats[0])
+		assert.Equal(t, &CommitFileStat{Name: "docs/guide.md", Status: 'A', Additions: 3}, stats[1])
+	}
+}
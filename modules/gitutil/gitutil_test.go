@@ -0,0 +1,47 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRepository creates a repository with a single commit of README.md
+// and docs/guide.md, which is tagged by the lightweight tag v1.0 and the annotated tag v1.0-annotated.
+// Returns the repository and the id of the commit.
+func newTestRepository(t *testing.T) (*git.Repository, string, func()) {
+	dir, err := ioutil.TempDir("", "gitutil")
+	assert.NoError(t, err)
+	cleanup := func() { os.RemoveAll(dir) }
+
+	run := func(args ...string) string {
+		stdout, err := git.NewCommand(append([]string{
+			"-c", "user.name=Gitea", "-c", "user.email=gitea@fake.local",
+		}, args...)...).RunInDir(dir)
+		assert.NoError(t, err, strings.Join(args, " "))
+		return strings.TrimSpace(stdout)
+	}
+	run("init")
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "docs"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# test\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("# guide\n\nsteps\n"), 0644))
+	run("add", "README.md", "docs/guide.md")
+	run("commit", "-m", "initial commit")
+	run("tag", "v1.0")
+	run("tag", "-a", "v1.0-annotated", "-m", "the first release")
+	commitID := run("rev-parse", "HEAD")
+
+	repo, err := git.OpenRepository(dir)
+	assert.NoError(t, err)
+	return repo, commitID, cleanup
+}
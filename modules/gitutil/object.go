@@ -0,0 +1,34 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// GetObjectType returns the type of the object with the given id
+func GetObjectType(repo *git.Repository, idStr string) (git.ObjectType, error) {
+	id, err := git.NewIDFromString(idStr)
+	if err != nil {
+		return "", err
+	}
+	stdout, err := git.NewCommand("cat-file", "-t", id.String()).RunInDir(repo.Path)
+	if err != nil {
+		return "", git.ErrNotExist{ID: id.String()}
+	}
+	return git.ObjectType(strings.TrimSpace(stdout)), nil
+}
+
+// ReadBlob returns the content of the blob with the given id
+func ReadBlob(repo *git.Repository, idStr string) ([]byte, error) {
+	if tp, err := GetObjectType(repo, idStr); err != nil {
+		return nil, err
+	} else if tp != git.ObjectBlob {
+		return nil, git.ErrNotExist{ID: idStr}
+	}
+	return git.NewCommand("cat-file", "blob", idStr).RunInDirBytes(repo.Path)
+}
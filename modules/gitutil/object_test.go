@@ -0,0 +1,53 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetObjectType(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	tp, err := GetObjectType(repo, commitID)
+	assert.NoError(t, err)
+	assert.Equal(t, git.ObjectCommit, tp)
+
+	commit, err := repo.GetCommit(commitID)
+	assert.NoError(t, err)
+	tp, err = GetObjectType(repo, commit.Tree.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, git.ObjectTree, tp)
+
+	_, err = GetObjectType(repo, "0000000000000000000000000000000000000000")
+	assert.True(t, git.IsErrNotExist(err))
+	_, err = GetObjectType(repo, "HEAD")
+	assert.Error(t, err)
+}
+
+func TestReadBlob(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	entries, err := ListTreeEntries(repo, commitID, false)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		if entry.Path != "README.md" {
+			continue
+		}
+		data, err := ReadBlob(repo, entry.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "# test\n", string(data))
+	}
+
+	// only blobs can be read
+	_, err = ReadBlob(repo, commitID)
+	assert.True(t, git.IsErrNotExist(err))
+}
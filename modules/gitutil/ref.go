@@ -0,0 +1,50 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// Reference represents a git reference
+type Reference struct {
+	Name   string
+	Object git.SHA1
+	Type   git.ObjectType
+}
+
+// GetRefs returns all references of the repository
+func GetRefs(repo *git.Repository) ([]*Reference, error) {
+	return GetRefsFiltered(repo, "")
+}
+
+// GetRefsFiltered returns the references of the repository whose name starts
+// with the given prefix, e.g. "refs/heads/"
+func GetRefsFiltered(repo *git.Repository, prefix string) ([]*Reference, error) {
+	stdout, err := git.NewCommand("for-each-ref", "--format=%(objectname) %(objecttype) %(refname)").RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*Reference, 0, 10)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], prefix) {
+			continue
+		}
+		id, err := git.NewIDFromString(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, &Reference{
+			Name:   fields[2],
+			Object: id,
+			Type:   git.ObjectType(fields[1]),
+		})
+	}
+	return refs, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRefsFiltered(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	refs, err := GetRefs(repo)
+	assert.NoError(t, err)
+	assert.Len(t, refs, 3)
+
+	refs, err = GetRefsFiltered(repo, "refs/tags/")
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 2) {
+		assert.Equal(t, "refs/tags/v1.0", refs[0].Name)
+		assert.Equal(t, git.ObjectCommit, refs[0].Type)
+		assert.Equal(t, commitID, refs[0].Object.String())
+		assert.Equal(t, "refs/tags/v1.0-annotated", refs[1].Name)
+		assert.Equal(t, git.ObjectTag, refs[1].Type)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+)
+
+// parseSignature parses a signature of a git object without the leading
+// "tagger " or "author ", e.g. "Gitea <gitea@fake.local> 1378823654 +0200"
+func parseSignature(line string) (*git.Signature, error) {
+	emailStart := strings.LastIndexByte(line, '<')
+	emailEnd := strings.LastIndexByte(line, '>')
+	if emailStart < 0 || emailEnd < emailStart {
+		return nil, fmt.Errorf("invalid signature: %s", line)
+	}
+
+	sig := &git.Signature{
+		Name:  strings.TrimSpace(line[:emailStart]),
+		Email: line[emailStart+1 : emailEnd],
+		When:  time.Unix(0, 0),
+	}
+	fields := strings.Fields(line[emailEnd+1:])
+	if len(fields) == 0 {
+		return sig, nil
+	}
+	seconds, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature time: %s", line)
+	}
+	sig.When = time.Unix(seconds, 0)
+	if len(fields) > 1 {
+		sig.When = sig.When.In(parseTimezone(fields[1]))
+	}
+	return sig, nil
+}
+
+// parseTimezone parses a timezone offset like +0200, it returns UTC if the
+// offset is invalid
+func parseTimezone(offset string) *time.Location {
+	if len(offset) != 5 || (offset[0] != '+' && offset[0] != '-') {
+		return time.UTC
+	}
+	hours, err := strconv.Atoi(offset[1:3])
+	if err != nil {
+		return time.UTC
+	}
+	minutes, err := strconv.Atoi(offset[3:])
+	if err != nil {
+		return time.UTC
+	}
+	seconds := (hours*60 + minutes) * 60
+	if offset[0] == '-' {
+		seconds = -seconds
+	}
+	return time.FixedZone(offset, seconds)
+}
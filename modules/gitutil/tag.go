@@ -0,0 +1,97 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package gitutil provides operations on git repositories which are not
+// covered by code.gitea.io/git.
+package gitutil
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// GetTagCommit returns the commit of the tag with the given name. Unlike
+// (*git.Repository).GetTagCommit, it also resolves annotated tags, whose
+// reference points to a tag object instead of the commit.
+func GetTagCommit(repo *git.Repository, name string) (*git.Commit, error) {
+	id, err := repo.GetTagCommitID(name)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := git.NewCommand("rev-parse", id+"^{commit}").RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCommit(strings.TrimSpace(commitID))
+}
+
+// Tag represents an annotated tag object
+type Tag struct {
+	ID      git.SHA1
+	Name    string
+	Object  git.SHA1 // id of the tagged object
+	Type    git.ObjectType
+	Tagger  *git.Signature
+	Message string
+}
+
+// GetAnnotatedTag returns the annotated tag object with the given id
+func GetAnnotatedTag(repo *git.Repository, idStr string) (*Tag, error) {
+	if tp, err := GetObjectType(repo, idStr); err != nil {
+		return nil, err
+	} else if tp != git.ObjectTag {
+		return nil, git.ErrNotExist{ID: idStr}
+	}
+
+	id, err := git.NewIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := git.NewCommand("cat-file", "tag", id.String()).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := parseTag(data)
+	if err != nil {
+		return nil, err
+	}
+	tag.ID = id
+	return tag, nil
+}
+
+// parseTag parses the content of a tag object, whose headers are separated
+// from the message by an empty line
+func parseTag(data string) (*Tag, error) {
+	tag := &Tag{}
+	headers := data
+	if end := strings.Index(data, "\n\n"); end >= 0 {
+		headers, tag.Message = data[:end], data[end+2:]
+	}
+	for _, line := range strings.Split(headers, "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "object":
+			id, err := git.NewIDFromString(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			tag.Object = id
+		case "type":
+			tag.Type = git.ObjectType(fields[1])
+		case "tag":
+			tag.Name = fields[1]
+		case "tagger":
+			sig, err := parseSignature(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			tag.Tagger = sig
+		}
+	}
+	return tag, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTagCommit(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	for _, name := range []string{"v1.0", "v1.0-annotated"} {
+		commit, err := GetTagCommit(repo, name)
+		assert.NoError(t, err)
+		if assert.NotNil(t, commit) {
+			assert.Equal(t, commitID, commit.ID.String())
+		}
+	}
+
+	_, err := GetTagCommit(repo, "unknown")
+	assert.True(t, git.IsErrNotExist(err))
+}
+
+func TestGetAnnotatedTag(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	id, err := repo.GetTagCommitID("v1.0-annotated")
+	assert.NoError(t, err)
+	tag, err := GetAnnotatedTag(repo, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, tag.ID.String())
+	assert.Equal(t, "v1.0-annotated", tag.Name)
+	assert.Equal(t, commitID, tag.Object.String())
+	assert.Equal(t, git.ObjectCommit, tag.Type)
+	assert.Equal(t, "the first release\n", tag.Message)
+	if assert.NotNil(t, tag.Tagger) {
+		assert.Equal(t, "Gitea", tag.Tagger.Name)
+		assert.Equal(t, "gitea@fake.local", tag.Tagger.Email)
+	}
+
+	// lightweight tags have no tag object
+	_, err = GetAnnotatedTag(repo, commitID)
+	assert.True(t, git.IsErrNotExist(err))
+}
+
+func TestParseSignature(t *testing.T) {
+	sig, err := parseSignature("Gitea Admin <gitea@fake.local> 1378823654 +0200")
+	assert.NoError(t, err)
+	assert.Equal(t, "Gitea Admin", sig.Name)
+	assert.Equal(t, "gitea@fake.local", sig.Email)
+	assert.EqualValues(t, 1378823654, sig.When.Unix())
+	_, offset := sig.When.Zone()
+	assert.Equal(t, 2*60*60, offset)
+
+	_, err = parseSignature("no email")
+	assert.Error(t, err)
+}
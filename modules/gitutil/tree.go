@@ -0,0 +1,90 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// TreeEntry represents an entry of a tree
+type TreeEntry struct {
+	Path string // path relative to the listed tree
+	Mode git.EntryMode
+	Type git.ObjectType
+	ID   git.SHA1
+	Size int64 // size of blobs, zero for other types
+}
+
+// IsSubModule returns true if the entry is a submodule, whose commit belongs
+// to another repository
+func (te *TreeEntry) IsSubModule() bool {
+	return te.Mode == git.EntryModeCommit
+}
+
+// ListTreeEntries returns the entries of the tree with the given id, in the
+// order of git ls-tree. If recursive is true, the entries of its sub trees
+// are listed as well.
+func ListTreeEntries(repo *git.Repository, treeID string, recursive bool) ([]*TreeEntry, error) {
+	cmd := git.NewCommand("ls-tree", "-l", "-z")
+	if recursive {
+		cmd.AddArguments("-t", "-r")
+	}
+	stdout, err := cmd.AddArguments(treeID).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\x00"), "\x00")
+	entries := make([]*TreeEntry, 0, len(lines))
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := parseTreeEntry(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseTreeEntry parses an entry of the output of git ls-tree -l, which
+// consists of the mode, type, id and size followed by a tab and the path
+func parseTreeEntry(line string) (*TreeEntry, error) {
+	tab := strings.IndexByte(line, '\t')
+	if tab < 0 {
+		return nil, fmt.Errorf("invalid tree entry: %s", line)
+	}
+	fields := strings.Fields(line[:tab])
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("invalid tree entry: %s", line)
+	}
+
+	mode, err := strconv.ParseInt(fields[0], 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tree entry mode: %s", line)
+	}
+	id, err := git.NewIDFromString(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	entry := &TreeEntry{
+		Path: line[tab+1:],
+		Mode: git.EntryMode(mode),
+		Type: git.ObjectType(fields[1]),
+		ID:   id,
+	}
+	if fields[3] != "-" {
+		if entry.Size, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid tree entry size: %s", line)
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTreeEntries(t *testing.T) {
+	repo, commitID, cleanup := newTestRepository(t)
+	defer cleanup()
+	commit, err := repo.GetCommit(commitID)
+	assert.NoError(t, err)
+
+	paths := func(entries []*TreeEntry) []string {
+		paths := make([]string, len(entries))
+		for i, entry := range entries {
+			paths[i] = entry.Path
+		}
+		return paths
+	}
+
+	entries, err := ListTreeEntries(repo, commit.Tree.ID.String(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "docs"}, paths(entries))
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, git.EntryModeBlob, entries[0].Mode)
+		assert.Equal(t, git.ObjectBlob, entries[0].Type)
+		assert.EqualValues(t, 7, entries[0].Size)
+		assert.Equal(t, git.EntryModeTree, entries[1].Mode)
+		assert.Equal(t, git.ObjectTree, entries[1].Type)
+		assert.EqualValues(t, 0, entries[1].Size)
+	}
+
+	entries, err = ListTreeEntries(repo, commit.Tree.ID.String(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "docs", "docs/guide.md"}, paths(entries))
+}
@@ -0,0 +1,181 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"os"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/token/unicodenorm"
+	"github.com/blevesearch/bleve/index/upsidedown"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/registry"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+var bleveVersionKey = []byte("version")
+
+// openBleveIndex opens the bleve index at the given path. Returns nil if the
+// index does not exist, or if it was removed because it was created by an
+// incompatible bleve version or with an outdated mapping version.
+func openBleveIndex(path string, latestVersion int) (bleve.Index, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	index, err := bleve.Open(path)
+	if err != nil {
+		if err != upsidedown.IncompatibleVersion {
+			return nil, err
+		}
+		log.Warn("Incompatible bleve version, deleting and recreating index %s", path)
+		return nil, os.RemoveAll(path)
+	}
+
+	if bleveIndexVersion(index) == latestVersion {
+		return index, nil
+	}
+	log.Warn("Outdated mapping, deleting and recreating index %s", path)
+	if err = index.Close(); err != nil {
+		return nil, err
+	}
+	return nil, os.RemoveAll(path)
+}
+
+// createBleveIndex creates a bleve index at the given path with the given
+// mapping and mapping version
+func createBleveIndex(path string, mapping mapping.IndexMapping, version int) (bleve.Index, error) {
+	index, err := bleve.New(path, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if err = index.SetInternal(bleveVersionKey, []byte(strconv.Itoa(version))); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// bleveIndexVersion returns the mapping version of an index, indexes created
+// before versioning was introduced have version 0
+func bleveIndexVersion(index bleve.Index) int {
+	v, err := index.GetInternal(bleveVersionKey)
+	if err != nil || len(v) == 0 {
+		return 0
+	}
+	version, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// dropBleveIndex closes and removes the bleve index at the given path
+func dropBleveIndex(index bleve.Index, path string) error {
+	if index != nil {
+		if err := index.Close(); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(path)
+}
+
+// numericEqualityQuery a numeric equality query for the given value and field
+func numericEqualityQuery(value int64, field string) *query.NumericRangeQuery {
+	f := float64(value)
+	tru := true
+	q := bleve.NewNumericRangeInclusiveQuery(&f, &f, &tru, &tru)
+	q.SetField(field)
+	return q
+}
+
+// numericDisjunctionQuery a query matching any of the given values of the field
+func numericDisjunctionQuery(values []int64, field string) *query.DisjunctionQuery {
+	queries := make([]query.Query, len(values))
+	for i, value := range values {
+		queries[i] = numericEqualityQuery(value, field)
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+// boolFieldQuery a boolean equality query for the given value and field
+func boolFieldQuery(value bool, field string) *query.BoolFieldQuery {
+	q := bleve.NewBoolFieldQuery(value)
+	q.SetField(field)
+	return q
+}
+
+func newMatchPhraseQuery(matchPhrase, field, analyzer string) *query.MatchPhraseQuery {
+	q := bleve.NewMatchPhraseQuery(matchPhrase)
+	q.FieldVal = field
+	q.Analyzer = analyzer
+	return q
+}
+
+const unicodeNormalizeName = "unicodeNormalize"
+
+func addUnicodeNormalizeTokenFilter(m *mapping.IndexMappingImpl) error {
+	return m.AddCustomTokenFilter(unicodeNormalizeName, map[string]interface{}{
+		"type": unicodenorm.Name,
+		"form": unicodenorm.NFC,
+	})
+}
+
+func newTermQuery(term, field string) *query.TermQuery {
+	q := bleve.NewTermQuery(term)
+	q.FieldVal = field
+	return q
+}
+
+func newPrefixQuery(prefix, field string) *query.PrefixQuery {
+	q := bleve.NewPrefixQuery(prefix)
+	q.FieldVal = field
+	return q
+}
+
+const (
+	singleTokenizerName = "singleToken"
+	keywordAnalyzerName = "keyword"
+)
+
+// singleTokenizer a tokenizer emitting the whole input as a single token
+type singleTokenizer struct{}
+
+func (singleTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	if len(input) == 0 {
+		return analysis.TokenStream{}
+	}
+	return analysis.TokenStream{&analysis.Token{
+		Term:     input,
+		Position: 1,
+		Start:    0,
+		End:      len(input),
+		Type:     analysis.AlphaNumeric,
+	}}
+}
+
+func init() {
+	registry.RegisterTokenizer(singleTokenizerName, func(map[string]interface{}, *registry.Cache) (analysis.Tokenizer, error) {
+		return singleTokenizer{}, nil
+	})
+}
+
+// addKeywordAnalyzer adds an analyzer which indexes values as is, for exact
+// and prefix matches
+func addKeywordAnalyzer(m *mapping.IndexMappingImpl) error {
+	return m.AddCustomAnalyzer(keywordAnalyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  []string{},
+		"tokenizer":     singleTokenizerName,
+		"token_filters": []string{},
+	})
+}
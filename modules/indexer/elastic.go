@@ -0,0 +1,246 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// elasticMaxResultWindow the maximum number of hits Elasticsearch returns
+// for a search, see index.max_result_window
+const elasticMaxResultWindow = 10000
+
+// elasticClient a minimal client of the REST API of Elasticsearch 7, which
+// operates on a single index
+type elasticClient struct {
+	url    string
+	index  string
+	client *http.Client
+}
+
+func newElasticClient(url, index string) *elasticClient {
+	return &elasticClient{
+		url:    strings.TrimSuffix(url, "/"),
+		index:  index,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// elasticError an error returned by Elasticsearch
+type elasticError struct {
+	Status int
+	Body   string
+}
+
+func (err elasticError) Error() string {
+	return fmt.Sprintf("Elasticsearch responded with status %d: %s", err.Status, err.Body)
+}
+
+// request sends a request to the given path of the index. The body is sent
+// as is if it is a reader, otherwise it is encoded as JSON. If result is not
+// nil, the response is decoded into it.
+func (c *elasticClient) request(method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+		contentType = "application/x-ndjson"
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.url+"/"+c.index+path, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return elasticError{Status: resp.StatusCode, Body: string(data)}
+	} else if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// isElasticNotFound returns true if err is a not found response
+func isElasticNotFound(err error) bool {
+	e, ok := err.(elasticError)
+	return ok && e.Status == http.StatusNotFound
+}
+
+// init creates the index with the given mappings and mapping version, unless
+// it exists already. An existing index with another mapping version is
+// deleted and created again. Returns true if the index was created.
+func (c *elasticClient) init(mappings map[string]interface{}, version int) (bool, error) {
+	err := c.request("HEAD", "", nil, nil)
+	if err == nil {
+		existingVersion, err := c.mappingVersion()
+		if err != nil {
+			return false, err
+		} else if existingVersion == version {
+			return false, nil
+		}
+		log.Warn("Outdated mapping, deleting and recreating index %s", c.index)
+		if err = c.drop(); err != nil {
+			return false, err
+		}
+	} else if !isElasticNotFound(err) {
+		return false, err
+	}
+
+	mappings["_meta"] = map[string]interface{}{"version": version}
+	return true, c.request("PUT", "", map[string]interface{}{
+		"mappings": mappings,
+	}, nil)
+}
+
+// mappingVersion returns the mapping version of the index, indexes created
+// before versioning was introduced have version 0
+func (c *elasticClient) mappingVersion() (int, error) {
+	// the response is keyed by the name of the index, which differs from
+	// c.index if that is an alias
+	var result map[string]struct {
+		Mappings struct {
+			Meta struct {
+				Version int
+			} `json:"_meta"`
+		}
+	}
+	if err := c.request("GET", "/_mapping", nil, &result); err != nil {
+		return 0, err
+	}
+	for _, index := range result {
+		return index.Mappings.Meta.Version, nil
+	}
+	return 0, nil
+}
+
+// drop deletes the index with all of its documents
+func (c *elasticClient) drop() error {
+	if err := c.request("DELETE", "", nil, nil); err != nil && !isElasticNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// elasticBulkAction an action of a bulk request, documents must be nil for
+// deletions
+type elasticBulkAction struct {
+	Action   string
+	ID       string
+	Document interface{}
+}
+
+// bulk performs the actions in a single bulk request
+func (c *elasticClient) bulk(actions []elasticBulkAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, action := range actions {
+		if err := encoder.Encode(map[string]interface{}{
+			action.Action: map[string]string{"_id": action.ID},
+		}); err != nil {
+			return err
+		}
+		if action.Document != nil {
+			if err := encoder.Encode(action.Document); err != nil {
+				return err
+			}
+		}
+	}
+
+	var result struct {
+		Errors bool
+		Items  []map[string]struct {
+			Status int
+			Error  json.RawMessage
+		}
+	}
+	if err := c.request("POST", "/_bulk", &buf, &result); err != nil {
+		return err
+	} else if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for action, status := range item {
+			// deleting a document which does not exist is not an error
+			if status.Status/100 != 2 && !(action == "delete" && status.Status == http.StatusNotFound) {
+				return fmt.Errorf("bulk %s: %s", action, status.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// elasticSearchResult the response of a search
+type elasticSearchResult struct {
+	Hits struct {
+		Total struct {
+			Value int64
+		}
+		Hits []struct {
+			ID        string              `json:"_id"`
+			Source    json.RawMessage     `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		}
+	}
+}
+
+// search performs a search of the index
+func (c *elasticClient) search(search map[string]interface{}) (*elasticSearchResult, error) {
+	search["track_total_hits"] = true
+	var result elasticSearchResult
+	if err := c.request("POST", "/_search", search, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// deleteByQuery deletes all documents matching the query
+func (c *elasticClient) deleteByQuery(query map[string]interface{}) error {
+	return c.request("POST", "/_delete_by_query", map[string]interface{}{
+		"query": query,
+	}, nil)
+}
+
+// elasticPaging returns the from and size parameters of a search for the
+// given page, a page size of zero means all hits
+func elasticPaging(page, pageSize int) (int, int) {
+	if pageSize <= 0 {
+		return 0, elasticMaxResultWindow
+	}
+	if page <= 1 {
+		return 0, pageSize
+	}
+	return (page - 1) * pageSize, pageSize
+}
@@ -0,0 +1,240 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeElastic a stand-in for an Elasticsearch server with a single index,
+// searches return all documents and are recorded to be checked by the tests
+type fakeElastic struct {
+	sync.Mutex
+	exists    bool
+	version   int // mapping version of the index
+	documents map[string]json.RawMessage
+	searches  []map[string]interface{}
+	highlight string
+}
+
+func newFakeElastic(t *testing.T, index string) (*fakeElastic, *httptest.Server) {
+	fake := &fakeElastic{documents: make(map[string]json.RawMessage)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.Lock()
+		defer fake.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/"+index)
+		switch {
+		case path == "" && r.Method == "HEAD":
+			if !fake.exists {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case path == "" && r.Method == "PUT":
+			var body struct {
+				Mappings struct {
+					Meta struct {
+						Version int
+					} `json:"_meta"`
+				}
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			fake.exists = true
+			fake.version = body.Mappings.Meta.Version
+		case path == "/_mapping" && r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				index: map[string]interface{}{
+					"mappings": map[string]interface{}{
+						"_meta": map[string]int{"version": fake.version},
+					},
+				},
+			})
+		case path == "" && r.Method == "DELETE":
+			fake.exists = false
+			fake.documents = make(map[string]json.RawMessage)
+		case path == "/_bulk":
+			assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var action map[string]struct {
+					ID string `json:"_id"`
+				}
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+				if meta, ok := action["index"]; ok {
+					assert.True(t, scanner.Scan())
+					fake.documents[meta.ID] = append(json.RawMessage{}, scanner.Bytes()...)
+				} else if meta, ok := action["delete"]; ok {
+					delete(fake.documents, meta.ID)
+				}
+			}
+			w.Write([]byte(`{"errors":false}`))
+		case path == "/_search":
+			var search map[string]interface{}
+			data, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(data, &search))
+			fake.searches = append(fake.searches, search)
+
+			ids := make([]string, 0, len(fake.documents))
+			for id := range fake.documents {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			hits := make([]map[string]interface{}, len(ids))
+			for i, id := range ids {
+				hits[i] = map[string]interface{}{
+					"_id":       id,
+					"_source":   fake.documents[id],
+					"highlight": map[string][]string{"Content": {fake.highlight}},
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"hits": map[string]interface{}{
+					"total": map[string]interface{}{"value": len(hits)},
+					"hits":  hits,
+				},
+			})
+		case path == "/_delete_by_query":
+			fake.documents = make(map[string]json.RawMessage)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	return fake, server
+}
+
+func TestElasticIssueIndexer(t *testing.T) {
+	fake, server := newFakeElastic(t, "gitea_issues")
+	defer server.Close()
+
+	indexer := newElasticIssueIndexer(server.URL+"/", "gitea_issues")
+	populate, err := indexer.Init()
+	assert.NoError(t, err)
+	assert.True(t, populate)
+	populate, err = indexer.Init()
+	assert.NoError(t, err)
+	assert.False(t, populate)
+
+	assert.NoError(t, indexer.Index([]IssueIndexerUpdate{
+		{IssueID: 1, Data: &IssueIndexerData{RepoID: 1, Title: "first issue"}},
+		{IssueID: 2, Data: &IssueIndexerData{RepoID: 1, Title: "second issue"}},
+	}))
+	assert.Len(t, fake.documents, 2)
+
+	assert.NoError(t, indexer.Delete(2, 3))
+	assert.Len(t, fake.documents, 1)
+
+	total, issueIDs, err := indexer.Search(&IssueSearchOptions{
+		Keyword:  "first",
+		RepoIDs:  []int64{1},
+		LabelIDs: [][]int64{{1, 4}},
+		Page:     2,
+		PageSize: 10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.EqualValues(t, []int64{1}, issueIDs)
+
+	search := fake.searches[0]
+	assert.EqualValues(t, 10, search["from"])
+	assert.EqualValues(t, 10, search["size"])
+	boolQuery := search["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Contains(t, boolQuery["must"], "multi_match")
+	assert.Len(t, boolQuery["filter"], 2)
+
+	assert.NoError(t, indexer.Drop())
+	assert.False(t, fake.exists)
+	assert.Len(t, fake.documents, 0)
+}
+
+func TestElasticRepoIndexer(t *testing.T) {
+	fake, server := newFakeElastic(t, "gitea_codes")
+	defer server.Close()
+
+	indexer := newElasticRepoIndexer(server.URL, "gitea_codes")
+	_, err := indexer.Init()
+	assert.NoError(t, err)
+
+	assert.NoError(t, indexer.Index([]RepoIndexerUpdate{
+		{
+			Filepath: "README.md",
+			Op:       RepoIndexerOpUpdate,
+			Data: &RepoIndexerData{
+				RepoID:   1,
+				Filepath: "README.md",
+				Language: "markdown",
+				Content:  "# repo1\n\nDescription for repo1",
+			},
+		},
+		{
+			Filepath: "LICENSE",
+			Op:       RepoIndexerOpDelete,
+			Data:     &RepoIndexerData{RepoID: 1},
+		},
+	}))
+	assert.Len(t, fake.documents, 1)
+
+	fake.highlight = "# repo1\n\n\uE000Description\uE001 for repo1"
+	total, results, err := indexer.Search(&RepoSearchOptions{
+		RepoIDs:    []int64{1},
+		Keyword:    "Description",
+		Language:   "markdown",
+		PathPrefix: "READ",
+		Page:       1,
+		PageSize:   10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	if assert.Len(t, results, 1) {
+		assert.EqualValues(t, 1, results[0].RepoID)
+		assert.Equal(t, "README.md", results[0].Filename)
+		assert.Equal(t, "markdown", results[0].Language)
+		assert.Equal(t, "Description", results[0].Content[results[0].StartIndex:results[0].EndIndex])
+	}
+
+	boolQuery := fake.searches[0]["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Len(t, boolQuery["filter"], 3)
+
+	assert.NoError(t, indexer.DeleteRepo(1))
+	assert.Len(t, fake.documents, 0)
+
+	// indexes are recreated if their mapping is outdated
+	populate, err := indexer.Init()
+	assert.NoError(t, err)
+	assert.False(t, populate)
+
+	fake.version = elasticRepoIndexerLatestVersion - 1
+	fake.documents["1_README.md"] = json.RawMessage(`{"RepoID":1}`)
+	populate, err = indexer.Init()
+	assert.NoError(t, err)
+	assert.True(t, populate)
+	assert.Len(t, fake.documents, 0)
+	assert.EqualValues(t, elasticRepoIndexerLatestVersion, fake.version)
+}
+
+func TestElasticHighlightRange(t *testing.T) {
+	for _, testCase := range []struct {
+		highlighted string
+		start, end  int
+	}{
+		{"no keyword", -1, -1},
+		{"\uE000key\uE001 word", 0, 3},
+		{"a \uE000key\uE001 and \uE000key\uE001.", 2, 13},
+		{"ü \uE000schlüssel\uE001", 3, 13},
+	} {
+		start, end := elasticHighlightRange(testCase.highlighted)
+		assert.Equal(t, testCase.start, start, testCase.highlighted)
+		assert.Equal(t, testCase.end, end, testCase.highlighted)
+	}
+}
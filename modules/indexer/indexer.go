@@ -7,14 +7,9 @@ package indexer
 import (
 	"fmt"
 	"strconv"
-
-	"github.com/blevesearch/bleve"
-	"github.com/blevesearch/bleve/analysis/token/unicodenorm"
-	"github.com/blevesearch/bleve/mapping"
-	"github.com/blevesearch/bleve/search/query"
 )
 
-// indexerID a bleve-compatible unique identifier for an integer id
+// indexerID a unique identifier for an integer id
 func indexerID(id int64) string {
 	return strconv.FormatInt(id, 36)
 }
@@ -28,34 +23,11 @@ func idOfIndexerID(indexerID string) (int64, error) {
 	return id, nil
 }
 
-// numericEqualityQuery a numeric equality query for the given value and field
-func numericEqualityQuery(value int64, field string) *query.NumericRangeQuery {
-	f := float64(value)
-	tru := true
-	q := bleve.NewNumericRangeInclusiveQuery(&f, &f, &tru, &tru)
-	q.SetField(field)
-	return q
-}
-
-func newMatchPhraseQuery(matchPhrase, field, analyzer string) *query.MatchPhraseQuery {
-	q := bleve.NewMatchPhraseQuery(matchPhrase)
-	q.FieldVal = field
-	q.Analyzer = analyzer
-	return q
-}
-
-const unicodeNormalizeName = "unicodeNormalize"
-
-func addUnicodeNormalizeTokenFilter(m *mapping.IndexMappingImpl) error {
-	return m.AddCustomTokenFilter(unicodeNormalizeName, map[string]interface{}{
-		"type": unicodenorm.Name,
-		"form": unicodenorm.NFC,
-	})
-}
-
-// Update represents an update to an indexer
+// Update represents an update to an indexer, either an IssueIndexerUpdate or
+// a RepoIndexerUpdate
 type Update interface {
-	addToBatch(batch *bleve.Batch) error
+	// documentID the indexer id of the updated document
+	documentID() string
 }
 
 const maxBatchSize = 16
@@ -63,20 +35,14 @@ const maxBatchSize = 16
 // Batch batch of indexer updates that automatically flushes once it
 // reaches a certain size
 type Batch struct {
-	batch *bleve.Batch
-	index bleve.Index
+	updates []Update
+	flush   func(updates []Update) error
 }
 
 // Add add update to batch, possibly flushing
 func (batch *Batch) Add(update Update) error {
-	if err := update.addToBatch(batch.batch); err != nil {
-		return err
-	}
-	return batch.flushIfFull()
-}
-
-func (batch *Batch) flushIfFull() error {
-	if batch.batch.Size() >= maxBatchSize {
+	batch.updates = append(batch.updates, update)
+	if len(batch.updates) >= maxBatchSize {
 		return batch.Flush()
 	}
 	return nil
@@ -84,9 +50,12 @@ func (batch *Batch) flushIfFull() error {
 
 // Flush manually flush the batch, regardless of its size
 func (batch *Batch) Flush() error {
-	if err := batch.index.Batch(batch.batch); err != nil {
+	if len(batch.updates) == 0 {
+		return nil
+	}
+	if err := batch.flush(batch.updates); err != nil {
 		return err
 	}
-	batch.batch.Reset()
+	batch.updates = batch.updates[:0]
 	return nil
 }
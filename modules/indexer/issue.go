@@ -5,27 +5,45 @@
 package indexer
 
 import (
-	"os"
+	"fmt"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-
-	"github.com/blevesearch/bleve"
-	"github.com/blevesearch/bleve/analysis/analyzer/custom"
-	"github.com/blevesearch/bleve/analysis/token/lowercase"
-	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
-	"github.com/blevesearch/bleve/index/upsidedown"
+	"code.gitea.io/gitea/modules/util"
 )
 
+// IssueIndexer represents a backend of the issue indexer
+type IssueIndexer interface {
+	// Init opens the index, or creates it if it does not exist yet. Returns
+	// true if the index was created and has to be populated.
+	Init() (bool, error)
+	// Index adds the given issues to the index, or replaces them
+	Index(updates []IssueIndexerUpdate) error
+	// Delete removes the given issues from the index
+	Delete(issueIDs ...int64) error
+	// Search returns the total number of matching issues and the IDs of the
+	// matching issues of the requested page
+	Search(opts *IssueSearchOptions) (int64, []int64, error)
+	// Drop removes the index with all of its issues
+	Drop() error
+}
+
 // issueIndexer (thread-safe) index for searching issues
-var issueIndexer bleve.Index
+var issueIndexer IssueIndexer
 
 // IssueIndexerData data stored in the issue indexer
 type IssueIndexerData struct {
-	RepoID   int64
-	Title    string
-	Content  string
-	Comments []string
+	RepoID      int64
+	Title       string
+	Content     string
+	Comments    []string
+	LabelIDs    []int64
+	MilestoneID int64
+	PosterID    int64
+	AssigneeID  int64
+	IsClosed    bool
+	IsPull      bool
+	UpdatedUnix int64
 }
 
 // IssueIndexerUpdate an update to the issue indexer
@@ -34,99 +52,103 @@ type IssueIndexerUpdate struct {
 	Data    *IssueIndexerData
 }
 
-func (update IssueIndexerUpdate) addToBatch(batch *bleve.Batch) error {
-	return batch.Index(indexerID(update.IssueID), update.Data)
+func (update IssueIndexerUpdate) documentID() string {
+	return indexerID(update.IssueID)
+}
+
+// IssueSearchOptions options for searching the issue indexer
+type IssueSearchOptions struct {
+	Keyword  string
+	RepoIDs  []int64 // Empty means all repositories
+	IsClosed util.OptionalBool
+	IsPull   util.OptionalBool
+	// Issues must have at least one label of every entry, e.g. the IDs of
+	// all labels with the same name across repositories
+	LabelIDs [][]int64
+	PosterID int64
+	Page     int
+	PageSize int // Zero means all matching issues
 }
 
-const issueIndexerAnalyzer = "issueIndexer"
+// newIssueIndexer returns the issue indexer backend of the given type
+func newIssueIndexer(indexerType string) (IssueIndexer, error) {
+	switch indexerType {
+	case setting.IndexerTypeBleve:
+		return newBleveIssueIndexer(setting.Indexer.IssuePath), nil
+	case setting.IndexerTypeElasticsearch:
+		return newElasticIssueIndexer(setting.Indexer.IssueConnStr, setting.Indexer.IssueIndexerName), nil
+	}
+	return nil, fmt.Errorf("Unknown issue indexer type: %s", indexerType)
+}
 
 // InitIssueIndexer initialize issue indexer
 func InitIssueIndexer(populateIndexer func() error) {
-	_, err := os.Stat(setting.Indexer.IssuePath)
-	if err != nil && !os.IsNotExist(err) {
+	var err error
+	if issueIndexer, err = newIssueIndexer(setting.Indexer.IssueType); err != nil {
 		log.Fatal(4, "InitIssueIndexer: %v", err)
-	} else if err == nil {
-		issueIndexer, err = bleve.Open(setting.Indexer.IssuePath)
-		if err == nil {
-			return
-		} else if err != upsidedown.IncompatibleVersion {
-			log.Fatal(4, "InitIssueIndexer, open index: %v", err)
-		}
-		log.Warn("Incompatible bleve version, deleting and recreating issue indexer")
-		if err = os.RemoveAll(setting.Indexer.IssuePath); err != nil {
-			log.Fatal(4, "InitIssueIndexer: remove index, %v", err)
-		}
 	}
-	if err = createIssueIndexer(); err != nil {
-		log.Fatal(4, "InitIssuesIndexer: create index, %v", err)
+
+	populate, err := issueIndexer.Init()
+	if err != nil {
+		log.Fatal(4, "InitIssueIndexer: %v", err)
+	} else if !populate {
+		return
 	}
 	if err = populateIndexer(); err != nil {
 		log.Fatal(4, "InitIssueIndexer: populate index, %v", err)
 	}
 }
 
-// createIssueIndexer create an issue indexer if one does not already exist
-func createIssueIndexer() error {
-	mapping := bleve.NewIndexMapping()
-	docMapping := bleve.NewDocumentMapping()
-
-	docMapping.AddFieldMappingsAt("RepoID", bleve.NewNumericFieldMapping())
-
-	textFieldMapping := bleve.NewTextFieldMapping()
-	docMapping.AddFieldMappingsAt("Title", textFieldMapping)
-	docMapping.AddFieldMappingsAt("Content", textFieldMapping)
-	docMapping.AddFieldMappingsAt("Comments", textFieldMapping)
-
-	if err := addUnicodeNormalizeTokenFilter(mapping); err != nil {
-		return err
-	} else if err = mapping.AddCustomAnalyzer(issueIndexerAnalyzer, map[string]interface{}{
-		"type":          custom.Name,
-		"char_filters":  []string{},
-		"tokenizer":     unicode.Name,
-		"token_filters": []string{unicodeNormalizeName, lowercase.Name},
-	}); err != nil {
+// RebuildIssueIndexer drops the issue indexer and populates it again
+func RebuildIssueIndexer(populateIndexer func() error) error {
+	var err error
+	if issueIndexer, err = newIssueIndexer(setting.Indexer.IssueType); err != nil {
 		return err
 	}
-
-	mapping.DefaultAnalyzer = issueIndexerAnalyzer
-	mapping.AddDocumentMapping("issues", docMapping)
-
-	var err error
-	issueIndexer, err = bleve.New(setting.Indexer.IssuePath, mapping)
-	return err
+	if _, err = issueIndexer.Init(); err != nil {
+		return fmt.Errorf("init index: %v", err)
+	} else if err = issueIndexer.Drop(); err != nil {
+		return fmt.Errorf("drop index: %v", err)
+	} else if _, err = issueIndexer.Init(); err != nil {
+		return fmt.Errorf("create index: %v", err)
+	}
+	return populateIndexer()
 }
 
 // IssueIndexerBatch batch to add updates to
 func IssueIndexerBatch() *Batch {
 	return &Batch{
-		batch: issueIndexer.NewBatch(),
-		index: issueIndexer,
+		flush: func(updates []Update) error {
+			issueUpdates := make([]IssueIndexerUpdate, len(updates))
+			for i, update := range updates {
+				issueUpdates[i] = update.(IssueIndexerUpdate)
+			}
+			return issueIndexer.Index(issueUpdates)
+		},
+	}
+}
+
+// DeleteIssuesFromIndexer removes the given issues from the issue indexer
+func DeleteIssuesFromIndexer(issueIDs ...int64) error {
+	if issueIndexer == nil || len(issueIDs) == 0 {
+		return nil
 	}
+	return issueIndexer.Delete(issueIDs...)
+}
+
+// SearchIssues searches for issues by given conditions, ordered by relevance
+// and then by recent update. Returns the total number of matching issues and
+// the issue IDs of the requested page
+func SearchIssues(opts *IssueSearchOptions) (int64, []int64, error) {
+	return issueIndexer.Search(opts)
 }
 
 // SearchIssuesByKeyword searches for issues by given conditions.
 // Returns the matching issue IDs
 func SearchIssuesByKeyword(repoID int64, keyword string) ([]int64, error) {
-	indexerQuery := bleve.NewConjunctionQuery(
-		numericEqualityQuery(repoID, "RepoID"),
-		bleve.NewDisjunctionQuery(
-			newMatchPhraseQuery(keyword, "Title", issueIndexerAnalyzer),
-			newMatchPhraseQuery(keyword, "Content", issueIndexerAnalyzer),
-			newMatchPhraseQuery(keyword, "Comments", issueIndexerAnalyzer),
-		))
-	search := bleve.NewSearchRequestOptions(indexerQuery, 2147483647, 0, false)
-
-	result, err := issueIndexer.Search(search)
-	if err != nil {
-		return nil, err
-	}
-
-	issueIDs := make([]int64, len(result.Hits))
-	for i, hit := range result.Hits {
-		issueIDs[i], err = idOfIndexerID(hit.ID)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return issueIDs, nil
+	_, issueIDs, err := SearchIssues(&IssueSearchOptions{
+		Keyword: keyword,
+		RepoIDs: []int64{repoID},
+	})
+	return issueIDs, err
 }
@@ -0,0 +1,164 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+const (
+	issueIndexerAnalyzer = "issueIndexer"
+
+	// bleveIssueIndexerLatestVersion must be bumped whenever the mapping of
+	// the issue indexer changes, so that existing indexes are rebuilt
+	bleveIssueIndexerLatestVersion = 1
+)
+
+// bleveIssueIndexer an issue indexer stored in a local bleve index
+type bleveIssueIndexer struct {
+	path  string
+	index bleve.Index
+}
+
+func newBleveIssueIndexer(path string) *bleveIssueIndexer {
+	return &bleveIssueIndexer{path: path}
+}
+
+// Init opens the bleve index, or creates it if it does not exist yet
+func (b *bleveIssueIndexer) Init() (bool, error) {
+	var err error
+	b.index, err = openBleveIndex(b.path, bleveIssueIndexerLatestVersion)
+	if err != nil {
+		return false, err
+	} else if b.index != nil {
+		return false, nil
+	}
+
+	mapping := bleve.NewIndexMapping()
+	docMapping := bleve.NewDocumentMapping()
+
+	numericFieldMapping := bleve.NewNumericFieldMapping()
+	docMapping.AddFieldMappingsAt("RepoID", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("LabelIDs", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("MilestoneID", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("PosterID", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("AssigneeID", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("UpdatedUnix", numericFieldMapping)
+
+	boolFieldMapping := bleve.NewBooleanFieldMapping()
+	docMapping.AddFieldMappingsAt("IsClosed", boolFieldMapping)
+	docMapping.AddFieldMappingsAt("IsPull", boolFieldMapping)
+
+	textFieldMapping := bleve.NewTextFieldMapping()
+	docMapping.AddFieldMappingsAt("Title", textFieldMapping)
+	docMapping.AddFieldMappingsAt("Content", textFieldMapping)
+	docMapping.AddFieldMappingsAt("Comments", textFieldMapping)
+
+	if err = addUnicodeNormalizeTokenFilter(mapping); err != nil {
+		return false, err
+	} else if err = mapping.AddCustomAnalyzer(issueIndexerAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  []string{},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{unicodeNormalizeName, lowercase.Name},
+	}); err != nil {
+		return false, err
+	}
+
+	mapping.DefaultAnalyzer = issueIndexerAnalyzer
+	mapping.AddDocumentMapping("issues", docMapping)
+
+	b.index, err = createBleveIndex(b.path, mapping, bleveIssueIndexerLatestVersion)
+	return true, err
+}
+
+// Index adds the given issues to the index, or replaces them
+func (b *bleveIssueIndexer) Index(updates []IssueIndexerUpdate) error {
+	batch := b.index.NewBatch()
+	for _, update := range updates {
+		if err := batch.Index(update.documentID(), update.Data); err != nil {
+			return err
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+// Delete removes the given issues from the index
+func (b *bleveIssueIndexer) Delete(issueIDs ...int64) error {
+	batch := b.index.NewBatch()
+	for _, issueID := range issueIDs {
+		batch.Delete(indexerID(issueID))
+	}
+	return b.index.Batch(batch)
+}
+
+// Search searches the index for issues
+func (b *bleveIssueIndexer) Search(opts *IssueSearchOptions) (int64, []int64, error) {
+	queries := make([]query.Query, 0, 5)
+	if len(opts.Keyword) > 0 {
+		queries = append(queries, bleve.NewDisjunctionQuery(
+			newMatchPhraseQuery(opts.Keyword, "Title", issueIndexerAnalyzer),
+			newMatchPhraseQuery(opts.Keyword, "Content", issueIndexerAnalyzer),
+			newMatchPhraseQuery(opts.Keyword, "Comments", issueIndexerAnalyzer),
+		))
+	}
+	if len(opts.RepoIDs) > 0 {
+		queries = append(queries, numericDisjunctionQuery(opts.RepoIDs, "RepoID"))
+	}
+	if opts.IsClosed != util.OptionalBoolNone {
+		queries = append(queries, boolFieldQuery(opts.IsClosed.IsTrue(), "IsClosed"))
+	}
+	if opts.IsPull != util.OptionalBoolNone {
+		queries = append(queries, boolFieldQuery(opts.IsPull.IsTrue(), "IsPull"))
+	}
+	for _, labelIDs := range opts.LabelIDs {
+		queries = append(queries, numericDisjunctionQuery(labelIDs, "LabelIDs"))
+	}
+	if opts.PosterID > 0 {
+		queries = append(queries, numericEqualityQuery(opts.PosterID, "PosterID"))
+	}
+
+	var indexerQuery query.Query = bleve.NewMatchAllQuery()
+	if len(queries) > 0 {
+		indexerQuery = bleve.NewConjunctionQuery(queries...)
+	}
+
+	size, from := 2147483647, 0
+	if opts.PageSize > 0 {
+		size = opts.PageSize
+		if opts.Page > 1 {
+			from = (opts.Page - 1) * opts.PageSize
+		}
+	}
+	search := bleve.NewSearchRequestOptions(indexerQuery, size, from, false)
+	search.SortBy([]string{"-_score", "-UpdatedUnix"})
+
+	result, err := b.index.Search(search)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	issueIDs := make([]int64, len(result.Hits))
+	for i, hit := range result.Hits {
+		issueIDs[i], err = idOfIndexerID(hit.ID)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return int64(result.Total), issueIDs, nil
+}
+
+// Drop closes and removes the bleve index
+func (b *bleveIssueIndexer) Drop() error {
+	err := dropBleveIndex(b.index, b.path)
+	b.index = nil
+	return err
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"code.gitea.io/gitea/modules/util"
+)
+
+// elasticIssueIndexerLatestVersion is the version of the mapping of the issue
+// indexer, bump it on changes so that existing indexes are recreated
+const elasticIssueIndexerLatestVersion = 1
+
+// elasticIssueIndexer an issue indexer stored in an Elasticsearch index,
+// which can be shared by several Gitea instances
+type elasticIssueIndexer struct {
+	client *elasticClient
+}
+
+func newElasticIssueIndexer(url, index string) *elasticIssueIndexer {
+	return &elasticIssueIndexer{client: newElasticClient(url, index)}
+}
+
+// Init creates the Elasticsearch index if it does not exist yet or if its
+// mapping is outdated
+func (e *elasticIssueIndexer) Init() (bool, error) {
+	return e.client.init(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"RepoID":      map[string]string{"type": "long"},
+			"Title":       map[string]string{"type": "text"},
+			"Content":     map[string]string{"type": "text"},
+			"Comments":    map[string]string{"type": "text"},
+			"LabelIDs":    map[string]string{"type": "long"},
+			"MilestoneID": map[string]string{"type": "long"},
+			"PosterID":    map[string]string{"type": "long"},
+			"AssigneeID":  map[string]string{"type": "long"},
+			"IsClosed":    map[string]string{"type": "boolean"},
+			"IsPull":      map[string]string{"type": "boolean"},
+			"UpdatedUnix": map[string]string{"type": "long"},
+		},
+	}, elasticIssueIndexerLatestVersion)
+}
+
+// Index adds the given issues to the index, or replaces them
+func (e *elasticIssueIndexer) Index(updates []IssueIndexerUpdate) error {
+	actions := make([]elasticBulkAction, len(updates))
+	for i, update := range updates {
+		actions[i] = elasticBulkAction{
+			Action:   "index",
+			ID:       update.documentID(),
+			Document: update.Data,
+		}
+	}
+	return e.client.bulk(actions)
+}
+
+// Delete removes the given issues from the index
+func (e *elasticIssueIndexer) Delete(issueIDs ...int64) error {
+	actions := make([]elasticBulkAction, len(issueIDs))
+	for i, issueID := range issueIDs {
+		actions[i] = elasticBulkAction{
+			Action: "delete",
+			ID:     indexerID(issueID),
+		}
+	}
+	return e.client.bulk(actions)
+}
+
+// Search searches the index for issues
+func (e *elasticIssueIndexer) Search(opts *IssueSearchOptions) (int64, []int64, error) {
+	var must interface{} = map[string]interface{}{"match_all": map[string]interface{}{}}
+	if len(opts.Keyword) > 0 {
+		must = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  opts.Keyword,
+				"type":   "phrase",
+				"fields": []string{"Title", "Content", "Comments"},
+			},
+		}
+	}
+
+	filters := make([]interface{}, 0, 5)
+	if len(opts.RepoIDs) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"terms": map[string]interface{}{"RepoID": opts.RepoIDs},
+		})
+	}
+	if opts.IsClosed != util.OptionalBoolNone {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"IsClosed": opts.IsClosed.IsTrue()},
+		})
+	}
+	if opts.IsPull != util.OptionalBoolNone {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"IsPull": opts.IsPull.IsTrue()},
+		})
+	}
+	for _, labelIDs := range opts.LabelIDs {
+		filters = append(filters, map[string]interface{}{
+			"terms": map[string]interface{}{"LabelIDs": labelIDs},
+		})
+	}
+	if opts.PosterID > 0 {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"PosterID": opts.PosterID},
+		})
+	}
+
+	from, size := elasticPaging(opts.Page, opts.PageSize)
+	result, err := e.client.search(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   must,
+				"filter": filters,
+			},
+		},
+		"sort": []interface{}{
+			"_score",
+			map[string]string{"UpdatedUnix": "desc"},
+		},
+		"from":    from,
+		"size":    size,
+		"_source": false,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	issueIDs := make([]int64, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		issueIDs[i], err = idOfIndexerID(hit.ID)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return result.Hits.Total.Value, issueIDs, nil
+}
+
+// Drop deletes the Elasticsearch index
+func (e *elasticIssueIndexer) Drop() error {
+	return e.client.drop()
+}
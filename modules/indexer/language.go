@@ -0,0 +1,101 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+var (
+	// languages of files without extension, by lower-cased name
+	languageFileNames = map[string]string{
+		"dockerfile":  "dockerfile",
+		"makefile":    "makefile",
+		"gnumakefile": "makefile",
+	}
+
+	// languages by lower-cased file extension
+	languageExts = map[string]string{
+		".bat":    "batch",
+		".c":      "c",
+		".h":      "c",
+		".cc":     "cpp",
+		".cpp":    "cpp",
+		".cxx":    "cpp",
+		".hpp":    "cpp",
+		".cs":     "csharp",
+		".css":    "css",
+		".dart":   "dart",
+		".ex":     "elixir",
+		".exs":    "elixir",
+		".erl":    "erlang",
+		".go":     "go",
+		".groovy": "groovy",
+		".hs":     "haskell",
+		".htm":    "html",
+		".html":   "html",
+		".ini":    "ini",
+		".java":   "java",
+		".js":     "javascript",
+		".jsx":    "javascript",
+		".json":   "json",
+		".kt":     "kotlin",
+		".less":   "less",
+		".lua":    "lua",
+		".md":     "markdown",
+		".m":      "objectivec",
+		".pl":     "perl",
+		".php":    "php",
+		".ps1":    "powershell",
+		".py":     "python",
+		".r":      "r",
+		".rb":     "ruby",
+		".rs":     "rust",
+		".scala":  "scala",
+		".scss":   "scss",
+		".sh":     "shell",
+		".bash":   "shell",
+		".sql":    "sql",
+		".swift":  "swift",
+		".tmpl":   "template",
+		".toml":   "toml",
+		".ts":     "typescript",
+		".tsx":    "typescript",
+		".vb":     "vbnet",
+		".vue":    "vue",
+		".xml":    "xml",
+		".yaml":   "yaml",
+		".yml":    "yaml",
+	}
+)
+
+// FileLanguage returns the language of a file based on its name, or an empty
+// string if the language is unknown
+func FileLanguage(filename string) string {
+	filename = strings.ToLower(path.Base(filename))
+	if language, ok := languageFileNames[filename]; ok {
+		return language
+	}
+	return languageExts[path.Ext(filename)]
+}
+
+// Languages returns all languages which can be searched for, sorted by name
+func Languages() []string {
+	set := make(map[string]struct{}, len(languageExts))
+	for _, language := range languageFileNames {
+		set[language] = struct{}{}
+	}
+	for _, language := range languageExts {
+		set[language] = struct{}{}
+	}
+	languages := make([]string, 0, len(set))
+	for language := range set {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
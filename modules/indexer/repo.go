@@ -5,23 +5,31 @@
 package indexer
 
 import (
-	"os"
+	"fmt"
 	"strings"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-
-	"github.com/blevesearch/bleve"
-	"github.com/blevesearch/bleve/analysis/analyzer/custom"
-	"github.com/blevesearch/bleve/analysis/token/camelcase"
-	"github.com/blevesearch/bleve/analysis/token/lowercase"
-	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
 )
 
-const repoIndexerAnalyzer = "repoIndexerAnalyzer"
+// RepoIndexer represents a backend of the repo indexer
+type RepoIndexer interface {
+	// Init opens the index, or creates it if it does not exist yet. Returns
+	// true if the index was created and has to be populated.
+	Init() (bool, error)
+	// Index adds, replaces or deletes the given files
+	Index(updates []RepoIndexerUpdate) error
+	// DeleteRepo removes all files of a repository from the index
+	DeleteRepo(repoID int64) error
+	// Search returns the total number of matching files and the matching
+	// files of the requested page, with the location of the keyword
+	Search(opts *RepoSearchOptions) (int64, []*RepoSearchResult, error)
+	// Drop removes the index with all of its files
+	Drop() error
+}
 
 // repoIndexer (thread-safe) index for repository contents
-var repoIndexer bleve.Index
+var repoIndexer RepoIndexer
 
 // RepoIndexerOp type of operation to perform on repo indexer
 type RepoIndexerOp int
@@ -36,8 +44,15 @@ const (
 
 // RepoIndexerData data stored in the repo indexer
 type RepoIndexerData struct {
-	RepoID  int64
-	Content string
+	RepoID   int64
+	Filepath string
+	Language string
+	Content  string
+}
+
+// Type returns the document mapping type of the bleve repo indexer
+func (d *RepoIndexerData) Type() string {
+	return repoIndexerDocType
 }
 
 // RepoIndexerUpdate an update to the repo indexer
@@ -47,65 +62,73 @@ type RepoIndexerUpdate struct {
 	Data     *RepoIndexerData
 }
 
-func (update RepoIndexerUpdate) addToBatch(batch *bleve.Batch) error {
-	id := filenameIndexerID(update.Data.RepoID, update.Filepath)
-	switch update.Op {
-	case RepoIndexerOpUpdate:
-		return batch.Index(id, update.Data)
-	case RepoIndexerOpDelete:
-		batch.Delete(id)
-	default:
-		log.Error(4, "Unrecognized repo indexer op: %d", update.Op)
+func (update RepoIndexerUpdate) documentID() string {
+	return filenameIndexerID(update.Data.RepoID, update.Filepath)
+}
+
+// RepoSearchResult result of performing a search in a repo
+type RepoSearchResult struct {
+	RepoID     int64
+	StartIndex int
+	EndIndex   int
+	Filename   string
+	Language   string
+	Content    string
+}
+
+// RepoSearchOptions options for searching the repo indexer
+type RepoSearchOptions struct {
+	RepoIDs    []int64
+	Keyword    string
+	Language   string // Empty means all languages
+	PathPrefix string // Empty means all files
+	Page       int
+	PageSize   int
+}
+
+// newRepoIndexer returns the repo indexer backend of the given type
+func newRepoIndexer(indexerType string) (RepoIndexer, error) {
+	switch indexerType {
+	case setting.IndexerTypeBleve:
+		return newBleveRepoIndexer(setting.Indexer.RepoPath), nil
+	case setting.IndexerTypeElasticsearch:
+		return newElasticRepoIndexer(setting.Indexer.RepoConnStr, setting.Indexer.RepoIndexerName), nil
 	}
-	return nil
+	return nil, fmt.Errorf("Unknown repo indexer type: %s", indexerType)
 }
 
 // InitRepoIndexer initialize repo indexer
 func InitRepoIndexer(populateIndexer func() error) {
-	_, err := os.Stat(setting.Indexer.RepoPath)
+	var err error
+	if repoIndexer, err = newRepoIndexer(setting.Indexer.RepoType); err != nil {
+		log.Fatal(4, "InitRepoIndexer: %v", err)
+	}
+
+	populate, err := repoIndexer.Init()
 	if err != nil {
-		if os.IsNotExist(err) {
-			if err = createRepoIndexer(); err != nil {
-				log.Fatal(4, "CreateRepoIndexer: %v", err)
-			}
-			if err = populateIndexer(); err != nil {
-				log.Fatal(4, "PopulateRepoIndex: %v", err)
-			}
-		} else {
-			log.Fatal(4, "InitRepoIndexer: %v", err)
-		}
-	} else {
-		repoIndexer, err = bleve.Open(setting.Indexer.RepoPath)
-		if err != nil {
-			log.Fatal(4, "InitRepoIndexer, open index: %v", err)
-		}
+		log.Fatal(4, "InitRepoIndexer: %v", err)
+	} else if !populate {
+		return
+	}
+	if err = populateIndexer(); err != nil {
+		log.Fatal(4, "PopulateRepoIndex: %v", err)
 	}
 }
 
-// createRepoIndexer create a repo indexer if one does not already exist
-func createRepoIndexer() error {
-	docMapping := bleve.NewDocumentMapping()
-	docMapping.AddFieldMappingsAt("RepoID", bleve.NewNumericFieldMapping())
-
-	textFieldMapping := bleve.NewTextFieldMapping()
-	docMapping.AddFieldMappingsAt("Content", textFieldMapping)
-
-	mapping := bleve.NewIndexMapping()
-	if err := addUnicodeNormalizeTokenFilter(mapping); err != nil {
-		return err
-	} else if err := mapping.AddCustomAnalyzer(repoIndexerAnalyzer, map[string]interface{}{
-		"type":          custom.Name,
-		"char_filters":  []string{},
-		"tokenizer":     unicode.Name,
-		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
-	}); err != nil {
+// RebuildRepoIndexer drops the repo indexer and populates it again
+func RebuildRepoIndexer(populateIndexer func() error) error {
+	var err error
+	if repoIndexer, err = newRepoIndexer(setting.Indexer.RepoType); err != nil {
 		return err
 	}
-	mapping.DefaultAnalyzer = repoIndexerAnalyzer
-	mapping.AddDocumentMapping("repo", docMapping)
-	var err error
-	repoIndexer, err = bleve.New(setting.Indexer.RepoPath, mapping)
-	return err
+	if _, err = repoIndexer.Init(); err != nil {
+		return fmt.Errorf("init index: %v", err)
+	} else if err = repoIndexer.Drop(); err != nil {
+		return fmt.Errorf("drop index: %v", err)
+	} else if _, err = repoIndexer.Init(); err != nil {
+		return fmt.Errorf("create index: %v", err)
+	}
+	return populateIndexer()
 }
 
 func filenameIndexerID(repoID int64, filename string) string {
@@ -123,77 +146,38 @@ func filenameOfIndexerID(indexerID string) string {
 // RepoIndexerBatch batch to add updates to
 func RepoIndexerBatch() *Batch {
 	return &Batch{
-		batch: repoIndexer.NewBatch(),
-		index: repoIndexer,
+		flush: func(updates []Update) error {
+			repoUpdates := make([]RepoIndexerUpdate, len(updates))
+			for i, update := range updates {
+				repoUpdates[i] = update.(RepoIndexerUpdate)
+			}
+			return repoIndexer.Index(repoUpdates)
+		},
 	}
 }
 
 // DeleteRepoFromIndexer delete all of a repo's files from indexer
 func DeleteRepoFromIndexer(repoID int64) error {
-	query := numericEqualityQuery(repoID, "RepoID")
-	searchRequest := bleve.NewSearchRequestOptions(query, 2147483647, 0, false)
-	result, err := repoIndexer.Search(searchRequest)
-	if err != nil {
-		return err
-	}
-	batch := RepoIndexerBatch()
-	for _, hit := range result.Hits {
-		batch.batch.Delete(hit.ID)
-		if err = batch.flushIfFull(); err != nil {
-			return err
-		}
-	}
-	return batch.Flush()
+	return repoIndexer.DeleteRepo(repoID)
 }
 
-// RepoSearchResult result of performing a search in a repo
-type RepoSearchResult struct {
-	StartIndex int
-	EndIndex   int
-	Filename   string
-	Content    string
+// SearchRepos searches for files in the given repositories, ordered by
+// relevance. Returns the total number of matching files and the matching
+// files of the requested page
+func SearchRepos(opts *RepoSearchOptions) (int64, []*RepoSearchResult, error) {
+	if len(opts.RepoIDs) == 0 {
+		return 0, nil, nil
+	}
+	return repoIndexer.Search(opts)
 }
 
 // SearchRepoByKeyword searches for files in the specified repo.
 // Returns the matching file-paths
 func SearchRepoByKeyword(repoID int64, keyword string, page, pageSize int) (int64, []*RepoSearchResult, error) {
-	phraseQuery := bleve.NewMatchPhraseQuery(keyword)
-	phraseQuery.FieldVal = "Content"
-	phraseQuery.Analyzer = repoIndexerAnalyzer
-	indexerQuery := bleve.NewConjunctionQuery(
-		numericEqualityQuery(repoID, "RepoID"),
-		phraseQuery,
-	)
-	from := (page - 1) * pageSize
-	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, pageSize, from, false)
-	searchRequest.Fields = []string{"Content"}
-	searchRequest.IncludeLocations = true
-
-	result, err := repoIndexer.Search(searchRequest)
-	if err != nil {
-		return 0, nil, err
-	}
-
-	searchResults := make([]*RepoSearchResult, len(result.Hits))
-	for i, hit := range result.Hits {
-		var startIndex, endIndex int = -1, -1
-		for _, locations := range hit.Locations["Content"] {
-			location := locations[0]
-			locationStart := int(location.Start)
-			locationEnd := int(location.End)
-			if startIndex < 0 || locationStart < startIndex {
-				startIndex = locationStart
-			}
-			if endIndex < 0 || locationEnd > endIndex {
-				endIndex = locationEnd
-			}
-		}
-		searchResults[i] = &RepoSearchResult{
-			StartIndex: startIndex,
-			EndIndex:   endIndex,
-			Filename:   filenameOfIndexerID(hit.ID),
-			Content:    hit.Fields["Content"].(string),
-		}
-	}
-	return int64(result.Total), searchResults, nil
+	return SearchRepos(&RepoSearchOptions{
+		RepoIDs:  []int64{repoID},
+		Keyword:  keyword,
+		Page:     page,
+		PageSize: pageSize,
+	})
 }
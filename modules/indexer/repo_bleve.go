@@ -0,0 +1,173 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/token/camelcase"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+const (
+	repoIndexerAnalyzer = "repoIndexerAnalyzer"
+	repoIndexerDocType  = "repo"
+
+	// bleveRepoIndexerLatestVersion must be bumped whenever the mapping of
+	// the repo indexer changes, so that existing indexes are rebuilt
+	bleveRepoIndexerLatestVersion = 1
+)
+
+// bleveRepoIndexer a repo indexer stored in a local bleve index
+type bleveRepoIndexer struct {
+	path  string
+	index bleve.Index
+}
+
+func newBleveRepoIndexer(path string) *bleveRepoIndexer {
+	return &bleveRepoIndexer{path: path}
+}
+
+// Init opens the bleve index, or creates it if it does not exist yet
+func (b *bleveRepoIndexer) Init() (bool, error) {
+	var err error
+	b.index, err = openBleveIndex(b.path, bleveRepoIndexerLatestVersion)
+	if err != nil {
+		return false, err
+	} else if b.index != nil {
+		return false, nil
+	}
+
+	docMapping := bleve.NewDocumentMapping()
+	docMapping.AddFieldMappingsAt("RepoID", bleve.NewNumericFieldMapping())
+
+	keywordFieldMapping := bleve.NewTextFieldMapping()
+	keywordFieldMapping.Analyzer = keywordAnalyzerName
+	keywordFieldMapping.IncludeInAll = false
+	docMapping.AddFieldMappingsAt("Filepath", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("Language", keywordFieldMapping)
+
+	textFieldMapping := bleve.NewTextFieldMapping()
+	docMapping.AddFieldMappingsAt("Content", textFieldMapping)
+
+	mapping := bleve.NewIndexMapping()
+	if err = addUnicodeNormalizeTokenFilter(mapping); err != nil {
+		return false, err
+	} else if err = addKeywordAnalyzer(mapping); err != nil {
+		return false, err
+	} else if err = mapping.AddCustomAnalyzer(repoIndexerAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  []string{},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
+	}); err != nil {
+		return false, err
+	}
+	mapping.DefaultAnalyzer = repoIndexerAnalyzer
+	mapping.AddDocumentMapping(repoIndexerDocType, docMapping)
+
+	b.index, err = createBleveIndex(b.path, mapping, bleveRepoIndexerLatestVersion)
+	return true, err
+}
+
+// Index adds, replaces or deletes the given files
+func (b *bleveRepoIndexer) Index(updates []RepoIndexerUpdate) error {
+	batch := b.index.NewBatch()
+	for _, update := range updates {
+		switch update.Op {
+		case RepoIndexerOpUpdate:
+			if err := batch.Index(update.documentID(), update.Data); err != nil {
+				return err
+			}
+		case RepoIndexerOpDelete:
+			batch.Delete(update.documentID())
+		default:
+			log.Error(4, "Unrecognized repo indexer op: %d", update.Op)
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+// DeleteRepo removes all files of a repository from the index
+func (b *bleveRepoIndexer) DeleteRepo(repoID int64) error {
+	query := numericEqualityQuery(repoID, "RepoID")
+	searchRequest := bleve.NewSearchRequestOptions(query, 2147483647, 0, false)
+	result, err := b.index.Search(searchRequest)
+	if err != nil {
+		return err
+	}
+	batch := b.index.NewBatch()
+	for _, hit := range result.Hits {
+		batch.Delete(hit.ID)
+		if batch.Size() >= maxBatchSize {
+			if err = b.index.Batch(batch); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+// Search searches the files of the given repositories for the keyword
+func (b *bleveRepoIndexer) Search(opts *RepoSearchOptions) (int64, []*RepoSearchResult, error) {
+	queries := []query.Query{
+		numericDisjunctionQuery(opts.RepoIDs, "RepoID"),
+		newMatchPhraseQuery(opts.Keyword, "Content", repoIndexerAnalyzer),
+	}
+	if len(opts.Language) > 0 {
+		queries = append(queries, newTermQuery(opts.Language, "Language"))
+	}
+	if len(opts.PathPrefix) > 0 {
+		queries = append(queries, newPrefixQuery(opts.PathPrefix, "Filepath"))
+	}
+	indexerQuery := bleve.NewConjunctionQuery(queries...)
+	from := (opts.Page - 1) * opts.PageSize
+	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, opts.PageSize, from, false)
+	searchRequest.Fields = []string{"Content", "RepoID", "Language"}
+	searchRequest.IncludeLocations = true
+
+	result, err := b.index.Search(searchRequest)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	searchResults := make([]*RepoSearchResult, len(result.Hits))
+	for i, hit := range result.Hits {
+		var startIndex, endIndex int = -1, -1
+		for _, locations := range hit.Locations["Content"] {
+			location := locations[0]
+			locationStart := int(location.Start)
+			locationEnd := int(location.End)
+			if startIndex < 0 || locationStart < startIndex {
+				startIndex = locationStart
+			}
+			if endIndex < 0 || locationEnd > endIndex {
+				endIndex = locationEnd
+			}
+		}
+		language, _ := hit.Fields["Language"].(string)
+		searchResults[i] = &RepoSearchResult{
+			RepoID:     int64(hit.Fields["RepoID"].(float64)),
+			StartIndex: startIndex,
+			EndIndex:   endIndex,
+			Filename:   filenameOfIndexerID(hit.ID),
+			Language:   language,
+			Content:    hit.Fields["Content"].(string),
+		}
+	}
+	return int64(result.Total), searchResults, nil
+}
+
+// Drop closes and removes the bleve index
+func (b *bleveRepoIndexer) Drop() error {
+	err := dropBleveIndex(b.index, b.path)
+	b.index = nil
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBleveRepoIndexer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repo-indexer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	indexer := newBleveRepoIndexer(path.Join(dir, "repos.bleve"))
+	populate, err := indexer.Init()
+	assert.NoError(t, err)
+	assert.True(t, populate)
+	defer indexer.Drop()
+
+	var updates []RepoIndexerUpdate
+	for _, file := range []struct {
+		repoID   int64
+		filepath string
+		content  string
+	}{
+		{1, "main.go", "func main() {\n\tinitRouter()\n}"},
+		{1, "routers/init.go", "// initRouter initializes the router\nfunc initRouter() {}"},
+		{2, "public/js/index.js", "initRouter();"},
+		{3, "main.go", "initRouter()"},
+	} {
+		updates = append(updates, RepoIndexerUpdate{
+			Filepath: file.filepath,
+			Op:       RepoIndexerOpUpdate,
+			Data: &RepoIndexerData{
+				RepoID:   file.repoID,
+				Filepath: file.filepath,
+				Language: FileLanguage(file.filepath),
+				Content:  file.content,
+			},
+		})
+	}
+	assert.NoError(t, indexer.Index(updates))
+
+	search := func(opts *RepoSearchOptions) map[string]int64 {
+		opts.RepoIDs = []int64{1, 2}
+		opts.Keyword = "initRouter"
+		opts.Page = 1
+		opts.PageSize = 10
+		total, results, err := indexer.Search(opts)
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(results), total)
+		files := make(map[string]int64, len(results))
+		for _, result := range results {
+			assert.True(t, result.StartIndex >= 0)
+			assert.Equal(t, "initRouter", result.Content[result.StartIndex:result.EndIndex])
+			files[result.Filename] = result.RepoID
+		}
+		return files
+	}
+
+	assert.Equal(t, map[string]int64{
+		"main.go":            1,
+		"routers/init.go":    1,
+		"public/js/index.js": 2,
+	}, search(&RepoSearchOptions{}))
+	assert.Equal(t, map[string]int64{
+		"main.go":         1,
+		"routers/init.go": 1,
+	}, search(&RepoSearchOptions{Language: "go"}))
+	assert.Equal(t, map[string]int64{
+		"routers/init.go": 1,
+	}, search(&RepoSearchOptions{PathPrefix: "routers/"}))
+	assert.Equal(t, map[string]int64{}, search(&RepoSearchOptions{Language: "go", PathPrefix: "public/"}))
+}
+
+func TestFileLanguage(t *testing.T) {
+	assert.Equal(t, "go", FileLanguage("modules/indexer/repo.go"))
+	assert.Equal(t, "javascript", FileLanguage("public/js/INDEX.JS"))
+	assert.Equal(t, "dockerfile", FileLanguage("docker/Dockerfile"))
+	assert.Equal(t, "", FileLanguage("LICENSE"))
+	assert.Contains(t, Languages(), "go")
+}
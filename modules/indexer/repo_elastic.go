@@ -0,0 +1,172 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// tags surrounding the keyword in highlighted contents, characters of the
+// unicode private use area are used since they do not appear in source code
+const (
+	elasticHighlightPreTag  = "\uE000"
+	elasticHighlightPostTag = "\uE001"
+)
+
+// elasticRepoIndexerLatestVersion is the version of the mapping below, bump it
+// on changes so that existing indexes are recreated
+const elasticRepoIndexerLatestVersion = 1
+
+// elasticRepoIndexer a repo indexer stored in an Elasticsearch index, which
+// can be shared by several Gitea instances
+type elasticRepoIndexer struct {
+	client *elasticClient
+}
+
+func newElasticRepoIndexer(url, index string) *elasticRepoIndexer {
+	return &elasticRepoIndexer{client: newElasticClient(url, index)}
+}
+
+// Init creates the Elasticsearch index if it does not exist yet or if its
+// mapping is outdated
+func (e *elasticRepoIndexer) Init() (bool, error) {
+	return e.client.init(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"RepoID":   map[string]string{"type": "long"},
+			"Filepath": map[string]string{"type": "keyword"},
+			"Language": map[string]string{"type": "keyword"},
+			"Content": map[string]string{
+				"type":        "text",
+				"term_vector": "with_positions_offsets",
+			},
+		},
+	}, elasticRepoIndexerLatestVersion)
+}
+
+// Index adds, replaces or deletes the given files
+func (e *elasticRepoIndexer) Index(updates []RepoIndexerUpdate) error {
+	actions := make([]elasticBulkAction, 0, len(updates))
+	for _, update := range updates {
+		switch update.Op {
+		case RepoIndexerOpUpdate:
+			actions = append(actions, elasticBulkAction{
+				Action:   "index",
+				ID:       update.documentID(),
+				Document: update.Data,
+			})
+		case RepoIndexerOpDelete:
+			actions = append(actions, elasticBulkAction{
+				Action: "delete",
+				ID:     update.documentID(),
+			})
+		default:
+			log.Error(4, "Unrecognized repo indexer op: %d", update.Op)
+		}
+	}
+	return e.client.bulk(actions)
+}
+
+// DeleteRepo removes all files of a repository from the index
+func (e *elasticRepoIndexer) DeleteRepo(repoID int64) error {
+	return e.client.deleteByQuery(map[string]interface{}{
+		"term": map[string]interface{}{"RepoID": repoID},
+	})
+}
+
+// Search searches the files of the given repositories for the keyword
+func (e *elasticRepoIndexer) Search(opts *RepoSearchOptions) (int64, []*RepoSearchResult, error) {
+	filters := []interface{}{
+		map[string]interface{}{
+			"terms": map[string]interface{}{"RepoID": opts.RepoIDs},
+		},
+	}
+	if len(opts.Language) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"Language": opts.Language},
+		})
+	}
+	if len(opts.PathPrefix) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"prefix": map[string]interface{}{"Filepath": opts.PathPrefix},
+		})
+	}
+
+	from, size := elasticPaging(opts.Page, opts.PageSize)
+	result, err := e.client.search(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"match_phrase": map[string]interface{}{"Content": opts.Keyword},
+				},
+				"filter": filters,
+			},
+		},
+		"highlight": map[string]interface{}{
+			"pre_tags":  []string{elasticHighlightPreTag},
+			"post_tags": []string{elasticHighlightPostTag},
+			"fields": map[string]interface{}{
+				// highlight the whole content, to find the location of the keyword
+				"Content": map[string]interface{}{"number_of_fragments": 0},
+			},
+		},
+		"from": from,
+		"size": size,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	searchResults := make([]*RepoSearchResult, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		var data RepoIndexerData
+		if err = json.Unmarshal(hit.Source, &data); err != nil {
+			return 0, nil, err
+		}
+		startIndex, endIndex := -1, -1
+		if highlights := hit.Highlight["Content"]; len(highlights) > 0 {
+			startIndex, endIndex = elasticHighlightRange(highlights[0])
+		}
+		searchResults[i] = &RepoSearchResult{
+			RepoID:     data.RepoID,
+			StartIndex: startIndex,
+			EndIndex:   endIndex,
+			Filename:   filenameOfIndexerID(hit.ID),
+			Language:   data.Language,
+			Content:    data.Content,
+		}
+	}
+	return result.Hits.Total.Value, searchResults, nil
+}
+
+// elasticHighlightRange returns the start of the first and the end of the last
+// highlighted keyword, as byte offsets into the content without tags
+func elasticHighlightRange(highlighted string) (int, int) {
+	startIndex, endIndex := -1, -1
+	var offset int // length of the tags before the current position
+	for pos := 0; pos < len(highlighted); {
+		if strings.HasPrefix(highlighted[pos:], elasticHighlightPreTag) {
+			if startIndex < 0 {
+				startIndex = pos - offset
+			}
+			offset += len(elasticHighlightPreTag)
+			pos += len(elasticHighlightPreTag)
+		} else if strings.HasPrefix(highlighted[pos:], elasticHighlightPostTag) {
+			endIndex = pos - offset
+			offset += len(elasticHighlightPostTag)
+			pos += len(elasticHighlightPostTag)
+		} else {
+			pos++
+		}
+	}
+	return startIndex, endIndex
+}
+
+// Drop deletes the Elasticsearch index
+func (e *elasticRepoIndexer) Drop() error {
+	return e.client.drop()
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrRemoteRequest represents a failed request to the API of a source host.
+type ErrRemoteRequest struct {
+	URL    string
+	Status string
+}
+
+// IsErrRemoteRequest checks if an error is a ErrRemoteRequest.
+func IsErrRemoteRequest(err error) bool {
+	_, ok := err.(ErrRemoteRequest)
+	return ok
+}
+
+func (err ErrRemoteRequest) Error() string {
+	return fmt.Sprintf("remote request failed [url: %s, status: %s]", err.URL, err.Status)
+}
+
+// restClient requests the JSON REST API of a source host.
+type restClient struct {
+	baseURL string
+	header  http.Header
+	client  *http.Client
+}
+
+func newRestClient(baseURL string) *restClient {
+	return &restClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		header:  make(http.Header),
+		client: &http.Client{
+			Timeout: time.Duration(setting.Git.Timeout.Migrate) * time.Second,
+		},
+	}
+}
+
+// get requests the given absolute URL and returns the body of the response.
+func (c *restClient) get(rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, ErrRemoteRequest{URL: rawURL, Status: resp.Status}
+	}
+	return resp.Body, nil
+}
+
+// getJSON requests the given API path and decodes the response into v.
+func (c *restClient) getJSON(path string, query url.Values, v interface{}) error {
+	rawURL := c.baseURL + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	body, err := c.get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err = json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("decode %s: %v", path, err)
+	}
+	return nil
+}
+
+// pageQuery returns the query parameters to request a page of a list.
+func pageQuery(page, perPage int, params ...string) url.Values {
+	query := url.Values{}
+	query.Set("page", fmt.Sprint(page))
+	query.Set("per_page", fmt.Sprint(perPage))
+	for i := 0; i+1 < len(params); i += 2 {
+		query.Set(params[i], params[i+1])
+	}
+	return query
+}
+
+// basicAuth returns the credentials for the HTTP basic authentication.
+func basicAuth(userName, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(userName + ":" + password))
+}
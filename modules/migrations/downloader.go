@@ -0,0 +1,26 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "io"
+
+// Downloader reads the information of a repository from its source host.
+// GetIssues and GetPullRequests are paginated, the page number starts at 1.
+type Downloader interface {
+	GetRepoInfo() (*Repository, error)
+	GetMilestones() ([]*Milestone, error)
+	GetLabels() ([]*Label, error)
+	GetReleases() ([]*Release, error)
+	GetAsset(asset *ReleaseAsset) (io.ReadCloser, error)
+	GetIssues(page, perPage int) (issues []*Issue, isEnd bool, err error)
+	GetComments(issueNumber int64) ([]*Comment, error)
+	GetPullRequests(page, perPage int) (prs []*PullRequest, isEnd bool, err error)
+}
+
+// DownloaderFactory creates a Downloader for the repositories of a source host
+type DownloaderFactory interface {
+	Match(opts MigrateOptions) (bool, error)
+	New(opts MigrateOptions) (Downloader, error)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotSupported is returned by a Downloader which cannot read an item of a repository.
+var ErrNotSupported = errors.New("not supported")
+
+// PlainGitDownloader only migrates the git data of a repository
+type PlainGitDownloader struct {
+	ownerName string
+	repoName  string
+	remoteURL string
+}
+
+// NewPlainGitDownloader creates a git downloader
+func NewPlainGitDownloader(ownerName, repoName, remoteURL string) *PlainGitDownloader {
+	return &PlainGitDownloader{
+		ownerName: ownerName,
+		repoName:  repoName,
+		remoteURL: remoteURL,
+	}
+}
+
+// GetRepoInfo returns the repository information
+func (g *PlainGitDownloader) GetRepoInfo() (*Repository, error) {
+	return &Repository{
+		Owner:    g.ownerName,
+		Name:     g.repoName,
+		CloneURL: g.remoteURL,
+	}, nil
+}
+
+// GetMilestones is not supported
+func (g *PlainGitDownloader) GetMilestones() ([]*Milestone, error) {
+	return nil, ErrNotSupported
+}
+
+// GetLabels is not supported
+func (g *PlainGitDownloader) GetLabels() ([]*Label, error) {
+	return nil, ErrNotSupported
+}
+
+// GetReleases is not supported
+func (g *PlainGitDownloader) GetReleases() ([]*Release, error) {
+	return nil, ErrNotSupported
+}
+
+// GetAsset is not supported
+func (g *PlainGitDownloader) GetAsset(asset *ReleaseAsset) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+// GetIssues is not supported
+func (g *PlainGitDownloader) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	return nil, false, ErrNotSupported
+}
+
+// GetComments is not supported
+func (g *PlainGitDownloader) GetComments(issueNumber int64) ([]*Comment, error) {
+	return nil, ErrNotSupported
+}
+
+// GetPullRequests is not supported
+func (g *PlainGitDownloader) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	return nil, false, ErrNotSupported
+}
@@ -0,0 +1,346 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+
+	gouuid "github.com/satori/go.uuid"
+)
+
+// GiteaLocalUploader writes a migrated repository into this Gitea instance.
+// Everything is created in the name of the doer, the names of the original
+// authors of issues and comments are kept.
+type GiteaLocalUploader struct {
+	doer  *models.User
+	owner *models.User
+	repo  *models.Repository
+
+	labels     map[string]*models.Label
+	milestones map[string]int64
+	issues     map[int64]*models.Issue
+}
+
+// NewGiteaLocalUploader creates an uploader migrating into a repository of owner
+func NewGiteaLocalUploader(doer, owner *models.User) *GiteaLocalUploader {
+	return &GiteaLocalUploader{
+		doer:       doer,
+		owner:      owner,
+		labels:     make(map[string]*models.Label),
+		milestones: make(map[string]int64),
+		issues:     make(map[int64]*models.Issue),
+	}
+}
+
+// CreateRepo clones the repository
+func (g *GiteaLocalUploader) CreateRepo(repo *Repository, opts MigrateOptions) error {
+	description := opts.Description
+	if len(description) == 0 {
+		description = repo.Description
+	}
+
+	var err error
+	g.repo, err = models.MigrateRepository(g.doer, g.owner, models.MigrateRepoOptions{
+		Name:        opts.Name,
+		Description: description,
+		IsPrivate:   opts.Private,
+		IsMirror:    opts.Mirror,
+		RemoteAddr:  opts.RemoteURL,
+	})
+	return err
+}
+
+// CreateMilestones creates the milestones
+func (g *GiteaLocalUploader) CreateMilestones(milestones ...*Milestone) error {
+	noDeadline, _ := time.ParseInLocation("2006-01-02", "9999-12-31", time.Local)
+
+	ms := make([]*models.Milestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		m := &models.Milestone{
+			RepoID:   g.repo.ID,
+			Name:     milestone.Title,
+			Content:  milestone.Description,
+			IsClosed: milestone.IsClosed,
+			Deadline: noDeadline,
+		}
+		if milestone.Deadline != nil {
+			m.Deadline = *milestone.Deadline
+		}
+		if milestone.Closed != nil {
+			m.ClosedDateUnix = milestone.Closed.Unix()
+		}
+		ms = append(ms, m)
+	}
+
+	if err := models.InsertMilestones(ms...); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		g.milestones[m.Name] = m.ID
+	}
+	return nil
+}
+
+// CreateLabels creates the labels
+func (g *GiteaLocalUploader) CreateLabels(labels ...*Label) error {
+	ls := make([]*models.Label, 0, len(labels))
+	for _, label := range labels {
+		ls = append(ls, &models.Label{
+			RepoID: g.repo.ID,
+			Name:   label.Name,
+			Color:  "#" + label.Color,
+		})
+	}
+
+	if len(ls) > 0 {
+		if err := models.NewLabels(ls...); err != nil {
+			return err
+		}
+	}
+	for _, l := range ls {
+		g.labels[l.Name] = l
+	}
+	return nil
+}
+
+// CreateReleases creates the releases and downloads their assets
+func (g *GiteaLocalUploader) CreateReleases(downloader Downloader, releases ...*Release) error {
+	rels := make([]*models.Release, 0, len(releases))
+	for _, release := range releases {
+		rel := &models.Release{
+			RepoID:       g.repo.ID,
+			PublisherID:  g.doer.ID,
+			TagName:      release.TagName,
+			Target:       release.TargetCommitish,
+			Title:        release.Name,
+			Note:         release.Body,
+			IsDraft:      release.Draft,
+			IsPrerelease: release.Prerelease,
+			CreatedUnix:  release.Created.Unix(),
+		}
+
+		for _, asset := range release.Assets {
+			attach, err := g.downloadAsset(downloader, asset)
+			if err != nil {
+				return fmt.Errorf("downloadAsset [%s]: %v", asset.Name, err)
+			}
+			rel.Attachments = append(rel.Attachments, attach)
+		}
+		rels = append(rels, rel)
+	}
+	return models.InsertReleases(rels...)
+}
+
+func (g *GiteaLocalUploader) downloadAsset(downloader Downloader, asset *ReleaseAsset) (*models.Attachment, error) {
+	attach := &models.Attachment{
+		UUID:          gouuid.NewV4().String(),
+		Name:          asset.Name,
+		DownloadCount: asset.DownloadCount,
+		CreatedUnix:   asset.Created.Unix(),
+	}
+
+	rc, err := downloader.GetAsset(asset)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	localPath := attach.LocalPath()
+	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll: %v", err)
+	}
+	fw, err := os.Create(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+	defer fw.Close()
+
+	if _, err = io.Copy(fw, rc); err != nil {
+		return nil, fmt.Errorf("Copy: %v", err)
+	}
+	return attach, nil
+}
+
+func (g *GiteaLocalUploader) newIssue(number int64, posterID int64, posterName, title, content, milestone string,
+	labels []*Label, isClosed bool, created, updated time.Time) *models.Issue {
+	issue := &models.Issue{
+		RepoID:           g.repo.ID,
+		Repo:             g.repo,
+		Index:            number,
+		PosterID:         g.doer.ID,
+		OriginalAuthor:   posterName,
+		OriginalAuthorID: posterID,
+		Title:            title,
+		Content:          content,
+		MilestoneID:      g.milestones[milestone],
+		IsClosed:         isClosed,
+		CreatedUnix:      created.Unix(),
+		UpdatedUnix:      updated.Unix(),
+	}
+	if issue.UpdatedUnix < issue.CreatedUnix {
+		issue.UpdatedUnix = issue.CreatedUnix
+	}
+
+	// Labels which have not been migrated are dropped.
+	for _, label := range labels {
+		if l, ok := g.labels[label.Name]; ok {
+			issue.Labels = append(issue.Labels, l)
+		}
+	}
+	return issue
+}
+
+// CreateIssues creates the issues
+func (g *GiteaLocalUploader) CreateIssues(issues ...*Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	is := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		is = append(is, g.newIssue(issue.Number, issue.PosterID, issue.PosterName, issue.Title, issue.Content,
+			issue.Milestone, issue.Labels, issue.IsClosed, issue.Created, issue.Updated))
+	}
+
+	if err := models.InsertIssues(is...); err != nil {
+		return err
+	}
+	for _, issue := range is {
+		g.issues[issue.Index] = issue
+	}
+	return nil
+}
+
+// CreateComments creates the comments of issues and pull requests
+func (g *GiteaLocalUploader) CreateComments(comments ...*Comment) error {
+	cs := make([]*models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		issue, ok := g.issues[comment.IssueIndex]
+		if !ok {
+			return fmt.Errorf("comment of unknown issue %d", comment.IssueIndex)
+		}
+		cs = append(cs, &models.Comment{
+			Type:             models.CommentTypeComment,
+			IssueID:          issue.ID,
+			PosterID:         g.doer.ID,
+			OriginalAuthor:   comment.PosterName,
+			OriginalAuthorID: comment.PosterID,
+			Content:          comment.Content,
+			CreatedUnix:      comment.Created.Unix(),
+			UpdatedUnix:      comment.Created.Unix(),
+		})
+	}
+	return models.InsertIssueComments(cs...)
+}
+
+// CreatePullRequests creates the pull requests and the git references of their heads
+func (g *GiteaLocalUploader) CreatePullRequests(prs ...*PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	gprs := make([]*models.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		gpr := &models.PullRequest{
+			Type:           models.PullRequestGitea,
+			Status:         models.PullRequestStatusMergeable,
+			HeadRepoID:     g.repo.ID,
+			BaseRepoID:     g.repo.ID,
+			HeadUserName:   g.owner.Name,
+			HeadBranch:     migratedHeadBranch(pr),
+			BaseBranch:     pr.Base.Ref,
+			MergeBase:      pr.Base.SHA,
+			HasMerged:      pr.Merged,
+			MergedCommitID: pr.MergeCommitSHA,
+		}
+		if pr.IsForkPullRequest() && len(pr.Head.OwnerName) > 0 {
+			gpr.HeadUserName = pr.Head.OwnerName
+		}
+		if pr.Merged {
+			gpr.MergerID = g.doer.ID
+			if pr.MergedTime != nil {
+				gpr.MergedUnix = pr.MergedTime.Unix()
+			}
+		} else if !pr.IsClosed {
+			// Open pull requests are tested once their head branch exists,
+			// they cannot be merged if their head is gone.
+			if len(pr.Head.SHA) > 0 {
+				gpr.Status = models.PullRequestStatusChecking
+			} else {
+				gpr.Status = models.PullRequestStatusConflict
+			}
+		}
+
+		gpr.Issue = g.newIssue(pr.Number, pr.PosterID, pr.PosterName, pr.Title, pr.Content,
+			pr.Milestone, pr.Labels, pr.IsClosed, pr.Created, pr.Updated)
+		gpr.Issue.IsPull = true
+		gprs = append(gprs, gpr)
+	}
+
+	if err := models.InsertPullRequests(gprs...); err != nil {
+		return err
+	}
+
+	repoPath := g.repo.RepoPath()
+	for i, gpr := range gprs {
+		g.issues[gpr.Index] = gpr.Issue
+
+		// The head commit may be gone from the source repository, e.g. if the fork has been deleted.
+		if len(prs[i].Head.SHA) == 0 {
+			continue
+		}
+		if _, err := git.NewCommand("update-ref", fmt.Sprintf("refs/pull/%d/head", gpr.Index), prs[i].Head.SHA).RunInDir(repoPath); err != nil {
+			log.Warn("Migrate head of pull request %d of %s: %v", gpr.Index, repoPath, err)
+			continue
+		}
+
+		if gpr.Status != models.PullRequestStatusChecking {
+			continue
+		}
+		if !git.IsBranchExist(repoPath, gpr.HeadBranch) {
+			if _, err := git.NewCommand("update-ref", git.BranchPrefix+gpr.HeadBranch, prs[i].Head.SHA).RunInDir(repoPath); err != nil {
+				log.Warn("Create head branch of pull request %d of %s: %v", gpr.Index, repoPath, err)
+				continue
+			}
+		}
+		if err := gpr.UpdatePatch(); err != nil {
+			log.Warn("UpdatePatch of pull request %d of %s: %v", gpr.Index, repoPath, err)
+			continue
+		}
+		gpr.AddToTaskQueue()
+	}
+	return nil
+}
+
+// migratedHeadBranch returns the branch of the head of a pull request in the
+// migrated repository. The heads of pull requests from forks are created as
+// branches prefixed by the owner of the fork, to not clash with the branches
+// of the repository.
+func migratedHeadBranch(pr *PullRequest) string {
+	if !pr.IsForkPullRequest() {
+		return pr.Head.Ref
+	}
+	if len(pr.Head.OwnerName) > 0 {
+		return pr.Head.OwnerName + "/" + pr.Head.Ref
+	}
+	return fmt.Sprintf("pull/%d/%s", pr.Number, pr.Head.Ref)
+}
+
+// Finish updates the counters of the repository after everything has been migrated
+func (g *GiteaLocalUploader) Finish() error {
+	if err := models.UpdateRepoIssueStats(g.repo.ID); err != nil {
+		return fmt.Errorf("UpdateRepoIssueStats: %v", err)
+	}
+	return nil
+}
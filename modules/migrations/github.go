@@ -0,0 +1,376 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const githubPerPage = 100
+
+func init() {
+	RegisterDownloaderFactory(&GithubDownloaderV3Factory{})
+}
+
+// GithubDownloaderV3Factory creates downloaders for repositories on github.com
+type GithubDownloaderV3Factory struct{}
+
+// Match returns true if the repository is hosted on github.com
+func (f *GithubDownloaderV3Factory) Match(opts MigrateOptions) (bool, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(u.Host, "github.com"), nil
+}
+
+// New returns a downloader for the repository of the migration
+func (f *GithubDownloaderV3Factory) New(opts MigrateOptions) (Downloader, error) {
+	ownerName, repoName, err := parseOwnerAndRepo(opts.RemoteURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewGithubDownloaderV3("https://api.github.com", opts.AuthUsername, opts.AuthPassword, ownerName, repoName), nil
+}
+
+// GithubDownloaderV3 reads a repository from the GitHub API v3
+type GithubDownloaderV3 struct {
+	client   *restClient
+	repoPath string
+	owner    string
+	repo     string
+}
+
+// NewGithubDownloaderV3 creates a GitHub downloader. A password without a
+// username is used as personal access token.
+func NewGithubDownloaderV3(baseURL, userName, password, owner, repo string) *GithubDownloaderV3 {
+	client := newRestClient(baseURL)
+	client.header.Set("Accept", "application/vnd.github.v3+json")
+	if len(userName) > 0 {
+		client.header.Set("Authorization", "Basic "+basicAuth(userName, password))
+	} else if len(password) > 0 {
+		client.header.Set("Authorization", "token "+password)
+	}
+
+	return &GithubDownloaderV3{
+		client:   client,
+		repoPath: fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo)),
+		owner:    owner,
+		repo:     repo,
+	}
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type githubRepository struct {
+	Name        string     `json:"name"`
+	Owner       githubUser `json:"owner"`
+	Private     bool       `json:"private"`
+	Description string     `json:"description"`
+	CloneURL    string     `json:"clone_url"`
+	HTMLURL     string     `json:"html_url"`
+}
+
+// GetRepoInfo returns the repository information
+func (g *GithubDownloaderV3) GetRepoInfo() (*Repository, error) {
+	var repo githubRepository
+	if err := g.client.getJSON(g.repoPath, nil, &repo); err != nil {
+		return nil, err
+	}
+	return &Repository{
+		Name:        repo.Name,
+		Owner:       repo.Owner.Login,
+		IsPrivate:   repo.Private,
+		Description: repo.Description,
+		CloneURL:    repo.CloneURL,
+		OriginalURL: repo.HTMLURL,
+	}, nil
+}
+
+type githubMilestone struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// GetMilestones returns all milestones
+func (g *GithubDownloaderV3) GetMilestones() ([]*Milestone, error) {
+	milestones := make([]*Milestone, 0, githubPerPage)
+	for page := 1; ; page++ {
+		var ms []githubMilestone
+		if err := g.client.getJSON(g.repoPath+"/milestones", pageQuery(page, githubPerPage, "state", "all"), &ms); err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			milestones = append(milestones, &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				Deadline:    m.DueOn,
+				Created:     m.CreatedAt,
+				Closed:      m.ClosedAt,
+				IsClosed:    m.State == "closed",
+			})
+		}
+		if len(ms) < githubPerPage {
+			return milestones, nil
+		}
+	}
+}
+
+type githubLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func convertGithubLabels(ls []githubLabel) []*Label {
+	labels := make([]*Label, len(ls))
+	for i, l := range ls {
+		labels[i] = &Label{
+			Name:  l.Name,
+			Color: l.Color,
+		}
+	}
+	return labels
+}
+
+// GetLabels returns all labels
+func (g *GithubDownloaderV3) GetLabels() ([]*Label, error) {
+	labels := make([]*Label, 0, githubPerPage)
+	for page := 1; ; page++ {
+		var ls []githubLabel
+		if err := g.client.getJSON(g.repoPath+"/labels", pageQuery(page, githubPerPage), &ls); err != nil {
+			return nil, err
+		}
+		labels = append(labels, convertGithubLabels(ls)...)
+		if len(ls) < githubPerPage {
+			return labels, nil
+		}
+	}
+}
+
+type githubReleaseAsset struct {
+	Name               string    `json:"name"`
+	BrowserDownloadURL string    `json:"browser_download_url"`
+	DownloadCount      int64     `json:"download_count"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type githubRelease struct {
+	TagName         string               `json:"tag_name"`
+	TargetCommitish string               `json:"target_commitish"`
+	Name            string               `json:"name"`
+	Body            string               `json:"body"`
+	Draft           bool                 `json:"draft"`
+	Prerelease      bool                 `json:"prerelease"`
+	Author          githubUser           `json:"author"`
+	Assets          []githubReleaseAsset `json:"assets"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// GetReleases returns all releases
+func (g *GithubDownloaderV3) GetReleases() ([]*Release, error) {
+	releases := make([]*Release, 0, githubPerPage)
+	for page := 1; ; page++ {
+		var rels []githubRelease
+		if err := g.client.getJSON(g.repoPath+"/releases", pageQuery(page, githubPerPage), &rels); err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			r := &Release{
+				TagName:         rel.TagName,
+				TargetCommitish: rel.TargetCommitish,
+				Name:            rel.Name,
+				Body:            rel.Body,
+				Draft:           rel.Draft,
+				Prerelease:      rel.Prerelease,
+				PublisherID:     rel.Author.ID,
+				PublisherName:   rel.Author.Login,
+				Created:         rel.CreatedAt,
+			}
+			for _, asset := range rel.Assets {
+				r.Assets = append(r.Assets, &ReleaseAsset{
+					Name:          asset.Name,
+					DownloadURL:   asset.BrowserDownloadURL,
+					DownloadCount: asset.DownloadCount,
+					Created:       asset.CreatedAt,
+				})
+			}
+			releases = append(releases, r)
+		}
+		if len(rels) < githubPerPage {
+			return releases, nil
+		}
+	}
+}
+
+// GetAsset returns the content of a release asset
+func (g *GithubDownloaderV3) GetAsset(asset *ReleaseAsset) (io.ReadCloser, error) {
+	return g.client.get(asset.DownloadURL)
+}
+
+type githubIssue struct {
+	Number      int64            `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	User        githubUser       `json:"user"`
+	State       string           `json:"state"`
+	Labels      []githubLabel    `json:"labels"`
+	Milestone   *githubMilestone `json:"milestone"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ClosedAt    *time.Time       `json:"closed_at"`
+	PullRequest *struct{}        `json:"pull_request"`
+}
+
+// GetIssues returns a page of issues, pull requests are skipped.
+func (g *GithubDownloaderV3) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	var is []githubIssue
+	if err := g.client.getJSON(g.repoPath+"/issues",
+		pageQuery(page, perPage, "state", "all", "sort", "created", "direction", "asc"), &is); err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*Issue, 0, len(is))
+	for _, issue := range is {
+		// The issues API of GitHub lists pull requests as well.
+		if issue.PullRequest != nil {
+			continue
+		}
+
+		var milestone string
+		if issue.Milestone != nil {
+			milestone = issue.Milestone.Title
+		}
+		issues = append(issues, &Issue{
+			Number:     issue.Number,
+			PosterID:   issue.User.ID,
+			PosterName: issue.User.Login,
+			Title:      issue.Title,
+			Content:    issue.Body,
+			Milestone:  milestone,
+			IsClosed:   issue.State == "closed",
+			Created:    issue.CreatedAt,
+			Updated:    issue.UpdatedAt,
+			Closed:     issue.ClosedAt,
+			Labels:     convertGithubLabels(issue.Labels),
+		})
+	}
+	return issues, len(is) < perPage, nil
+}
+
+type githubComment struct {
+	Body      string     `json:"body"`
+	User      githubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// GetComments returns all comments of an issue or pull request
+func (g *GithubDownloaderV3) GetComments(issueNumber int64) ([]*Comment, error) {
+	comments := make([]*Comment, 0, 10)
+	for page := 1; ; page++ {
+		var cs []githubComment
+		if err := g.client.getJSON(fmt.Sprintf("%s/issues/%d/comments", g.repoPath, issueNumber),
+			pageQuery(page, githubPerPage), &cs); err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			comments = append(comments, &Comment{
+				IssueIndex: issueNumber,
+				PosterID:   c.User.ID,
+				PosterName: c.User.Login,
+				Content:    c.Body,
+				Created:    c.CreatedAt,
+			})
+		}
+		if len(cs) < githubPerPage {
+			return comments, nil
+		}
+	}
+}
+
+type githubPullRequestBranch struct {
+	Ref  string `json:"ref"`
+	SHA  string `json:"sha"`
+	Repo *struct {
+		Name  string     `json:"name"`
+		Owner githubUser `json:"owner"`
+	} `json:"repo"`
+}
+
+func (b githubPullRequestBranch) convert() PullRequestBranch {
+	branch := PullRequestBranch{
+		Ref: b.Ref,
+		SHA: b.SHA,
+	}
+	// The repository of the head is missing if the fork has been deleted.
+	if b.Repo != nil {
+		branch.OwnerName = b.Repo.Owner.Login
+		branch.RepoName = b.Repo.Name
+	}
+	return branch
+}
+
+type githubPullRequest struct {
+	Number         int64                   `json:"number"`
+	Title          string                  `json:"title"`
+	Body           string                  `json:"body"`
+	User           githubUser              `json:"user"`
+	State          string                  `json:"state"`
+	Labels         []githubLabel           `json:"labels"`
+	Milestone      *githubMilestone        `json:"milestone"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+	ClosedAt       *time.Time              `json:"closed_at"`
+	MergedAt       *time.Time              `json:"merged_at"`
+	MergeCommitSHA string                  `json:"merge_commit_sha"`
+	Head           githubPullRequestBranch `json:"head"`
+	Base           githubPullRequestBranch `json:"base"`
+}
+
+// GetPullRequests returns a page of pull requests
+func (g *GithubDownloaderV3) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	var ps []githubPullRequest
+	if err := g.client.getJSON(g.repoPath+"/pulls",
+		pageQuery(page, perPage, "state", "all", "sort", "created", "direction", "asc"), &ps); err != nil {
+		return nil, false, err
+	}
+
+	prs := make([]*PullRequest, 0, len(ps))
+	for _, pr := range ps {
+		var milestone string
+		if pr.Milestone != nil {
+			milestone = pr.Milestone.Title
+		}
+		prs = append(prs, &PullRequest{
+			Number:         pr.Number,
+			PosterID:       pr.User.ID,
+			PosterName:     pr.User.Login,
+			Title:          pr.Title,
+			Content:        pr.Body,
+			Milestone:      milestone,
+			IsClosed:       pr.State == "closed",
+			Created:        pr.CreatedAt,
+			Updated:        pr.UpdatedAt,
+			Closed:         pr.ClosedAt,
+			Labels:         convertGithubLabels(pr.Labels),
+			Merged:         pr.MergedAt != nil,
+			MergedTime:     pr.MergedAt,
+			MergeCommitSHA: pr.MergeCommitSHA,
+			Head:           pr.Head.convert(),
+			Base:           pr.Base.convert(),
+		})
+	}
+	return prs, len(ps) < perPage, nil
+}
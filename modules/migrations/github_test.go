@@ -0,0 +1,117 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubDownloaderV3Factory_Match(t *testing.T) {
+	factory := &GithubDownloaderV3Factory{}
+	for remoteURL, expected := range map[string]bool{
+		"https://github.com/go-gitea/gitea":     true,
+		"https://GitHub.com/go-gitea/gitea.git": true,
+		"https://gitlab.com/gitea/test_repo":    false,
+		"https://try.gitea.io/user/repo.git":    false,
+	} {
+		match, err := factory.Match(MigrateOptions{RemoteURL: remoteURL})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, match, remoteURL)
+	}
+}
+
+func TestGithubDownloaderV3(t *testing.T) {
+	server := newFixtureServer("github")
+	defer server.Close()
+
+	downloader := NewGithubDownloaderV3(server.URL, "", "", "go-gitea", "test_repo")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, &Repository{
+		Name:        "test_repo",
+		Owner:       "go-gitea",
+		Description: "Test repository for testing migration from github to gitea",
+		CloneURL:    "https://github.com/go-gitea/test_repo.git",
+		OriginalURL: "https://github.com/go-gitea/test_repo",
+	}, repo)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 2) {
+		assert.Equal(t, "1.0.0", milestones[0].Title)
+		assert.False(t, milestones[0].IsClosed)
+		assert.NotNil(t, milestones[0].Deadline)
+		assert.Equal(t, "0.9.0", milestones[1].Title)
+		assert.True(t, milestones[1].IsClosed)
+		assert.NotNil(t, milestones[1].Closed)
+	}
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Label{
+		{Name: "bug", Color: "d73a4a"},
+		{Name: "enhancement", Color: "a2eeef"},
+	}, labels)
+
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	if assert.Len(t, releases, 1) {
+		assert.Equal(t, "v1.1", releases[0].TagName)
+		assert.Equal(t, "First Release", releases[0].Name)
+		if assert.Len(t, releases[0].Assets, 1) {
+			asset := releases[0].Assets[0]
+			assert.Equal(t, "test_repo-v1.1.zip", asset.Name)
+			assert.EqualValues(t, 42, asset.DownloadCount)
+
+			rc, err := downloader.GetAsset(asset)
+			if assert.NoError(t, err) {
+				data, err := ioutil.ReadAll(rc)
+				rc.Close()
+				assert.NoError(t, err)
+				assert.Equal(t, "release asset", string(data))
+			}
+		}
+	}
+
+	// Pull requests are listed as issues as well, but only returned as pull requests.
+	issues, isEnd, err := downloader.GetIssues(1, 2)
+	assert.NoError(t, err)
+	assert.False(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "guillep2k", issues[0].PosterName)
+		assert.True(t, issues[0].IsClosed)
+		assert.Equal(t, "1.0.0", issues[0].Milestone)
+		assert.Equal(t, []*Label{{Name: "bug", Color: "d73a4a"}}, issues[0].Labels)
+		assert.EqualValues(t, 2, issues[1].Number)
+		assert.False(t, issues[1].IsClosed)
+	}
+
+	comments, err := downloader.GetComments(1)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 2) {
+		assert.EqualValues(t, 1, comments[0].IssueIndex)
+		assert.Equal(t, "mrsdizzie", comments[0].PosterName)
+		assert.Equal(t, "This is a comment", comments[0].Content)
+	}
+
+	prs, isEnd, err := downloader.GetPullRequests(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, prs, 1) {
+		pr := prs[0]
+		assert.EqualValues(t, 3, pr.Number)
+		assert.True(t, pr.Merged)
+		assert.True(t, pr.IsClosed)
+		assert.True(t, pr.IsForkPullRequest())
+		assert.Equal(t, "mrsdizzie", pr.Head.OwnerName)
+		assert.Equal(t, "master", pr.Base.Ref)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", pr.Head.SHA)
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const gitlabPerPage = 100
+
+func init() {
+	RegisterDownloaderFactory(&GitlabDownloaderFactory{})
+}
+
+// GitlabDownloaderFactory creates downloaders for repositories on gitlab.com
+type GitlabDownloaderFactory struct{}
+
+// Match returns true if the repository is hosted on gitlab.com
+func (f *GitlabDownloaderFactory) Match(opts MigrateOptions) (bool, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(u.Host, "gitlab.com"), nil
+}
+
+// New returns a downloader for the repository of the migration
+func (f *GitlabDownloaderFactory) New(opts MigrateOptions) (Downloader, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return nil, err
+	}
+	projectPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	return NewGitlabDownloader("https://gitlab.com", opts.AuthPassword, projectPath), nil
+}
+
+// GitlabDownloader reads a repository from the GitLab API v4.
+// GitLab numbers issues and merge requests independently, so the merge
+// requests are numbered after the highest issue number.
+type GitlabDownloader struct {
+	client      *restClient
+	projectPath string
+
+	maxIssueIID int64
+	maxIssueSet bool
+}
+
+// NewGitlabDownloader creates a GitLab downloader, the token is a personal access token.
+func NewGitlabDownloader(baseURL, token, projectPath string) *GitlabDownloader {
+	client := newRestClient(baseURL + "/api/v4")
+	if len(token) > 0 {
+		client.header.Set("PRIVATE-TOKEN", token)
+	}
+	return &GitlabDownloader{
+		client:      client,
+		projectPath: "/projects/" + url.PathEscape(projectPath),
+	}
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitlabProject struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Visibility    string `json:"visibility"`
+	HTTPURLToRepo string `json:"http_url_to_repo"`
+	WebURL        string `json:"web_url"`
+	Namespace     struct {
+		Path string `json:"path"`
+	} `json:"namespace"`
+}
+
+// GetRepoInfo returns the repository information
+func (g *GitlabDownloader) GetRepoInfo() (*Repository, error) {
+	var project gitlabProject
+	if err := g.client.getJSON(g.projectPath, nil, &project); err != nil {
+		return nil, err
+	}
+	return &Repository{
+		Name:        project.Name,
+		Owner:       project.Namespace.Path,
+		IsPrivate:   project.Visibility == "private",
+		Description: project.Description,
+		CloneURL:    project.HTTPURLToRepo,
+		OriginalURL: project.WebURL,
+	}, nil
+}
+
+type gitlabMilestone struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	DueDate     string    `json:"due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GetMilestones returns all milestones
+func (g *GitlabDownloader) GetMilestones() ([]*Milestone, error) {
+	milestones := make([]*Milestone, 0, gitlabPerPage)
+	for page := 1; ; page++ {
+		var ms []gitlabMilestone
+		if err := g.client.getJSON(g.projectPath+"/milestones", pageQuery(page, gitlabPerPage), &ms); err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			milestone := &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				Created:     m.CreatedAt,
+				IsClosed:    m.State == "closed",
+			}
+			if len(m.DueDate) > 0 {
+				if deadline, err := time.Parse("2006-01-02", m.DueDate); err == nil {
+					milestone.Deadline = &deadline
+				}
+			}
+			if milestone.IsClosed {
+				closed := m.UpdatedAt
+				milestone.Closed = &closed
+			}
+			milestones = append(milestones, milestone)
+		}
+		if len(ms) < gitlabPerPage {
+			return milestones, nil
+		}
+	}
+}
+
+type gitlabLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// GetLabels returns all labels
+func (g *GitlabDownloader) GetLabels() ([]*Label, error) {
+	labels := make([]*Label, 0, gitlabPerPage)
+	for page := 1; ; page++ {
+		var ls []gitlabLabel
+		if err := g.client.getJSON(g.projectPath+"/labels", pageQuery(page, gitlabPerPage), &ls); err != nil {
+			return nil, err
+		}
+		for _, l := range ls {
+			labels = append(labels, &Label{
+				Name:  l.Name,
+				Color: strings.TrimPrefix(l.Color, "#"),
+			})
+		}
+		if len(ls) < gitlabPerPage {
+			return labels, nil
+		}
+	}
+}
+
+type gitlabTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID            string    `json:"id"`
+		CommittedDate time.Time `json:"committed_date"`
+	} `json:"commit"`
+	Release *struct {
+		Description string `json:"description"`
+	} `json:"release"`
+}
+
+// GetReleases returns the tags which have release notes
+func (g *GitlabDownloader) GetReleases() ([]*Release, error) {
+	releases := make([]*Release, 0, 10)
+	for page := 1; ; page++ {
+		var tags []gitlabTag
+		if err := g.client.getJSON(g.projectPath+"/repository/tags", pageQuery(page, gitlabPerPage), &tags); err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if tag.Release == nil {
+				continue
+			}
+			releases = append(releases, &Release{
+				TagName:         tag.Name,
+				TargetCommitish: tag.Commit.ID,
+				Name:            tag.Name,
+				Body:            tag.Release.Description,
+				Created:         tag.Commit.CommittedDate,
+			})
+		}
+		if len(tags) < gitlabPerPage {
+			return releases, nil
+		}
+	}
+}
+
+// GetAsset is not supported, GitLab releases have no assets.
+func (g *GitlabDownloader) GetAsset(asset *ReleaseAsset) (io.ReadCloser, error) {
+	return nil, errors.New("GitLab releases have no assets")
+}
+
+type gitlabIssue struct {
+	IID         int64            `json:"iid"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	State       string           `json:"state"`
+	Labels      []string         `json:"labels"`
+	Milestone   *gitlabMilestone `json:"milestone"`
+	Author      gitlabUser       `json:"author"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func convertGitlabLabels(names []string) []*Label {
+	labels := make([]*Label, len(names))
+	for i := range names {
+		labels[i] = &Label{Name: names[i]}
+	}
+	return labels
+}
+
+// getMaxIssueIID returns the highest issue number, which is the offset of the merge request numbers.
+func (g *GitlabDownloader) getMaxIssueIID() (int64, error) {
+	if g.maxIssueSet {
+		return g.maxIssueIID, nil
+	}
+
+	var is []gitlabIssue
+	if err := g.client.getJSON(g.projectPath+"/issues",
+		pageQuery(1, 1, "scope", "all", "order_by", "created_at", "sort", "desc"), &is); err != nil {
+		return 0, err
+	}
+	if len(is) > 0 {
+		g.maxIssueIID = is[0].IID
+	}
+	g.maxIssueSet = true
+	return g.maxIssueIID, nil
+}
+
+// GetIssues returns a page of issues
+func (g *GitlabDownloader) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	var is []gitlabIssue
+	if err := g.client.getJSON(g.projectPath+"/issues",
+		pageQuery(page, perPage, "scope", "all", "order_by", "created_at", "sort", "asc"), &is); err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*Issue, 0, len(is))
+	for _, issue := range is {
+		var milestone string
+		if issue.Milestone != nil {
+			milestone = issue.Milestone.Title
+		}
+		i := &Issue{
+			Number:     issue.IID,
+			PosterID:   issue.Author.ID,
+			PosterName: issue.Author.Username,
+			Title:      issue.Title,
+			Content:    issue.Description,
+			Milestone:  milestone,
+			IsClosed:   issue.State == "closed",
+			Created:    issue.CreatedAt,
+			Updated:    issue.UpdatedAt,
+			Labels:     convertGitlabLabels(issue.Labels),
+		}
+		if i.IsClosed {
+			closed := issue.UpdatedAt
+			i.Closed = &closed
+		}
+		issues = append(issues, i)
+	}
+	return issues, len(is) < perPage, nil
+}
+
+type gitlabNote struct {
+	Body      string     `json:"body"`
+	Author    gitlabUser `json:"author"`
+	CreatedAt time.Time  `json:"created_at"`
+	System    bool       `json:"system"`
+}
+
+// GetComments returns all comments of an issue or merge request, system notes are skipped.
+func (g *GitlabDownloader) GetComments(issueNumber int64) ([]*Comment, error) {
+	maxIssueIID, err := g.getMaxIssueIID()
+	if err != nil {
+		return nil, err
+	}
+
+	notesPath := fmt.Sprintf("%s/issues/%d/notes", g.projectPath, issueNumber)
+	if issueNumber > maxIssueIID {
+		notesPath = fmt.Sprintf("%s/merge_requests/%d/notes", g.projectPath, issueNumber-maxIssueIID)
+	}
+
+	comments := make([]*Comment, 0, 10)
+	for page := 1; ; page++ {
+		var notes []gitlabNote
+		if err := g.client.getJSON(notesPath,
+			pageQuery(page, gitlabPerPage, "order_by", "created_at", "sort", "asc"), &notes); err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			if note.System {
+				continue
+			}
+			comments = append(comments, &Comment{
+				IssueIndex: issueNumber,
+				PosterID:   note.Author.ID,
+				PosterName: note.Author.Username,
+				Content:    note.Body,
+				Created:    note.CreatedAt,
+			})
+		}
+		if len(notes) < gitlabPerPage {
+			return comments, nil
+		}
+	}
+}
+
+type gitlabMergeRequest struct {
+	IID             int64            `json:"iid"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description"`
+	State           string           `json:"state"`
+	Labels          []string         `json:"labels"`
+	Milestone       *gitlabMilestone `json:"milestone"`
+	Author          gitlabUser       `json:"author"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	SHA             string           `json:"sha"`
+	MergeCommitSHA  string           `json:"merge_commit_sha"`
+	SourceBranch    string           `json:"source_branch"`
+	TargetBranch    string           `json:"target_branch"`
+	SourceProjectID int64            `json:"source_project_id"`
+	TargetProjectID int64            `json:"target_project_id"`
+	DiffRefs        struct {
+		BaseSHA string `json:"base_sha"`
+	} `json:"diff_refs"`
+}
+
+// GetPullRequests returns a page of merge requests
+func (g *GitlabDownloader) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	maxIssueIID, err := g.getMaxIssueIID()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mrs []gitlabMergeRequest
+	if err := g.client.getJSON(g.projectPath+"/merge_requests",
+		pageQuery(page, perPage, "state", "all", "order_by", "created_at", "sort", "asc"), &mrs); err != nil {
+		return nil, false, err
+	}
+
+	prs := make([]*PullRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		var milestone string
+		if mr.Milestone != nil {
+			milestone = mr.Milestone.Title
+		}
+		pr := &PullRequest{
+			Number:         mr.IID + maxIssueIID,
+			PosterID:       mr.Author.ID,
+			PosterName:     mr.Author.Username,
+			Title:          mr.Title,
+			Content:        mr.Description,
+			Milestone:      milestone,
+			IsClosed:       mr.State == "closed" || mr.State == "merged",
+			Created:        mr.CreatedAt,
+			Updated:        mr.UpdatedAt,
+			Labels:         convertGitlabLabels(mr.Labels),
+			Merged:         mr.State == "merged",
+			MergeCommitSHA: mr.MergeCommitSHA,
+			Head: PullRequestBranch{
+				Ref: mr.SourceBranch,
+				SHA: mr.SHA,
+			},
+			Base: PullRequestBranch{
+				Ref: mr.TargetBranch,
+				SHA: mr.DiffRefs.BaseSHA,
+			},
+		}
+		// Only the project IDs are known, which is enough to tell forks apart.
+		pr.Head.RepoName = fmt.Sprint(mr.SourceProjectID)
+		pr.Base.RepoName = fmt.Sprint(mr.TargetProjectID)
+		if pr.IsClosed {
+			closed := mr.UpdatedAt
+			pr.Closed = &closed
+		}
+		if pr.Merged {
+			pr.MergedTime = pr.Closed
+		}
+		prs = append(prs, pr)
+	}
+	return prs, len(mrs) < perPage, nil
+}
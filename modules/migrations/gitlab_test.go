@@ -0,0 +1,96 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitlabDownloader(t *testing.T) {
+	server := newFixtureServer("gitlab")
+	defer server.Close()
+
+	downloader := NewGitlabDownloader(server.URL, "", "gitea/test_repo")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, &Repository{
+		Name:        "test_repo",
+		Owner:       "gitea",
+		Description: "Test repository for testing migration from gitlab to gitea",
+		CloneURL:    "https://gitlab.com/gitea/test_repo.git",
+		OriginalURL: "https://gitlab.com/gitea/test_repo",
+	}, repo)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 2) {
+		assert.Equal(t, "1.0.0", milestones[0].Title)
+		assert.True(t, milestones[0].IsClosed)
+		assert.Nil(t, milestones[0].Deadline)
+		assert.Equal(t, "1.1.0", milestones[1].Title)
+		assert.False(t, milestones[1].IsClosed)
+		assert.NotNil(t, milestones[1].Deadline)
+	}
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Label{
+		{Name: "bug", Color: "d9534f"},
+		{Name: "discussion", Color: "428bca"},
+	}, labels)
+
+	// Only tags with release notes are releases.
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	if assert.Len(t, releases, 1) {
+		assert.Equal(t, "v1.1", releases[0].TagName)
+		assert.Equal(t, "First release", releases[0].Body)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", releases[0].TargetCommitish)
+	}
+
+	issues, isEnd, err := downloader.GetIssues(1, 10)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "lafriks", issues[0].PosterName)
+		assert.True(t, issues[0].IsClosed)
+		assert.Equal(t, "1.0.0", issues[0].Milestone)
+		assert.Len(t, issues[0].Labels, 2)
+		assert.EqualValues(t, 2, issues[1].Number)
+		assert.False(t, issues[1].IsClosed)
+	}
+
+	// System notes are skipped.
+	comments, err := downloader.GetComments(1)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, "axifive", comments[0].PosterName)
+		assert.Equal(t, "This is a comment", comments[0].Content)
+	}
+
+	// Merge requests are numbered after the issues.
+	prs, isEnd, err := downloader.GetPullRequests(1, 10)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, prs, 1) {
+		pr := prs[0]
+		assert.EqualValues(t, 3, pr.Number)
+		assert.True(t, pr.Merged)
+		assert.False(t, pr.IsForkPullRequest())
+		assert.Equal(t, "feature", pr.Head.Ref)
+		assert.Equal(t, "master", pr.Base.Ref)
+	}
+
+	comments, err = downloader.GetComments(3)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 3, comments[0].IssueIndex)
+		assert.Equal(t, "LGTM", comments[0].Content)
+	}
+}
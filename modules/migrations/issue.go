@@ -0,0 +1,47 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "time"
+
+// Milestone defines a milestone on the source host
+type Milestone struct {
+	Title       string
+	Description string
+	Deadline    *time.Time
+	Created     time.Time
+	Closed      *time.Time
+	IsClosed    bool
+}
+
+// Label defines a label on the source host
+type Label struct {
+	Name  string
+	Color string // hex color without a leading '#'
+}
+
+// Issue defines an issue on the source host
+type Issue struct {
+	Number     int64
+	PosterID   int64
+	PosterName string
+	Title      string
+	Content    string
+	Milestone  string
+	IsClosed   bool
+	Created    time.Time
+	Updated    time.Time
+	Closed     *time.Time
+	Labels     []*Label
+}
+
+// Comment defines a comment of an issue or pull request on the source host
+type Comment struct {
+	IssueIndex int64
+	PosterID   int64
+	PosterName string
+	Content    string
+	Created    time.Time
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const itemsPerPage = 100
+
+var factories []DownloaderFactory
+
+// RegisterDownloaderFactory registers a downloader factory
+func RegisterDownloaderFactory(factory DownloaderFactory) {
+	factories = append(factories, factory)
+}
+
+// parseOwnerAndRepo returns the owner and repository name of a remote URL like https://host/owner/repo.git
+func parseOwnerAndRepo(remoteURL string) (string, string, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", "", err
+	}
+	fields := strings.Split(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/")
+	if len(fields) != 2 || len(fields[0]) == 0 || len(fields[1]) == 0 {
+		return "", "", fmt.Errorf("invalid repository address: %s", u.Path)
+	}
+	return fields[0], fields[1], nil
+}
+
+// MigrateRepository migrates a repository into the given owner. Besides the git data,
+// the items selected in opts are migrated if a downloader for the source host exists.
+// The returned repository is not nil if it has been created, even if the migration failed.
+func MigrateRepository(doer, owner *models.User, opts MigrateOptions) (*models.Repository, error) {
+	var downloader Downloader
+	if opts.HasItems() && !opts.Mirror {
+		for _, factory := range factories {
+			match, err := factory.Match(opts)
+			if err != nil {
+				return nil, err
+			} else if !match {
+				continue
+			}
+			if downloader, err = factory.New(opts); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if downloader == nil {
+		opts.Milestones = false
+		opts.Labels = false
+		opts.Releases = false
+		opts.Issues = false
+		opts.PullRequests = false
+		downloader = NewPlainGitDownloader(owner.Name, opts.Name, opts.RemoteURL)
+	}
+
+	uploader := NewGiteaLocalUploader(doer, owner)
+	err := migrateRepository(downloader, uploader, opts)
+	return uploader.repo, err
+}
+
+func migrateRepository(downloader Downloader, uploader Uploader, opts MigrateOptions) error {
+	repo, err := downloader.GetRepoInfo()
+	if err != nil {
+		return fmt.Errorf("GetRepoInfo: %v", err)
+	}
+	// The error is returned as is, callers handle errors like an existing repository name.
+	if err = uploader.CreateRepo(repo, opts); err != nil {
+		return err
+	}
+
+	if opts.Milestones {
+		log.Trace("Migrating milestones of %s/%s", repo.Owner, repo.Name)
+		milestones, err := downloader.GetMilestones()
+		if err != nil {
+			return fmt.Errorf("GetMilestones: %v", err)
+		}
+		if err = uploader.CreateMilestones(milestones...); err != nil {
+			return fmt.Errorf("CreateMilestones: %v", err)
+		}
+	}
+
+	if opts.Labels {
+		log.Trace("Migrating labels of %s/%s", repo.Owner, repo.Name)
+		labels, err := downloader.GetLabels()
+		if err != nil {
+			return fmt.Errorf("GetLabels: %v", err)
+		}
+		if err = uploader.CreateLabels(labels...); err != nil {
+			return fmt.Errorf("CreateLabels: %v", err)
+		}
+	}
+
+	if opts.Releases {
+		log.Trace("Migrating releases of %s/%s", repo.Owner, repo.Name)
+		releases, err := downloader.GetReleases()
+		if err != nil {
+			return fmt.Errorf("GetReleases: %v", err)
+		}
+		if err = uploader.CreateReleases(downloader, releases...); err != nil {
+			return fmt.Errorf("CreateReleases: %v", err)
+		}
+	}
+
+	if opts.Issues {
+		log.Trace("Migrating issues of %s/%s", repo.Owner, repo.Name)
+		for page := 1; ; page++ {
+			issues, isEnd, err := downloader.GetIssues(page, itemsPerPage)
+			if err != nil {
+				return fmt.Errorf("GetIssues: %v", err)
+			}
+			if err = uploader.CreateIssues(issues...); err != nil {
+				return fmt.Errorf("CreateIssues: %v", err)
+			}
+			for _, issue := range issues {
+				if err = migrateComments(downloader, uploader, issue.Number); err != nil {
+					return err
+				}
+			}
+			if isEnd {
+				break
+			}
+		}
+	}
+
+	if opts.PullRequests {
+		log.Trace("Migrating pull requests of %s/%s", repo.Owner, repo.Name)
+		for page := 1; ; page++ {
+			prs, isEnd, err := downloader.GetPullRequests(page, itemsPerPage)
+			if err != nil {
+				return fmt.Errorf("GetPullRequests: %v", err)
+			}
+			if err = uploader.CreatePullRequests(prs...); err != nil {
+				return fmt.Errorf("CreatePullRequests: %v", err)
+			}
+			for _, pr := range prs {
+				if err = migrateComments(downloader, uploader, pr.Number); err != nil {
+					return err
+				}
+			}
+			if isEnd {
+				break
+			}
+		}
+	}
+
+	return uploader.Finish()
+}
+
+func migrateComments(downloader Downloader, uploader Uploader, issueNumber int64) error {
+	comments, err := downloader.GetComments(issueNumber)
+	if err != nil {
+		return fmt.Errorf("GetComments [%d]: %v", issueNumber, err)
+	}
+	if err = uploader.CreateComments(comments...); err != nil {
+		return fmt.Errorf("CreateComments [%d]: %v", issueNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOwnerAndRepo(t *testing.T) {
+	owner, repo, err := parseOwnerAndRepo("https://github.com/go-gitea/gitea.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "go-gitea", owner)
+	assert.Equal(t, "gitea", repo)
+
+	_, _, err = parseOwnerAndRepo("https://github.com/go-gitea")
+	assert.Error(t, err)
+}
+
+func TestMigrateRepository(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	attachmentPath, err := ioutil.TempDir("", "attachments")
+	assert.NoError(t, err)
+	defer os.RemoveAll(attachmentPath)
+	oldAttachmentPath := setting.AttachmentPath
+	setting.AttachmentPath = attachmentPath
+	defer func() { setting.AttachmentPath = oldAttachmentPath }()
+
+	server := newFixtureServer("github")
+	defer server.Close()
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	uploader := NewGiteaLocalUploader(doer, owner)
+	assert.NoError(t, migrateRepository(NewGithubDownloaderV3(server.URL, "", "", "go-gitea", "test_repo"), uploader, MigrateOptions{
+		RemoteURL:    models.RepoPath("user2", "repo1"),
+		Name:         "migrated",
+		Milestones:   true,
+		Labels:       true,
+		Releases:     true,
+		Issues:       true,
+		PullRequests: true,
+	}))
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: owner.ID, LowerName: "migrated"}).(*models.Repository)
+	assert.Equal(t, "Test repository for testing migration from github to gitea", repo.Description)
+	assert.EqualValues(t, 2, repo.NumIssues)
+	assert.EqualValues(t, 1, repo.NumClosedIssues)
+	assert.EqualValues(t, 1, repo.NumPulls)
+	assert.EqualValues(t, 1, repo.NumClosedPulls)
+	assert.EqualValues(t, 2, repo.NumMilestones)
+	assert.EqualValues(t, 1, repo.NumClosedMilestones)
+
+	milestone := models.AssertExistsAndLoadBean(t, &models.Milestone{RepoID: repo.ID, Name: "1.0.0"}).(*models.Milestone)
+	assert.EqualValues(t, 2, milestone.NumIssues)
+	assert.EqualValues(t, 2, milestone.NumClosedIssues)
+
+	label := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: repo.ID, Name: "enhancement"}).(*models.Label)
+	assert.Equal(t, "#a2eeef", label.Color)
+	assert.EqualValues(t, 2, label.NumIssues)
+	assert.EqualValues(t, 1, label.NumClosedIssues)
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}).(*models.Issue)
+	assert.Equal(t, doer.ID, issue.PosterID)
+	assert.Equal(t, "guillep2k", issue.OriginalAuthor)
+	assert.EqualValues(t, 18600385, issue.OriginalAuthorID)
+	assert.True(t, issue.IsClosed)
+	assert.EqualValues(t, 2, issue.NumComments)
+	assert.EqualValues(t, 1507636800, issue.CreatedUnix)
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: labelID(t, repo.ID, "bug")})
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, OriginalAuthor: "mrsdizzie", Content: "This is a comment"})
+
+	pull := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 3}).(*models.Issue)
+	assert.True(t, pull.IsPull)
+	assert.EqualValues(t, 1, pull.NumComments)
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{IssueID: pull.ID}).(*models.PullRequest)
+	assert.True(t, pr.HasMerged)
+	assert.Equal(t, "mrsdizzie", pr.HeadUserName)
+	assert.EqualValues(t, 3, pr.Index)
+
+	// The head branch of an open pull request from a fork is created so it
+	// can be tested and merged.
+	created := time.Date(2017, 10, 14, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, uploader.CreatePullRequests(&PullRequest{
+		Number:     4,
+		PosterName: "mrsdizzie",
+		Title:      "Open pull request",
+		Created:    created,
+		Updated:    created,
+		Head:       PullRequestBranch{Ref: "master", SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", OwnerName: "mrsdizzie", RepoName: "test_repo"},
+		Base:       PullRequestBranch{Ref: "master", SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", OwnerName: "go-gitea", RepoName: "test_repo"},
+	}))
+	pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo.ID, Index: 4}).(*models.PullRequest)
+	assert.Equal(t, "mrsdizzie/master", pr.HeadBranch)
+	assert.Equal(t, models.PullRequestStatusChecking, pr.Status)
+	assert.True(t, git.IsBranchExist(repo.RepoPath(), "mrsdizzie/master"))
+
+	// An open pull request without head cannot be merged.
+	assert.NoError(t, uploader.CreatePullRequests(&PullRequest{
+		Number:     5,
+		PosterName: "mrsdizzie",
+		Title:      "Deleted fork",
+		Created:    created,
+		Updated:    created,
+		Base:       PullRequestBranch{Ref: "master", SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", OwnerName: "go-gitea", RepoName: "test_repo"},
+	}))
+	pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo.ID, Index: 5}).(*models.PullRequest)
+	assert.Equal(t, models.PullRequestStatusConflict, pr.Status)
+
+	// The release created for the existing tag is updated with the migrated one.
+	release := models.AssertExistsAndLoadBean(t, &models.Release{RepoID: repo.ID, LowerTagName: "v1.1"}).(*models.Release)
+	assert.Equal(t, "First Release", release.Title)
+	assert.Equal(t, "A test release", release.Note)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", release.Sha1)
+	attach := models.AssertExistsAndLoadBean(t, &models.Attachment{ReleaseID: release.ID}).(*models.Attachment)
+	assert.Equal(t, "test_repo-v1.1.zip", attach.Name)
+	assert.EqualValues(t, 42, attach.DownloadCount)
+	data, err := ioutil.ReadFile(attach.LocalPath())
+	assert.NoError(t, err)
+	assert.Equal(t, "release asset", string(data))
+}
+
+func labelID(t *testing.T, repoID int64, name string) int64 {
+	return models.AssertExistsAndLoadBean(t, &models.Label{RepoID: repoID, Name: name}).(*models.Label).ID
+}
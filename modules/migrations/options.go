@@ -0,0 +1,29 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+// MigrateOptions defines the way a repository gets migrated
+type MigrateOptions struct {
+	// RemoteURL is the address the repository is cloned from, including any credentials.
+	RemoteURL    string
+	AuthUsername string
+	AuthPassword string
+
+	Name        string
+	Description string
+	Private     bool
+	Mirror      bool
+
+	Milestones   bool
+	Labels       bool
+	Releases     bool
+	Issues       bool
+	PullRequests bool
+}
+
+// HasItems returns true if anything else than the git data should be migrated.
+func (opts MigrateOptions) HasItems() bool {
+	return opts.Milestones || opts.Labels || opts.Releases || opts.Issues || opts.PullRequests
+}
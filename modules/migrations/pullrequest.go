@@ -0,0 +1,40 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "time"
+
+// PullRequest defines a pull request on the source host
+type PullRequest struct {
+	Number         int64
+	PosterID       int64
+	PosterName     string
+	Title          string
+	Content        string
+	Milestone      string
+	IsClosed       bool
+	Created        time.Time
+	Updated        time.Time
+	Closed         *time.Time
+	Labels         []*Label
+	Merged         bool
+	MergedTime     *time.Time
+	MergeCommitSHA string
+	Head           PullRequestBranch
+	Base           PullRequestBranch
+}
+
+// PullRequestBranch defines the head or base of a pull request
+type PullRequestBranch struct {
+	Ref       string
+	SHA       string
+	OwnerName string
+	RepoName  string
+}
+
+// IsForkPullRequest returns true if the head of the pull request is not in the base repository.
+func (pr *PullRequest) IsForkPullRequest() bool {
+	return pr.Head.OwnerName != pr.Base.OwnerName || pr.Head.RepoName != pr.Base.RepoName
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "time"
+
+// Release defines a release on the source host
+type Release struct {
+	TagName         string
+	TargetCommitish string
+	Name            string
+	Body            string
+	Draft           bool
+	Prerelease      bool
+	PublisherID     int64
+	PublisherName   string
+	Assets          []*ReleaseAsset
+	Created         time.Time
+}
+
+// ReleaseAsset defines a file attached to a release on the source host
+type ReleaseAsset struct {
+	Name          string
+	DownloadURL   string
+	DownloadCount int64
+	Created       time.Time
+}
@@ -0,0 +1,15 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+// Repository defines the information of a repository on the source host
+type Repository struct {
+	Name        string
+	Owner       string
+	IsPrivate   bool
+	Description string
+	CloneURL    string
+	OriginalURL string
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// newFixtureServer serves the API responses stored below testdata/<host>.
+// The file of a request is named after its path, with "_desc" appended for
+// descending lists and "_page<N>" for pages after the first one.
+func newFixtureServer(host string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Replace(strings.Trim(r.URL.EscapedPath(), "/"), "%2F", "_", -1)
+		query := r.URL.Query()
+		if query.Get("sort") == "desc" {
+			name += "_desc"
+		}
+		page := query.Get("page")
+		if len(page) > 0 && page != "1" {
+			name += "_page" + page
+		}
+
+		fixturePath := filepath.Join("testdata", host, filepath.FromSlash(name))
+		data, err := ioutil.ReadFile(fixturePath + ".json")
+		if os.IsNotExist(err) {
+			data, err = ioutil.ReadFile(fixturePath)
+		}
+		if os.IsNotExist(err) && len(page) > 0 && page != "1" {
+			data, err = []byte("[]"), nil
+		}
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(strings.Replace(string(data), "{{SERVER}}", server.URL, -1)))
+	}))
+	return server
+}
@@ -0,0 +1,19 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+// Uploader writes the information read by a Downloader to the target repository.
+// CreateRepo must be called first, comments can only be created for issues and
+// pull requests which have already been created.
+type Uploader interface {
+	CreateRepo(repo *Repository, opts MigrateOptions) error
+	CreateMilestones(milestones ...*Milestone) error
+	CreateLabels(labels ...*Label) error
+	CreateReleases(downloader Downloader, releases ...*Release) error
+	CreateIssues(issues ...*Issue) error
+	CreateComments(comments ...*Comment) error
+	CreatePullRequests(prs ...*PullRequest) error
+	Finish() error
+}
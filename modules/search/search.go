@@ -16,10 +16,17 @@ import (
 
 // Result a search result to display
 type Result struct {
+	RepoID         int64
 	Filename       string
+	Language       string
 	HighlightClass string
 	LineNumbers    []int
 	FormattedLines gotemplate.HTML
+	// Snippet the unformatted lines around the keyword, MatchStart and
+	// MatchEnd are the byte offsets of the keyword within the snippet
+	Snippet    string
+	MatchStart int
+	MatchEnd   int
 }
 
 func indices(content string, selectionStartIndex, selectionEndIndex int) (int, int) {
@@ -97,20 +104,35 @@ func searchResult(result *indexer.RepoSearchResult, startIndex, endIndex int) (*
 		index += len(line)
 	}
 	return &Result{
+		RepoID:         result.RepoID,
 		Filename:       result.Filename,
+		Language:       result.Language,
 		HighlightClass: highlight.FileNameToHighlightClass(result.Filename),
 		LineNumbers:    lineNumbers,
 		FormattedLines: gotemplate.HTML(formattedLinesBuffer.String()),
+		Snippet:        result.Content[startIndex:endIndex],
+		MatchStart:     util.Max(result.StartIndex-startIndex, 0),
+		MatchEnd:       util.Max(result.EndIndex-startIndex, 0),
 	}, nil
 }
 
 // PerformSearch perform a search on a repository
 func PerformSearch(repoID int64, keyword string, page, pageSize int) (int, []*Result, error) {
-	if len(keyword) == 0 {
+	return PerformGlobalSearch(&indexer.RepoSearchOptions{
+		RepoIDs:  []int64{repoID},
+		Keyword:  keyword,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// PerformGlobalSearch perform a search on the given repositories
+func PerformGlobalSearch(opts *indexer.RepoSearchOptions) (int, []*Result, error) {
+	if len(opts.Keyword) == 0 {
 		return 0, nil, nil
 	}
 
-	total, results, err := indexer.SearchRepoByKeyword(repoID, keyword, page, pageSize)
+	total, results, err := indexer.SearchRepos(opts)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -61,6 +61,12 @@ const (
 	LandingPageOrganizations LandingPage = "/explore/organizations"
 )
 
+// enumerates all the indexer backend types
+const (
+	IndexerTypeBleve         = "bleve"
+	IndexerTypeElasticsearch = "elasticsearch"
+)
+
 // MarkupParser defines the external parser configured in ini
 type MarkupParser struct {
 	Enabled        bool
@@ -154,26 +160,41 @@ var (
 	UseTiDB       bool
 
 	// Indexer settings
-	Indexer struct {
+	Indexer = struct {
+		IssueType          string
 		IssuePath          string
+		IssueConnStr       string
+		IssueIndexerName   string
 		RepoIndexerEnabled bool
+		RepoType           string
 		RepoPath           string
+		RepoConnStr        string
+		RepoIndexerName    string
 		UpdateQueueLength  int
 		MaxIndexerFileSize int64
+	}{
+		IssueType: IndexerTypeBleve,
+		RepoType:  IndexerTypeBleve,
 	}
 
 	// Webhook settings
 	Webhook = struct {
-		QueueLength    int
-		DeliverTimeout int
-		SkipTLSVerify  bool
-		Types          []string
-		PagingNum      int
+		QueueLength         int
+		DeliverTimeout      int
+		SkipTLSVerify       bool
+		Types               []string
+		PagingNum           int
+		MaxAttempts         int
+		RetryBackoff        int
+		SendSecretInPayload bool
 	}{
-		QueueLength:    1000,
-		DeliverTimeout: 5,
-		SkipTLSVerify:  false,
-		PagingNum:      10,
+		QueueLength:         1000,
+		DeliverTimeout:      5,
+		SkipTLSVerify:       false,
+		PagingNum:           10,
+		MaxAttempts:         5,
+		RetryBackoff:        60,
+		SendSecretInPayload: false,
 	}
 
 	// Repository settings
@@ -498,9 +519,24 @@ var (
 
 	// API settings
 	API = struct {
-		MaxResponseItems int
+		MaxResponseItems       int
+		DefaultGitTreesPerPage int
 	}{
-		MaxResponseItems: 50,
+		MaxResponseItems:       50,
+		DefaultGitTreesPerPage: 1000,
+	}
+
+	// OAuth2 settings of Gitea as an OAuth2 provider
+	OAuth2 = struct {
+		Enable                     bool
+		AccessTokenExpirationTime  int64
+		RefreshTokenExpirationTime int64
+		JWTSigningPrivateKeyFile   string `ini:"JWT_SIGNING_PRIVATE_KEY_FILE"`
+	}{
+		Enable:                     true,
+		AccessTokenExpirationTime:  3600,
+		RefreshTokenExpirationTime: 730,
+		JWTSigningPrivateKeyFile:   "oauth2/jwt/private.pem",
 	}
 
 	// I18n settings
@@ -1054,6 +1090,11 @@ func NewContext() {
 		log.Fatal(4, "Failed to map Git settings: %v", err)
 	} else if err = Cfg.Section("api").MapTo(&API); err != nil {
 		log.Fatal(4, "Failed to map API settings: %v", err)
+	} else if err = Cfg.Section("oauth2").MapTo(&OAuth2); err != nil {
+		log.Fatal(4, "Failed to map OAuth2 settings: %v", err)
+	}
+	if !filepath.IsAbs(OAuth2.JWTSigningPrivateKeyFile) {
+		OAuth2.JWTSigningPrivateKeyFile = filepath.Join(AppDataPath, OAuth2.JWTSigningPrivateKeyFile)
 	}
 
 	sec = Cfg.Section("mirror")
@@ -1141,6 +1182,8 @@ var Service struct {
 	DefaultAllowCreateOrganization          bool
 	DefaultEnableTimetracking               bool
 	DefaultAllowOnlyContributorsToTrackTime bool
+	DefaultEnableDependencies               bool
+	AllowCrossRepositoryDependencies        bool
 	NoReplyAddress                          string
 
 	// OpenID settings
@@ -1164,6 +1207,8 @@ func newService() {
 	Service.DefaultAllowCreateOrganization = sec.Key("DEFAULT_ALLOW_CREATE_ORGANIZATION").MustBool(true)
 	Service.DefaultEnableTimetracking = sec.Key("DEFAULT_ENABLE_TIMETRACKING").MustBool(true)
 	Service.DefaultAllowOnlyContributorsToTrackTime = sec.Key("DEFAULT_ALLOW_ONLY_CONTRIBUTORS_TO_TRACK_TIME").MustBool(true)
+	Service.DefaultEnableDependencies = sec.Key("DEFAULT_ENABLE_DEPENDENCIES").MustBool(true)
+	Service.AllowCrossRepositoryDependencies = sec.Key("ALLOW_CROSS_REPOSITORY_DEPENDENCIES").MustBool(true)
 	Service.NoReplyAddress = sec.Key("NO_REPLY_ADDRESS").MustString("noreply.example.org")
 
 	sec = Cfg.Section("openid")
@@ -1512,6 +1557,9 @@ func newWebhookService() {
 	Webhook.SkipTLSVerify = sec.Key("SKIP_TLS_VERIFY").MustBool()
 	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk"}
 	Webhook.PagingNum = sec.Key("PAGING_NUM").MustInt(10)
+	Webhook.MaxAttempts = sec.Key("MAX_ATTEMPTS").MustInt(5)
+	Webhook.RetryBackoff = sec.Key("RETRY_BACKOFF").MustInt(60)
+	Webhook.SendSecretInPayload = sec.Key("SEND_SECRET_IN_PAYLOAD").MustBool()
 }
 
 // NewServices initializes the services
@@ -448,6 +448,29 @@ function initRepository() {
         });
     }
 
+    // Topics
+    $('#manage_topic').click(function (e) {
+        e.preventDefault();
+        $('#repo-topic').hide();
+        $('#topic_edit').show();
+        $('#topic_edit input[name=topics]').focus();
+    });
+
+    // New repository
+    if ($('.repository.new.repo').length > 0) {
+        $('#repo_template_search').dropdown({
+            onChange: function (value) {
+                if (value) {
+                    $('#template_units').show();
+                    $('#non_template').hide();
+                } else {
+                    $('#template_units').hide();
+                    $('#non_template').show();
+                }
+            }
+        });
+    }
+
     // Milestones
     if ($('.repository.milestones').length > 0) {
 
@@ -608,6 +631,29 @@ function initRepository() {
                 $item.find(".bar .add").css("width", addPercent + "%");
             });
         }
+
+        // Code comments
+        $('.add-code-comment').click(function () {
+            var $this = $(this);
+            var $row = $this.closest('tr');
+            if ($row.next().hasClass('add-comment')) {
+                $row.next().find('textarea').focus();
+                return false;
+            }
+
+            var $form = $('#code-comment-form-template').children('form').clone();
+            $form.find('input[name="tree_path"]').val($this.data('path'));
+            $form.find('input[name="side"]').val($this.data('side'));
+            $form.find('input[name="line"]').val($this.data('idx'));
+            $form.find('.cancel-code-comment').click(function () {
+                $(this).closest('tr').remove();
+            });
+
+            var $cell = $('<td>').attr('colspan', $row.children('td').length).append($form);
+            $('<tr class="add-comment">').append($cell).insertAfter($row);
+            $form.find('textarea').focus();
+            return false;
+        });
     }
 
     // Quick start and repository home
@@ -673,6 +719,65 @@ function initTeamSettings() {
     });
 }
 
+function initProjectBoard() {
+    var $board = $('.repository.project .project-board[data-url]');
+    if ($board.length === 0) {
+        return;
+    }
+
+    var $dragged = null;
+    $board.find('.project-card').on('dragstart', function (e) {
+        $dragged = $(this);
+        $dragged.addClass('dragging');
+        // Firefox doesn't start dragging without data.
+        e.originalEvent.dataTransfer.setData('text/plain', $dragged.data('issue'));
+        e.originalEvent.dataTransfer.effectAllowed = 'move';
+    }).on('dragend', function () {
+        $(this).removeClass('dragging');
+        $dragged = null;
+    });
+
+    $board.find('.project-column').on('dragover', function (e) {
+        if (!$dragged) {
+            return;
+        }
+        e.preventDefault();
+
+        // Place the card before the first card whose middle is below the pointer.
+        var $cards = $(this).find('.project-cards');
+        var $before = null;
+        $cards.children('.project-card').not($dragged).each(function () {
+            var $card = $(this);
+            if (e.originalEvent.clientY < this.getBoundingClientRect().top + $card.outerHeight() / 2) {
+                $before = $card;
+                return false;
+            }
+        });
+        if ($before) {
+            $dragged.insertBefore($before);
+        } else {
+            $cards.append($dragged);
+        }
+    }).on('drop', function (e) {
+        if (!$dragged) {
+            return;
+        }
+        e.preventDefault();
+
+        $board.find('.project-column').each(function () {
+            $(this).find('.project-column-header .label').text($(this).find('.project-card').length);
+        });
+        $.post($board.data('url'), {
+            "_csrf": csrf,
+            "issue": $dragged.data('issue'),
+            "column": $(this).data('column'),
+            "sorting": $dragged.index()
+        }).fail(function () {
+            window.location.reload();
+        });
+    });
+}
+
 function initWikiForm() {
     var $editArea = $('.repository.wiki textarea#edit_area');
     if ($editArea.length > 0) {
@@ -1511,6 +1616,7 @@ $(document).ready(function () {
     initCodeView();
     initVueApp();
     initTeamSettings();
+    initProjectBoard();
     initCtrlEnterSubmit();
 
     // Repo clone url.
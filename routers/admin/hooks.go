@@ -0,0 +1,45 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplAdminHooks base.TplName = "admin/hooks"
+)
+
+// SystemHooks show the system webhooks, which are triggered for all repositories
+func SystemHooks(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.hooks")
+	ctx.Data["BaseLink"] = setting.AppSubURL + "/admin/hooks"
+	ctx.Data["Description"] = ctx.Tr("admin.hooks.desc", "https://docs.gitea.io/en-us/webhooks/")
+
+	ws, err := models.GetSystemWebhooks()
+	if err != nil {
+		ctx.Handle(500, "GetSystemWebhooks", err)
+		return
+	}
+
+	ctx.Data["Webhooks"] = ws
+	ctx.HTML(200, tplAdminHooks)
+}
+
+// DeleteSystemHook response for deleting a system webhook
+func DeleteSystemHook(ctx *context.Context) {
+	if err := models.DeleteSystemWebhook(ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteSystemWebhook: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/admin/hooks",
+	})
+}
@@ -45,6 +45,7 @@
 package v1
 
 import (
+	"fmt"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -128,6 +129,44 @@ func repoAssignment() macaron.Handler {
 	}
 }
 
+// tokenHasScope returns false if the request is authenticated by an access
+// token which does not have the scope
+func tokenHasScope(ctx *context.Context, scope models.AccessTokenScope) bool {
+	tokenScope, ok := ctx.Data["AccessTokenScope"].(models.AccessTokenScope)
+	return !ok || tokenScope.Has(scope)
+}
+
+// reqTokenScope requires the access token of the request, if any, to have
+// readScope for reading requests and writeScope for all other requests
+func reqTokenScope(readScope, writeScope models.AccessTokenScope) macaron.Handler {
+	return func(ctx *context.APIContext) {
+		scope := writeScope
+		if ctx.Req.Method == "GET" || ctx.Req.Method == "HEAD" {
+			scope = readScope
+		}
+		if !tokenHasScope(ctx.Context, scope) {
+			ctx.Error(403, "", fmt.Sprintf("Token does not have the required scope: %s", scope))
+			return
+		}
+	}
+}
+
+func reqRepoScope() macaron.Handler {
+	return reqTokenScope(models.AccessTokenScopeRepoRead, models.AccessTokenScopeRepo)
+}
+
+func reqUserScope() macaron.Handler {
+	return reqTokenScope(models.AccessTokenScopeUser, models.AccessTokenScopeUser)
+}
+
+func reqOrgScope() macaron.Handler {
+	return reqTokenScope(models.AccessTokenScopeOrg, models.AccessTokenScopeOrg)
+}
+
+func reqNotificationScope() macaron.Handler {
+	return reqTokenScope(models.AccessTokenScopeNotification, models.AccessTokenScopeNotification)
+}
+
 // Contexter middleware already checks token for user sign in process.
 func reqToken() macaron.Handler {
 	return func(ctx *context.Context) {
@@ -149,7 +188,7 @@ func reqBasicAuth() macaron.Handler {
 
 func reqAdmin() macaron.Handler {
 	return func(ctx *context.Context) {
-		if !ctx.IsSigned || !ctx.User.IsAdmin {
+		if !ctx.IsSigned || !ctx.User.IsAdmin || !tokenHasScope(ctx, models.AccessTokenScopeAdmin) {
 			ctx.Error(403)
 			return
 		}
@@ -158,13 +197,31 @@ func reqAdmin() macaron.Handler {
 
 func reqRepoWriter() macaron.Handler {
 	return func(ctx *context.Context) {
-		if !ctx.Repo.IsWriter() {
+		if !ctx.Repo.IsWriter() || !tokenHasScope(ctx, models.AccessTokenScopeRepo) {
+			ctx.Error(403)
+			return
+		}
+	}
+}
+
+func reqRepoAdmin() macaron.Handler {
+	return func(ctx *context.Context) {
+		if !ctx.Repo.IsAdmin() || !tokenHasScope(ctx, models.AccessTokenScopeRepo) {
 			ctx.Error(403)
 			return
 		}
 	}
 }
 
+func reqRepoNotArchived() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.Repo.Repository.IsArchived {
+			ctx.Error(403, "RepoArchived", "repository is archived")
+			return
+		}
+	}
+}
+
 func reqOrgMembership() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		var orgID int64
@@ -177,7 +234,10 @@ func reqOrgMembership() macaron.Handler {
 			return
 		}
 
-		if !models.IsOrganizationMember(orgID, ctx.User.ID) {
+		if !tokenHasScope(ctx.Context, models.AccessTokenScopeOrg) {
+			ctx.Error(403, "", "Token does not have the required scope: org")
+			return
+		} else if !models.IsOrganizationMember(orgID, ctx.User.ID) {
 			if ctx.Org.Organization != nil {
 				ctx.Error(403, "", "Must be an organization member")
 			} else {
@@ -200,7 +260,10 @@ func reqOrgOwnership() macaron.Handler {
 			return
 		}
 
-		if !models.IsOrganizationOwner(orgID, ctx.User.ID) {
+		if !tokenHasScope(ctx.Context, models.AccessTokenScopeOrg) {
+			ctx.Error(403, "", "Token does not have the required scope: org")
+			return
+		} else if !models.IsOrganizationOwner(orgID, ctx.User.ID) {
 			if ctx.Org.Organization != nil {
 				ctx.Error(403, "", "Must be an organization owner")
 			} else {
@@ -259,6 +322,13 @@ func mustEnableIssues(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableProjects(ctx *context.APIContext) {
+	if !ctx.Repo.Repository.UnitEnabled(models.UnitTypeProjects) {
+		ctx.Status(404)
+		return
+	}
+}
+
 func mustAllowPulls(ctx *context.Context) {
 	if !ctx.Repo.Repository.AllowsPulls() {
 		ctx.Status(404)
@@ -287,7 +357,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Group("/:username", func() {
 				m.Get("", user.GetInfo)
 
-				m.Get("/repos", user.ListUserRepos)
+				m.Get("/repos", reqRepoScope(), user.ListUserRepos)
 				m.Group("/tokens", func() {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
@@ -297,71 +367,95 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 		m.Group("/users", func() {
 			m.Group("/:username", func() {
-				m.Get("/keys", user.ListPublicKeys)
-				m.Get("/gpg_keys", user.ListGPGKeys)
-
-				m.Get("/followers", user.ListFollowers)
-				m.Group("/following", func() {
-					m.Get("", user.ListFollowing)
-					m.Get("/:target", user.CheckFollowing)
-				})
+				m.Group("", func() {
+					m.Get("/keys", user.ListPublicKeys)
+					m.Get("/gpg_keys", user.ListGPGKeys)
+
+					m.Get("/followers", user.ListFollowers)
+					m.Group("/following", func() {
+						m.Get("", user.ListFollowing)
+						m.Get("/:target", user.CheckFollowing)
+					})
+				}, reqUserScope())
 
-				m.Get("/starred", user.GetStarredRepos)
+				m.Group("", func() {
+					m.Get("/starred", user.GetStarredRepos)
 
-				m.Get("/subscriptions", user.GetWatchedRepos)
+					m.Get("/subscriptions", user.GetWatchedRepos)
+				}, reqRepoScope())
 			})
 		}, reqToken())
 
 		m.Group("/user", func() {
-			m.Get("", user.GetAuthenticatedUser)
-			m.Combo("/emails").Get(user.ListEmails).
-				Post(bind(api.CreateEmailOption{}), user.AddEmail).
-				Delete(bind(api.DeleteEmailOption{}), user.DeleteEmail)
-
-			m.Get("/followers", user.ListMyFollowers)
-			m.Group("/following", func() {
-				m.Get("", user.ListMyFollowing)
-				m.Combo("/:username").Get(user.CheckMyFollowing).Put(user.Follow).Delete(user.Unfollow)
-			})
-
-			m.Group("/keys", func() {
-				m.Combo("").Get(user.ListMyPublicKeys).
-					Post(bind(api.CreateKeyOption{}), user.CreatePublicKey)
-				m.Combo("/:id").Get(user.GetPublicKey).
-					Delete(user.DeletePublicKey)
-			})
+			m.Group("", func() {
+				m.Get("", user.GetAuthenticatedUser)
+				m.Combo("/emails").Get(user.ListEmails).
+					Post(bind(api.CreateEmailOption{}), user.AddEmail).
+					Delete(bind(api.DeleteEmailOption{}), user.DeleteEmail)
 
-			m.Group("/gpg_keys", func() {
-				m.Combo("").Get(user.ListMyGPGKeys).
-					Post(bind(api.CreateGPGKeyOption{}), user.CreateGPGKey)
-				m.Combo("/:id").Get(user.GetGPGKey).
-					Delete(user.DeleteGPGKey)
-			})
+				m.Get("/followers", user.ListMyFollowers)
+				m.Group("/following", func() {
+					m.Get("", user.ListMyFollowing)
+					m.Combo("/:username").Get(user.CheckMyFollowing).Put(user.Follow).Delete(user.Unfollow)
+				})
 
-			m.Combo("/repos").Get(user.ListMyRepos).
-				Post(bind(api.CreateRepoOption{}), repo.Create)
+				m.Group("/keys", func() {
+					m.Combo("").Get(user.ListMyPublicKeys).
+						Post(bind(api.CreateKeyOption{}), user.CreatePublicKey)
+					m.Combo("/:id").Get(user.GetPublicKey).
+						Delete(user.DeletePublicKey)
+				})
 
-			m.Group("/starred", func() {
-				m.Get("", user.GetMyStarredRepos)
-				m.Group("/:username/:reponame", func() {
-					m.Get("", user.IsStarring)
-					m.Put("", user.Star)
-					m.Delete("", user.Unstar)
-				}, repoAssignment())
-			})
-			m.Get("/times", repo.ListMyTrackedTimes)
+				m.Group("/gpg_keys", func() {
+					m.Combo("").Get(user.ListMyGPGKeys).
+						Post(bind(api.CreateGPGKeyOption{}), user.CreateGPGKey)
+					m.Combo("/:id").Get(user.GetGPGKey).
+						Delete(user.DeleteGPGKey)
+				})
+			}, reqUserScope())
+
+			m.Group("", func() {
+				m.Combo("/repos").Get(user.ListMyRepos).
+					Post(bind(api.CreateRepoOption{}), repo.Create)
+
+				m.Group("/starred", func() {
+					m.Get("", user.GetMyStarredRepos)
+					m.Group("/:username/:reponame", func() {
+						m.Get("", user.IsStarring)
+						m.Put("", user.Star)
+						m.Delete("", user.Unstar)
+					}, repoAssignment())
+				})
+				m.Get("/times", repo.ListMyTrackedTimes)
 
-			m.Get("/subscriptions", user.GetMyWatchedRepos)
+				m.Get("/subscriptions", user.GetMyWatchedRepos)
+			}, reqRepoScope())
 		}, reqToken())
 
+		// Notifications
+		m.Group("/notifications", func() {
+			m.Combo("").Get(user.ListNotifications).
+				Put(user.ReadNotifications)
+			m.Get("/new", user.CheckNotifications)
+			m.Combo("/threads/:id").Get(user.GetNotificationThread).
+				Patch(user.SetNotificationThreadStatus)
+		}, reqToken(), reqNotificationScope())
+		m.Combo("/repos/:username/:reponame/notifications", reqToken(), reqNotificationScope(), repoAssignment()).
+			Get(user.ListRepoNotifications).
+			Put(user.ReadRepoNotifications)
+
 		// Repositories
-		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+		m.Post("/org/:org/repos", reqToken(), reqRepoScope(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
-		})
+			m.Get("/issues/search", repo.SearchIssues)
+			m.Get("/code/search", repo.SearchCode)
+		}, reqRepoScope())
+
+		m.Get("/topics/search", repo.TopicSearch)
 
-		m.Combo("/repositories/:id", reqToken()).Get(repo.GetByID)
+		m.Combo("/repositories/:id", reqToken(), reqRepoScope()).Get(repo.GetByID)
 
 		m.Group("/repos", func() {
 			m.Post("/migrate", reqToken(), bind(auth.MigrateRepoForm{}), repo.Migrate)
@@ -374,6 +468,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("/:id").Get(repo.GetHook).
 						Patch(bind(api.EditHookOption{}), repo.EditHook).
 						Delete(repo.DeleteHook)
+					m.Post("/:id/deliveries/:uuid/redeliver", repo.RedeliverHook)
 				}, reqToken(), reqRepoWriter())
 				m.Group("/collaborators", func() {
 					m.Get("", repo.ListCollaborators)
@@ -382,13 +477,55 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(repo.DeleteCollaborator)
 				}, reqToken())
 				m.Get("/raw/*", context.RepoRefByType(context.RepoRefAny), repo.GetRawFile)
+				m.Group("/contents", func() {
+					m.Get("", repo.GetContents)
+					m.Get("/*", repo.GetContents)
+					m.Group("/*", func() {
+						m.Post("", bind(api.CreateFileOptions{}), repo.CreateFile)
+						m.Put("", bind(api.UpdateFileOptions{}), repo.UpdateFile)
+						m.Delete("", bind(api.DeleteFileOptions{}), repo.DeleteFile)
+					}, reqToken(), reqRepoNotArchived(), reqRepoWriter())
+				}, context.ReferencesGitRepo())
+				m.Get("/blame/*", context.ReferencesGitRepo(), repo.GetBlame)
 				m.Get("/archive/*", repo.GetArchive)
+				m.Group("/git", func() {
+					m.Get("/refs", repo.ListAllGitRefs)
+					m.Get("/refs/*", repo.ListGitRefs)
+					m.Get("/trees/:sha", repo.GetTree)
+					m.Get("/blobs/:sha", repo.GetBlob)
+					m.Get("/commits/:sha", repo.GetGitCommit)
+					m.Get("/tags/:sha", repo.GetAnnotatedTag)
+				}, context.ReferencesGitRepo())
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Post("/generate", reqToken(), bind(api.GenerateRepoOption{}), repo.Generate)
+				m.Group("/topics", func() {
+					m.Combo("").Get(repo.ListTopics).
+						Put(reqToken(), reqRepoAdmin(), bind(api.RepoTopicOptions{}), repo.UpdateTopics)
+					m.Combo("/:topic", reqToken(), reqRepoAdmin()).
+						Put(repo.AddTopic).
+						Delete(repo.DeleteTopic)
+				})
 				m.Group("/branches", func() {
-					m.Get("", repo.ListBranches)
-					m.Get("/*", context.RepoRefByType(context.RepoRefBranch), repo.GetBranch)
+					m.Combo("").Get(repo.ListBranches).
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), context.ReferencesGitRepo(),
+							bind(api.CreateBranchOption{}), repo.CreateBranch)
+					m.Combo("/*").Get(context.RepoRefByType(context.RepoRefBranch), repo.GetBranch).
+						Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteBranch)
 				})
+				m.Group("/branch_protections", func() {
+					m.Combo("").Get(repo.ListBranchProtections).
+						Post(bind(api.CreateBranchProtectionOption{}), repo.CreateBranchProtection)
+					m.Combo("/*").Get(repo.GetBranchProtection).
+						Patch(bind(api.EditBranchProtectionOption{}), repo.EditBranchProtection).
+						Delete(repo.DeleteBranchProtection)
+				}, reqToken(), reqRepoAdmin(), context.ReferencesGitRepo())
+				m.Group("/tags", func() {
+					m.Combo("").Get(repo.ListTags).
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreateTagOption{}), repo.CreateTag)
+					m.Combo("/*").Get(repo.GetTag).
+						Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteTag)
+				}, context.ReferencesGitRepo())
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
@@ -402,52 +539,76 @@ func RegisterRoutes(m *macaron.Macaron) {
 				}, mustEnableIssues)
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
-						Post(reqToken(), bind(api.CreateIssueOption{}), repo.CreateIssue)
+						Post(reqToken(), reqRepoNotArchived(), bind(api.CreateIssueOption{}), repo.CreateIssue)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
-						m.Combo("/:id", reqToken()).
+						m.Combo("/:id", reqToken(), reqRepoNotArchived()).
 							Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
 							Delete(repo.DeleteIssueComment)
+						m.Combo("/:id/reactions").Get(repo.ListIssueCommentReactions).
+							Post(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.PostIssueCommentReaction).
+							Delete(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.DeleteIssueCommentReaction)
 					})
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetIssue).
-							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
+							Patch(reqToken(), reqRepoNotArchived(), bind(api.EditIssueOption{}), repo.EditIssue)
 
 						m.Group("/comments", func() {
 							m.Combo("").Get(repo.ListIssueComments).
-								Post(reqToken(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
-							m.Combo("/:id", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
+								Post(reqToken(), reqRepoNotArchived(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
+							m.Combo("/:id", reqToken(), reqRepoNotArchived()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
 
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
-								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
-								Put(reqToken(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
-								Delete(reqToken(), repo.ClearIssueLabels)
-							m.Delete("/:id", reqToken(), repo.DeleteIssueLabel)
+								Post(reqToken(), reqRepoNotArchived(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
+								Put(reqToken(), reqRepoNotArchived(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
+								Delete(reqToken(), reqRepoNotArchived(), repo.ClearIssueLabels)
+							m.Delete("/:id", reqToken(), reqRepoNotArchived(), repo.DeleteIssueLabel)
 						})
 
 						m.Group("/times", func() {
 							m.Combo("").Get(repo.ListTrackedTimes).
-								Post(reqToken(), bind(api.AddTimeOption{}), repo.AddTime)
+								Post(reqToken(), reqRepoNotArchived(), bind(api.AddTimeOption{}), repo.AddTime)
 						})
+
+						m.Combo("/reactions").Get(repo.ListIssueReactions).
+							Post(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
+							Delete(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
 					})
 				}, mustEnableIssues)
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
-						Post(reqToken(), bind(api.CreateLabelOption{}), repo.CreateLabel)
+						Post(reqToken(), reqRepoNotArchived(), bind(api.CreateLabelOption{}), repo.CreateLabel)
 					m.Combo("/:id").Get(repo.GetLabel).
-						Patch(reqToken(), bind(api.EditLabelOption{}), repo.EditLabel).
-						Delete(reqToken(), repo.DeleteLabel)
+						Patch(reqToken(), reqRepoNotArchived(), bind(api.EditLabelOption{}), repo.EditLabel).
+						Delete(reqToken(), reqRepoNotArchived(), repo.DeleteLabel)
 				})
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
+						Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
 					m.Combo("/:id").Get(repo.GetMilestone).
-						Patch(reqToken(), reqRepoWriter(), bind(api.EditMilestoneOption{}), repo.EditMilestone).
-						Delete(reqToken(), reqRepoWriter(), repo.DeleteMilestone)
+						Patch(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.EditMilestoneOption{}), repo.EditMilestone).
+						Delete(reqToken(), reqRepoNotArchived(), reqRepoWriter(), repo.DeleteMilestone)
 				})
+				m.Group("/projects", func() {
+					m.Combo("").Get(repo.ListProjects).
+						Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.CreateProjectOption{}), repo.CreateProject)
+					m.Group("/:id", func() {
+						m.Combo("").Get(repo.GetProject).
+							Patch(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.EditProjectOption{}), repo.EditProject).
+							Delete(reqToken(), reqRepoNotArchived(), reqRepoWriter(), repo.DeleteProject)
+						m.Combo("/columns").Get(repo.ListProjectColumns).
+							Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.CreateProjectColumnOption{}), repo.CreateProjectColumn)
+						m.Combo("/columns/:column").
+							Patch(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.EditProjectColumnOption{}), repo.EditProjectColumn).
+							Delete(reqToken(), reqRepoNotArchived(), reqRepoWriter(), repo.DeleteProjectColumn)
+						m.Combo("/cards").Get(repo.ListProjectCards).
+							Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.MoveProjectCardOption{}), repo.MoveProjectCard)
+						m.Delete("/cards/:index", reqToken(), reqRepoNotArchived(), reqRepoWriter(), repo.DeleteProjectCard)
+					})
+				}, mustEnableProjects)
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
@@ -457,21 +618,30 @@ func RegisterRoutes(m *macaron.Macaron) {
 				})
 				m.Group("/releases", func() {
 					m.Combo("").Get(repo.ListReleases).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
+						Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
 					m.Combo("/:id").Get(repo.GetRelease).
-						Patch(reqToken(), reqRepoWriter(), bind(api.EditReleaseOption{}), repo.EditRelease).
-						Delete(reqToken(), reqRepoWriter(), repo.DeleteRelease)
+						Patch(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.EditReleaseOption{}), repo.EditRelease).
+						Delete(reqToken(), reqRepoNotArchived(), reqRepoWriter(), repo.DeleteRelease)
 				})
 				m.Post("/mirror-sync", reqToken(), reqRepoWriter(), repo.MirrorSync)
 				m.Get("/editorconfig/:filename", context.RepoRef(), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(bind(api.ListPullRequestsOptions{}), repo.ListPullRequests).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+						Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetPullRequest).
-							Patch(reqToken(), reqRepoWriter(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
+							Patch(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), reqRepoWriter(), repo.MergePullRequest)
+							Post(reqToken(), reqRepoNotArchived(), reqRepoWriter(), bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Group("/reviews", func() {
+							m.Combo("").Get(repo.ListPullReviews).
+								Post(reqToken(), reqRepoNotArchived(), bind(api.CreatePullReviewOptions{}), repo.CreatePullReview)
+							m.Group("/:id", func() {
+								m.Combo("").Get(repo.GetPullReview).
+									Post(reqToken(), reqRepoNotArchived(), bind(api.SubmitPullReviewOptions{}), repo.SubmitPullReview)
+								m.Get("/comments", repo.GetPullReviewComments)
+							})
+						})
 					})
 
 				}, mustAllowPulls, context.ReferencesGitRepo())
@@ -484,11 +654,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("/statuses", repo.GetCommitStatusesByRef)
 				})
 			}, repoAssignment())
-		})
+		}, reqRepoScope())
 
 		// Organizations
-		m.Get("/user/orgs", reqToken(), org.ListMyOrgs)
-		m.Get("/users/:username/orgs", org.ListUserOrgs)
+		m.Get("/user/orgs", reqToken(), reqOrgScope(), org.ListMyOrgs)
+		m.Get("/users/:username/orgs", reqOrgScope(), org.ListUserOrgs)
 		m.Group("/orgs/:orgname", func() {
 			m.Get("/repos", user.ListOrgRepos)
 			m.Combo("").Get(org.Get).
@@ -512,8 +682,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:id").Get(org.GetHook).
 					Patch(reqOrgOwnership(), bind(api.EditHookOption{}), org.EditHook).
 					Delete(reqOrgOwnership(), org.DeleteHook)
+				m.Post("/:id/deliveries/:uuid/redeliver", reqOrgOwnership(), org.RedeliverHook)
 			}, reqToken(), reqOrgMembership())
-		}, orgAssignment(true))
+		}, reqOrgScope(), orgAssignment(true))
 		m.Group("/teams/:teamid", func() {
 			m.Combo("").Get(org.GetTeam).
 				Patch(reqOrgOwnership(), bind(api.EditTeamOption{}), org.EditTeam).
@@ -530,7 +701,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Put(org.AddTeamRepository).
 					Delete(org.RemoveTeamRepository)
 			})
-		}, orgAssignment(false, true), reqToken(), reqOrgMembership())
+		}, reqOrgScope(), orgAssignment(false, true), reqToken(), reqOrgMembership())
 
 		m.Any("/*", func(ctx *context.Context) {
 			ctx.Error(404)
@@ -547,6 +718,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				})
 			})
+			m.Group("/hooks", func() {
+				m.Combo("").Get(admin.ListHooks).
+					Post(bind(api.CreateHookOption{}), admin.CreateHook)
+				m.Combo("/:id").Get(admin.GetHook).
+					Patch(bind(api.EditHookOption{}), admin.EditHook).
+					Delete(admin.DeleteHook)
+			})
 		}, reqAdmin())
 	}, context.APIContexter())
 }
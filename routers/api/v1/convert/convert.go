@@ -6,6 +6,7 @@ package convert
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Unknwon/com"
 
@@ -13,6 +14,7 @@ import (
 
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // ToEmail convert models.EmailAddress to api.Email
@@ -74,6 +76,18 @@ func ToCommit(c *git.Commit) *api.PayloadCommit {
 	}
 }
 
+// ToCommitUser convert a git.Signature to an api.CommitUser
+func ToCommitUser(sig *git.Signature) *api.CommitUser {
+	if sig == nil {
+		return nil
+	}
+	return &api.CommitUser{
+		Name:  sig.Name,
+		Email: sig.Email,
+		Date:  sig.When,
+	}
+}
+
 // ToPublicKey convert models.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *models.PublicKey) *api.PublicKey {
 	return &api.PublicKey{
@@ -131,7 +145,8 @@ func ToGPGKeyEmail(email *models.EmailAddress) *api.GPGKeyEmail {
 	}
 }
 
-// ToHook convert models.Webhook to api.Hook
+// ToHook convert models.Webhook to api.Hook,
+// repoLink is ignored for system webhooks.
 func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	config := map[string]string{
 		"url":          w.URL,
@@ -145,10 +160,15 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 		config["color"] = s.Color
 	}
 
+	url := fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID)
+	if w.IsSystemWebhook {
+		url = fmt.Sprintf("%s/admin/hooks/%d", setting.AppSubURL, w.ID)
+	}
+
 	return &api.Hook{
 		ID:      w.ID,
 		Type:    w.HookTaskType.Name(),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		URL:     url,
 		Active:  w.IsActive,
 		Config:  config,
 		Events:  w.EventsArray(),
@@ -191,3 +211,30 @@ func ToTeam(team *models.Team) *api.Team {
 		Permission:  team.Authorize.String(),
 	}
 }
+
+// ToNotificationThread convert a notification to an api.NotificationThread,
+// the repository and the issue of the notification must be loaded
+func ToNotificationThread(n *models.Notification, mode models.AccessMode) *api.NotificationThread {
+	subject := &api.NotificationSubject{
+		Title:   n.Issue.Title,
+		HTMLURL: n.Issue.HTMLURL(),
+		State:   n.Issue.State(),
+	}
+	if n.Issue.IsPull {
+		subject.Type = "Pull"
+		subject.URL = fmt.Sprintf("%s/pulls/%d", n.Repository.APIURL(), n.Issue.Index)
+	} else {
+		subject.Type = "Issue"
+		subject.URL = fmt.Sprintf("%s/issues/%d", n.Repository.APIURL(), n.Issue.Index)
+	}
+
+	return &api.NotificationThread{
+		ID:         n.ID,
+		Repository: n.Repository.APIFormat(mode),
+		Subject:    subject,
+		Unread:     n.Status == models.NotificationStatusUnread,
+		Pinned:     n.Status == models.NotificationStatusPinned,
+		UpdatedAt:  time.Unix(n.UpdatedUnix, 0),
+		URL:        fmt.Sprintf("%sapi/v1/notifications/threads/%d", setting.AppURL, n.ID),
+	}
+}
@@ -89,6 +89,37 @@ func EditHook(ctx *context.APIContext, form api.EditHookOption) {
 	utils.EditOrgHook(ctx, &form, hookID)
 }
 
+// RedeliverHook deliver again a payload sent by a hook of an organization
+func RedeliverHook(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/{id}/deliveries/{uuid}/redeliver organization orgRedeliverHook
+	// ---
+	// summary: Deliver again a payload sent by a hook
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the delivery to send again
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	hook, err := utils.GetOrgHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.RedeliverHookTask(ctx, hook)
+}
+
 // DeleteHook delete a hook of an organization
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /orgs/{org}/hooks/{id} organization orgDeleteHook
@@ -0,0 +1,134 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+func toBlameCommit(repository *models.Repository, commit *gitutil.BlameCommit) *api.BlameCommit {
+	sha := commit.ID.String()
+	return &api.BlameCommit{
+		SHA:          sha,
+		URL:          gitObjectURL(repository, git.ObjectCommit, sha),
+		HTMLURL:      repository.HTMLURL() + "/commit/" + sha,
+		Author:       convert.ToCommitUser(commit.Author),
+		Committer:    convert.ToCommitUser(commit.Committer),
+		Summary:      commit.Summary,
+		Path:         commit.Path,
+		PreviousSHA:  commit.PreviousID,
+		PreviousPath: commit.PreviousPath,
+	}
+}
+
+// GetBlame get the blame of a file of a repository
+func GetBlame(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/blame/{filepath} repository repoGetBlame
+	// ---
+	// summary: Get the blame of a file, as ranges of consecutive lines last
+	//          changed by the same commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "branch, tag or commit to read from, defaults to the default branch"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BlameRangeList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if !ctx.Repo.HasAccess() || ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	treePath, ok := cleanTreePath(ctx.Params("*"))
+	if !ok || len(treePath) == 0 {
+		ctx.Status(404)
+		return
+	}
+	ref := getContentsRef(ctx)
+	if ref == nil {
+		return
+	}
+
+	entry, err := ref.Commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+		}
+		return
+	} else if entry.IsDir() || entry.IsSubModule() {
+		ctx.Status(404)
+		return
+	}
+
+	reader, err := entry.Blob().Data()
+	if err != nil {
+		ctx.Error(500, "Data", err)
+		return
+	}
+	buf := make([]byte, 1024)
+	n, _ := reader.Read(buf)
+	if !base.IsTextFile(buf[:n]) {
+		ctx.Error(422, "", "cannot blame a binary file")
+		return
+	}
+
+	parts, err := gitutil.GetBlame(ctx.Repo.GitRepo, ref.Commit.ID.String(), treePath)
+	if err != nil {
+		ctx.Error(500, "GetBlame", err)
+		return
+	}
+
+	commits := make(map[*gitutil.BlameCommit]*api.BlameCommit)
+	ranges := make([]*api.BlameRange, len(parts))
+	for i, part := range parts {
+		commit, ok := commits[part.Commit]
+		if !ok {
+			commit = toBlameCommit(ctx.Repo.Repository, part.Commit)
+			commits[part.Commit] = commit
+		}
+		ranges[i] = &api.BlameRange{
+			StartLine:     part.StartLine,
+			EndLine:       part.EndLine(),
+			OrigStartLine: part.OrigStartLine,
+			Commit:        commit,
+			Lines:         part.Lines,
+		}
+	}
+
+	ctx.JSON(200, &ranges)
+}
@@ -5,10 +5,12 @@
 package repo
 
 import (
+	"code.gitea.io/git"
 	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 )
 
@@ -103,3 +105,176 @@ func ListBranches(ctx *context.APIContext) {
 
 	ctx.JSON(200, &apiBranches)
 }
+
+// getRefCommit returns the commit of a branch, a tag or a commit SHA, the
+// default branch if name is empty
+func getRefCommit(ctx *context.APIContext, name string) (*git.Commit, bool) {
+	gitRepo := ctx.Repo.GitRepo
+	if len(name) == 0 {
+		name = ctx.Repo.Repository.DefaultBranch
+	}
+
+	var (
+		commit *git.Commit
+		err    error
+	)
+	if gitRepo.IsBranchExist(name) {
+		commit, err = gitRepo.GetBranchCommit(name)
+	} else if gitRepo.IsTagExist(name) {
+		commit, err = gitutil.GetTagCommit(gitRepo, name)
+	} else if sha1Pattern.MatchString(name) {
+		commit, err = gitRepo.GetCommit(name)
+	} else {
+		ctx.Status(404)
+		return nil, false
+	}
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetCommit", err)
+		}
+		return nil, false
+	}
+	return commit, true
+}
+
+// CreateBranch create a branch of a repository
+func CreateBranch(ctx *context.APIContext, opt api.CreateBranchOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/branches repository repoCreateBranch
+	// ---
+	// summary: Create a branch
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBranchOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	repo := ctx.Repo.Repository
+	if !repo.CanCreateBranch() {
+		ctx.Error(403, "CanCreateBranch", "cannot create branches in this repository")
+		return
+	}
+
+	oldRefName := opt.OldRefName
+	if len(oldRefName) == 0 {
+		oldRefName = repo.DefaultBranch
+	}
+
+	var err error
+	if ctx.Repo.GitRepo.IsBranchExist(oldRefName) {
+		err = repo.CreateNewBranch(ctx.User, oldRefName, opt.BranchName)
+	} else {
+		commit, ok := getRefCommit(ctx, oldRefName)
+		if !ok {
+			return
+		}
+		err = repo.CreateNewBranchFromCommit(ctx.User, commit.ID.String(), opt.BranchName)
+	}
+	if err != nil {
+		if models.IsErrBranchAlreadyExists(err) || models.IsErrTagAlreadyExists(err) || models.IsErrBranchNameConflict(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "CreateNewBranch", err)
+		}
+		return
+	}
+
+	branch, err := repo.GetBranch(opt.BranchName)
+	if err != nil {
+		ctx.Error(500, "GetBranch", err)
+		return
+	}
+	c, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(500, "GetCommit", err)
+		return
+	}
+
+	ctx.JSON(201, convert.ToBranch(branch, c))
+}
+
+// DeleteBranch delete a branch of a repository
+func DeleteBranch(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/branches/{branch} repository repoDeleteBranch
+	// ---
+	// summary: Delete a branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: branch to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	repo := ctx.Repo.Repository
+	branchName := ctx.Params("*")
+	if repo.IsMirror {
+		ctx.Error(403, "", "cannot delete branches of a mirror")
+		return
+	} else if branchName == repo.DefaultBranch {
+		ctx.Error(422, "", "cannot delete the default branch")
+		return
+	}
+
+	isProtected, err := repo.IsProtectedBranch(branchName, ctx.User)
+	if err != nil {
+		ctx.Error(500, "IsProtectedBranch", err)
+		return
+	} else if isProtected {
+		ctx.Error(403, "", "branch is protected")
+		return
+	}
+
+	if err = repo.DeleteBranch(ctx.User, branchName); err != nil {
+		if models.IsErrBranchNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "DeleteBranch", err)
+		}
+		return
+	}
+
+	ctx.Status(204)
+}
@@ -0,0 +1,452 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// toBranchProtection returns the API representation of a protected branch
+func toBranchProtection(pb *models.ProtectedBranch) (*api.BranchProtection, error) {
+	userNames := func(ids []int64) ([]string, error) {
+		users, err := models.GetUsersByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(users))
+		for i := range users {
+			names[i] = users[i].Name
+		}
+		return names, nil
+	}
+	teamNames := func(ids []int64) ([]string, error) {
+		names := make([]string, 0, len(ids))
+		for _, id := range ids {
+			team, err := models.GetTeamByID(id)
+			if err == models.ErrTeamNotExist {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			names = append(names, team.Name)
+		}
+		return names, nil
+	}
+
+	bp := &api.BranchProtection{
+		BranchName:          pb.BranchName,
+		EnablePushWhitelist: pb.EnableWhitelist,
+		RequiredApprovals:   pb.RequiredApprovals,
+		EnableStatusCheck:   pb.EnableStatusCheck,
+		StatusCheckContexts: pb.StatusCheckContexts,
+		Created:             time.Unix(pb.CreatedUnix, 0),
+		Updated:             time.Unix(pb.UpdatedUnix, 0),
+	}
+	if bp.StatusCheckContexts == nil {
+		bp.StatusCheckContexts = []string{}
+	}
+
+	var err error
+	if bp.PushWhitelistUsernames, err = userNames(pb.WhitelistUserIDs); err != nil {
+		return nil, err
+	}
+	if bp.PushWhitelistTeams, err = teamNames(pb.WhitelistTeamIDs); err != nil {
+		return nil, err
+	}
+	if bp.ApprovalsWhitelistUsernames, err = userNames(pb.ApprovalsWhitelistUserIDs); err != nil {
+		return nil, err
+	}
+	if bp.ApprovalsWhitelistTeams, err = teamNames(pb.ApprovalsWhitelistTeamIDs); err != nil {
+		return nil, err
+	}
+	return bp, nil
+}
+
+// getUserIDsByNames returns the IDs of the users with the given names, it
+// writes a 422 response if a user does not exist
+func getUserIDsByNames(ctx *context.APIContext, names []string) ([]int64, bool) {
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		u, err := models.GetUserByName(name)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "GetUserByName", err)
+			}
+			return nil, false
+		}
+		ids = append(ids, u.ID)
+	}
+	return ids, true
+}
+
+// getTeamIDsByNames returns the IDs of the teams of the repository owner with
+// the given names, it writes a 422 response if a team does not exist
+func getTeamIDsByNames(ctx *context.APIContext, names []string) ([]int64, bool) {
+	ids := make([]int64, 0, len(names))
+	if len(names) == 0 {
+		return ids, true
+	} else if !ctx.Repo.Owner.IsOrganization() {
+		ctx.Error(422, "", "teams can only be whitelisted in repositories of organizations")
+		return nil, false
+	}
+
+	for _, name := range names {
+		team, err := models.GetTeam(ctx.Repo.Owner.ID, name)
+		if err != nil {
+			if err == models.ErrTeamNotExist {
+				ctx.Error(422, "", fmt.Sprintf("team does not exist [name: %s]", name))
+			} else {
+				ctx.Error(500, "GetTeam", err)
+			}
+			return nil, false
+		}
+		ids = append(ids, team.ID)
+	}
+	return ids, true
+}
+
+// updateBranchProtection saves a protected branch with the whitelists of the
+// given user and team names
+func updateBranchProtection(ctx *context.APIContext, pb *models.ProtectedBranch,
+	users, teams, approvalsUsers, approvalsTeams []string) bool {
+	if pb.RequiredApprovals < 0 {
+		ctx.Error(422, "", "required_approvals must not be negative")
+		return false
+	}
+
+	var (
+		opts models.WhitelistOptions
+		ok   bool
+	)
+	if opts.UserIDs, ok = getUserIDsByNames(ctx, users); !ok {
+		return false
+	}
+	if opts.TeamIDs, ok = getTeamIDsByNames(ctx, teams); !ok {
+		return false
+	}
+	if opts.ApprovalsUserIDs, ok = getUserIDsByNames(ctx, approvalsUsers); !ok {
+		return false
+	}
+	if opts.ApprovalsTeamIDs, ok = getTeamIDsByNames(ctx, approvalsTeams); !ok {
+		return false
+	}
+
+	contexts := make([]string, 0, len(pb.StatusCheckContexts))
+	for _, context := range pb.StatusCheckContexts {
+		if context = strings.TrimSpace(context); len(context) > 0 {
+			contexts = append(contexts, context)
+		}
+	}
+	pb.StatusCheckContexts = contexts
+
+	if err := models.UpdateProtectBranch(ctx.Repo.Repository, pb, opts); err != nil {
+		ctx.Error(500, "UpdateProtectBranch", err)
+		return false
+	}
+	return true
+}
+
+// getBranchProtection returns the protected branch named by the path, it
+// writes a 404 response if the branch is not protected
+func getBranchProtection(ctx *context.APIContext) (*models.ProtectedBranch, bool) {
+	pb, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, ctx.Params("*"))
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return nil, false
+	} else if pb == nil {
+		ctx.Status(404)
+		return nil, false
+	}
+	return pb, true
+}
+
+// writeBranchProtection writes the API representation of a protected branch
+func writeBranchProtection(ctx *context.APIContext, status int, pb *models.ProtectedBranch) {
+	bp, err := toBranchProtection(pb)
+	if err != nil {
+		ctx.Error(500, "toBranchProtection", err)
+		return
+	}
+	ctx.JSON(status, bp)
+}
+
+// ListBranchProtections list the branch protections of a repository
+func ListBranchProtections(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections repository repoListBranchProtection
+	// ---
+	// summary: List the branch protections of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	pbs, err := ctx.Repo.Repository.GetProtectedBranches()
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranches", err)
+		return
+	}
+
+	apiBps := make([]*api.BranchProtection, len(pbs))
+	for i := range pbs {
+		if apiBps[i], err = toBranchProtection(pbs[i]); err != nil {
+			ctx.Error(500, "toBranchProtection", err)
+			return
+		}
+	}
+
+	ctx.JSON(200, &apiBps)
+}
+
+// GetBranchProtection get the protection of a branch
+func GetBranchProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections/{branch} repository repoGetBranchProtection
+	// ---
+	// summary: Get the protection of a branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	pb, ok := getBranchProtection(ctx)
+	if !ok {
+		return
+	}
+	writeBranchProtection(ctx, 200, pb)
+}
+
+// CreateBranchProtection protect a branch of a repository
+func CreateBranchProtection(ctx *context.APIContext, opt api.CreateBranchProtectionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/branch_protections repository repoCreateBranchProtection
+	// ---
+	// summary: Protect a branch
+	// description: Users and teams without write access are dropped from the
+	//   whitelists.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBranchProtectionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	repo := ctx.Repo.Repository
+	if !ctx.Repo.GitRepo.IsBranchExist(opt.BranchName) {
+		ctx.Status(404)
+		return
+	}
+
+	pb, err := models.GetProtectedBranchBy(repo.ID, opt.BranchName)
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return
+	} else if pb != nil {
+		ctx.Error(409, "", fmt.Sprintf("branch is already protected [name: %s]", opt.BranchName))
+		return
+	}
+
+	pb = &models.ProtectedBranch{
+		RepoID:              repo.ID,
+		BranchName:          opt.BranchName,
+		EnableWhitelist:     opt.EnablePushWhitelist,
+		RequiredApprovals:   opt.RequiredApprovals,
+		EnableStatusCheck:   opt.EnableStatusCheck,
+		StatusCheckContexts: opt.StatusCheckContexts,
+	}
+	if !updateBranchProtection(ctx, pb, opt.PushWhitelistUsernames, opt.PushWhitelistTeams,
+		opt.ApprovalsWhitelistUsernames, opt.ApprovalsWhitelistTeams) {
+		return
+	}
+
+	writeBranchProtection(ctx, 201, pb)
+}
+
+// EditBranchProtection edit the protection of a branch
+func EditBranchProtection(ctx *context.APIContext, opt api.EditBranchProtectionOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/branch_protections/{branch} repository repoEditBranchProtection
+	// ---
+	// summary: Edit the protection of a branch
+	// description: Fields which are not set are left unchanged.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditBranchProtectionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	pb, ok := getBranchProtection(ctx)
+	if !ok {
+		return
+	}
+
+	current, err := toBranchProtection(pb)
+	if err != nil {
+		ctx.Error(500, "toBranchProtection", err)
+		return
+	}
+
+	if opt.EnablePushWhitelist != nil {
+		pb.EnableWhitelist = *opt.EnablePushWhitelist
+	}
+	if opt.RequiredApprovals != nil {
+		pb.RequiredApprovals = *opt.RequiredApprovals
+	}
+	if opt.EnableStatusCheck != nil {
+		pb.EnableStatusCheck = *opt.EnableStatusCheck
+	}
+	if opt.StatusCheckContexts != nil {
+		pb.StatusCheckContexts = opt.StatusCheckContexts
+	}
+	if opt.PushWhitelistUsernames != nil {
+		current.PushWhitelistUsernames = opt.PushWhitelistUsernames
+	}
+	if opt.PushWhitelistTeams != nil {
+		current.PushWhitelistTeams = opt.PushWhitelistTeams
+	}
+	if opt.ApprovalsWhitelistUsernames != nil {
+		current.ApprovalsWhitelistUsernames = opt.ApprovalsWhitelistUsernames
+	}
+	if opt.ApprovalsWhitelistTeams != nil {
+		current.ApprovalsWhitelistTeams = opt.ApprovalsWhitelistTeams
+	}
+	if !updateBranchProtection(ctx, pb, current.PushWhitelistUsernames, current.PushWhitelistTeams,
+		current.ApprovalsWhitelistUsernames, current.ApprovalsWhitelistTeams) {
+		return
+	}
+
+	writeBranchProtection(ctx, 200, pb)
+}
+
+// DeleteBranchProtection remove the protection of a branch
+func DeleteBranchProtection(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/branch_protections/{branch} repository repoDeleteBranchProtection
+	// ---
+	// summary: Remove the protection of a branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	pb, ok := getBranchProtection(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.Repo.Repository.DeleteProtectedBranch(pb.ID); err != nil {
+		ctx.Error(500, "DeleteProtectedBranch", err)
+		return
+	}
+
+	ctx.Status(204)
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/indexer"
+	"code.gitea.io/gitea/modules/search"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// SearchCode search the code of all repositories the user has access to
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/code/search repository repoSearchCode
+	// ---
+	// summary: Search for code across the repositories that the user has access to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: search string
+	//   type: string
+	//   required: true
+	// - name: owner
+	//   in: query
+	//   description: search only the repositories of the user or organization
+	//   type: string
+	// - name: language
+	//   in: query
+	//   description: search only files of the language, as derived from the file extension
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: search only files whose path starts with the prefix
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResultList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.Status(404)
+		return
+	}
+
+	var ownerID int64
+	if ownerName := strings.TrimSpace(ctx.Query("owner")); len(ownerName) > 0 {
+		owner, err := models.GetUserByName(ownerName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "GetUserByName", err)
+			}
+			return
+		}
+		ownerID = owner.ID
+	}
+
+	repoIDs, err := models.GetReadableRepoIDs(ctx.User, ownerID)
+	if err != nil {
+		ctx.Error(500, "GetReadableRepoIDs", err)
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	total, searchResults, err := search.PerformGlobalSearch(&indexer.RepoSearchOptions{
+		RepoIDs:    repoIDs,
+		Keyword:    strings.TrimSpace(ctx.Query("q")),
+		Language:   strings.ToLower(strings.TrimSpace(ctx.Query("language"))),
+		PathPrefix: strings.TrimLeft(strings.TrimSpace(ctx.Query("path")), "/"),
+		Page:       page,
+		PageSize:   setting.UI.RepoSearchPagingNum,
+	})
+	if err != nil {
+		ctx.Error(500, "PerformGlobalSearch", err)
+		return
+	}
+
+	resultRepoIDs := make([]int64, 0, len(searchResults))
+	for _, result := range searchResults {
+		resultRepoIDs = append(resultRepoIDs, result.RepoID)
+	}
+	repos, err := models.GetRepositoriesMapByIDs(resultRepoIDs)
+	if err != nil {
+		ctx.Error(500, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	var userID int64
+	if ctx.IsSigned {
+		userID = ctx.User.ID
+	}
+	apiRepos := make(map[int64]*api.Repository, len(repos))
+	for id, repo := range repos {
+		accessMode, err := models.AccessLevel(userID, repo)
+		if err != nil {
+			ctx.Error(500, "AccessLevel", err)
+			return
+		}
+		apiRepos[id] = repo.APIFormat(accessMode)
+	}
+
+	apiResults := make([]*api.CodeSearchResult, len(searchResults))
+	for i, result := range searchResults {
+		repo := repos[result.RepoID]
+		apiResults[i] = &api.CodeSearchResult{
+			Repository: apiRepos[result.RepoID],
+			Filename:   result.Filename,
+			Language:   result.Language,
+			HTMLURL:    repo.HTMLURL() + "/src/branch/" + repo.DefaultBranch + "/" + result.Filename,
+			Snippet:    result.Snippet,
+			MatchStart: result.MatchStart,
+			MatchEnd:   result.MatchEnd,
+		}
+		if len(result.LineNumbers) > 0 {
+			apiResults[i].StartLine = result.LineNumbers[0]
+		}
+	}
+
+	ctx.SetLinkHeader(total, setting.UI.RepoSearchPagingNum)
+	ctx.JSON(200, &apiResults)
+}
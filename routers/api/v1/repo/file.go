@@ -5,11 +5,23 @@
 package repo
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
 	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/repo"
+
+	api "code.gitea.io/sdk/gitea"
 )
 
 // GetRawFile get a file by path on a repository
@@ -140,3 +152,570 @@ func GetEditorconfig(ctx *context.APIContext) {
 	}
 	ctx.JSON(200, def)
 }
+
+// contentsRef is the commit which the contents API reads from
+type contentsRef struct {
+	Name   string
+	Commit *git.Commit
+	// SubURL is the part of the web links specifying the ref, e.g. branch/master
+	SubURL string
+}
+
+// getContentsRef resolves the ref query parameter, which can be a branch, a
+// tag or a commit and defaults to the default branch. It returns nil if it
+// wrote an error.
+func getContentsRef(ctx *context.APIContext) *contentsRef {
+	gitRepo := ctx.Repo.GitRepo
+	ref := &contentsRef{Name: ctx.Query("ref")}
+	if len(ref.Name) == 0 {
+		ref.Name = ctx.Repo.Repository.DefaultBranch
+	}
+
+	var err error
+	if gitRepo.IsBranchExist(ref.Name) {
+		ref.Commit, err = gitRepo.GetBranchCommit(ref.Name)
+		ref.SubURL = "branch/" + ref.Name
+	} else if gitRepo.IsTagExist(ref.Name) {
+		ref.Commit, err = gitutil.GetTagCommit(gitRepo, ref.Name)
+		ref.SubURL = "tag/" + ref.Name
+	} else if len(ref.Name) == 40 {
+		ref.Commit, err = gitRepo.GetCommit(ref.Name)
+		if err == nil {
+			ref.SubURL = "commit/" + ref.Commit.ID.String()
+		}
+	} else {
+		ctx.Status(404)
+		return nil
+	}
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(404, "GetCommit", err)
+		} else {
+			ctx.Error(500, "GetCommit", err)
+		}
+		return nil
+	}
+	return ref
+}
+
+// cleanTreePath returns the path of the request without leading and
+// trailing slashes, or false if the path is not valid
+func cleanTreePath(treePath string) (string, bool) {
+	treePath = strings.Trim(treePath, "/")
+	if len(treePath) == 0 {
+		return "", true
+	}
+	if cleaned := path.Clean(treePath); cleaned != treePath || strings.HasPrefix(cleaned, "..") {
+		return "", false
+	}
+	return treePath, true
+}
+
+func escapeTreePath(treePath string) string {
+	parts := strings.Split(treePath, "/")
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
+
+// toContentsResponse converts a tree entry to its API format, the content
+// is only included for files if withContent is true
+func toContentsResponse(repository *models.Repository, ref *contentsRef, treePath string, entry *git.TreeEntry, withContent bool) (*api.ContentsResponse, error) {
+	escapedPath := escapeTreePath(treePath)
+	contents := &api.ContentsResponse{
+		Name:    entry.Name(),
+		Path:    treePath,
+		SHA:     entry.ID.String(),
+		URL:     repository.APIURL() + "/contents/" + escapedPath + "?ref=" + url.QueryEscape(ref.Name),
+		HTMLURL: repository.HTMLURL() + "/src/" + ref.SubURL + "/" + escapedPath,
+	}
+
+	switch {
+	case entry.IsDir():
+		contents.Type = "dir"
+	case entry.IsSubModule():
+		contents.Type = "submodule"
+	case entry.IsLink():
+		contents.Type = "symlink"
+		data, err := readBlob(entry.Blob())
+		if err != nil {
+			return nil, err
+		}
+		contents.Target = string(data)
+	default:
+		contents.Type = "file"
+		contents.Size = entry.Size()
+		contents.DownloadURL = repository.HTMLURL() + "/raw/" + ref.SubURL + "/" + escapedPath
+		if withContent {
+			data, err := readBlob(entry.Blob())
+			if err != nil {
+				return nil, err
+			}
+			contents.Encoding = "base64"
+			contents.Content = base64.StdEncoding.EncodeToString(data)
+		}
+	}
+	return contents, nil
+}
+
+func readBlob(blob *git.Blob) ([]byte, error) {
+	reader, err := blob.Data()
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// GetContents gets the metadata and the content of a file or the entries of
+// a directory of a repository
+func GetContents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/contents/{filepath} repository repoGetContents
+	// ---
+	// summary: Gets the metadata and the content of a file, or the entries of a directory of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file or directory
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "branch, tag or commit to read from, defaults to the default branch"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContentsResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if !ctx.Repo.HasAccess() || ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	treePath, ok := cleanTreePath(ctx.Params("*"))
+	if !ok {
+		ctx.Status(404)
+		return
+	}
+	ref := getContentsRef(ctx)
+	if ref == nil {
+		return
+	}
+
+	tree := &ref.Commit.Tree
+	if len(treePath) > 0 {
+		entry, err := ref.Commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.Status(404)
+			} else {
+				ctx.Error(500, "GetTreeEntryByPath", err)
+			}
+			return
+		}
+		if !entry.IsDir() {
+			contents, err := toContentsResponse(ctx.Repo.Repository, ref, treePath, entry, true)
+			if err != nil {
+				ctx.Error(500, "toContentsResponse", err)
+				return
+			}
+			ctx.JSON(200, contents)
+			return
+		}
+		if tree, err = ref.Commit.SubTree(treePath); err != nil {
+			ctx.Error(500, "SubTree", err)
+			return
+		}
+	}
+
+	entries, err := tree.ListEntries()
+	if err != nil {
+		ctx.Error(500, "ListEntries", err)
+		return
+	}
+	list := make([]*api.ContentsResponse, len(entries))
+	for i, entry := range entries {
+		if list[i], err = toContentsResponse(ctx.Repo.Repository, ref, path.Join(treePath, entry.Name()), entry, false); err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+	ctx.JSON(200, list)
+}
+
+// fileChange is a change of a file which is committed to a branch
+type fileChange struct {
+	TreePath  string
+	FromPath  string
+	SHA       string
+	Content   []byte
+	IsNewFile bool
+	IsDelete  bool
+}
+
+// prepareFileChange validates the options shared by all changes of files,
+// it returns false if it wrote an error
+func prepareFileChange(ctx *context.APIContext, opts *api.FileOptions) (treePath string, author *git.Signature, ok bool) {
+	if ctx.Repo.Repository.IsBare {
+		ctx.Error(422, "", "repository is empty")
+		return "", nil, false
+	}
+	treePath, ok = cleanTreePath(ctx.Params("*"))
+	if !ok || len(treePath) == 0 {
+		ctx.Error(422, "", "invalid file path")
+		return "", nil, false
+	}
+
+	if len(opts.BranchName) == 0 {
+		opts.BranchName = ctx.Repo.Repository.DefaultBranch
+	}
+	if !ctx.Repo.GitRepo.IsBranchExist(opts.BranchName) {
+		ctx.Error(404, "", fmt.Sprintf("branch does not exist: %s", opts.BranchName))
+		return "", nil, false
+	}
+	if len(opts.NewBranchName) == 0 {
+		opts.NewBranchName = opts.BranchName
+	}
+	if opts.NewBranchName != opts.BranchName {
+		if ctx.Repo.GitRepo.IsBranchExist(opts.NewBranchName) {
+			ctx.Error(422, "", fmt.Sprintf("branch already exists: %s", opts.NewBranchName))
+			return "", nil, false
+		}
+	} else {
+		protected, err := ctx.Repo.Repository.IsProtectedBranch(opts.BranchName, ctx.User)
+		if err != nil {
+			ctx.Error(500, "IsProtectedBranch", err)
+			return "", nil, false
+		} else if protected {
+			ctx.Error(403, "", fmt.Sprintf("branch is protected: %s", opts.BranchName))
+			return "", nil, false
+		}
+	}
+
+	if len(opts.Author.Name) > 0 || len(opts.Author.Email) > 0 {
+		if len(opts.Author.Name) == 0 || len(opts.Author.Email) == 0 {
+			ctx.Error(422, "", "author requires both a name and an email")
+			return "", nil, false
+		}
+		author = &git.Signature{
+			Name:  opts.Author.Name,
+			Email: opts.Author.Email,
+			When:  time.Now(),
+		}
+	}
+	return treePath, author, true
+}
+
+// commitFileChange validates and commits a change of a file, and writes the
+// resulting file and commit
+func commitFileChange(ctx *context.APIContext, opts api.FileOptions, change fileChange) {
+	treePath, author, ok := prepareFileChange(ctx, &opts)
+	if !ok {
+		return
+	}
+	change.TreePath = treePath
+	if len(change.FromPath) == 0 {
+		change.FromPath = treePath
+	} else if change.FromPath, ok = cleanTreePath(change.FromPath); !ok || len(change.FromPath) == 0 {
+		ctx.Error(422, "", "invalid from_path")
+		return
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetBranchCommit(opts.BranchName)
+	if err != nil {
+		ctx.Error(500, "GetBranchCommit", err)
+		return
+	}
+
+	if !change.IsNewFile {
+		entry, err := commit.GetTreeEntryByPath(change.FromPath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.Error(404, "", fmt.Sprintf("file does not exist: %s", change.FromPath))
+			} else {
+				ctx.Error(500, "GetTreeEntryByPath", err)
+			}
+			return
+		} else if entry.IsDir() || entry.IsLink() || entry.IsSubModule() {
+			ctx.Error(422, "", fmt.Sprintf("not a regular file: %s", change.FromPath))
+			return
+		}
+	}
+	if change.IsNewFile || change.FromPath != change.TreePath {
+		// the new path must neither exist nor be below a file
+		var parentPath string
+		for _, part := range strings.Split(change.TreePath, "/") {
+			parentPath = path.Join(parentPath, part)
+			entry, err := commit.GetTreeEntryByPath(parentPath)
+			if err != nil {
+				if git.IsErrNotExist(err) {
+					break
+				}
+				ctx.Error(500, "GetTreeEntryByPath", err)
+				return
+			}
+			if parentPath == change.TreePath || !entry.IsDir() {
+				ctx.Error(422, "", fmt.Sprintf("file already exists: %s", parentPath))
+				return
+			}
+		}
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if change.IsDelete {
+		if len(message) == 0 {
+			message = fmt.Sprintf("Delete '%s'", change.TreePath)
+		}
+		err = ctx.Repo.Repository.DeleteRepoFile(ctx.User, models.DeleteRepoFileOptions{
+			LastCommitID: commit.ID.String(),
+			OldBranch:    opts.BranchName,
+			NewBranch:    opts.NewBranchName,
+			TreePath:     change.TreePath,
+			Message:      message,
+			SHA:          change.SHA,
+			Author:       author,
+		})
+	} else {
+		if len(message) == 0 {
+			if change.IsNewFile {
+				message = fmt.Sprintf("Add '%s'", change.TreePath)
+			} else {
+				message = fmt.Sprintf("Update '%s'", change.TreePath)
+			}
+		}
+		err = ctx.Repo.Repository.UpdateRepoFile(ctx.User, models.UpdateRepoFileOptions{
+			LastCommitID: commit.ID.String(),
+			OldBranch:    opts.BranchName,
+			NewBranch:    opts.NewBranchName,
+			OldTreeName:  change.FromPath,
+			NewTreeName:  change.TreePath,
+			Message:      message,
+			Content:      string(change.Content),
+			IsNewFile:    change.IsNewFile,
+			SHA:          change.SHA,
+			Author:       author,
+		})
+	}
+	if err != nil {
+		switch {
+		case models.IsErrSHADoesNotMatch(err):
+			ctx.Error(409, "", err)
+		case models.IsErrRepoFileDoesNotExist(err):
+			ctx.Error(404, "", err)
+		case models.IsErrRepoFileAlreadyExist(err):
+			ctx.Error(422, "", err)
+		case models.IsErrRepoArchived(err):
+			ctx.Error(403, "", err)
+		default:
+			ctx.Error(500, "UpdateRepoFile", err)
+		}
+		return
+	}
+
+	if commit, err = ctx.Repo.GitRepo.GetBranchCommit(opts.NewBranchName); err != nil {
+		ctx.Error(500, "GetBranchCommit", err)
+		return
+	}
+	resp := &api.FileResponse{
+		Commit: &api.FileCommitResponse{
+			SHA:       commit.ID.String(),
+			HTMLURL:   ctx.Repo.Repository.HTMLURL() + "/commit/" + commit.ID.String(),
+			Message:   commit.Message(),
+			Author:    convert.ToCommitUser(commit.Author),
+			Committer: convert.ToCommitUser(commit.Committer),
+		},
+	}
+	if !change.IsDelete {
+		entry, err := commit.GetTreeEntryByPath(change.TreePath)
+		if err != nil {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+			return
+		}
+		ref := &contentsRef{
+			Name:   opts.NewBranchName,
+			Commit: commit,
+			SubURL: "branch/" + opts.NewBranchName,
+		}
+		if resp.Content, err = toContentsResponse(ctx.Repo.Repository, ref, change.TreePath, entry, false); err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+
+	if change.IsNewFile {
+		ctx.JSON(201, resp)
+	} else {
+		ctx.JSON(200, resp)
+	}
+}
+
+func decodeContent(ctx *context.APIContext, content string) ([]byte, bool) {
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		ctx.Error(422, "", "content is not base64 encoded")
+		return nil, false
+	}
+	return data, true
+}
+
+// CreateFile creates a file in a repository
+func CreateFile(ctx *context.APIContext, opts api.CreateFileOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/contents/{filepath} repository repoCreateFile
+	// ---
+	// summary: Create a file in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to create
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateFileOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	content, ok := decodeContent(ctx, opts.Content)
+	if !ok {
+		return
+	}
+	commitFileChange(ctx, opts.FileOptions, fileChange{
+		Content:   content,
+		IsNewFile: true,
+	})
+}
+
+// UpdateFile updates, and possibly moves, a file of a repository
+func UpdateFile(ctx *context.APIContext, opts api.UpdateFileOptions) {
+	// swagger:operation PUT /repos/{owner}/{repo}/contents/{filepath} repository repoUpdateFile
+	// ---
+	// summary: Update a file in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to update
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UpdateFileOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	content, ok := decodeContent(ctx, opts.Content)
+	if !ok {
+		return
+	}
+	commitFileChange(ctx, opts.FileOptions, fileChange{
+		FromPath: opts.FromPath,
+		SHA:      opts.SHA,
+		Content:  content,
+	})
+}
+
+// DeleteFile deletes a file of a repository
+func DeleteFile(ctx *context.APIContext, opts api.DeleteFileOptions) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/contents/{filepath} repository repoDeleteFile
+	// ---
+	// summary: Delete a file in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to delete
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/DeleteFileOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	commitFileChange(ctx, opts.FileOptions, fileChange{
+		SHA:      opts.SHA,
+		IsDelete: true,
+	})
+}
@@ -0,0 +1,475 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+var sha1Pattern = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// gitObjectURL returns the API URL of a git object, e.g. .../git/commits/<sha>
+func gitObjectURL(repository *models.Repository, tp git.ObjectType, sha string) string {
+	return repository.APIURL() + "/git/" + string(tp) + "s/" + sha
+}
+
+// getGitObject checks that the object given by the :sha parameter exists and
+// is of the given type, it responds with 404 otherwise
+func getGitObject(ctx *context.APIContext, tp git.ObjectType) (string, bool) {
+	sha := ctx.Params(":sha")
+	if ctx.Repo.Repository.IsBare || !sha1Pattern.MatchString(sha) {
+		ctx.Status(404)
+		return "", false
+	}
+
+	objectType, err := gitutil.GetObjectType(ctx.Repo.GitRepo, sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetObjectType", err)
+		}
+		return "", false
+	} else if objectType != tp {
+		ctx.Status(404)
+		return "", false
+	}
+	return sha, true
+}
+
+// ListAllGitRefs lists all references of a repository
+func ListAllGitRefs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/refs repository repoListAllGitRefs
+	// ---
+	// summary: Get all references of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReferenceList"
+	listGitRefs(ctx, "refs/")
+}
+
+// ListGitRefs lists the references of a repository with the given prefix
+func ListGitRefs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/refs/{ref} repository repoListGitRefs
+	// ---
+	// summary: Get the references of a repository whose name starts with the given prefix
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: prefix of the references without refs/, e.g. heads/ or tags/v1
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReferenceList"
+	listGitRefs(ctx, "refs/"+ctx.Params("*"))
+}
+
+func listGitRefs(ctx *context.APIContext, prefix string) {
+	apiRefs := make([]*api.Reference, 0, 10)
+	if ctx.Repo.Repository.IsBare {
+		ctx.JSON(200, &apiRefs)
+		return
+	}
+
+	refs, err := gitutil.GetRefsFiltered(ctx.Repo.GitRepo, prefix)
+	if err != nil {
+		ctx.Error(500, "GetRefsFiltered", err)
+		return
+	}
+	for _, ref := range refs {
+		apiRefs = append(apiRefs, &api.Reference{
+			Ref: ref.Name,
+			URL: ctx.Repo.Repository.APIURL() + "/git/" + escapeTreePath(ref.Name),
+			Object: &api.GitObject{
+				Type: string(ref.Type),
+				SHA:  ref.Object.String(),
+				URL:  gitObjectURL(ctx.Repo.Repository, ref.Type, ref.Object.String()),
+			},
+		})
+	}
+	ctx.JSON(200, &apiRefs)
+}
+
+// getTreeID returns the id of the tree given by the :sha parameter, which may
+// also be a commit, a branch or a tag
+func getTreeID(ctx *context.APIContext) (string, bool) {
+	name := ctx.Params(":sha")
+	gitRepo := ctx.Repo.GitRepo
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return "", false
+	}
+
+	var (
+		commit *git.Commit
+		err    error
+	)
+	if gitRepo.IsBranchExist(name) {
+		commit, err = gitRepo.GetBranchCommit(name)
+	} else if gitRepo.IsTagExist(name) {
+		commit, err = gitutil.GetTagCommit(gitRepo, name)
+	} else if !sha1Pattern.MatchString(name) {
+		ctx.Status(404)
+		return "", false
+	} else {
+		var tp git.ObjectType
+		if tp, err = gitutil.GetObjectType(gitRepo, name); err == nil {
+			switch tp {
+			case git.ObjectTree:
+				return name, true
+			case git.ObjectCommit:
+				commit, err = gitRepo.GetCommit(name)
+			default:
+				err = git.ErrNotExist{ID: name}
+			}
+		}
+	}
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetTree", err)
+		}
+		return "", false
+	}
+	return commit.Tree.ID.String(), true
+}
+
+// GetTree gets a page of the entries of a tree
+func GetTree(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/trees/{sha} repository repoGetTree
+	// ---
+	// summary: Gets a page of the entries of a tree of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the tree or of a commit, or a branch or tag name
+	//   type: string
+	//   required: true
+	// - name: recursive
+	//   in: query
+	//   description: also list the entries of all sub trees
+	//   type: boolean
+	//   required: false
+	// - name: page
+	//   in: query
+	//   description: page number of the entries, defaults to 1
+	//   type: integer
+	//   required: false
+	// - name: limit
+	//   in: query
+	//   description: number of entries per page
+	//   type: integer
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitTreeResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	sha, ok := getTreeID(ctx)
+	if !ok {
+		return
+	}
+
+	entries, err := gitutil.ListTreeEntries(ctx.Repo.GitRepo, sha, ctx.QueryBool("recursive"))
+	if err != nil {
+		ctx.Error(500, "ListTreeEntries", err)
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 || limit > setting.API.DefaultGitTreesPerPage {
+		limit = setting.API.DefaultGitTreesPerPage
+	}
+
+	resp := &api.GitTreeResponse{
+		SHA:        sha,
+		URL:        gitObjectURL(ctx.Repo.Repository, git.ObjectTree, sha),
+		Entries:    make([]*api.GitEntry, 0, limit),
+		Page:       page,
+		TotalCount: len(entries),
+	}
+	start := (page - 1) * limit
+	if start < len(entries) {
+		end := start + limit
+		if end < len(entries) {
+			resp.Truncated = true
+		} else {
+			end = len(entries)
+		}
+		for _, entry := range entries[start:end] {
+			apiEntry := &api.GitEntry{
+				Path: entry.Path,
+				Mode: fmt.Sprintf("%06o", entry.Mode),
+				Type: string(entry.Type),
+				SHA:  entry.ID.String(),
+			}
+			switch {
+			case entry.IsSubModule():
+				// the commit of a submodule belongs to another repository
+			case entry.Type == git.ObjectBlob:
+				apiEntry.Size = entry.Size
+				fallthrough
+			default:
+				apiEntry.URL = gitObjectURL(ctx.Repo.Repository, entry.Type, apiEntry.SHA)
+			}
+			resp.Entries = append(resp.Entries, apiEntry)
+		}
+	}
+	ctx.JSON(200, resp)
+}
+
+// GetBlob gets a blob of a repository
+func GetBlob(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/blobs/{sha} repository repoGetBlob
+	// ---
+	// summary: Gets a blob of a repository with base64 encoded content
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the blob
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitBlobResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	sha, ok := getGitObject(ctx, git.ObjectBlob)
+	if !ok {
+		return
+	}
+
+	data, err := gitutil.ReadBlob(ctx.Repo.GitRepo, sha)
+	if err != nil {
+		ctx.Error(500, "ReadBlob", err)
+		return
+	}
+	ctx.JSON(200, &api.GitBlobResponse{
+		SHA:      sha,
+		URL:      gitObjectURL(ctx.Repo.Repository, git.ObjectBlob, sha),
+		Size:     int64(len(data)),
+		Encoding: "base64",
+		Content:  base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// commitFileStatuses maps the status letters of git diff-tree to the
+// statuses of the API
+var commitFileStatuses = map[byte]string{
+	'A': "added",
+	'C': "copied",
+	'D': "removed",
+	'M': "modified",
+	'R': "renamed",
+	'T': "modified",
+}
+
+// GetGitCommit gets a commit of a repository with the files it changes
+func GetGitCommit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha} repository repoGetGitCommit
+	// ---
+	// summary: Gets a commit of a repository with the files it changes compared to its first parent
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the commit
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitCommit"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	sha, ok := getGitObject(ctx, git.ObjectCommit)
+	if !ok {
+		return
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetCommit(sha)
+	if err != nil {
+		ctx.Error(500, "GetCommit", err)
+		return
+	}
+	fileStats, err := gitutil.GetCommitFileStats(ctx.Repo.GitRepo, commit)
+	if err != nil {
+		ctx.Error(500, "GetCommitFileStats", err)
+		return
+	}
+
+	repository := ctx.Repo.Repository
+	treeID := commit.Tree.ID.String()
+	resp := &api.GitCommit{
+		SHA:       sha,
+		URL:       gitObjectURL(repository, git.ObjectCommit, sha),
+		HTMLURL:   repository.HTMLURL() + "/commit/" + sha,
+		Author:    convert.ToCommitUser(commit.Author),
+		Committer: convert.ToCommitUser(commit.Committer),
+		Message:   commit.Message(),
+		Tree: &api.CommitMeta{
+			SHA: treeID,
+			URL: gitObjectURL(repository, git.ObjectTree, treeID),
+		},
+		Parents: make([]*api.CommitMeta, 0, commit.ParentCount()),
+		Stats:   &api.CommitStats{},
+		Files:   make([]*api.CommitAffectedFile, 0, len(fileStats)),
+	}
+	for i := 0; i < commit.ParentCount(); i++ {
+		parentID, err := commit.ParentID(i)
+		if err != nil {
+			ctx.Error(500, "ParentID", err)
+			return
+		}
+		resp.Parents = append(resp.Parents, &api.CommitMeta{
+			SHA: parentID.String(),
+			URL: gitObjectURL(repository, git.ObjectCommit, parentID.String()),
+		})
+	}
+	for _, stat := range fileStats {
+		resp.Files = append(resp.Files, &api.CommitAffectedFile{
+			Filename:         stat.Name,
+			PreviousFilename: stat.OldName,
+			Status:           commitFileStatuses[stat.Status],
+			Additions:        stat.Additions,
+			Deletions:        stat.Deletions,
+			Changes:          stat.Additions + stat.Deletions,
+			Binary:           stat.IsBinary,
+		})
+		resp.Stats.Additions += stat.Additions
+		resp.Stats.Deletions += stat.Deletions
+	}
+	resp.Stats.Total = resp.Stats.Additions + resp.Stats.Deletions
+	ctx.JSON(200, resp)
+}
+
+// GetAnnotatedTag gets an annotated tag of a repository
+func GetAnnotatedTag(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/tags/{sha} repository repoGetAnnotatedTag
+	// ---
+	// summary: Gets an annotated tag of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the tag object, lightweight tags have none
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AnnotatedTag"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	sha, ok := getGitObject(ctx, git.ObjectTag)
+	if !ok {
+		return
+	}
+
+	tag, err := gitutil.GetAnnotatedTag(ctx.Repo.GitRepo, sha)
+	if err != nil {
+		ctx.Error(500, "GetAnnotatedTag", err)
+		return
+	}
+	ctx.JSON(200, &api.AnnotatedTag{
+		Tag:     tag.Name,
+		SHA:     sha,
+		URL:     gitObjectURL(ctx.Repo.Repository, git.ObjectTag, sha),
+		Message: tag.Message,
+		Tagger:  convert.ToCommitUser(tag.Tagger),
+		Object: &api.GitObject{
+			Type: string(tag.Type),
+			SHA:  tag.Object.String(),
+			URL:  gitObjectURL(ctx.Repo.Repository, tag.Type, tag.Object.String()),
+		},
+	})
+}
@@ -144,6 +144,42 @@ func EditHook(ctx *context.APIContext, form api.EditHookOption) {
 	utils.EditRepoHook(ctx, &form, hookID)
 }
 
+// RedeliverHook deliver again a payload sent by a hook of a repository
+func RedeliverHook(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/deliveries/{uuid}/redeliver repository repoRedeliverHook
+	// ---
+	// summary: Deliver again a payload sent by a hook
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the delivery to send again
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.RedeliverHookTask(ctx, hook)
+}
+
 // DeleteHook delete a hook of a repository
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /repos/{user}/{repo}/hooks/{id} repository repoDeleteHook
@@ -75,6 +75,107 @@ func ListIssues(ctx *context.APIContext) {
 	ctx.JSON(200, &apiIssues)
 }
 
+// SearchIssues search for issues across the repositories that the user has access to
+func SearchIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/issues/search issue issueSearchIssues
+	// ---
+	// summary: Search for issues across the repositories that the user has access to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: search string
+	//   type: string
+	// - name: state
+	//   in: query
+	//   description: whether issue is open or closed
+	//   type: string
+	// - name: labels
+	//   in: query
+	//   description: comma separated list of label names, the issues must have all of them
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	// - name: created_by
+	//   in: query
+	//   description: filter by the username of the issue author
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of requested issues
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	var isClosed util.OptionalBool
+	switch ctx.Query("state") {
+	case "closed":
+		isClosed = util.OptionalBoolTrue
+	case "all":
+		isClosed = util.OptionalBoolNone
+	default:
+		isClosed = util.OptionalBoolFalse
+	}
+
+	var isPull util.OptionalBool
+	switch ctx.Query("type") {
+	case "pulls":
+		isPull = util.OptionalBoolTrue
+	case "issues":
+		isPull = util.OptionalBoolFalse
+	default:
+		isPull = util.OptionalBoolNone
+	}
+
+	var labels []string
+	for _, label := range strings.Split(ctx.Query("labels"), ",") {
+		if label = strings.TrimSpace(label); len(label) > 0 {
+			labels = append(labels, label)
+		}
+	}
+
+	opts := &models.SearchIssuesOptions{
+		Keyword:  strings.Trim(ctx.Query("q"), " "),
+		Doer:     ctx.User,
+		IsClosed: isClosed,
+		IsPull:   isPull,
+		Labels:   labels,
+		Page:     ctx.QueryInt("page"),
+		PageSize: setting.UI.IssuePagingNum,
+	}
+	if createdBy := ctx.Query("created_by"); len(createdBy) > 0 {
+		poster, err := models.GetUserByName(createdBy)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "GetUserByName", err)
+			}
+			return
+		}
+		opts.PosterID = poster.ID
+	}
+
+	issues, count, err := models.SearchIssues(opts)
+	if err != nil {
+		ctx.Error(500, "SearchIssues", err)
+		return
+	}
+
+	apiIssues := make([]*api.Issue, len(issues))
+	for i := range issues {
+		apiIssues[i] = issues[i].APIFormat()
+	}
+
+	ctx.SetLinkHeader(int(count), setting.UI.IssuePagingNum)
+	ctx.JSON(200, &apiIssues)
+}
+
 // GetIssue get an issue of a repository
 func GetIssue(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues/{id} issue issueGetIssue
@@ -220,6 +321,8 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Issue"
+	//   "412":
+	//     "$ref": "#/responses/error"
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
@@ -280,6 +383,10 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	}
 	if form.State != nil {
 		if err = issue.ChangeStatus(ctx.User, ctx.Repo.Repository, api.StateClosed == api.StateType(*form.State)); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Error(412, "DependenciesLeft", "cannot close this issue because it still has open dependencies")
+				return
+			}
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
@@ -0,0 +1,324 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListIssueReactions list the reactions to an issue
+func ListIssueReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/reactions issue issueGetReactions
+	// ---
+	// summary: List the reactions to an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reactions, err := models.FindIssueReactions(issue)
+	if err != nil {
+		ctx.Error(500, "FindIssueReactions", err)
+		return
+	}
+	ctx.JSON(200, toAPIReactions(reactions))
+}
+
+// PostIssueReaction add a reaction to an issue
+func PostIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/reactions issue issuePostReaction
+	// ---
+	// summary: Add a reaction to an issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reaction, err := models.CreateIssueReaction(ctx.User, issue, form.Reaction)
+	if err != nil {
+		if models.IsErrForbiddenReaction(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "CreateIssueReaction", err)
+		}
+		return
+	}
+	ctx.JSON(201, reaction.APIFormat())
+}
+
+// DeleteIssueReaction remove a reaction from an issue
+func DeleteIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/reactions issue issueDeleteReaction
+	// ---
+	// summary: Remove a reaction from an issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteIssueReaction(ctx.User, issue, form.Reaction); err != nil {
+		ctx.Error(500, "DeleteIssueReaction", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListIssueCommentReactions list the reactions to an issue comment
+func ListIssueCommentReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issueGetCommentReactions
+	// ---
+	// summary: List the reactions to an issue comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reactions, err := models.FindCommentReactions(comment)
+	if err != nil {
+		ctx.Error(500, "FindCommentReactions", err)
+		return
+	}
+	ctx.JSON(200, toAPIReactions(reactions))
+}
+
+// PostIssueCommentReaction add a reaction to an issue comment
+func PostIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issuePostCommentReaction
+	// ---
+	// summary: Add a reaction to an issue comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reaction, err := models.CreateCommentReaction(ctx.User, comment, form.Reaction)
+	if err != nil {
+		if models.IsErrForbiddenReaction(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "CreateCommentReaction", err)
+		}
+		return
+	}
+	ctx.JSON(201, reaction.APIFormat())
+}
+
+// DeleteIssueCommentReaction remove a reaction from an issue comment
+func DeleteIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issueDeleteCommentReaction
+	// ---
+	// summary: Remove a reaction from an issue comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteCommentReaction(ctx.User, comment, form.Reaction); err != nil {
+		ctx.Error(500, "DeleteCommentReaction", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+func getReactionIssue(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetRawIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetRawIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// getReactionComment returns the comment of the context, which must belong to an issue of the repository
+func getReactionComment(ctx *context.APIContext) *models.Comment {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrCommentNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetCommentByID", err)
+		}
+		return nil
+	}
+
+	issue, err := models.GetIssueByID(comment.IssueID)
+	if err != nil {
+		ctx.Error(500, "GetIssueByID", err)
+		return nil
+	} else if issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.Status(404)
+		return nil
+	}
+	return comment
+}
+
+func toAPIReactions(reactions models.ReactionList) []*api.Reaction {
+	apiReactions := make([]*api.Reaction, len(reactions))
+	for i := range reactions {
+		apiReactions[i] = reactions[i].APIFormat()
+	}
+	return apiReactions
+}
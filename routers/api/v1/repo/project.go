@@ -0,0 +1,630 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// getProject returns the project board of the repository, or writes a 404 if it doesn't exist
+func getProject(ctx *context.APIContext) *models.Project {
+	project, err := models.GetProjectByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetProjectByRepoID", err)
+		}
+		return nil
+	}
+	return project
+}
+
+// getProjectColumn returns the column of a project board, or writes a 404 if it doesn't exist
+func getProjectColumn(ctx *context.APIContext, project *models.Project) *models.ProjectColumn {
+	column, err := models.GetProjectColumn(project.ID, ctx.ParamsInt64(":column"))
+	if err != nil {
+		if models.IsErrProjectColumnNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetProjectColumn", err)
+		}
+		return nil
+	}
+	return column
+}
+
+// ListProjects list the project boards of a repository
+func ListProjects(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects repository repoListProjects
+	// ---
+	// summary: List a repository's project boards
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: whether to list open, closed or all project boards, defaults to open
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectList"
+	var projects []*models.Project
+	for _, isClosed := range []bool{false, true} {
+		state := ctx.Query("state")
+		if state != "all" && isClosed != (state == "closed") {
+			continue
+		}
+		list, err := models.GetProjects(ctx.Repo.Repository.ID, 0, isClosed)
+		if err != nil {
+			ctx.Error(500, "GetProjects", err)
+			return
+		}
+		projects = append(projects, list...)
+	}
+
+	apiProjects := make([]*api.Project, len(projects))
+	for i := range projects {
+		if err := projects[i].LoadCreator(); err != nil {
+			ctx.Error(500, "LoadCreator", err)
+			return
+		}
+		apiProjects[i] = projects[i].APIFormat()
+	}
+	ctx.JSON(200, &apiProjects)
+}
+
+// GetProject get a project board of a repository
+func GetProject(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id} repository repoGetProject
+	// ---
+	// summary: Get a project board
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := project.LoadCreator(); err != nil {
+		ctx.Error(500, "LoadCreator", err)
+		return
+	}
+	ctx.JSON(200, project.APIFormat())
+}
+
+// CreateProject create a project board for a repository
+func CreateProject(ctx *context.APIContext, form api.CreateProjectOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects repository repoCreateProject
+	// ---
+	// summary: Create a project board
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Project"
+	project := &models.Project{
+		RepoID:      ctx.Repo.Repository.ID,
+		CreatorID:   ctx.User.ID,
+		Title:       form.Title,
+		Description: form.Description,
+	}
+	if err := models.NewProject(project); err != nil {
+		ctx.Error(500, "NewProject", err)
+		return
+	}
+
+	project, err := models.GetProjectByRepoID(ctx.Repo.Repository.ID, project.ID)
+	if err != nil {
+		ctx.Error(500, "GetProjectByRepoID", err)
+		return
+	}
+	project.Creator = ctx.User
+	ctx.JSON(201, project.APIFormat())
+}
+
+// EditProject modify a project board of a repository
+func EditProject(ctx *context.APIContext, form api.EditProjectOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/projects/{id} repository repoEditProject
+	// ---
+	// summary: Update a project board
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project to edit
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Title != nil || form.Description != nil {
+		if form.Title != nil && len(*form.Title) > 0 {
+			project.Title = *form.Title
+		}
+		if form.Description != nil {
+			project.Description = *form.Description
+		}
+		if err := models.UpdateProject(project); err != nil {
+			ctx.Error(500, "UpdateProject", err)
+			return
+		}
+	}
+	if form.State != nil {
+		if err := models.ChangeProjectStatus(project, *form.State == string(api.StateClosed)); err != nil {
+			ctx.Error(500, "ChangeProjectStatus", err)
+			return
+		}
+	}
+
+	if err := project.LoadCreator(); err != nil {
+		ctx.Error(500, "LoadCreator", err)
+		return
+	}
+	ctx.JSON(200, project.APIFormat())
+}
+
+// DeleteProject delete a project board of a repository
+func DeleteProject(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id} repository repoDeleteProject
+	// ---
+	// summary: Delete a project board together with its columns and cards
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project to delete
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	if err := models.DeleteProjectByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		ctx.Error(500, "DeleteProjectByRepoID", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListProjectColumns list the columns of a project board in their order
+func ListProjectColumns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/columns repository repoListProjectColumns
+	// ---
+	// summary: List a project board's columns
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectColumnList"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	columns, err := project.GetColumns()
+	if err != nil {
+		ctx.Error(500, "GetColumns", err)
+		return
+	}
+	apiColumns := make([]*api.ProjectColumn, len(columns))
+	for i := range columns {
+		apiColumns[i] = columns[i].APIFormat()
+	}
+	ctx.JSON(200, &apiColumns)
+}
+
+// CreateProjectColumn add a column at the end of a project board
+func CreateProjectColumn(ctx *context.APIContext, form api.CreateProjectColumnOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects/{id}/columns repository repoCreateProjectColumn
+	// ---
+	// summary: Add a column at the end of a project board
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectColumnOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectColumn"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	column := &models.ProjectColumn{
+		ProjectID: project.ID,
+		Title:     form.Title,
+	}
+	if err := models.NewProjectColumn(column); err != nil {
+		ctx.Error(500, "NewProjectColumn", err)
+		return
+	}
+	ctx.JSON(201, column.APIFormat())
+}
+
+// EditProjectColumn rename a column of a project board or move it to another position
+func EditProjectColumn(ctx *context.APIContext, form api.EditProjectColumnOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/projects/{id}/columns/{column} repository repoEditProjectColumn
+	// ---
+	// summary: Rename a column of a project board or move it to another position
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// - name: column
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectColumnOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectColumn"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	column := getProjectColumn(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Title != nil && len(*form.Title) > 0 {
+		column.Title = *form.Title
+		if err := models.UpdateProjectColumn(column); err != nil {
+			ctx.Error(500, "UpdateProjectColumn", err)
+			return
+		}
+	}
+	if form.Sorting != nil {
+		if err := models.MoveProjectColumn(column, *form.Sorting); err != nil {
+			ctx.Error(500, "MoveProjectColumn", err)
+			return
+		}
+	}
+	ctx.JSON(200, column.APIFormat())
+}
+
+// DeleteProjectColumn delete a column of a project board together with its cards
+func DeleteProjectColumn(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id}/columns/{column} repository repoDeleteProjectColumn
+	// ---
+	// summary: Delete a column of a project board together with its cards
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// - name: column
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	column := getProjectColumn(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectColumn(column); err != nil {
+		ctx.Error(500, "DeleteProjectColumn", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListProjectCards list the cards of a project board
+func ListProjectCards(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/cards repository repoListProjectCards
+	// ---
+	// summary: List a project board's cards
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectCardList"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	cards, err := project.GetCards()
+	if err != nil {
+		ctx.Error(500, "GetCards", err)
+		return
+	}
+	apiCards := make([]*api.ProjectCard, 0, len(cards))
+	for _, card := range cards {
+		if card.Issue != nil {
+			apiCards = append(apiCards, card.APIFormat())
+		}
+	}
+	ctx.JSON(200, &apiCards)
+}
+
+// MoveProjectCard place an issue on a project board or move its card to another position
+func MoveProjectCard(ctx *context.APIContext, form api.MoveProjectCardOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects/{id}/cards repository repoMoveProjectCard
+	// ---
+	// summary: Place an issue on a project board or move its card to another position
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MoveProjectCardOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectCard"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	column, err := models.GetProjectColumn(project.ID, form.ColumnID)
+	if err != nil {
+		if models.IsErrProjectColumnNotExist(err) {
+			ctx.Error(422, "GetProjectColumn", err)
+		} else {
+			ctx.Error(500, "GetProjectColumn", err)
+		}
+		return
+	}
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, form.Issue)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Error(422, "GetIssueByIndex", err)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	card, err := models.MoveIssueToProjectColumn(issue, column, form.Sorting)
+	if err != nil {
+		ctx.Error(500, "MoveIssueToProjectColumn", err)
+		return
+	}
+	ctx.JSON(200, card.APIFormat())
+}
+
+// DeleteProjectCard remove an issue from a project board
+func DeleteProjectCard(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id}/cards/{index} repository repoDeleteProjectCard
+	// ---
+	// summary: Remove an issue from a project board
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue to remove
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetRawIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetRawIssueByIndex", err)
+		}
+		return
+	}
+
+	if err = models.RemoveIssueFromProject(project, issue); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "RemoveIssueFromProject", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 
@@ -302,6 +303,8 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/PullRequest"
+	//   "412":
+	//     "$ref": "#/responses/error"
 	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrPullRequestNotExist(err) {
@@ -365,6 +368,10 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 	}
 	if form.State != nil {
 		if err = issue.ChangeStatus(ctx.User, ctx.Repo.Repository, api.StateClosed == api.StateType(*form.State)); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Error(412, "DependenciesLeft", "cannot close this pull request because it still has open dependencies")
+				return
+			}
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
@@ -434,7 +441,7 @@ func IsPullRequestMerged(ctx *context.APIContext) {
 }
 
 // MergePullRequest merges a PR given an index
-func MergePullRequest(ctx *context.APIContext) {
+func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge repository repoMergePullRequest
 	// ---
 	// summary: Merge a pull request
@@ -456,11 +463,17 @@ func MergePullRequest(ctx *context.APIContext) {
 	//   description: index of the pull request to merge
 	//   type: integer
 	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MergePullRequestOption"
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/empty"
 	//   "405":
 	//     "$ref": "#/responses/empty"
+	//   "412":
+	//     "$ref": "#/responses/error"
 	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrPullRequestNotExist(err) {
@@ -497,7 +510,32 @@ func MergePullRequest(ctx *context.APIContext) {
 		return
 	}
 
-	if err := pr.Merge(ctx.User, ctx.Repo.GitRepo); err != nil {
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		switch models.MergeStyle(form.Do) {
+		case models.MergeStyleMerge, models.MergeStyleRebaseMerge:
+			message = pr.GetDefaultMergeMessage()
+		case models.MergeStyleSquash:
+			message = pr.GetDefaultSquashMessage()
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	if err := pr.Merge(ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
+		if models.IsErrInvalidMergeStyle(err) {
+			ctx.Status(405)
+			return
+		} else if models.IsErrNotAllowedToMerge(err) {
+			ctx.Error(405, "Merge", err)
+			return
+		} else if models.IsErrDependenciesLeft(err) {
+			ctx.Error(412, "DependenciesLeft", "cannot merge pull request because it has open dependencies")
+			return
+		}
 		ctx.Error(500, "Merge", err)
 		return
 	}
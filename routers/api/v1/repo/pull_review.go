@@ -0,0 +1,381 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// ListPullReviews lists all reviews of a pull request
+func ListPullReviews(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews repository repoListPullReviews
+	// ---
+	// summary: List all reviews for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reviews, err := models.GetVisibleReviewsByIssueID(issue.ID, ctx.User)
+	if err != nil {
+		ctx.Error(500, "GetVisibleReviewsByIssueID", err)
+		return
+	}
+
+	apiReviews := make([]*api.PullReview, len(reviews))
+	for i := range reviews {
+		apiReviews[i] = reviews[i].APIFormat()
+	}
+	ctx.JSON(200, &apiReviews)
+}
+
+// GetPullReview gets a specific review of a pull request
+func GetPullReview(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repository repoGetPullReview
+	// ---
+	// summary: Get a specific review for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	review := getPullReview(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// GetPullReviewComments lists all code comments of a pull request review
+func GetPullReviewComments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments repository repoGetPullReviewComments
+	// ---
+	// summary: Get the code comments of a pull review
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewCommentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	review := getPullReview(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	apiComments := make([]*api.PullReviewComment, len(review.CodeComments))
+	for i := range review.CodeComments {
+		apiComments[i] = review.CodeComments[i].APIFormatCodeComment()
+	}
+	ctx.JSON(200, &apiComments)
+}
+
+// CreatePullReview creates a review for a pull request
+func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews repository repoCreatePullReview
+	// ---
+	// summary: Create a review for a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreatePullReviewOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reviewType := models.ReviewTypeFromAPIState(opts.State)
+	if opts.State == api.ReviewStateUnknown {
+		reviewType = models.ReviewTypePending
+	}
+	if !checkReviewType(ctx, issue, reviewType) {
+		return
+	}
+
+	commitID := opts.CommitID
+	if len(commitID) == 0 {
+		var err error
+		if commitID, err = issue.PullRequest.GetHeadCommitID(); err != nil {
+			ctx.Error(500, "GetHeadCommitID", err)
+			return
+		}
+	}
+
+	review, err := models.GetOrCreatePendingReview(ctx.User, issue, commitID)
+	if err != nil {
+		ctx.Error(500, "GetOrCreatePendingReview", err)
+		return
+	}
+
+	for _, c := range opts.Comments {
+		line := c.NewLineNum
+		if c.OldLineNum > 0 {
+			line = c.OldLineNum * -1
+		}
+		if _, err = models.CreateCodeComment(ctx.User, ctx.Repo.Repository, issue, c.Body, c.Path, line, review.ID, commitID); err != nil {
+			ctx.Error(500, "CreateCodeComment", err)
+			return
+		}
+	}
+
+	if reviewType != models.ReviewTypePending {
+		if review, _, err = models.SubmitReview(ctx.User, issue, reviewType, opts.Body, commitID); err != nil {
+			if models.IsErrReviewEmpty(err) {
+				ctx.Error(422, "", err)
+				return
+			}
+			ctx.Error(500, "SubmitReview", err)
+			return
+		}
+		notification.Service.NotifyIssue(issue, ctx.User.ID)
+	}
+
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// SubmitPullReview submits a pending review of a pull request
+func SubmitPullReview(ctx *context.APIContext, opts api.SubmitPullReviewOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repository repoSubmitPullReview
+	// ---
+	// summary: Submit a pending review to a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/SubmitPullReviewOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	review := getPullReview(ctx)
+	if ctx.Written() {
+		return
+	}
+	if review.Type != models.ReviewTypePending || review.ReviewerID != ctx.User.ID {
+		ctx.Error(422, "", "only your own pending reviews can be submitted")
+		return
+	}
+
+	reviewType := models.ReviewTypeFromAPIState(opts.State)
+	if reviewType == models.ReviewTypePending || reviewType == models.ReviewTypeUnknown {
+		ctx.Error(422, "", "invalid review state")
+		return
+	}
+	if !checkReviewType(ctx, review.Issue, reviewType) {
+		return
+	}
+
+	review, _, err := models.SubmitReview(ctx.User, review.Issue, reviewType, opts.Body, "")
+	if err != nil {
+		if models.IsErrReviewEmpty(err) {
+			ctx.Error(422, "", err)
+			return
+		}
+		ctx.Error(500, "SubmitReview", err)
+		return
+	}
+	notification.Service.NotifyIssue(review.Issue, ctx.User.ID)
+
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// getPullIssue returns the issue of the pull request given by the :index parameter
+func getPullIssue(ctx *context.APIContext) *models.Issue {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(500, "LoadIssue", err)
+		return nil
+	}
+	if err = pr.Issue.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return nil
+	}
+	return pr.Issue
+}
+
+// getPullReview returns the review given by the :id parameter if it is visible to the doer
+func getPullReview(ctx *context.APIContext) *models.Review {
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return nil
+	}
+
+	review, err := models.GetReviewByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrReviewNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetReviewByID", err)
+		}
+		return nil
+	}
+	if review.IssueID != issue.ID ||
+		(review.Type == models.ReviewTypePending && (ctx.User == nil || review.ReviewerID != ctx.User.ID)) {
+		ctx.Status(404)
+		return nil
+	}
+
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return nil
+	}
+	return review
+}
+
+// checkReviewType makes sure the poster of a pull request does not approve or reject it
+func checkReviewType(ctx *context.APIContext, issue *models.Issue, reviewType models.ReviewType) bool {
+	if reviewType == models.ReviewTypeUnknown {
+		ctx.Error(422, "", "invalid review state")
+		return false
+	}
+	if (reviewType == models.ReviewTypeApprove || reviewType == models.ReviewTypeReject) && issue.IsPoster(ctx.User.ID) {
+		ctx.Error(422, "", "approving or rejecting your own pull request is not allowed")
+		return false
+	}
+	return true
+}
@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/v1/convert"
@@ -32,6 +33,10 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: keyword
 	//   type: string
+	// - name: topic
+	//   in: query
+	//   description: search only for repos that have the given topic
+	//   type: string
 	// - name: uid
 	//   in: query
 	//   description: search only for repos that the user with the given id owns or contributes to
@@ -53,6 +58,11 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: if `uid` is given, search only for repos that the user owns
 	//   type: boolean
+	// - name: archived
+	//   in: query
+	//   description: show only archived, or only non-archived, repositories.
+	//                If not given, both are returned
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/SearchResults"
@@ -60,6 +70,7 @@ func Search(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 	opts := &models.SearchRepoOptions{
 		Keyword:     strings.Trim(ctx.Query("q"), " "),
+		Topic:       strings.Trim(ctx.Query("topic"), " "),
 		OwnerID:     ctx.QueryInt64("uid"),
 		Page:        ctx.QueryInt("page"),
 		PageSize:    convert.ToCorrectPageSize(ctx.QueryInt("limit")),
@@ -70,6 +81,10 @@ func Search(ctx *context.APIContext) {
 		opts.Collaborate = util.OptionalBoolFalse
 	}
 
+	if len(ctx.Query("archived")) > 0 {
+		opts.Archived = util.OptionalBoolOf(ctx.QueryBool("archived"))
+	}
+
 	var mode = ctx.Query("mode")
 	switch mode {
 	case "source":
@@ -253,6 +268,94 @@ func CreateOrgRepo(ctx *context.APIContext, opt api.CreateRepoOption) {
 	CreateUserRepo(ctx, org, opt)
 }
 
+// Generate create a repository using a template
+func Generate(ctx *context.APIContext, form api.GenerateRepoOption) {
+	// swagger:operation POST /repos/{template_owner}/{template_repo}/generate repository generateRepo
+	// ---
+	// summary: Create a repository using a template
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: template_owner
+	//   in: path
+	//   description: name of the template repository owner
+	//   type: string
+	//   required: true
+	// - name: template_repo
+	//   in: path
+	//   description: name of the template repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateRepoOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	templateRepo := ctx.Repo.Repository
+	if !templateRepo.IsTemplate {
+		ctx.Error(422, "", models.ErrRepoNotTemplate{ID: templateRepo.ID})
+		return
+	}
+
+	opts := models.GenerateRepoOptions{
+		Name:        form.Name,
+		Description: form.Description,
+		IsPrivate:   form.Private || setting.Repository.ForcePrivate,
+		GitContent:  form.GitContent,
+		Labels:      form.Labels,
+		Webhooks:    form.Webhooks,
+		Units:       form.Units,
+		Topics:      form.Topics,
+	}
+	if !opts.IsValid() {
+		ctx.Error(422, "", "must select at least one template item")
+		return
+	}
+
+	owner := ctx.User
+	if form.Owner != ctx.User.Name {
+		org, err := models.GetOrgByName(form.Owner)
+		if err != nil {
+			if models.IsErrOrgNotExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "GetOrgByName", err)
+			}
+			return
+		}
+
+		if !ctx.User.IsAdmin && !org.IsOwnedBy(ctx.User.ID) {
+			ctx.Error(403, "", "Given user is not owner of organization.")
+			return
+		}
+		owner = org
+	}
+
+	repo, err := models.GenerateRepository(ctx.User, owner, templateRepo, opts)
+	if err != nil {
+		if models.IsErrRepoAlreadyExist(err) ||
+			models.IsErrNameReserved(err) ||
+			models.IsErrNamePatternNotAllowed(err) ||
+			models.IsErrReachLimitOfRepo(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "GenerateRepository", err)
+		}
+		return
+	}
+
+	log.Trace("Repository generated [%d]: %s/%s", repo.ID, owner.Name, repo.Name)
+	ctx.JSON(201, repo.APIFormat(models.AccessModeOwner))
+}
+
 // Migrate migrate remote git repository to gitea
 func Migrate(ctx *context.APIContext, form auth.MigrateRepoForm) {
 	// swagger:operation POST /repos/migrate repository repoMigrate
@@ -319,12 +422,19 @@ func Migrate(ctx *context.APIContext, form auth.MigrateRepoForm) {
 		return
 	}
 
-	repo, err := models.MigrateRepository(ctx.User, ctxUser, models.MigrateRepoOptions{
-		Name:        form.RepoName,
-		Description: form.Description,
-		IsPrivate:   form.Private || setting.Repository.ForcePrivate,
-		IsMirror:    form.Mirror,
-		RemoteAddr:  remoteAddr,
+	repo, err := migrations.MigrateRepository(ctx.User, ctxUser, migrations.MigrateOptions{
+		RemoteURL:    remoteAddr,
+		AuthUsername: form.AuthUsername,
+		AuthPassword: form.AuthPassword,
+		Name:         form.RepoName,
+		Description:  form.Description,
+		Private:      form.Private || setting.Repository.ForcePrivate,
+		Mirror:       form.Mirror,
+		Milestones:   form.Milestones,
+		Labels:       form.Labels,
+		Releases:     form.Releases,
+		Issues:       form.Issues,
+		PullRequests: form.PullRequests,
 	})
 	if err != nil {
 		if repo != nil {
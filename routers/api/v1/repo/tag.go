@@ -0,0 +1,245 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+)
+
+// toTag returns the API representation of the tag with the given name
+func toTag(ctx *context.APIContext, name string) (*api.Tag, error) {
+	gitRepo := ctx.Repo.GitRepo
+	repo := ctx.Repo.Repository
+
+	id, err := gitRepo.GetTagCommitID(name)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := gitutil.GetTagCommit(gitRepo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &api.Tag{
+		Name: name,
+		ID:   id,
+		Commit: &api.CommitMeta{
+			SHA: commit.ID.String(),
+			URL: gitObjectURL(repo, "commit", commit.ID.String()),
+		},
+		ZipballURL: fmt.Sprintf("%s/archive/%s.zip", repo.HTMLURL(), name),
+		TarballURL: fmt.Sprintf("%s/archive/%s.tar.gz", repo.HTMLURL(), name),
+	}
+	if id != tag.Commit.SHA {
+		annotated, err := gitutil.GetAnnotatedTag(gitRepo, id)
+		if err != nil {
+			return nil, err
+		}
+		tag.Message = annotated.Message
+	}
+	return tag, nil
+}
+
+// ListTags list all the tags of a repository
+func ListTags(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tags repository repoListTags
+	// ---
+	// summary: List a repository's tags
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagList"
+	if ctx.Repo.Repository.IsBare {
+		ctx.JSON(200, []*api.Tag{})
+		return
+	}
+
+	names, err := ctx.Repo.GitRepo.GetTags()
+	if err != nil {
+		ctx.Error(500, "GetTags", err)
+		return
+	}
+
+	apiTags := make([]*api.Tag, len(names))
+	for i, name := range names {
+		if apiTags[i], err = toTag(ctx, name); err != nil {
+			ctx.Error(500, "toTag", err)
+			return
+		}
+	}
+
+	ctx.JSON(200, &apiTags)
+}
+
+// GetTag get a tag of a repository
+func GetTag(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tags/{tag} repository repoGetTag
+	// ---
+	// summary: Get a tag of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: tag
+	//   in: path
+	//   description: name of the tag
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Tag"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	name := ctx.Params("*")
+	if ctx.Repo.Repository.IsBare || !ctx.Repo.GitRepo.IsTagExist(name) {
+		ctx.Status(404)
+		return
+	}
+
+	tag, err := toTag(ctx, name)
+	if err != nil {
+		ctx.Error(500, "toTag", err)
+		return
+	}
+	ctx.JSON(200, tag)
+}
+
+// CreateTag create a tag in a repository
+func CreateTag(ctx *context.APIContext, opt api.CreateTagOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/tags repository repoCreateTag
+	// ---
+	// summary: Create a tag, the tag is annotated if a message is given
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateTagOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Tag"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(403, "", "cannot create tags in a mirror")
+		return
+	}
+
+	commit, ok := getRefCommit(ctx, opt.Target)
+	if !ok {
+		return
+	}
+
+	if err := ctx.Repo.Repository.CreateTag(ctx.User, opt.TagName, commit.ID.String(), opt.Message); err != nil {
+		if models.IsErrTagAlreadyExists(err) {
+			ctx.Error(409, "", err)
+		} else if models.IsErrInvalidTagName(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "CreateTag", err)
+		}
+		return
+	}
+
+	tag, err := toTag(ctx, opt.TagName)
+	if err != nil {
+		ctx.Error(500, "toTag", err)
+		return
+	}
+	ctx.JSON(201, tag)
+}
+
+// DeleteTag delete a tag of a repository
+func DeleteTag(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/tags/{tag} repository repoDeleteTag
+	// ---
+	// summary: Delete a tag, a release of the tag becomes a draft
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: tag
+	//   in: path
+	//   description: name of the tag to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(403, "", "cannot delete tags of a mirror")
+		return
+	}
+
+	if err := ctx.Repo.Repository.DeleteTag(ctx.User, ctx.Params("*")); err != nil {
+		if models.IsErrTagNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "DeleteTag", err)
+		}
+		return
+	}
+
+	ctx.Status(204)
+}
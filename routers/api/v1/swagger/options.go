@@ -30,6 +30,8 @@ type swaggerParameterBodies struct {
 
 	IssueLabelsOption api.IssueLabelsOption
 
+	EditReactionOption api.EditReactionOption
+
 	CreateKeyOption api.CreateKeyOption
 
 	CreateLabelOption api.CreateLabelOption
@@ -40,6 +42,12 @@ type swaggerParameterBodies struct {
 	CreateMilestoneOption api.CreateMilestoneOption
 	EditMilestoneOption   api.EditMilestoneOption
 
+	CreateProjectOption       api.CreateProjectOption
+	EditProjectOption         api.EditProjectOption
+	CreateProjectColumnOption api.CreateProjectColumnOption
+	EditProjectColumnOption   api.EditProjectColumnOption
+	MoveProjectCardOption     api.MoveProjectCardOption
+
 	CreateOrgOption api.CreateOrgOption
 	EditOrgOption   api.EditOrgOption
 
@@ -49,8 +57,10 @@ type swaggerParameterBodies struct {
 	CreateReleaseOption api.CreateReleaseOption
 	EditReleaseOption   api.EditReleaseOption
 
-	CreateRepoOption api.CreateRepoOption
-	CreateForkOption api.CreateForkOption
+	CreateRepoOption   api.CreateRepoOption
+	CreateForkOption   api.CreateForkOption
+	GenerateRepoOption api.GenerateRepoOption
+	RepoTopicOptions   api.RepoTopicOptions
 
 	CreateStatusOption api.CreateStatusOption
 
@@ -63,4 +73,18 @@ type swaggerParameterBodies struct {
 	EditUserOption   api.EditUserOption
 
 	MigrateRepoForm auth.MigrateRepoForm
+
+	MergePullRequestOption auth.MergePullRequestForm
+
+	CreatePullReviewOptions api.CreatePullReviewOptions
+	SubmitPullReviewOptions api.SubmitPullReviewOptions
+
+	CreateFileOptions api.CreateFileOptions
+	UpdateFileOptions api.UpdateFileOptions
+	DeleteFileOptions api.DeleteFileOptions
+
+	CreateBranchOption           api.CreateBranchOption
+	CreateTagOption              api.CreateTagOption
+	CreateBranchProtectionOption api.CreateBranchProtectionOption
+	EditBranchProtectionOption   api.EditBranchProtectionOption
 }
@@ -68,6 +68,24 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// swagger:response PullReview
+type swaggerResponsePullReview struct {
+	// in:body
+	Body api.PullReview `json:"body"`
+}
+
+// swagger:response PullReviewList
+type swaggerResponsePullReviewList struct {
+	// in:body
+	Body []api.PullReview `json:"body"`
+}
+
+// swagger:response PullReviewCommentList
+type swaggerResponsePullReviewCommentList struct {
+	// in:body
+	Body []api.PullReviewComment `json:"body"`
+}
+
 // swagger:response Status
 type swaggerResponseStatus struct {
 	// in:body
@@ -90,3 +108,135 @@ type swaggerResponseWatchInfo struct {
 type swaggerResponseSearchResults struct {
 	Body api.SearchResults `json:"body"`
 }
+
+// swagger:response Project
+type swaggerResponseProject struct {
+	// in:body
+	Body api.Project `json:"body"`
+}
+
+// swagger:response ProjectList
+type swaggerResponseProjectList struct {
+	// in:body
+	Body []api.Project `json:"body"`
+}
+
+// swagger:response ProjectColumn
+type swaggerResponseProjectColumn struct {
+	// in:body
+	Body api.ProjectColumn `json:"body"`
+}
+
+// swagger:response ProjectColumnList
+type swaggerResponseProjectColumnList struct {
+	// in:body
+	Body []api.ProjectColumn `json:"body"`
+}
+
+// swagger:response ProjectCard
+type swaggerResponseProjectCard struct {
+	// in:body
+	Body api.ProjectCard `json:"body"`
+}
+
+// swagger:response ProjectCardList
+type swaggerResponseProjectCardList struct {
+	// in:body
+	Body []api.ProjectCard `json:"body"`
+}
+
+// swagger:response TopicListResponse
+type swaggerTopicListResponse struct {
+	// in: body
+	Body []api.TopicResponse `json:"body"`
+}
+
+// swagger:response TopicNames
+type swaggerTopicNames struct {
+	// in: body
+	Body api.TopicName `json:"body"`
+}
+
+// swagger:response CodeSearchResultList
+type swaggerCodeSearchResultList struct {
+	// in: body
+	Body []api.CodeSearchResult `json:"body"`
+}
+
+// swagger:response ContentsResponse
+type swaggerContentsResponse struct {
+	// in: body
+	Body api.ContentsResponse `json:"body"`
+}
+
+// swagger:response ContentsListResponse
+type swaggerContentsListResponse struct {
+	// in: body
+	Body []api.ContentsResponse `json:"body"`
+}
+
+// swagger:response FileResponse
+type swaggerFileResponse struct {
+	// in: body
+	Body api.FileResponse `json:"body"`
+}
+
+// swagger:response ReferenceList
+type swaggerReferenceList struct {
+	// in: body
+	Body []api.Reference `json:"body"`
+}
+
+// swagger:response GitTreeResponse
+type swaggerGitTreeResponse struct {
+	// in: body
+	Body api.GitTreeResponse `json:"body"`
+}
+
+// swagger:response GitBlobResponse
+type swaggerGitBlobResponse struct {
+	// in: body
+	Body api.GitBlobResponse `json:"body"`
+}
+
+// swagger:response GitCommit
+type swaggerGitCommit struct {
+	// in: body
+	Body api.GitCommit `json:"body"`
+}
+
+// swagger:response AnnotatedTag
+type swaggerAnnotatedTag struct {
+	// in: body
+	Body api.AnnotatedTag `json:"body"`
+}
+
+// swagger:response Tag
+type swaggerTag struct {
+	// in: body
+	Body api.Tag `json:"body"`
+}
+
+// swagger:response TagList
+type swaggerTagList struct {
+	// in: body
+	Body []api.Tag `json:"body"`
+}
+
+// swagger:response BranchProtection
+type swaggerBranchProtection struct {
+	// in: body
+	Body api.BranchProtection `json:"body"`
+}
+
+// swagger:response BranchProtectionList
+type swaggerBranchProtectionList struct {
+	// in: body
+	Body []api.BranchProtection `json:"body"`
+}
+
+// swagger:response BlameRangeList
+type swaggerBlameRangeList struct {
+	// in: body
+	Body []api.BlameRange `json:"body"`
+}
@@ -5,6 +5,8 @@
 package user
 
 import (
+	"time"
+
 	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/models"
@@ -29,14 +31,31 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{
-			Name: tokens[i].Name,
-			Sha1: tokens[i].Sha1,
-		}
+		apiTokens[i] = toAccessToken(tokens[i])
 	}
 	ctx.JSON(200, &apiTokens)
 }
 
+// toAccessToken converts an access token to its API format
+func toAccessToken(t *models.AccessToken) *api.AccessToken {
+	apiToken := &api.AccessToken{
+		Name: t.Name,
+		Sha1: t.Sha1,
+	}
+	for _, scope := range t.Scope.List() {
+		apiToken.Scopes = append(apiToken.Scopes, string(scope))
+	}
+	if t.CanExpire() {
+		expires := t.Expires
+		apiToken.ExpiresAt = &expires
+	}
+	if t.HasUsed {
+		lastUsed := t.LastUsed
+		apiToken.LastUsedAt = &lastUsed
+	}
+	return apiToken
+}
+
 // CreateAccessToken create access tokens
 func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption) {
 	// swagger:operation POST /users/{username}/tokens user userCreateToken
@@ -49,16 +68,33 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/AccessToken"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	scope := models.AccessTokenScopeAll
+	if len(form.Scopes) > 0 {
+		var err error
+		if scope, err = models.NewAccessTokenScope(form.Scopes); err != nil {
+			ctx.Error(422, "", err)
+			return
+		}
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: scope,
+	}
+	if form.ExpiresAt != nil {
+		if !form.ExpiresAt.After(time.Now()) {
+			ctx.Error(422, "", "expires_at must be in the future")
+			return
+		}
+		t.ExpiresUnix = form.ExpiresAt.Unix()
 	}
 	if err := models.NewAccessToken(t); err != nil {
 		ctx.Error(500, "NewAccessToken", err)
 		return
 	}
-	ctx.JSON(201, &api.AccessToken{
-		Name: t.Name,
-		Sha1: t.Sha1,
-	})
+	t.Expires = time.Unix(t.ExpiresUnix, 0).Local()
+	ctx.JSON(201, toAccessToken(t))
 }
@@ -0,0 +1,394 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"time"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+var notificationStatuses = map[string]models.NotificationStatus{
+	string(api.NotificationStatusUnread): models.NotificationStatusUnread,
+	string(api.NotificationStatusRead):   models.NotificationStatusRead,
+	string(api.NotificationStatusPinned): models.NotificationStatusPinned,
+}
+
+// parseTimeQuery parses an optional RFC 3339 timestamp query parameter, it
+// writes a 422 response if the timestamp is invalid
+func parseTimeQuery(ctx *context.APIContext, name string) (int64, bool) {
+	value := ctx.Query(name)
+	if len(value) == 0 {
+		return 0, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		ctx.Error(422, "", fmt.Sprintf("%s is not a RFC 3339 timestamp", name))
+		return 0, false
+	}
+	return t.Unix(), true
+}
+
+// toNotificationThread returns the API representation of a notification, or
+// nil if the repository or the issue of the notification is not accessible
+// by the user anymore
+func toNotificationThread(ctx *context.APIContext, n *models.Notification) (*api.NotificationThread, error) {
+	if err := n.LoadAttributes(); err != nil {
+		if models.IsErrRepoNotExist(err) || models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	mode, err := models.AccessLevel(ctx.User.ID, n.Repository)
+	if err != nil {
+		return nil, err
+	} else if mode < models.AccessModeRead {
+		return nil, nil
+	}
+	return convert.ToNotificationThread(n, mode), nil
+}
+
+func listNotifications(ctx *context.APIContext, repoID int64) {
+	opts := models.FindNotificationOptions{
+		UserID:   ctx.User.ID,
+		RepoID:   repoID,
+		Page:     ctx.QueryInt("page"),
+		PageSize: convert.ToCorrectPageSize(ctx.QueryInt("limit")),
+	}
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+
+	for _, name := range ctx.QueryStrings("status") {
+		status, ok := notificationStatuses[name]
+		if !ok {
+			ctx.Error(422, "", fmt.Sprintf("invalid notification status: %s", name))
+			return
+		}
+		opts.Status = append(opts.Status, status)
+	}
+	if len(opts.Status) == 0 {
+		opts.Status = []models.NotificationStatus{models.NotificationStatusUnread, models.NotificationStatusPinned}
+	}
+
+	var ok bool
+	if opts.UpdatedAfterUnix, ok = parseTimeQuery(ctx, "since"); !ok {
+		return
+	}
+
+	notifications, err := models.GetNotifications(opts)
+	if err != nil {
+		ctx.Error(500, "GetNotifications", err)
+		return
+	}
+
+	threads := make([]*api.NotificationThread, 0, len(notifications))
+	for _, n := range notifications {
+		thread, err := toNotificationThread(ctx, n)
+		if err != nil {
+			ctx.Error(500, "toNotificationThread", err)
+			return
+		} else if thread != nil {
+			threads = append(threads, thread)
+		}
+	}
+
+	ctx.JSON(200, &threads)
+}
+
+func readNotifications(ctx *context.APIContext, repoID int64) {
+	opts := models.FindNotificationOptions{
+		UserID: ctx.User.ID,
+		RepoID: repoID,
+		Status: []models.NotificationStatus{models.NotificationStatusUnread},
+	}
+
+	var ok bool
+	if opts.UpdatedBeforeUnix, ok = parseTimeQuery(ctx, "last_read_at"); !ok {
+		return
+	}
+
+	if err := models.UpdateNotificationStatuses(opts, models.NotificationStatusRead); err != nil {
+		ctx.Error(500, "UpdateNotificationStatuses", err)
+		return
+	}
+
+	ctx.Status(204)
+}
+
+// ListNotifications list the notifications of the authenticated user
+func ListNotifications(ctx *context.APIContext) {
+	// swagger:operation GET /notifications notification notifyGetList
+	// ---
+	// summary: List the notifications of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: statuses of the notifications to list, unread, read or
+	//                pinned. Defaults to unread and pinned notifications
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
+	// - name: since
+	//   in: query
+	//   description: only list the notifications updated at or after this time,
+	//                as a RFC 3339 timestamp
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of the results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	listNotifications(ctx, 0)
+}
+
+// ListRepoNotifications list the notifications of the authenticated user for
+// a repository
+func ListRepoNotifications(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/notifications notification notifyGetRepoList
+	// ---
+	// summary: List the notifications of the authenticated user for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: status
+	//   in: query
+	//   description: statuses of the notifications to list, unread, read or
+	//                pinned. Defaults to unread and pinned notifications
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
+	// - name: since
+	//   in: query
+	//   description: only list the notifications updated at or after this time,
+	//                as a RFC 3339 timestamp
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of the results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	listNotifications(ctx, ctx.Repo.Repository.ID)
+}
+
+// ReadNotifications mark the unread notifications of the authenticated user
+// as read
+func ReadNotifications(ctx *context.APIContext) {
+	// swagger:operation PUT /notifications notification notifyReadList
+	// ---
+	// summary: Mark the unread notifications of the authenticated user as read
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: last_read_at
+	//   in: query
+	//   description: only mark the notifications updated at or before this time
+	//                as read, as a RFC 3339 timestamp
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	readNotifications(ctx, 0)
+}
+
+// ReadRepoNotifications mark the unread notifications of the authenticated
+// user for a repository as read
+func ReadRepoNotifications(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/notifications notification notifyReadRepoList
+	// ---
+	// summary: Mark the unread notifications of the authenticated user for a
+	//          repository as read
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: last_read_at
+	//   in: query
+	//   description: only mark the notifications updated at or before this time
+	//                as read, as a RFC 3339 timestamp
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	readNotifications(ctx, ctx.Repo.Repository.ID)
+}
+
+// getNotification returns the notification of the authenticated user with
+// the ID of the path, it writes a 404 response if there is none
+func getNotification(ctx *context.APIContext) (*models.Notification, bool) {
+	n, err := models.GetNotificationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrNotificationNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetNotificationByID", err)
+		}
+		return nil, false
+	} else if n.UserID != ctx.User.ID {
+		ctx.Status(404)
+		return nil, false
+	}
+	return n, true
+}
+
+// writeNotificationThread writes the API representation of a notification
+func writeNotificationThread(ctx *context.APIContext, n *models.Notification) {
+	thread, err := toNotificationThread(ctx, n)
+	if err != nil {
+		ctx.Error(500, "toNotificationThread", err)
+		return
+	} else if thread == nil {
+		ctx.Status(404)
+		return
+	}
+	ctx.JSON(200, thread)
+}
+
+// GetNotificationThread get a notification of the authenticated user
+func GetNotificationThread(ctx *context.APIContext) {
+	// swagger:operation GET /notifications/threads/{id} notification notifyGetThread
+	// ---
+	// summary: Get a notification of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the notification
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThread"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	n, ok := getNotification(ctx)
+	if !ok {
+		return
+	}
+	writeNotificationThread(ctx, n)
+}
+
+// SetNotificationThreadStatus change the status of a notification of the
+// authenticated user
+func SetNotificationThreadStatus(ctx *context.APIContext) {
+	// swagger:operation PATCH /notifications/threads/{id} notification notifyReadThread
+	// ---
+	// summary: Mark a notification of the authenticated user as read, unread or
+	//          pinned
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the notification
+	//   type: integer
+	//   required: true
+	// - name: status
+	//   in: query
+	//   description: new status of the notification, unread, read or pinned.
+	//                Defaults to read
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThread"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	n, ok := getNotification(ctx)
+	if !ok {
+		return
+	}
+
+	status := models.NotificationStatusRead
+	if name := ctx.Query("status"); len(name) > 0 {
+		if status, ok = notificationStatuses[name]; !ok {
+			ctx.Error(422, "", fmt.Sprintf("invalid notification status: %s", name))
+			return
+		}
+	}
+
+	if err := models.SetNotificationStatus(n.ID, ctx.User, status); err != nil {
+		ctx.Error(500, "SetNotificationStatus", err)
+		return
+	}
+
+	if n, ok = getNotification(ctx); ok {
+		writeNotificationThread(ctx, n)
+	}
+}
+
+// CheckNotifications returns the number of unread notifications of the
+// authenticated user
+func CheckNotifications(ctx *context.APIContext) {
+	// swagger:operation GET /notifications/new notification notifyNewAvailable
+	// ---
+	// summary: Get the number of unread notifications of the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationCount"
+	count, err := models.GetNotificationCount(ctx.User, models.NotificationStatusUnread)
+	if err != nil {
+		ctx.Error(500, "GetNotificationCount", err)
+		return
+	}
+	ctx.JSON(200, &api.NotificationCount{New: count})
+}
@@ -45,6 +45,21 @@ func GetRepoHook(ctx *context.APIContext, repoID, hookID int64) (*models.Webhook
 	return w, nil
 }
 
+// GetSystemHook get a system webhook. If there is an error, write to `ctx`
+// accordingly and return the error
+func GetSystemHook(ctx *context.APIContext, hookID int64) (*models.Webhook, error) {
+	w, err := models.GetSystemWebhook(hookID)
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetSystemWebhook", err)
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
 // CheckCreateHookOption check if a CreateHookOption form is valid. If invalid,
 // write the appropriate error to `ctx`. Return whether the form is valid
 func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption) bool {
@@ -68,7 +83,7 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 // AddOrgHook add a hook to an organization. Writes to `ctx` accordingly
 func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	org := ctx.Org.Organization
-	hook, ok := addHook(ctx, form, org.ID, 0)
+	hook, ok := addHook(ctx, form, org.ID, 0, false)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(org.HomeLink(), hook))
 	}
@@ -77,15 +92,23 @@ func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 // AddRepoHook add a hook to a repo. Writes to `ctx` accordingly
 func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	repo := ctx.Repo
-	hook, ok := addHook(ctx, form, 0, repo.Repository.ID)
+	hook, ok := addHook(ctx, form, 0, repo.Repository.ID, false)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(repo.RepoLink, hook))
 	}
 }
 
-// addHook add the hook specified by `form`, `orgID` and `repoID`. If there is
-// an error, write to `ctx` accordingly. Return (webhook, ok)
-func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64) (*models.Webhook, bool) {
+// AddSystemHook add a system webhook. Writes to `ctx` accordingly
+func AddSystemHook(ctx *context.APIContext, form *api.CreateHookOption) {
+	hook, ok := addHook(ctx, form, 0, 0, true)
+	if ok {
+		ctx.JSON(http.StatusCreated, convert.ToHook("", hook))
+	}
+}
+
+// addHook add the hook specified by `form`, `orgID`, `repoID` and `isSystemWebhook`.
+// If there is an error, write to `ctx` accordingly. Return (webhook, ok)
+func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64, isSystemWebhook bool) (*models.Webhook, bool) {
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
@@ -98,13 +121,24 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		HookEvent: &models.HookEvent{
 			ChooseEvents: true,
 			HookEvents: models.HookEvents{
-				Create:      com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
-				Push:        com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
-				PullRequest: com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				Create:            com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
+				Delete:            com.IsSliceContainsStr(form.Events, string(models.HookEventDelete)),
+				Fork:              com.IsSliceContainsStr(form.Events, string(models.HookEventFork)),
+				Issues:            com.IsSliceContainsStr(form.Events, string(models.HookEventIssues)),
+				IssueComment:      com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment)),
+				Push:              com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
+				PullRequest:       com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				PullRequestReview: com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequestReview)),
+				Release:           com.IsSliceContainsStr(form.Events, string(models.HookEventRelease)),
+				Repository:        com.IsSliceContainsStr(form.Events, string(models.HookEventRepository)),
+				User:              com.IsSliceContainsStr(form.Events, string(models.HookEventUser)),
+				Member:            com.IsSliceContainsStr(form.Events, string(models.HookEventMember)),
 			},
 		},
 		IsActive:     form.Active,
 		HookTaskType: models.ToHookTaskType(form.Type),
+
+		IsSystemWebhook: isSystemWebhook,
 	}
 	if w.HookTaskType == models.SLACK {
 		channel, ok := form.Config["channel"]
@@ -169,6 +203,38 @@ func EditRepoHook(ctx *context.APIContext, form *api.EditHookOption, hookID int6
 	ctx.JSON(200, convert.ToHook(repo.RepoLink, updated))
 }
 
+// EditSystemHook edit system webhook `w` according to `form`. Writes to `ctx` accordingly
+func EditSystemHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
+	hook, err := GetSystemHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	if !editHook(ctx, form, hook) {
+		return
+	}
+	updated, err := GetSystemHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	ctx.JSON(200, convert.ToHook("", updated))
+}
+
+// RedeliverHookTask queues a new delivery of the payload of the hook task
+// with the delivery UUID given in the path. Writes to `ctx` accordingly
+func RedeliverHookTask(ctx *context.APIContext, w *models.Webhook) {
+	t, err := models.ReplayHookTask(w, ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "ReplayHookTask", err)
+		}
+		return
+	}
+	go models.HookQueue.Add(t.RepoID)
+	ctx.Status(204)
+}
+
 // editHook edit the webhook `w` according to `form`. If an error occurs, write
 // to `ctx` accordingly and return the error. Return whether successful
 func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webhook) bool {
@@ -209,8 +275,17 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.SendEverything = false
 	w.ChooseEvents = true
 	w.Create = com.IsSliceContainsStr(form.Events, string(models.HookEventCreate))
+	w.Delete = com.IsSliceContainsStr(form.Events, string(models.HookEventDelete))
+	w.Fork = com.IsSliceContainsStr(form.Events, string(models.HookEventFork))
+	w.Issues = com.IsSliceContainsStr(form.Events, string(models.HookEventIssues))
+	w.IssueComment = com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment))
 	w.Push = com.IsSliceContainsStr(form.Events, string(models.HookEventPush))
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest))
+	w.PullRequestReview = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequestReview))
+	w.Release = com.IsSliceContainsStr(form.Events, string(models.HookEventRelease))
+	w.Repository = com.IsSliceContainsStr(form.Events, string(models.HookEventRepository))
+	w.User = com.IsSliceContainsStr(form.Events, string(models.HookEventUser))
+	w.Member = com.IsSliceContainsStr(form.Events, string(models.HookEventMember))
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(500, "UpdateEvent", err)
 		return false
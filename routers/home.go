@@ -11,6 +11,8 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/indexer"
+	"code.gitea.io/gitea/modules/search"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/user"
@@ -27,6 +29,10 @@ const (
 	tplExploreUsers base.TplName = "explore/users"
 	// tplExploreOrganizations explore organizations page template
 	tplExploreOrganizations base.TplName = "explore/organizations"
+	// tplExploreIssues explore issues page template
+	tplExploreIssues base.TplName = "explore/issues"
+	// tplExploreCode explore code page template
+	tplExploreCode base.TplName = "explore/code"
 )
 
 // Home render home page
@@ -106,6 +112,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	}
 
 	keyword := strings.Trim(ctx.Query("q"), " ")
+	topic := strings.Trim(ctx.Query("topic"), " ")
 
 	repos, count, err = models.SearchRepositoryByName(&models.SearchRepoOptions{
 		Page:      page,
@@ -113,6 +120,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		OrderBy:   orderBy,
 		Private:   opts.Private,
 		Keyword:   keyword,
+		Topic:     topic,
 		OwnerID:   opts.OwnerID,
 		AllPublic: true,
 	})
@@ -121,6 +129,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		return
 	}
 	ctx.Data["Keyword"] = keyword
+	ctx.Data["Topic"] = topic
 	ctx.Data["Total"] = count
 	ctx.Data["Page"] = paginater.New(int(count), opts.PageSize, page, 5)
 	ctx.Data["Repos"] = repos
@@ -223,6 +232,161 @@ func ExploreOrganizations(ctx *context.Context) {
 	}, tplExploreOrganizations)
 }
 
+// ExploreIssues render explore issues page, which searches the issues of all
+// repositories the user can read
+func ExploreIssues(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreIssues"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	var isClosed util.OptionalBool
+	state := ctx.Query("state")
+	if state == "closed" {
+		isClosed = util.OptionalBoolTrue
+	} else {
+		state = "open"
+		isClosed = util.OptionalBoolFalse
+	}
+
+	var labels []string
+	for _, label := range strings.Split(ctx.Query("labels"), ",") {
+		if label = strings.TrimSpace(label); len(label) > 0 {
+			labels = append(labels, label)
+		}
+	}
+
+	keyword := strings.Trim(ctx.Query("q"), " ")
+	posterName := strings.Trim(ctx.Query("poster"), " ")
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["State"] = state
+	ctx.Data["Labels"] = strings.Join(labels, ",")
+	ctx.Data["Poster"] = posterName
+
+	var poster *models.User
+	if len(posterName) > 0 {
+		var err error
+		poster, err = models.GetUserByName(posterName)
+		if err != nil && !models.IsErrUserNotExist(err) {
+			ctx.Handle(500, "GetUserByName", err)
+			return
+		}
+	}
+
+	var (
+		issues []*models.Issue
+		count  int64
+	)
+	if isKeywordValid(keyword) && (len(posterName) == 0 || poster != nil) {
+		opts := &models.SearchIssuesOptions{
+			Keyword:  keyword,
+			Doer:     ctx.User,
+			IsClosed: isClosed,
+			IsPull:   util.OptionalBoolFalse,
+			Labels:   labels,
+			Page:     page,
+			PageSize: setting.UI.IssuePagingNum,
+		}
+		if poster != nil {
+			opts.PosterID = poster.ID
+		}
+
+		var err error
+		issues, count, err = models.SearchIssues(opts)
+		if err != nil {
+			ctx.Handle(500, "SearchIssues", err)
+			return
+		}
+	}
+	ctx.Data["Issues"] = issues
+	ctx.Data["Total"] = count
+	ctx.Data["Page"] = paginater.New(int(count), setting.UI.IssuePagingNum, page, 5)
+
+	ctx.HTML(200, tplExploreIssues)
+}
+
+// ExploreCode render explore code page
+func ExploreCode(ctx *context.Context) {
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.Redirect(setting.AppSubURL+"/explore/repos", 302)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreCode"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	keyword := strings.TrimSpace(ctx.Query("q"))
+	ownerName := strings.TrimSpace(ctx.Query("owner"))
+	language := strings.ToLower(strings.TrimSpace(ctx.Query("language")))
+	pathPrefix := strings.TrimLeft(strings.TrimSpace(ctx.Query("path")), "/")
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["Owner"] = ownerName
+	ctx.Data["Language"] = language
+	ctx.Data["Path"] = pathPrefix
+	ctx.Data["Languages"] = indexer.Languages()
+
+	var ownerID int64
+	if len(ownerName) > 0 {
+		owner, err := models.GetUserByName(ownerName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Data["Page"] = paginater.New(0, setting.UI.RepoSearchPagingNum, page, 5)
+				ctx.HTML(200, tplExploreCode)
+			} else {
+				ctx.Handle(500, "GetUserByName", err)
+			}
+			return
+		}
+		ownerID = owner.ID
+	}
+
+	repoIDs, err := models.GetReadableRepoIDs(ctx.User, ownerID)
+	if err != nil {
+		ctx.Handle(500, "GetReadableRepoIDs", err)
+		return
+	}
+
+	total, searchResults, err := search.PerformGlobalSearch(&indexer.RepoSearchOptions{
+		RepoIDs:    repoIDs,
+		Keyword:    keyword,
+		Language:   language,
+		PathPrefix: pathPrefix,
+		Page:       page,
+		PageSize:   setting.UI.RepoSearchPagingNum,
+	})
+	if err != nil {
+		ctx.Handle(500, "PerformGlobalSearch", err)
+		return
+	}
+
+	resultRepoIDs := make([]int64, 0, len(searchResults))
+	for _, result := range searchResults {
+		resultRepoIDs = append(resultRepoIDs, result.RepoID)
+	}
+	repos, err := models.GetRepositoriesMapByIDs(resultRepoIDs)
+	if err != nil {
+		ctx.Handle(500, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	ctx.Data["SearchResults"] = searchResults
+	ctx.Data["Repos"] = repos
+	ctx.Data["Total"] = total
+	ctx.Data["Page"] = paginater.New(total, setting.UI.RepoSearchPagingNum, page, 5)
+	ctx.Data["RequireHighlightJS"] = true
+	ctx.HTML(200, tplExploreCode)
+}
+
 // NotFound render 404 page
 func NotFound(ctx *context.Context) {
 	ctx.Data["Title"] = "Page Not Found"
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/migrations"
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/cron"
 	"code.gitea.io/gitea/modules/highlight"
@@ -61,6 +62,11 @@ func GlobalInit() {
 		}
 		models.HasEngine = true
 		models.InitOAuth2()
+		if setting.OAuth2.Enable {
+			if err := oauth2.InitSigningKey(setting.OAuth2.JWTSigningPrivateKeyFile); err != nil {
+				log.Fatal(4, "Failed to initialize OAuth2 JWT signing key: %v", err)
+			}
+		}
 
 		models.LoadRepoConfig()
 		models.NewRepoContext()
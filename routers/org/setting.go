@@ -23,6 +23,10 @@ const (
 	tplSettingsDelete base.TplName = "org/settings/delete"
 	// tplSettingsHooks template path for render hook settings
 	tplSettingsHooks base.TplName = "org/settings/hooks"
+	// tplSettingsApplications template path for render the oauth2 applications
+	tplSettingsApplications base.TplName = "org/settings/applications"
+	// tplSettingsOAuthApplicationEdit template path for render editing an oauth2 application
+	tplSettingsOAuthApplicationEdit base.TplName = "org/settings/oauth2_application_edit"
 )
 
 // Settings render the main settings page
@@ -146,7 +150,7 @@ func SettingsDelete(ctx *context.Context) {
 func Webhooks(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("org.settings")
 	ctx.Data["PageIsSettingsHooks"] = true
-	ctx.Data["BaseLink"] = ctx.Org.OrgLink
+	ctx.Data["BaseLink"] = ctx.Org.OrgLink + "/settings/hooks"
 	ctx.Data["Description"] = ctx.Tr("org.settings.hooks_desc")
 
 	ws, err := models.GetWebhooksByOrgID(ctx.Org.Organization.ID)
@@ -171,3 +175,66 @@ func DeleteWebhook(ctx *context.Context) {
 		"redirect": ctx.Org.OrgLink + "/settings/hooks",
 	})
 }
+
+func orgOAuth2ApplicationHandlers(ctx *context.Context) *user.OAuth2ApplicationHandlers {
+	return &user.OAuth2ApplicationHandlers{
+		OwnerID:  ctx.Org.Organization.ID,
+		BasePath: ctx.Org.OrgLink + "/settings/applications",
+		TplList:  tplSettingsApplications,
+		TplEdit:  tplSettingsOAuthApplicationEdit,
+		LoadList: loadApplicationsData,
+	}
+}
+
+// loadApplicationsData loads the OAuth2 applications of the organization
+func loadApplicationsData(ctx *context.Context) bool {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	ctx.Data["OAuth2BasePath"] = ctx.Org.OrgLink + "/settings/applications"
+
+	apps, err := models.GetOAuth2ApplicationsByUserID(ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Handle(500, "GetOAuth2ApplicationsByUserID", err)
+		return false
+	}
+	ctx.Data["Applications"] = apps
+	return true
+}
+
+// Applications render the OAuth2 applications of the organization
+func Applications(ctx *context.Context) {
+	if !loadApplicationsData(ctx) {
+		return
+	}
+	ctx.HTML(200, tplSettingsApplications)
+}
+
+// OAuthApplicationsPost registers a new OAuth2 application of the organization
+func OAuthApplicationsPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	orgOAuth2ApplicationHandlers(ctx).CreateApplication(ctx, form)
+}
+
+// OAuthApplicationEdit shows an OAuth2 application of the organization
+func OAuthApplicationEdit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	orgOAuth2ApplicationHandlers(ctx).EditShow(ctx)
+}
+
+// OAuthApplicationEditPost updates an OAuth2 application of the organization
+func OAuthApplicationEditPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	orgOAuth2ApplicationHandlers(ctx).EditSave(ctx, form)
+}
+
+// OAuthApplicationRegenerateSecret replaces the client secret of an OAuth2
+// application of the organization
+func OAuthApplicationRegenerateSecret(ctx *context.Context) {
+	orgOAuth2ApplicationHandlers(ctx).RegenerateSecret(ctx)
+}
+
+// DeleteOAuthApplication deletes an OAuth2 application of the organization
+func DeleteOAuthApplication(ctx *context.Context) {
+	orgOAuth2ApplicationHandlers(ctx).DeleteApplication(ctx)
+}
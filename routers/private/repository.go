@@ -0,0 +1,28 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"code.gitea.io/gitea/models"
+
+	macaron "gopkg.in/macaron.v1"
+)
+
+// GetRepository returns the repository by ID
+func GetRepository(ctx *macaron.Context) {
+	repo, err := models.GetRepositoryByID(ctx.ParamsInt64(":rid"))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Error(404)
+			return
+		}
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(200, repo)
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	gotemplate "html/template"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplBlame base.TplName = "repo/blame"
+)
+
+// blamePart is a part of a blame with the user who authored its commit and
+// its lines rendered like in the file view
+type blamePart struct {
+	*gitutil.BlamePart
+	User     *models.User
+	LineNums gotemplate.HTML
+	Code     gotemplate.HTML
+}
+
+func renderBlamePart(part *gitutil.BlamePart) (lineNums, code gotemplate.HTML) {
+	var output bytes.Buffer
+	for i, line := range part.Lines {
+		line = gotemplate.HTMLEscapeString(line)
+		if i != len(part.Lines)-1 {
+			line += "\n"
+		}
+		n := part.StartLine + i
+		output.WriteString(fmt.Sprintf(`<li class="L%d" rel="L%d">%s</li>`, n, n, line))
+	}
+	code = gotemplate.HTML(output.String())
+
+	output.Reset()
+	for i := range part.Lines {
+		n := part.StartLine + i
+		output.WriteString(fmt.Sprintf(`<span id="L%d">%d</span>`, n, n))
+	}
+	return gotemplate.HTML(output.String()), code
+}
+
+// RefBlame render the blame of a file
+func RefBlame(ctx *context.Context) {
+	ctx.Data["PageIsViewCode"] = true
+	ctx.Data["IsBlame"] = true
+	ctx.Data["RequireHighlightJS"] = true
+
+	treePath := ctx.Repo.TreePath
+	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		ctx.NotFoundOrServerError("Repo.Commit.GetTreeEntryByPath", git.IsErrNotExist, err)
+		return
+	}
+
+	srcLink := ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL() + "/" + treePath
+	if entry.IsDir() || entry.IsSubModule() {
+		ctx.Redirect(srcLink)
+		return
+	}
+
+	blob := entry.Blob()
+	reader, err := blob.Data()
+	if err != nil {
+		ctx.Handle(500, "Data", err)
+		return
+	}
+	buf := make([]byte, 1024)
+	n, _ := reader.Read(buf)
+	if !base.IsTextFile(buf[:n]) {
+		ctx.Redirect(srcLink)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Repo.Repository.Owner.Name + "/" + ctx.Repo.Repository.Name + ": " + treePath
+	ctx.Data["FileSize"] = blob.Size()
+	ctx.Data["FileName"] = blob.Name()
+	ctx.Data["HighlightClass"] = highlight.FileNameToHighlightClass(blob.Name())
+	ctx.Data["RawFileLink"] = ctx.Repo.RepoLink + "/raw/" + ctx.Repo.BranchNameSubURL() + "/" + treePath
+	ctx.Data["FileLink"] = srcLink
+
+	treeNames := strings.Split(treePath, "/")
+	paths := make([]string, 0, len(treeNames))
+	for i := range treeNames {
+		paths = append(paths, strings.Join(treeNames[:i+1], "/"))
+	}
+	ctx.Data["TreeNames"] = treeNames
+	ctx.Data["Paths"] = paths
+	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL()
+
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		ctx.Data["IsFileTooLarge"] = true
+		ctx.HTML(200, tplBlame)
+		return
+	}
+
+	parts, err := gitutil.GetBlame(ctx.Repo.GitRepo, ctx.Repo.CommitID, treePath)
+	if err != nil {
+		ctx.Handle(500, "GetBlame", err)
+		return
+	}
+
+	emails := make(map[string]*models.User)
+	blameParts := make([]*blamePart, len(parts))
+	for i, part := range parts {
+		email := part.Commit.Author.Email
+		user, ok := emails[email]
+		if !ok {
+			user, _ = models.GetUserByEmail(email)
+			emails[email] = user
+		}
+		blameParts[i] = &blamePart{BlamePart: part, User: user}
+		blameParts[i].LineNums, blameParts[i].Code = renderBlamePart(part)
+	}
+	ctx.Data["BlameParts"] = blameParts
+
+	ctx.HTML(200, tplBlame)
+}
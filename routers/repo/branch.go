@@ -130,6 +130,12 @@ func deleteBranch(ctx *context.Context, branchName string) error {
 		log.Warn("AddDeletedBranch: %v", err)
 	}
 
+	if err := models.PrepareDeleteWebhooks(ctx.User, ctx.Repo.Repository, "branch", branchName); err != nil {
+		log.Error(4, "PrepareDeleteWebhooks: %v", err)
+	} else {
+		go models.HookQueue.Add(ctx.Repo.Repository.ID)
+	}
+
 	return nil
 }
 
@@ -148,7 +148,8 @@ func HTTP(ctx *context.Context) {
 				// Assume password is a token.
 				token, err := models.GetAccessTokenBySHA(authToken)
 				if err != nil {
-					if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
+					if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) ||
+						models.IsErrAccessTokenExpired(err) {
 						ctx.HandleText(http.StatusUnauthorized, "invalid credentials")
 					} else {
 						ctx.Handle(http.StatusInternalServerError, "GetAccessTokenBySha", err)
@@ -156,6 +157,15 @@ func HTTP(ctx *context.Context) {
 					return
 				}
 
+				requiredScope := models.AccessTokenScopeRepo
+				if isPull {
+					requiredScope = models.AccessTokenScopeRepoRead
+				}
+				if !token.Scope.Has(requiredScope) {
+					ctx.HandleText(http.StatusForbidden, "access token does not have the required scope")
+					return
+				}
+
 				if isUsernameToken {
 					authUser, err = models.GetUserByID(token.UID)
 					if err != nil {
@@ -167,9 +177,8 @@ func HTTP(ctx *context.Context) {
 					return
 				}
 
-				token.Updated = time.Now()
-				if err = models.UpdateAccessToken(token); err != nil {
-					ctx.Handle(http.StatusInternalServerError, "UpdateAccessToken", err)
+				if err = models.UpdateAccessTokenLastUsed(token); err != nil {
+					ctx.Handle(http.StatusInternalServerError, "UpdateAccessTokenLastUsed", err)
 				}
 			} else {
 				_, err = models.GetTwoFactorByUID(authUser.ID)
@@ -373,8 +382,8 @@ func serviceRPC(h serviceHandler, service string) {
 		}
 	}
 
-	// set this for allow pre-receive and post-receive execute
-	h.environ = append(h.environ, "SSH_ORIGINAL_COMMAND="+service)
+	// the hooks run "gitea hook", which must not try to start as a Windows service
+	h.environ = append(h.environ, "SKIP_MINWINSVC=1")
 
 	var stderr bytes.Buffer
 	cmd := exec.Command("git", service, "--stateless-rpc", h.dir)
@@ -81,7 +81,7 @@ func MustAllowPulls(ctx *context.Context) {
 	}
 
 	// User can send pull request if owns a forked repository.
-	if ctx.IsSigned && ctx.User.HasForkedRepo(ctx.Repo.Repository.ID) {
+	if ctx.IsSigned && !ctx.Repo.Repository.IsArchived && ctx.User.HasForkedRepo(ctx.Repo.Repository.ID) {
 		ctx.Repo.PullRequest.Allowed = true
 		ctx.Repo.PullRequest.HeadInfo = ctx.User.Name + ":" + ctx.Repo.BranchName
 	}
@@ -686,6 +686,95 @@ func ViewIssue(ctx *context.Context) {
 				ctx.Handle(500, "LoadAssignees", err)
 				return
 			}
+		} else if comment.Type == models.CommentTypeAddDependency || comment.Type == models.CommentTypeRemoveDependency {
+			if err = comment.LoadDependentIssue(); err != nil {
+				ctx.Handle(500, "LoadDependentIssue", err)
+				return
+			}
+			// Hide issues of other repositories the user can't read.
+			if comment.DependentIssue != nil {
+				canRead, err := canReadIssue(ctx, comment.DependentIssue)
+				if err != nil {
+					ctx.Handle(500, "canReadIssue", err)
+					return
+				} else if !canRead {
+					comment.DependentIssue = nil
+				}
+			}
+		} else if comment.Type == models.CommentTypeReview || comment.Type == models.CommentTypeCode {
+			if err = comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
+				ctx.Handle(500, "LoadReview", err)
+				return
+			}
+			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
+				ctx.Repo.Repository.ComposeMetas()))
+			if comment.Review == nil {
+				continue
+			}
+			for _, codeComment := range comment.Review.CodeComments {
+				codeComment.RenderedContent = string(markdown.Render([]byte(codeComment.Content), ctx.Repo.RepoLink,
+					ctx.Repo.Repository.ComposeMetas()))
+			}
+		}
+	}
+
+	if err = issue.LoadReactions(); err != nil {
+		ctx.Handle(500, "LoadReactions", err)
+		return
+	}
+
+	if ctx.Repo.Repository.IsDependenciesEnabled() {
+		ctx.Data["IsDependenciesEnabled"] = true
+		ctx.Data["CanCreateIssueDependencies"] = ctx.Repo.IsWriter()
+
+		blockedBy, err := issue.BlockedByDependencies()
+		if err != nil {
+			ctx.Handle(500, "BlockedByDependencies", err)
+			return
+		}
+		if ctx.Data["BlockedByDependencies"], err = filterReadableIssues(ctx, blockedBy); err != nil {
+			ctx.Handle(500, "filterReadableIssues", err)
+			return
+		}
+		// Closed dependencies don't block anymore, but they are still listed.
+		ctx.Data["HasOpenDependencies"] = false
+		for _, dep := range blockedBy {
+			if !dep.IsClosed {
+				ctx.Data["HasOpenDependencies"] = true
+				break
+			}
+		}
+
+		blocking, err := issue.BlockingDependencies()
+		if err != nil {
+			ctx.Handle(500, "BlockingDependencies", err)
+			return
+		}
+		if ctx.Data["BlockingDependencies"], err = filterReadableIssues(ctx, blocking); err != nil {
+			ctx.Handle(500, "filterReadableIssues", err)
+			return
+		}
+	}
+
+	if issue.IsPull {
+		// Code comments of submitted reviews are shown together with their review,
+		// only single code comments get an entry of their own.
+		comments := make([]*models.Comment, 0, len(issue.Comments))
+		for _, comment := range issue.Comments {
+			if comment.Type == models.CommentTypeReview && comment.Review == nil {
+				continue
+			}
+			if comment.Type == models.CommentTypeCode &&
+				(comment.Review == nil || comment.Review.Type != models.ReviewTypeComment || hasReviewComment(issue.Comments, comment.ReviewID)) {
+				continue
+			}
+			comments = append(comments, comment)
+		}
+		issue.Comments = comments
+
+		if ctx.Data["PullReviewers"], err = models.GetReviewersByIssueID(issue.ID); err != nil {
+			ctx.Handle(500, "GetReviewersByIssueID", err)
+			return
 		}
 	}
 
@@ -693,6 +782,33 @@ func ViewIssue(ctx *context.Context) {
 		pull := issue.PullRequest
 		canDelete := false
 
+		prUnit, err := ctx.Repo.Repository.GetUnit(models.UnitTypePullRequests)
+		if err != nil {
+			ctx.Handle(500, "GetUnit", err)
+			return
+		}
+		prConfig := prUnit.PullRequestsConfig()
+
+		// Check correct values and select default
+		if prConfig.AllowMerge {
+			ctx.Data["DefaultMergeStyle"] = models.MergeStyleMerge
+		} else if prConfig.AllowRebase {
+			ctx.Data["DefaultMergeStyle"] = models.MergeStyleRebase
+		} else if prConfig.AllowRebaseMerge {
+			ctx.Data["DefaultMergeStyle"] = models.MergeStyleRebaseMerge
+		} else if prConfig.AllowSquash {
+			ctx.Data["DefaultMergeStyle"] = models.MergeStyleSquash
+		} else {
+			ctx.Data["DefaultMergeStyle"] = ""
+		}
+
+		if !pull.HasMerged && ctx.Data["IsPullReuqestBroken"] == nil {
+			prepareProtectedBranchRules(ctx, pull)
+			if ctx.Written() {
+				return
+			}
+		}
+
 		if ctx.IsSigned {
 			if err := pull.GetHeadRepo(); err != nil {
 				log.Error(4, "GetHeadRepo: %v", err)
@@ -738,6 +854,16 @@ func GetActionIssue(ctx *context.Context) *models.Issue {
 	return issue
 }
 
+// hasReviewComment returns true if the review has been submitted with a comment of its own
+func hasReviewComment(comments []*models.Comment, reviewID int64) bool {
+	for _, comment := range comments {
+		if comment.Type == models.CommentTypeReview && comment.ReviewID == reviewID {
+			return true
+		}
+	}
+	return false
+}
+
 func getActionIssues(ctx *context.Context) []*models.Issue {
 	commaSeparatedIssueIDs := ctx.Query("issue_ids")
 	if len(commaSeparatedIssueIDs) == 0 {
@@ -894,6 +1020,10 @@ func UpdateIssueStatus(ctx *context.Context) {
 	}
 	for _, issue := range issues {
 		if err := issue.ChangeStatus(ctx.User, issue.Repo, isClosed); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_batch_close_blocked", issue.Index))
+				continue
+			}
 			ctx.Handle(500, "ChangeStatus", err)
 			return
 		}
@@ -957,6 +1087,14 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 			} else {
 				if err := issue.ChangeStatus(ctx.User, ctx.Repo.Repository, form.Status == "close"); err != nil {
 					log.Error(4, "ChangeStatus: %v", err)
+
+					if models.IsErrDependenciesLeft(err) {
+						if issue.IsPull {
+							ctx.Flash.Error(ctx.Tr("repo.issues.dependency.pr_close_blocked"))
+						} else {
+							ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_close_blocked"))
+						}
+					}
 				} else {
 					log.Trace("Issue [%d] status changed to closed: %v", issue.ID, issue.IsClosed)
 
@@ -1009,6 +1147,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		return
 	}
 
+	oldContent := comment.Content
 	comment.Content = ctx.Query("content")
 	if len(comment.Content) == 0 {
 		ctx.JSON(200, map[string]interface{}{
@@ -1016,7 +1155,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		})
 		return
 	}
-	if err = models.UpdateComment(comment); err != nil {
+	if err = models.UpdateComment(ctx.User, comment, oldContent); err != nil {
 		ctx.Handle(500, "UpdateComment", err)
 		return
 	}
@@ -1042,7 +1181,7 @@ func DeleteComment(ctx *context.Context) {
 		return
 	}
 
-	if err = models.DeleteComment(comment); err != nil {
+	if err = models.DeleteComment(ctx.User, comment); err != nil {
 		ctx.Handle(500, "DeleteCommentByID", err)
 		return
 	}
@@ -1050,6 +1189,104 @@ func DeleteComment(ctx *context.Context) {
 	ctx.Status(200)
 }
 
+// ChangeIssueReaction adds or removes a reaction of the user to an issue
+func ChangeIssueReaction(ctx *context.Context, form auth.ReactionForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/issues/%d", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	switch ctx.Params(":action") {
+	case "react":
+		if _, err := models.CreateIssueReaction(ctx.User, issue, form.Content); err != nil {
+			if models.IsErrForbiddenReaction(err) {
+				ctx.Flash.Error(ctx.Tr("repo.issues.reaction.not_allowed"))
+				ctx.Redirect(issueLink)
+				return
+			}
+			ctx.Handle(500, "CreateIssueReaction", err)
+			return
+		}
+		log.Trace("Reaction for issue created: %d/%d", ctx.Repo.Repository.ID, issue.ID)
+	case "unreact":
+		if err := models.DeleteIssueReaction(ctx.User, issue, form.Content); err != nil {
+			ctx.Handle(500, "DeleteIssueReaction", err)
+			return
+		}
+		log.Trace("Reaction for issue removed: %d/%d", ctx.Repo.Repository.ID, issue.ID)
+	default:
+		ctx.Handle(404, fmt.Sprintf("Unknown action %s", ctx.Params(":action")), nil)
+		return
+	}
+
+	ctx.Redirect(issueLink)
+}
+
+// ChangeCommentReaction adds or removes a reaction of the user to a comment
+func ChangeCommentReaction(ctx *context.Context, form auth.ReactionForm) {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetCommentByID", models.IsErrCommentNotExist, err)
+		return
+	}
+
+	issue, err := models.GetIssueByID(comment.IssueID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return
+	} else if issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.Handle(404, "ChangeCommentReaction", nil)
+		return
+	}
+	if issue.IsPull && !ctx.Repo.Repository.UnitEnabled(models.UnitTypePullRequests) ||
+		!issue.IsPull && !ctx.Repo.Repository.UnitEnabled(models.UnitTypeIssues) {
+		ctx.Handle(404, "IssueOrPullRequestUnitNotAllowed", nil)
+		return
+	}
+
+	commentLink := fmt.Sprintf("%s/issues/%d#%s", ctx.Repo.RepoLink, issue.Index, comment.HashTag())
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(commentLink)
+		return
+	} else if comment.Type != models.CommentTypeComment && comment.Type != models.CommentTypeCode && comment.Type != models.CommentTypeReview {
+		ctx.Error(204)
+		return
+	}
+
+	switch ctx.Params(":action") {
+	case "react":
+		if _, err := models.CreateCommentReaction(ctx.User, comment, form.Content); err != nil {
+			if models.IsErrForbiddenReaction(err) {
+				ctx.Flash.Error(ctx.Tr("repo.issues.reaction.not_allowed"))
+				ctx.Redirect(commentLink)
+				return
+			}
+			ctx.Handle(500, "CreateCommentReaction", err)
+			return
+		}
+		log.Trace("Reaction for comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
+	case "unreact":
+		if err := models.DeleteCommentReaction(ctx.User, comment, form.Content); err != nil {
+			ctx.Handle(500, "DeleteCommentReaction", err)
+			return
+		}
+		log.Trace("Reaction for comment removed: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
+	default:
+		ctx.Handle(404, fmt.Sprintf("Unknown action %s", ctx.Params(":action")), nil)
+		return
+	}
+
+	ctx.Redirect(commentLink)
+}
+
 // Milestones render milestones page
 func Milestones(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.milestones")
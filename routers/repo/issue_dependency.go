@@ -0,0 +1,179 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// canReadIssue returns true if the signed in user can read the issue.
+func canReadIssue(ctx *context.Context, issue *models.Issue) (bool, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return false, err
+	}
+	if issue.IsPull && !issue.Repo.UnitEnabled(models.UnitTypePullRequests) ||
+		!issue.IsPull && !issue.Repo.UnitEnabled(models.UnitTypeIssues) {
+		return false, nil
+	}
+
+	var userID int64
+	if ctx.IsSigned {
+		userID = ctx.User.ID
+	}
+	return models.HasAccess(userID, issue.Repo, models.AccessModeRead)
+}
+
+// filterReadableIssues removes the issues the signed in user can't read.
+func filterReadableIssues(ctx *context.Context, issues []*models.Issue) ([]*models.Issue, error) {
+	readable := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		canRead, err := canReadIssue(ctx, issue)
+		if err != nil {
+			return nil, err
+		} else if canRead {
+			readable = append(readable, issue)
+		}
+	}
+	return readable, nil
+}
+
+// getDependencyIssue returns the issue referenced by ref, which is either
+// "#index" for an issue of the current repository or "owner/repo#index".
+func getDependencyIssue(ctx *context.Context, ref string) (*models.Issue, error) {
+	ref = strings.TrimSpace(ref)
+	poundIndex := strings.IndexByte(ref, '#')
+
+	repo := ctx.Repo.Repository
+	if poundIndex > 0 {
+		slashIndex := strings.IndexByte(ref, '/')
+		if slashIndex < 0 || slashIndex >= poundIndex {
+			return nil, models.ErrIssueNotExist{}
+		}
+
+		var err error
+		repo, err = models.GetRepositoryByOwnerAndName(ref[:slashIndex], ref[slashIndex+1:poundIndex])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				return nil, models.ErrIssueNotExist{}
+			}
+			return nil, err
+		}
+	}
+
+	index, err := strconv.ParseInt(ref[poundIndex+1:], 10, 64)
+	if err != nil {
+		return nil, models.ErrIssueNotExist{}
+	}
+	issue, err := models.GetIssueByIndex(repo.ID, index)
+	if err != nil {
+		return nil, err
+	}
+	issue.Repo = repo
+	return issue, nil
+}
+
+// AddDependency adds an issue the current issue depends on
+func AddDependency(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.Repo.IsWriter() || !ctx.Repo.Repository.IsDependenciesEnabled() {
+		ctx.Handle(403, "AddDependency", nil)
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/issues/%d", ctx.Repo.RepoLink, issue.Index)
+	dep, err := getDependencyIssue(ctx, ctx.Query("dependency"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_exist"))
+			ctx.Redirect(issueLink)
+			return
+		}
+		ctx.Handle(500, "getDependencyIssue", err)
+		return
+	}
+
+	// The dependency has to be an issue the user can read, outside of the
+	// repository only if cross repository dependencies are allowed.
+	if dep.RepoID != issue.RepoID && !setting.Service.AllowCrossRepositoryDependencies {
+		ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_same_repo"))
+		ctx.Redirect(issueLink)
+		return
+	}
+	canRead, err := canReadIssue(ctx, dep)
+	if err != nil {
+		ctx.Handle(500, "canReadIssue", err)
+		return
+	} else if !canRead {
+		ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_exist"))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	if err = models.CreateIssueDependency(ctx.User, issue, dep); err != nil {
+		if models.IsErrDependencyExists(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_exists"))
+		} else if models.IsErrCircularDependency(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_cannot_create_circular"))
+		} else {
+			ctx.Handle(500, "CreateIssueDependency", err)
+			return
+		}
+	}
+	ctx.Redirect(issueLink)
+}
+
+// RemoveDependency removes a dependency of the current issue, or a dependency of another issue on it
+func RemoveDependency(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.Repo.IsWriter() || !ctx.Repo.Repository.IsDependenciesEnabled() {
+		ctx.Handle(403, "RemoveDependency", nil)
+		return
+	}
+
+	var depType models.DependencyType
+	switch ctx.Query("dependencyType") {
+	case "blockedBy":
+		depType = models.DependencyTypeBlockedBy
+	case "blocking":
+		depType = models.DependencyTypeBlocking
+	default:
+		ctx.Handle(400, "RemoveDependency", fmt.Errorf("unknown dependency type %q", ctx.Query("dependencyType")))
+		return
+	}
+
+	dep, err := models.GetIssueByID(ctx.QueryInt64("removeDependencyID"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/issues/%d", ctx.Repo.RepoLink, issue.Index)
+	if depType == models.DependencyTypeBlocking {
+		issue, dep = dep, issue
+	}
+	if err = models.RemoveIssueDependency(ctx.User, issue, dep); err != nil {
+		if models.IsErrDependencyNotExists(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.remove_error_dep_not_exist"))
+		} else {
+			ctx.Handle(500, "RemoveIssueDependency", err)
+			return
+		}
+	}
+	ctx.Redirect(issueLink)
+}
@@ -0,0 +1,384 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"math"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/Unknwon/paginater"
+)
+
+const (
+	tplProjects    base.TplName = "repo/projects/list"
+	tplProjectsNew base.TplName = "repo/projects/new"
+	tplProjectView base.TplName = "repo/projects/view"
+)
+
+// MustEnableProjects check if projects are enabled in the repository
+func MustEnableProjects(ctx *context.Context) {
+	if !ctx.Repo.Repository.UnitEnabled(models.UnitTypeProjects) {
+		ctx.Handle(404, "MustEnableProjects", nil)
+		return
+	}
+}
+
+// Projects renders the project boards of a repository
+func Projects(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects")
+	ctx.Data["PageIsProjects"] = true
+
+	isShowClosed := ctx.Query("state") == "closed"
+	openCount, closedCount := models.ProjectStats(ctx.Repo.Repository.ID)
+	ctx.Data["OpenCount"] = openCount
+	ctx.Data["ClosedCount"] = closedCount
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	var total int
+	if !isShowClosed {
+		total = int(openCount)
+	} else {
+		total = int(closedCount)
+	}
+	ctx.Data["Page"] = paginater.New(total, setting.UI.IssuePagingNum, page, 5)
+
+	projects, err := models.GetProjects(ctx.Repo.Repository.ID, page, isShowClosed)
+	if err != nil {
+		ctx.Handle(500, "GetProjects", err)
+		return
+	}
+	for _, p := range projects {
+		p.RenderedDescription = string(markdown.Render([]byte(p.Description), ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas()))
+	}
+	ctx.Data["Projects"] = projects
+
+	if isShowClosed {
+		ctx.Data["State"] = "closed"
+	} else {
+		ctx.Data["State"] = "open"
+	}
+
+	ctx.Data["IsShowClosed"] = isShowClosed
+	ctx.HTML(200, tplProjects)
+}
+
+// NewProject renders the page to create a project board
+func NewProject(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["PageIsProjects"] = true
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// NewProjectPost creates a project board
+func NewProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["PageIsProjects"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	p := &models.Project{
+		RepoID:      ctx.Repo.Repository.ID,
+		CreatorID:   ctx.User.ID,
+		Title:       form.Title,
+		Description: form.Description,
+	}
+	if err := models.NewProject(p); err != nil {
+		ctx.Handle(500, "NewProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.create_success", form.Title))
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
+
+// getProject returns the project board of the current repository, or writes a 404 if it doesn't exist
+func getProject(ctx *context.Context) *models.Project {
+	p, err := models.GetProjectByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetProjectByRepoID", models.IsErrProjectNotExist, err)
+		return nil
+	}
+	return p
+}
+
+// getProjectColumn returns the column of a project board, or writes a 404 if it doesn't exist
+func getProjectColumn(ctx *context.Context, p *models.Project, id int64) *models.ProjectColumn {
+	c, err := models.GetProjectColumn(p.ID, id)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetProjectColumn", models.IsErrProjectColumnNotExist, err)
+		return nil
+	}
+	return c
+}
+
+// EditProject renders the page to edit a project board
+func EditProject(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["PageIsEditProject"] = true
+
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["title"] = p.Title
+	ctx.Data["description"] = p.Description
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// EditProjectPost changes the title and description of a project board
+func EditProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["PageIsEditProject"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	p.Title = form.Title
+	p.Description = form.Description
+	if err := models.UpdateProject(p); err != nil {
+		ctx.Handle(500, "UpdateProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.edit_success", p.Title))
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
+
+// ChangeProjectStatus opens or closes a project board
+func ChangeProjectStatus(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	isClosed := ctx.Params(":action") == "close"
+	if err := models.ChangeProjectStatus(p, isClosed); err != nil {
+		ctx.Handle(500, "ChangeProjectStatus", err)
+		return
+	}
+
+	if isClosed {
+		ctx.Redirect(ctx.Repo.RepoLink + "/projects?state=closed")
+	} else {
+		ctx.Redirect(ctx.Repo.RepoLink + "/projects?state=open")
+	}
+}
+
+// DeleteProject deletes a project board
+func DeleteProject(ctx *context.Context) {
+	if err := models.DeleteProjectByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteProjectByRepoID: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.projects.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/projects",
+	})
+}
+
+// ViewProject renders a project board with its columns and cards
+func ViewProject(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := p.LoadColumns(); err != nil {
+		ctx.Handle(500, "LoadColumns", err)
+		return
+	}
+	p.RenderedDescription = string(markdown.Render([]byte(p.Description), ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas()))
+
+	ctx.Data["Title"] = p.Title
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["Project"] = p
+	ctx.HTML(200, tplProjectView)
+}
+
+// NewProjectColumn adds a column at the end of a project board
+func NewProjectColumn(ctx *context.Context, form auth.ProjectColumnForm) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	projectLink := fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(projectLink)
+		return
+	}
+
+	if err := models.NewProjectColumn(&models.ProjectColumn{
+		ProjectID: p.ID,
+		Title:     form.Title,
+	}); err != nil {
+		ctx.Handle(500, "NewProjectColumn", err)
+		return
+	}
+	ctx.Redirect(projectLink)
+}
+
+// EditProjectColumn renames a column of a project board
+func EditProjectColumn(ctx *context.Context, form auth.ProjectColumnForm) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	c := getProjectColumn(ctx, p, ctx.ParamsInt64(":columnID"))
+	if ctx.Written() {
+		return
+	}
+
+	projectLink := fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(projectLink)
+		return
+	}
+
+	c.Title = form.Title
+	if err := models.UpdateProjectColumn(c); err != nil {
+		ctx.Handle(500, "UpdateProjectColumn", err)
+		return
+	}
+	ctx.Redirect(projectLink)
+}
+
+// MoveProjectColumn moves a column of a project board to another position
+func MoveProjectColumn(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	c := getProjectColumn(ctx, p, ctx.ParamsInt64(":columnID"))
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.MoveProjectColumn(c, ctx.QueryInt("sorting")); err != nil {
+		ctx.Handle(500, "MoveProjectColumn", err)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
+
+// DeleteProjectColumn deletes a column of a project board together with its cards
+func DeleteProjectColumn(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	c := getProjectColumn(ctx, p, ctx.ParamsInt64(":columnID"))
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectColumn(c); err != nil {
+		ctx.Handle(500, "DeleteProjectColumn", err)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
+
+// AddProjectCard places an issue of the repository in a column of a project board
+func AddProjectCard(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	c := getProjectColumn(ctx, p, ctx.QueryInt64("column"))
+	if ctx.Written() {
+		return
+	}
+
+	projectLink := fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID)
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.QueryInt64("issue"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.projects.card.issue_not_exist"))
+			ctx.Redirect(projectLink)
+		} else {
+			ctx.Handle(500, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	// Positions past the end of the column are clamped, so the card is added last.
+	if _, err = models.MoveIssueToProjectColumn(issue, c, math.MaxInt32); err != nil {
+		ctx.Handle(500, "MoveIssueToProjectColumn", err)
+		return
+	}
+	ctx.Redirect(projectLink)
+}
+
+// MoveProjectCard moves the card of an issue to another position of a project board
+func MoveProjectCard(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	c := getProjectColumn(ctx, p, ctx.QueryInt64("column"))
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.QueryInt64("issue"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByIndex", models.IsErrIssueNotExist, err)
+		return
+	}
+
+	if _, err = models.MoveIssueToProjectColumn(issue, c, ctx.QueryInt("sorting")); err != nil {
+		ctx.Handle(500, "MoveIssueToProjectColumn", err)
+		return
+	}
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// RemoveProjectCard removes an issue from a project board
+func RemoveProjectCard(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.QueryInt64("issue"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByIndex", models.IsErrIssueNotExist, err)
+		return
+	}
+
+	if err = models.RemoveIssueFromProject(p, issue); err != nil && !models.IsErrProjectIssueNotExist(err) {
+		ctx.Handle(500, "RemoveIssueFromProject", err)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 
@@ -330,6 +331,43 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.PullReq
 	return prInfo
 }
 
+// prepareProtectedBranchRules shows which rules of the protected base branch the pull request does not meet yet
+func prepareProtectedBranchRules(ctx *context.Context, pull *models.PullRequest) {
+	protectBranch, err := pull.GetProtectedBranch()
+	if err != nil {
+		ctx.Handle(500, "GetProtectedBranch", err)
+		return
+	} else if protectBranch == nil {
+		return
+	}
+	ctx.Data["ProtectedBranch"] = protectBranch
+
+	approvals, err := protectBranch.GetGrantedApprovalsCount(pull)
+	if err != nil {
+		ctx.Handle(500, "GetGrantedApprovalsCount", err)
+		return
+	}
+	ctx.Data["GrantedApprovals"] = approvals
+	isBlocked := approvals < protectBranch.RequiredApprovals
+	ctx.Data["IsBlockedByApprovals"] = isBlocked
+
+	if protectBranch.EnableStatusCheck {
+		headCommitID, err := pull.GetHeadCommitID()
+		if err != nil {
+			ctx.Handle(500, "GetHeadCommitID", err)
+			return
+		}
+		unmet, err := protectBranch.GetUnmetStatusChecks(ctx.Repo.Repository, headCommitID)
+		if err != nil {
+			ctx.Handle(500, "GetUnmetStatusChecks", err)
+			return
+		}
+		ctx.Data["UnmetStatusChecks"] = unmet
+		isBlocked = isBlocked || len(unmet) > 0
+	}
+	ctx.Data["IsBlockedByProtectedBranch"] = isBlocked
+}
+
 // ViewPullCommits show commits for a pull request
 func ViewPullCommits(ctx *context.Context) {
 	ctx.Data["PageIsPullList"] = true
@@ -470,9 +508,40 @@ func ViewPullFiles(ctx *context.Context) {
 		ctx.Handle(500, "GetDiffRange", err)
 		return
 	}
+
+	if err = diff.LoadComments(issue, ctx.User); err != nil {
+		ctx.Handle(500, "LoadComments", err)
+		return
+	}
+	for _, file := range diff.Files {
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				for _, comment := range line.Comments {
+					comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
+						ctx.Repo.Repository.ComposeMetas()))
+				}
+			}
+		}
+	}
+
 	ctx.Data["Diff"] = diff
 	ctx.Data["DiffNotAvailable"] = diff.NumFiles() == 0
 
+	if ctx.IsSigned && !pull.HasMerged && !issue.IsClosed {
+		ctx.Data["CanAddCodeComment"] = true
+		currentReview, err := models.GetCurrentReview(ctx.User, issue)
+		if err != nil && !models.IsErrReviewNotExist(err) {
+			ctx.Handle(500, "GetCurrentReview", err)
+			return
+		} else if currentReview != nil {
+			if err = currentReview.LoadCodeComments(); err != nil {
+				ctx.Handle(500, "LoadCodeComments", err)
+				return
+			}
+		}
+		ctx.Data["CurrentReview"] = currentReview
+	}
+
 	commit, err := gitRepo.GetCommit(endCommitID)
 	if err != nil {
 		ctx.Handle(500, "GetCommit", err)
@@ -489,7 +558,7 @@ func ViewPullFiles(ctx *context.Context) {
 }
 
 // MergePullRequest response for merging pull request
-func MergePullRequest(ctx *context.Context) {
+func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 	issue := checkPullInfo(ctx)
 	if ctx.Written() {
 		return
@@ -516,7 +585,42 @@ func MergePullRequest(ctx *context.Context) {
 
 	pr.Issue = issue
 	pr.Issue.Repo = ctx.Repo.Repository
-	if err = pr.Merge(ctx.User, ctx.Repo.GitRepo); err != nil {
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		switch models.MergeStyle(form.Do) {
+		case models.MergeStyleMerge, models.MergeStyleRebaseMerge:
+			message = pr.GetDefaultMergeMessage()
+		case models.MergeStyleSquash:
+			message = pr.GetDefaultSquashMessage()
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	if err = pr.Merge(ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
+		if models.IsErrInvalidMergeStyle(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		} else if models.IsErrNotAllowedToMerge(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.protected_branch_rules_unmet"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		} else if models.IsErrDependenciesLeft(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.pr_close_blocked"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		}
 		ctx.Handle(500, "Merge", err)
 		return
 	}
@@ -984,5 +1088,11 @@ func CleanUpPullRequest(ctx *context.Context) {
 		log.Error(4, "DeleteBranch: %v", err)
 	}
 
+	if err := models.PrepareDeleteWebhooks(ctx.User, pr.HeadRepo, "branch", pr.HeadBranch); err != nil {
+		log.Error(4, "PrepareDeleteWebhooks: %v", err)
+	} else {
+		go models.HookQueue.Add(pr.HeadRepo.ID)
+	}
+
 	ctx.Flash.Success(ctx.Tr("repo.branch.deletion_success", fullBranchName))
 }
@@ -0,0 +1,129 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// CreateCodeComment will create a code comment including an pending review if required
+func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.Handle(404, "CreateCodeComment", nil)
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		return
+	}
+
+	commitID, err := issue.PullRequest.GetHeadCommitID()
+	if err != nil {
+		ctx.Handle(500, "GetHeadCommitID", err)
+		return
+	}
+
+	var review *models.Review
+	if form.IsReview {
+		review, err = models.GetOrCreatePendingReview(ctx.User, issue, commitID)
+		if err != nil {
+			ctx.Handle(500, "GetOrCreatePendingReview", err)
+			return
+		}
+	} else {
+		// A single comment is published right away as a review of its own.
+		review, err = models.CreateReview(models.CreateReviewOptions{
+			Type:     models.ReviewTypeComment,
+			Issue:    issue,
+			Reviewer: ctx.User,
+			CommitID: commitID,
+		})
+		if err != nil {
+			ctx.Handle(500, "CreateReview", err)
+			return
+		}
+	}
+
+	line := form.Line
+	if form.Side == "previous" {
+		line *= -1
+	}
+
+	comment, err := models.CreateCodeComment(ctx.User, ctx.Repo.Repository, issue, form.Content, form.TreePath, line, review.ID, commitID)
+	if err != nil {
+		ctx.Handle(500, "CreateCodeComment", err)
+		return
+	}
+
+	if !form.IsReview {
+		notification.Service.NotifyIssue(issue, ctx.User.ID)
+	}
+
+	log.Trace("Code comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
+	ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files#%s", ctx.Repo.RepoLink, issue.Index, comment.HashTag()))
+}
+
+// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
+func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.Handle(404, "SubmitReview", nil)
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		return
+	}
+
+	reviewType := form.ReviewType()
+	if (reviewType == models.ReviewTypeApprove || reviewType == models.ReviewTypeReject) && issue.IsPoster(ctx.User.ID) {
+		translated := ctx.Tr("repo.issues.review.self.approval")
+		if reviewType == models.ReviewTypeReject {
+			translated = ctx.Tr("repo.issues.review.self.rejection")
+		}
+		ctx.Flash.Error(translated)
+		ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		return
+	}
+
+	commitID, err := issue.PullRequest.GetHeadCommitID()
+	if err != nil {
+		ctx.Handle(500, "GetHeadCommitID", err)
+		return
+	}
+
+	review, comment, err := models.SubmitReview(ctx.User, issue, reviewType, form.Content, commitID)
+	if err != nil {
+		if models.IsErrReviewEmpty(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.review.content.empty"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+			return
+		}
+		ctx.Handle(500, "SubmitReview", err)
+		return
+	}
+
+	notification.Service.NotifyIssue(issue, ctx.User.ID)
+
+	log.Trace("Review submitted: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, review.ID)
+	ctx.Redirect(fmt.Sprintf("%s/pulls/%d#%s", ctx.Repo.RepoLink, issue.Index, comment.HashTag()))
+}
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/setting"
 )
 
@@ -102,9 +103,51 @@ func Create(ctx *context.Context) {
 	}
 	ctx.Data["ContextUser"] = ctxUser
 
+	if !loadTemplateRepositories(ctx) {
+		return
+	}
+	ctx.Data["repo_template"] = ctx.QueryInt64("template_id")
+	ctx.Data["git_content"] = true
+
 	ctx.HTML(200, tplCreate)
 }
 
+// loadTemplateRepositories sets the template repositories the user can
+// generate a repository from, it returns false if the response was written.
+func loadTemplateRepositories(ctx *context.Context) bool {
+	templates, err := models.GetTemplateRepositories(ctx.User)
+	if err != nil {
+		ctx.Handle(500, "GetTemplateRepositories", err)
+		return false
+	}
+	ctx.Data["Templates"] = templates
+	return true
+}
+
+// getTemplateRepository returns the template repository of given ID if the
+// user can read it, it writes a 404 response otherwise.
+func getTemplateRepository(ctx *context.Context, id int64) *models.Repository {
+	templateRepo, err := models.GetRepositoryByID(id)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Handle(404, "GetRepositoryByID", err)
+		} else {
+			ctx.Handle(500, "GetRepositoryByID", err)
+		}
+		return nil
+	}
+
+	canRead, err := models.HasAccess(ctx.User.ID, templateRepo, models.AccessModeRead)
+	if err != nil {
+		ctx.Handle(500, "HasAccess", err)
+		return nil
+	} else if !canRead || !templateRepo.IsTemplate {
+		ctx.Handle(404, "getTemplateRepository", nil)
+		return nil
+	}
+	return templateRepo
+}
+
 func handleCreateError(ctx *context.Context, owner *models.User, err error, name string, tpl base.TplName, form interface{}) {
 	switch {
 	case models.IsErrReachLimitOfRepo(err):
@@ -137,20 +180,49 @@ func CreatePost(ctx *context.Context, form auth.CreateRepoForm) {
 	}
 	ctx.Data["ContextUser"] = ctxUser
 
+	if !loadTemplateRepositories(ctx) {
+		return
+	}
+
 	if ctx.HasError() {
 		ctx.HTML(200, tplCreate)
 		return
 	}
 
-	repo, err := models.CreateRepository(ctx.User, ctxUser, models.CreateRepoOptions{
-		Name:        form.RepoName,
-		Description: form.Description,
-		Gitignores:  form.Gitignores,
-		License:     form.License,
-		Readme:      form.Readme,
-		IsPrivate:   form.Private || setting.Repository.ForcePrivate,
-		AutoInit:    form.AutoInit,
-	})
+	var repo *models.Repository
+	var err error
+	if form.RepoTemplate > 0 {
+		templateRepo := getTemplateRepository(ctx, form.RepoTemplate)
+		if ctx.Written() {
+			return
+		}
+
+		opts := models.GenerateRepoOptions{
+			Name:        form.RepoName,
+			Description: form.Description,
+			IsPrivate:   form.Private || setting.Repository.ForcePrivate,
+			GitContent:  form.GitContent,
+			Labels:      form.Labels,
+			Webhooks:    form.Webhooks,
+			Units:       form.Units,
+			Topics:      form.Topics,
+		}
+		if !opts.IsValid() {
+			ctx.RenderWithErr(ctx.Tr("repo.template.one_item"), tplCreate, &form)
+			return
+		}
+		repo, err = models.GenerateRepository(ctx.User, ctxUser, templateRepo, opts)
+	} else {
+		repo, err = models.CreateRepository(ctx.User, ctxUser, models.CreateRepoOptions{
+			Name:        form.RepoName,
+			Description: form.Description,
+			Gitignores:  form.Gitignores,
+			License:     form.License,
+			Readme:      form.Readme,
+			IsPrivate:   form.Private || setting.Repository.ForcePrivate,
+			AutoInit:    form.AutoInit,
+		})
+	}
 	if err == nil {
 		log.Trace("Repository created [%d]: %s/%s", repo.ID, ctxUser.Name, repo.Name)
 		ctx.Redirect(setting.AppSubURL + "/" + ctxUser.Name + "/" + repo.Name)
@@ -219,12 +291,19 @@ func MigratePost(ctx *context.Context, form auth.MigrateRepoForm) {
 		return
 	}
 
-	repo, err := models.MigrateRepository(ctx.User, ctxUser, models.MigrateRepoOptions{
-		Name:        form.RepoName,
-		Description: form.Description,
-		IsPrivate:   form.Private || setting.Repository.ForcePrivate,
-		IsMirror:    form.Mirror,
-		RemoteAddr:  remoteAddr,
+	repo, err := migrations.MigrateRepository(ctx.User, ctxUser, migrations.MigrateOptions{
+		RemoteURL:    remoteAddr,
+		AuthUsername: form.AuthUsername,
+		AuthPassword: form.AuthPassword,
+		Name:         form.RepoName,
+		Description:  form.Description,
+		Private:      form.Private || setting.Repository.ForcePrivate,
+		Mirror:       form.Mirror,
+		Milestones:   form.Milestones,
+		Labels:       form.Labels,
+		Releases:     form.Releases,
+		Issues:       form.Issues,
+		PullRequests: form.PullRequests,
 	})
 	if err == nil {
 		log.Trace("Repository migrated [%d]: %s/%s", repo.ID, ctxUser.Name, form.RepoName)
@@ -243,7 +322,7 @@ func MigratePost(ctx *context.Context, form auth.MigrateRepoForm) {
 		ctx.Data["Err_Auth"] = true
 		ctx.RenderWithErr(ctx.Tr("form.auth_failed", models.HandleCloneUserCredentials(err.Error(), true)), tplMigrate, &form)
 		return
-	} else if strings.Contains(err.Error(), "fatal:") {
+	} else if strings.Contains(err.Error(), "fatal:") || migrations.IsErrRemoteRequest(err) {
 		ctx.Data["Err_CloneAddr"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.migrate.failed", models.HandleCloneUserCredentials(err.Error(), true)), tplMigrate, &form)
 		return
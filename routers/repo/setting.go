@@ -32,6 +32,15 @@ const (
 func Settings(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+
+	if !ctx.Repo.Repository.IsMirror {
+		pushMirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.Handle(500, "GetPushMirrorsByRepoID", err)
+			return
+		}
+		ctx.Data["PushMirrors"] = pushMirrors
+	}
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -91,6 +100,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 
 		visibilityChanged := repo.IsPrivate != form.Private
 		repo.IsPrivate = form.Private
+		repo.IsTemplate = form.Template
 		if err := models.UpdateRepository(repo, visibilityChanged); err != nil {
 			ctx.Handle(500, "UpdateRepository", err)
 			return
@@ -142,6 +152,82 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "push-mirror-add":
+		if repo.IsMirror {
+			ctx.Handle(404, "", nil)
+			return
+		}
+
+		var interval time.Duration
+		if len(form.PushMirrorInterval) > 0 {
+			var err error
+			interval, err = time.ParseDuration(form.PushMirrorInterval)
+			if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+				ctx.Flash.Error(ctx.Tr("repo.mirror_interval_invalid"))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
+		}
+		if interval == 0 && !form.PushMirrorSyncOnPush {
+			ctx.Flash.Error(ctx.Tr("repo.settings.push_mirror_no_trigger"))
+			ctx.Redirect(repo.Link() + "/settings")
+			return
+		}
+
+		address, err := form.ParsePushMirrorAddr()
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.push_mirror_address_invalid"))
+			ctx.Redirect(repo.Link() + "/settings")
+			return
+		}
+
+		m, err := models.CreatePushMirror(repo, address, interval, form.PushMirrorSyncOnPush)
+		if err != nil {
+			ctx.Handle(500, "CreatePushMirror", err)
+			return
+		}
+		go models.PushMirrorQueue.Add(m.ID)
+
+		log.Trace("Push mirror added: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.Address())
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_add_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-sync":
+		m, err := models.GetPushMirrorByRepoID(repo.ID, form.PushMirrorID)
+		if err != nil {
+			if models.IsErrPushMirrorNotExist(err) {
+				ctx.Handle(404, "", nil)
+			} else {
+				ctx.Handle(500, "GetPushMirrorByRepoID", err)
+			}
+			return
+		}
+
+		go models.PushMirrorQueue.Add(m.ID)
+		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-remove":
+		m, err := models.GetPushMirrorByRepoID(repo.ID, form.PushMirrorID)
+		if err != nil {
+			if models.IsErrPushMirrorNotExist(err) {
+				ctx.Handle(404, "", nil)
+			} else {
+				ctx.Handle(500, "GetPushMirrorByRepoID", err)
+			}
+			return
+		}
+
+		m.Repo = repo
+		if err = models.DeletePushMirror(m); err != nil {
+			ctx.Handle(500, "DeletePushMirror", err)
+			return
+		}
+
+		log.Trace("Push mirror removed: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.RemoteName)
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_remove_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
 		var units []models.RepoUnit
 
@@ -200,6 +286,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					Config: &models.IssuesConfig{
 						EnableTimetracker:                form.EnableTimetracker,
 						AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
+						EnableDependencies:               form.EnableIssueDependencies,
 					},
 				})
 			}
@@ -209,6 +296,19 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypePullRequests,
+				Config: &models.PullRequestsConfig{
+					AllowMerge:       form.PullsAllowMerge,
+					AllowRebase:      form.PullsAllowRebase,
+					AllowRebaseMerge: form.PullsAllowRebaseMerge,
+					AllowSquash:      form.PullsAllowSquash,
+				},
+			})
+		}
+
+		if form.EnableProjects {
+			units = append(units, models.RepoUnit{
+				RepoID: repo.ID,
+				Type:   models.UnitTypeProjects,
 				Config: new(models.UnitConfig),
 			})
 		}
@@ -321,6 +421,32 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
 		ctx.Redirect(ctx.Repo.Owner.DashboardLink())
 
+	case "archive", "unarchive":
+		if !ctx.Repo.IsOwner() {
+			ctx.Error(404)
+			return
+		}
+
+		isArchived := ctx.Query("action") == "archive"
+		if repo.IsArchived == isArchived {
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+
+		if err := repo.SetArchiveRepoState(isArchived); err != nil {
+			ctx.Handle(500, "SetArchiveRepoState", err)
+			return
+		}
+
+		if isArchived {
+			log.Trace("Repository was archived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+			ctx.Flash.Success(ctx.Tr("repo.settings.archive.success"))
+		} else {
+			log.Trace("Repository was un-archived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+			ctx.Flash.Success(ctx.Tr("repo.settings.unarchive.success"))
+		}
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	case "delete-wiki":
 		if !ctx.Repo.IsOwner() {
 			ctx.Error(404)
@@ -0,0 +1,30 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// TopicsPost response for updating the topics of a repository
+func TopicsPost(ctx *context.Context, form auth.RepoTopicsForm) {
+	err := models.SaveTopics(ctx.Repo.Repository.ID, form.TopicNames()...)
+	switch {
+	case err == nil:
+		log.Trace("Repository topics updated: %s", ctx.Repo.Repository.FullName())
+		ctx.Flash.Success(ctx.Tr("repo.topic.update_success"))
+	case models.IsErrTopicNotValid(err):
+		ctx.Flash.Error(ctx.Tr("repo.topic.format_prompt"))
+	case models.IsErrTooManyTopics(err):
+		ctx.Flash.Error(ctx.Tr("repo.topic.count_prompt"))
+	default:
+		ctx.Handle(500, "SaveTopics", err)
+		return
+	}
+	ctx.Redirect(ctx.Repo.RepoLink)
+}
@@ -23,16 +23,17 @@ import (
 )
 
 const (
-	tplHooks      base.TplName = "repo/settings/hooks"
-	tplHookNew    base.TplName = "repo/settings/hook_new"
-	tplOrgHookNew base.TplName = "org/settings/hook_new"
+	tplHooks        base.TplName = "repo/settings/hooks"
+	tplHookNew      base.TplName = "repo/settings/hook_new"
+	tplOrgHookNew   base.TplName = "org/settings/hook_new"
+	tplAdminHookNew base.TplName = "admin/hook_new"
 )
 
 // Webhooks render web hooks list page
 func Webhooks(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings.hooks")
 	ctx.Data["PageIsSettingsHooks"] = true
-	ctx.Data["BaseLink"] = ctx.Repo.RepoLink
+	ctx.Data["BaseLink"] = ctx.Repo.RepoLink + "/settings/hooks"
 	ctx.Data["Description"] = ctx.Tr("repo.settings.hooks_desc", "https://godoc.org/code.gitea.io/sdk/gitea")
 
 	ws, err := models.GetWebhooksByRepoID(ctx.Repo.Repository.ID)
@@ -46,18 +47,20 @@ func Webhooks(ctx *context.Context) {
 }
 
 type orgRepoCtx struct {
-	OrgID       int64
-	RepoID      int64
-	Link        string
-	NewTemplate base.TplName
+	OrgID           int64
+	RepoID          int64
+	IsSystemWebhook bool
+	Link            string
+	NewTemplate     base.TplName
 }
 
-// getOrgRepoCtx determines whether this is a repo context or organization context.
+// getOrgRepoCtx determines whether this is a repo context, organization context
+// or the system webhooks of the admin panel.
 func getOrgRepoCtx(ctx *context.Context) (*orgRepoCtx, error) {
 	if len(ctx.Repo.RepoLink) > 0 {
 		return &orgRepoCtx{
 			RepoID:      ctx.Repo.Repository.ID,
-			Link:        ctx.Repo.RepoLink,
+			Link:        ctx.Repo.RepoLink + "/settings/hooks",
 			NewTemplate: tplHookNew,
 		}, nil
 	}
@@ -65,11 +68,19 @@ func getOrgRepoCtx(ctx *context.Context) (*orgRepoCtx, error) {
 	if len(ctx.Org.OrgLink) > 0 {
 		return &orgRepoCtx{
 			OrgID:       ctx.Org.Organization.ID,
-			Link:        ctx.Org.OrgLink,
+			Link:        ctx.Org.OrgLink + "/settings/hooks",
 			NewTemplate: tplOrgHookNew,
 		}, nil
 	}
 
+	if ctx.User.IsAdmin {
+		return &orgRepoCtx{
+			IsSystemWebhook: true,
+			Link:            setting.AppSubURL + "/admin/hooks",
+			NewTemplate:     tplAdminHookNew,
+		}, nil
+	}
+
 	return nil, errors.New("Unable to set OrgRepo context")
 }
 
@@ -118,10 +129,18 @@ func ParseHookEvent(form auth.WebhookForm) *models.HookEvent {
 		SendEverything: form.SendEverything(),
 		ChooseEvents:   form.ChooseEvents(),
 		HookEvents: models.HookEvents{
-			Create:      form.Create,
-			Push:        form.Push,
-			PullRequest: form.PullRequest,
-			Repository:  form.Repository,
+			Create:            form.Create,
+			Delete:            form.Delete,
+			Fork:              form.Fork,
+			Issues:            form.Issues,
+			IssueComment:      form.IssueComment,
+			Release:           form.Release,
+			Push:              form.Push,
+			PullRequest:       form.PullRequest,
+			PullRequestReview: form.PullRequestReview,
+			Repository:        form.Repository,
+			User:              form.User,
+			Member:            form.Member,
 		},
 	}
 }
@@ -160,6 +179,8 @@ func WebHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 		IsActive:     form.Active,
 		HookTaskType: models.GITEA,
 		OrgID:        orCtx.OrgID,
+
+		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Handle(500, "UpdateEvent", err)
@@ -170,7 +191,7 @@ func WebHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
-	ctx.Redirect(orCtx.Link + "/settings/hooks")
+	ctx.Redirect(orCtx.Link)
 }
 
 // GogsHooksNewPost response for creating webhook
@@ -207,6 +228,8 @@ func GogsHooksNewPost(ctx *context.Context, form auth.NewGogshookForm) {
 		IsActive:     form.Active,
 		HookTaskType: models.GITEA,
 		OrgID:        orCtx.OrgID,
+
+		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Handle(500, "UpdateEvent", err)
@@ -217,7 +240,7 @@ func GogsHooksNewPost(ctx *context.Context, form auth.NewGogshookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
-	ctx.Redirect(orCtx.Link + "/settings/hooks")
+	ctx.Redirect(orCtx.Link)
 }
 
 // DiscordHooksNewPost response for creating discord hook
@@ -256,6 +279,8 @@ func DiscordHooksNewPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 		HookTaskType: models.DISCORD,
 		Meta:         string(meta),
 		OrgID:        orCtx.OrgID,
+
+		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Handle(500, "UpdateEvent", err)
@@ -266,7 +291,7 @@ func DiscordHooksNewPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
-	ctx.Redirect(orCtx.Link + "/settings/hooks")
+	ctx.Redirect(orCtx.Link)
 }
 
 // DingtalkHooksNewPost response for creating dingtalk hook
@@ -296,6 +321,8 @@ func DingtalkHooksNewPost(ctx *context.Context, form auth.NewDingtalkHookForm) {
 		HookTaskType: models.DINGTALK,
 		Meta:         "",
 		OrgID:        orCtx.OrgID,
+
+		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Handle(500, "UpdateEvent", err)
@@ -306,7 +333,7 @@ func DingtalkHooksNewPost(ctx *context.Context, form auth.NewDingtalkHookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
-	ctx.Redirect(orCtx.Link + "/settings/hooks")
+	ctx.Redirect(orCtx.Link)
 }
 
 // SlackHooksNewPost response for creating slack hook
@@ -347,6 +374,8 @@ func SlackHooksNewPost(ctx *context.Context, form auth.NewSlackHookForm) {
 		HookTaskType: models.SLACK,
 		Meta:         string(meta),
 		OrgID:        orCtx.OrgID,
+
+		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Handle(500, "UpdateEvent", err)
@@ -357,7 +386,7 @@ func SlackHooksNewPost(ctx *context.Context, form auth.NewSlackHookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
-	ctx.Redirect(orCtx.Link + "/settings/hooks")
+	ctx.Redirect(orCtx.Link)
 }
 
 func checkWebhook(ctx *context.Context) (*orgRepoCtx, *models.Webhook) {
@@ -373,8 +402,10 @@ func checkWebhook(ctx *context.Context) (*orgRepoCtx, *models.Webhook) {
 	var w *models.Webhook
 	if orCtx.RepoID > 0 {
 		w, err = models.GetWebhookByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
-	} else {
+	} else if orCtx.OrgID > 0 {
 		w, err = models.GetWebhookByOrgID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	} else {
+		w, err = models.GetSystemWebhook(ctx.ParamsInt64(":id"))
 	}
 	if err != nil {
 		if models.IsErrWebhookNotExist(err) {
@@ -451,7 +482,7 @@ func WebHooksEditPost(ctx *context.Context, form auth.NewWebhookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
-	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", orCtx.Link, w.ID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
 // GogsHooksEditPost response for editing gogs hook
@@ -490,7 +521,7 @@ func GogsHooksEditPost(ctx *context.Context, form auth.NewGogshookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
-	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", orCtx.Link, w.ID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
 // SlackHooksEditPost response for editing slack hook
@@ -534,7 +565,7 @@ func SlackHooksEditPost(ctx *context.Context, form auth.NewSlackHookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
-	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", orCtx.Link, w.ID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
 // DiscordHooksEditPost response for editing discord hook
@@ -576,7 +607,7 @@ func DiscordHooksEditPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
-	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", orCtx.Link, w.ID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
 // DingtalkHooksEditPost response for editing discord hook
@@ -608,7 +639,7 @@ func DingtalkHooksEditPost(ctx *context.Context, form auth.NewDingtalkHookForm)
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
-	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", orCtx.Link, w.ID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
 // TestWebhook test if web hook is work fine
@@ -667,6 +698,28 @@ func TestWebhook(ctx *context.Context) {
 	}
 }
 
+// ReplayWebhook delivers again a payload sent by a webhook
+func ReplayWebhook(ctx *context.Context) {
+	orCtx, w := checkWebhook(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	t, err := models.ReplayHookTask(w, ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.Handle(404, "ReplayHookTask", nil)
+		} else {
+			ctx.Handle(500, "ReplayHookTask", err)
+		}
+		return
+	}
+
+	go models.HookQueue.Add(t.RepoID)
+	ctx.Flash.Success(ctx.Tr("repo.settings.webhook.redelivery_success", t.UUID))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
+}
+
 // DeleteWebhook delete a webhook
 func DeleteWebhook(ctx *context.Context) {
 	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
@@ -150,6 +150,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 		}
 	}
 
+	oauth2Enabled := func(ctx *context.Context) {
+		if !setting.OAuth2.Enable {
+			ctx.Error(404)
+			return
+		}
+	}
+
 	m.Use(user.GetNotificationCount)
 
 	// FIXME: not all routes need go through same middlewares.
@@ -167,6 +174,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/repos", routers.ExploreRepos)
 		m.Get("/users", routers.ExploreUsers)
 		m.Get("/organizations", routers.ExploreOrganizations)
+		m.Get("/issues", routers.ExploreIssues)
+		m.Get("/code", routers.ExploreCode)
 	}, ignSignIn)
 	m.Combo("/install", routers.InstallInit).Get(routers.Install).
 		Post(bindIgnErr(auth.InstallForm{}), routers.InstallPost)
@@ -233,6 +242,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Combo("/applications").Get(user.SettingsApplications).
 			Post(bindIgnErr(auth.NewAccessTokenForm{}), user.SettingsApplicationsPost)
 		m.Post("/applications/delete", user.SettingsDeleteApplication)
+		m.Group("/applications/oauth2", func() {
+			m.Post("", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.SettingsOAuthApplicationsPost)
+			m.Post("/delete", user.SettingsDeleteOAuthApplication)
+			m.Post("/revoke", user.SettingsRevokeOAuth2Grant)
+			m.Combo("/:id").Get(user.SettingsOAuthApplicationEdit).
+				Post(bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.SettingsOAuthApplicationEditPost)
+			m.Post("/:id/regenerate_secret", user.SettingsOAuthApplicationRegenerateSecret)
+		}, oauth2Enabled)
 		m.Route("/delete", "GET,POST", user.SettingsDelete)
 		m.Combo("/account_link").Get(user.SettingsAccountLinks).Post(user.SettingsDeleteAccountLink)
 		m.Get("/organization", user.SettingsOrganization)
@@ -247,6 +264,18 @@ func RegisterRoutes(m *macaron.Macaron) {
 		ctx.Data["PageIsUserSettings"] = true
 	})
 
+	// OAuth2 and OpenID Connect provider
+	m.Group("/login/oauth", func() {
+		m.Get("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
+		m.Post("/grant", bindIgnErr(auth.GrantApplicationForm{}), user.GrantApplicationOAuth)
+	}, reqSignIn, oauth2Enabled)
+	m.Group("/login/oauth", func() {
+		m.Post("/access_token", bindIgnErr(auth.AccessTokenForm{}), user.AccessTokenOAuth)
+		m.Get("/userinfo", user.InfoOAuth)
+		m.Get("/keys", user.OIDCKeys)
+	}, ignSignInAndCsrf, oauth2Enabled)
+	m.Get("/.well-known/openid-configuration", oauth2Enabled, user.OIDCWellKnown)
+
 	m.Group("/user", func() {
 		// r.Get("/feeds", binding.Bind(auth.FeedsForm{}), user.Feeds)
 		m.Any("/activate", user.Activate)
@@ -291,6 +320,26 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Post("/:authid/delete", admin.DeleteAuthSource)
 		})
 
+		m.Group("/hooks", func() {
+			m.Get("", admin.SystemHooks)
+			m.Post("/delete", admin.DeleteSystemHook)
+			m.Get("/:type/new", repo.WebhooksNew)
+			m.Post("/gitea/new", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksNewPost)
+			m.Post("/gogs/new", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
+			m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
+			m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
+			m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
+			m.Get("/:id", repo.WebHooksEdit)
+			m.Post("/:id/replay/:uuid", repo.ReplayWebhook)
+			m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
+			m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
+			m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
+			m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
+			m.Post("/dingtalk/:id", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
+		}, func(ctx *context.Context) {
+			ctx.Data["PageIsAdminHooks"] = true
+		})
+
 		m.Group("/notices", func() {
 			m.Get("", admin.Notices)
 			m.Post("/delete", admin.DeleteNotices)
@@ -347,6 +396,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 	reqRepoAdmin := context.RequireRepoAdmin()
 	reqRepoWriter := context.RequireRepoWriter()
+	reqRepoNotArchived := context.RepoMustNotBeArchived()
 
 	// ***** START: Organization *****
 	m.Group("/org", func() {
@@ -398,6 +448,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
 					m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
 					m.Get("/:id", repo.WebHooksEdit)
+					m.Post("/:id/replay/:uuid", repo.ReplayWebhook)
 					m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 					m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 					m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -405,6 +456,15 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/dingtalk/:id", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
 				})
 
+				m.Group("/applications", func() {
+					m.Get("", org.Applications)
+					m.Post("/oauth2", bindIgnErr(auth.EditOAuth2ApplicationForm{}), org.OAuthApplicationsPost)
+					m.Post("/oauth2/delete", org.DeleteOAuthApplication)
+					m.Combo("/oauth2/:id").Get(org.OAuthApplicationEdit).
+						Post(bindIgnErr(auth.EditOAuth2ApplicationForm{}), org.OAuthApplicationEditPost)
+					m.Post("/oauth2/:id/regenerate_secret", org.OAuthApplicationRegenerateSecret)
+				}, oauth2Enabled)
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
@@ -449,6 +509,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
 				m.Get("/:id", repo.WebHooksEdit)
 				m.Post("/:id/test", repo.TestWebhook)
+				m.Post("/:id/replay/:uuid", repo.ReplayWebhook)
 				m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 				m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
 				m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -471,6 +532,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 		}, func(ctx *context.Context) {
 			ctx.Data["PageIsSettings"] = true
 		})
+
+		m.Post("/topics", bindIgnErr(auth.RepoTopicsForm{}), repo.TopicsPost)
 	}, reqSignIn, context.RepoAssignment(), reqRepoAdmin, context.UnitTypes(), context.LoadRepoUnits(), context.RepoRef())
 
 	m.Get("/:username/:reponame/action/:action", reqSignIn, context.RepoAssignment(), repo.Action)
@@ -487,6 +550,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/title", repo.UpdateIssueTitle)
 				m.Post("/content", repo.UpdateIssueContent)
 				m.Post("/watch", repo.IssueWatch)
+				m.Post("/reactions/:action", bindIgnErr(auth.ReactionForm{}), repo.ChangeIssueReaction)
+				m.Group("/dependency", func() {
+					m.Post("/add", repo.AddDependency)
+					m.Post("/delete", repo.RemoveDependency)
+				})
 				m.Combo("/comments").Post(bindIgnErr(auth.CreateCommentForm{}), repo.NewComment)
 				m.Group("/times", func() {
 					m.Post("/add", bindIgnErr(auth.AddTimeManuallyForm{}), repo.AddTimeManually)
@@ -505,6 +573,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/comments/:id", func() {
 			m.Post("", repo.UpdateCommentContent)
 			m.Post("/delete", repo.DeleteComment)
+			m.Post("/reactions/:action", bindIgnErr(auth.ReactionForm{}), repo.ChangeCommentReaction)
 		}, context.CheckAnyUnit(models.UnitTypeIssues, models.UnitTypePullRequests))
 		m.Group("/labels", func() {
 			m.Post("/new", bindIgnErr(auth.CreateLabelForm{}), repo.NewLabel)
@@ -554,7 +623,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Post("/restore", repo.RestoreBranchPost)
 		}, reqRepoWriter, repo.MustBeNotBare, context.CheckUnit(models.UnitTypeCode))
 
-	}, reqSignIn, context.RepoAssignment(), context.UnitTypes(), context.LoadRepoUnits())
+	}, reqSignIn, context.RepoAssignment(), reqRepoNotArchived, context.UnitTypes(), context.LoadRepoUnits())
 
 	// Releases
 	m.Group("/:username/:reponame", func() {
@@ -565,11 +634,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/new", repo.NewRelease)
 			m.Post("/new", bindIgnErr(auth.NewReleaseForm{}), repo.NewReleasePost)
 			m.Post("/delete", repo.DeleteRelease)
-		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, context.RepoRef())
+		}, reqSignIn, repo.MustBeNotBare, reqRepoNotArchived, reqRepoWriter, context.RepoRef())
 		m.Group("/releases", func() {
 			m.Get("/edit/*", repo.EditRelease)
 			m.Post("/edit/*", bindIgnErr(auth.EditReleaseForm{}), repo.EditReleasePost)
-		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, func(ctx *context.Context) {
+		}, reqSignIn, repo.MustBeNotBare, reqRepoNotArchived, reqRepoWriter, func(ctx *context.Context) {
 			var err error
 			ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
 			if err != nil {
@@ -603,13 +672,37 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:page/_edit").Get(repo.EditWiki).
 					Post(bindIgnErr(auth.NewWikiForm{}), repo.EditWikiPost)
 				m.Post("/:page/delete", repo.DeleteWikiPagePost)
-			}, reqSignIn, reqRepoWriter)
+			}, reqSignIn, reqRepoNotArchived, reqRepoWriter)
 		}, repo.MustEnableWiki, context.RepoRef())
 
 		m.Group("/wiki", func() {
 			m.Get("/raw/*", repo.WikiRaw)
 		}, repo.MustEnableWiki)
 
+		m.Group("/projects", func() {
+			m.Get("", repo.Projects)
+
+			m.Group("", func() {
+				m.Combo("/new").Get(repo.NewProject).
+					Post(bindIgnErr(auth.CreateProjectForm{}), repo.NewProjectPost)
+				m.Post("/delete", repo.DeleteProject)
+				m.Group("/:id", func() {
+					m.Combo("/edit").Get(repo.EditProject).
+						Post(bindIgnErr(auth.CreateProjectForm{}), repo.EditProjectPost)
+					m.Get("/:action(open|close)", repo.ChangeProjectStatus)
+					m.Post("/columns/new", bindIgnErr(auth.ProjectColumnForm{}), repo.NewProjectColumn)
+					m.Post("/columns/:columnID/edit", bindIgnErr(auth.ProjectColumnForm{}), repo.EditProjectColumn)
+					m.Post("/columns/:columnID/move", repo.MoveProjectColumn)
+					m.Post("/columns/:columnID/delete", repo.DeleteProjectColumn)
+					m.Post("/cards", repo.AddProjectCard)
+					m.Post("/cards/move", repo.MoveProjectCard)
+					m.Post("/cards/delete", repo.RemoveProjectCard)
+				})
+			}, reqSignIn, reqRepoNotArchived, reqRepoWriter)
+
+			m.Get("/:id", repo.ViewProject)
+		}, repo.MustEnableProjects, context.RepoRef())
+
 		m.Group("/activity", func() {
 			m.Get("", repo.Activity)
 			m.Get("/:period", repo.Activity)
@@ -624,8 +717,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/pulls/:index", func() {
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Get("/files", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.ViewPullFiles)
-			m.Post("/merge", reqRepoWriter, repo.MergePullRequest)
+			m.Post("/merge", reqRepoNotArchived, reqRepoWriter, bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cleanup", context.RepoRef(), repo.CleanUpPullRequest)
+			m.Group("/files/reviews", func() {
+				m.Post("/comments", bindIgnErr(auth.CodeCommentForm{}), repo.CreateCodeComment)
+				m.Post("/submit", bindIgnErr(auth.SubmitReviewForm{}), repo.SubmitReview)
+			}, reqSignIn, reqRepoNotArchived)
 		}, repo.MustAllowPulls)
 
 		m.Group("/raw", func() {
@@ -649,6 +746,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/commit/:sha([a-f0-9]{7,40})$", repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.Diff)
 		}, repo.MustBeNotBare, context.RepoRef(), context.CheckUnit(models.UnitTypeCode))
 
+		m.Group("/blame", func() {
+			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RefBlame)
+			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.RefBlame)
+			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.RefBlame)
+		}, repo.MustBeNotBare, context.CheckUnit(models.UnitTypeCode), repo.SetEditorconfigIfExists)
+
 		m.Group("/src", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.Home)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.Home)
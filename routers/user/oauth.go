@@ -0,0 +1,704 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplGrantAccess base.TplName = "user/auth/grant"
+	tplGrantError  base.TplName = "user/auth/grant_error"
+)
+
+// AuthorizeErrorCode represents an error code specified in RFC 6749
+type AuthorizeErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest represents the according error in RFC 6749
+	ErrorCodeInvalidRequest AuthorizeErrorCode = "invalid_request"
+	// ErrorCodeUnauthorizedClient represents the according error in RFC 6749
+	ErrorCodeUnauthorizedClient AuthorizeErrorCode = "unauthorized_client"
+	// ErrorCodeAccessDenied represents the according error in RFC 6749
+	ErrorCodeAccessDenied AuthorizeErrorCode = "access_denied"
+	// ErrorCodeUnsupportedResponseType represents the according error in RFC 6749
+	ErrorCodeUnsupportedResponseType AuthorizeErrorCode = "unsupported_response_type"
+	// ErrorCodeInvalidScope represents the according error in RFC 6749
+	ErrorCodeInvalidScope AuthorizeErrorCode = "invalid_scope"
+	// ErrorCodeServerError represents the according error in RFC 6749
+	ErrorCodeServerError AuthorizeErrorCode = "server_error"
+)
+
+// AuthorizeError represents an error type specified in RFC 6749
+type AuthorizeError struct {
+	ErrorCode        AuthorizeErrorCode
+	ErrorDescription string
+	State            string
+}
+
+// Error returns the error message
+func (err AuthorizeError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// AccessTokenErrorCode represents an error code specified in RFC 6749
+type AccessTokenErrorCode string
+
+const (
+	// AccessTokenErrorCodeInvalidRequest represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidRequest AccessTokenErrorCode = "invalid_request"
+	// AccessTokenErrorCodeInvalidClient represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidClient AccessTokenErrorCode = "invalid_client"
+	// AccessTokenErrorCodeInvalidGrant represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidGrant AccessTokenErrorCode = "invalid_grant"
+	// AccessTokenErrorCodeUnsupportedGrantType represents an error code specified in RFC 6749
+	AccessTokenErrorCodeUnsupportedGrantType AccessTokenErrorCode = "unsupported_grant_type"
+	// AccessTokenErrorCodeServerError is not specified in RFC 6749, it is
+	// used for internal errors
+	AccessTokenErrorCodeServerError AccessTokenErrorCode = "server_error"
+)
+
+// AccessTokenError represents an error response specified in RFC 6749
+type AccessTokenError struct {
+	ErrorCode        AccessTokenErrorCode `json:"error"`
+	ErrorDescription string               `json:"error_description"`
+}
+
+// Error returns the error message
+func (err AccessTokenError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// AccessTokenResponse represents a successful access token response
+type AccessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// oauth2ScopeOption a scope shown on the page granting an application access
+type oauth2ScopeOption struct {
+	Scope   string
+	DescKey string
+}
+
+func oauth2ScopeOptions(scope string) []oauth2ScopeOption {
+	scopes := strings.Fields(scope)
+	options := make([]oauth2ScopeOption, len(scopes))
+	for i, s := range scopes {
+		options[i] = oauth2ScopeOption{
+			Scope:   s,
+			DescKey: "auth.oauth2_scope_" + strings.Replace(s, ":", "_", -1),
+		}
+	}
+	return options
+}
+
+// newAccessTokenResponse issues new tokens for the grant, publicClient is true
+// if the client did not authenticate with its secret
+func newAccessTokenResponse(grant *models.OAuth2Grant, publicClient bool) (*AccessTokenResponse, *AccessTokenError) {
+	serverError := &AccessTokenError{
+		ErrorCode:        AccessTokenErrorCodeServerError,
+		ErrorDescription: "cannot issue tokens",
+	}
+	now := time.Now()
+
+	// generate access token to access the API
+	expiresAt := now.Add(time.Duration(setting.OAuth2.AccessTokenExpirationTime) * time.Second)
+	accessToken := &models.OAuth2Token{
+		GrantID: grant.ID,
+		Type:    models.TypeAccessToken,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	signedAccessToken, err := accessToken.SignToken(oauth2.JWTSigningKey)
+	if err != nil {
+		log.Error(4, "SignToken: %v", err)
+		return nil, serverError
+	}
+
+	// generate refresh token to request an access token after it expired later
+	refreshToken := &models.OAuth2Token{
+		GrantID:      grant.ID,
+		Counter:      grant.Counter,
+		Type:         models.TypeRefreshToken,
+		PublicClient: publicClient,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: now.Add(time.Duration(setting.OAuth2.RefreshTokenExpirationTime) * time.Hour).Unix(),
+		},
+	}
+	signedRefreshToken, err := refreshToken.SignToken(oauth2.JWTSigningKey)
+	if err != nil {
+		log.Error(4, "SignToken: %v", err)
+		return nil, serverError
+	}
+
+	// generate OpenID Connect id token to authenticate the user
+	var signedIDToken string
+	if grant.ScopeContains(models.OAuth2ScopeOpenID) {
+		user, err := models.GetUserByID(grant.UserID)
+		if err != nil {
+			log.Error(4, "GetUserByID: %v", err)
+			return nil, serverError
+		}
+		idToken := &models.OIDCToken{
+			StandardClaims: jwt.StandardClaims{
+				ExpiresAt: expiresAt.Unix(),
+				Issuer:    setting.AppURL,
+				Audience:  grant.Application.ClientID,
+				Subject:   fmt.Sprint(grant.UserID),
+				IssuedAt:  now.Unix(),
+			},
+			Nonce: grant.Nonce,
+		}
+		if grant.ScopeContains(models.OAuth2ScopeProfile) {
+			idToken.Name = user.FullName
+			idToken.PreferredUsername = user.Name
+			idToken.Profile = user.HTMLURL()
+			idToken.Picture = user.AvatarLink()
+			idToken.Website = user.Website
+			idToken.UpdatedAt = user.UpdatedUnix
+		}
+		if grant.ScopeContains(models.OAuth2ScopeEmail) {
+			idToken.Email = user.Email
+			idToken.EmailVerified = user.IsActive
+		}
+		signedIDToken, err = idToken.SignToken(oauth2.JWTSigningKey, oauth2.SigningKeyID(oauth2.JWTSigningKey))
+		if err != nil {
+			log.Error(4, "SignToken: %v", err)
+			return nil, serverError
+		}
+	}
+
+	return &AccessTokenResponse{
+		AccessToken:  signedAccessToken,
+		TokenType:    "bearer",
+		ExpiresIn:    setting.OAuth2.AccessTokenExpirationTime,
+		RefreshToken: signedRefreshToken,
+		IDToken:      signedIDToken,
+	}, nil
+}
+
+// authorizationRequest the validated parameters of a request to authorize
+// an application, shared by the authorize and grant pages
+type authorizationRequest struct {
+	App                 *models.OAuth2Application
+	RedirectURI         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// validateAuthorizationRequest validates the parameters of an authorization
+// request. If it returns an error without a request, the client or the
+// redirect uri cannot be trusted and the error must not be redirected.
+func validateAuthorizationRequest(clientID, redirectURI, scope, codeChallenge, codeChallengeMethod, state string) (*authorizationRequest, *AuthorizeError, error) {
+	app, err := models.GetOAuth2ApplicationByClientID(clientID)
+	if err != nil {
+		if models.IsErrOAuthClientIDInvalid(err) {
+			return nil, &AuthorizeError{
+				ErrorCode:        ErrorCodeUnauthorizedClient,
+				ErrorDescription: "Client ID not registered",
+				State:            state,
+			}, nil
+		}
+		return nil, nil, err
+	}
+	if err = app.LoadOwner(); err != nil {
+		return nil, nil, err
+	}
+
+	// the redirect uri may only be omitted if a single one is registered
+	if len(redirectURI) == 0 && len(app.RedirectURIs) == 1 {
+		redirectURI = app.PrimaryRedirectURI()
+	}
+	if !app.ContainsRedirectURI(redirectURI) {
+		return nil, &AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: "Unregistered Redirect URI",
+			State:            state,
+		}, nil
+	}
+	req := &authorizationRequest{
+		App:                 app,
+		RedirectURI:         redirectURI,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+	}
+
+	if req.Scope, err = models.NormalizeOAuth2Scope(scope); err != nil {
+		return req, &AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidScope,
+			ErrorDescription: err.Error(),
+			State:            state,
+		}, nil
+	}
+
+	switch req.CodeChallengeMethod {
+	case "S256", "plain":
+		if len(req.CodeChallenge) == 0 {
+			return req, &AuthorizeError{
+				ErrorCode:        ErrorCodeInvalidRequest,
+				ErrorDescription: "code_challenge is required",
+				State:            state,
+			}, nil
+		}
+	case "":
+		// the default method of PKCE is plain
+		if len(req.CodeChallenge) > 0 {
+			req.CodeChallengeMethod = "plain"
+		}
+	default:
+		return req, &AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: "unsupported code challenge method",
+			State:            state,
+		}, nil
+	}
+	return req, nil, nil
+}
+
+// AuthorizeOAuth manages authorize requests
+func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
+	req, authErr, err := validateAuthorizationRequest(form.ClientID, form.RedirectURI, form.Scope,
+		form.CodeChallenge, form.CodeChallengeMethod, form.State)
+	if err != nil {
+		ctx.Handle(500, "validateAuthorizationRequest", err)
+		return
+	} else if authErr != nil {
+		if req == nil {
+			handleAuthorizeError(ctx, *authErr, "")
+		} else {
+			handleAuthorizeError(ctx, *authErr, req.RedirectURI)
+		}
+		return
+	}
+
+	if form.ResponseType != "code" {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeUnsupportedResponseType,
+			ErrorDescription: "Only code response type is supported.",
+			State:            form.State,
+		}, req.RedirectURI)
+		return
+	}
+
+	grant, err := req.App.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, req.RedirectURI)
+		return
+	}
+
+	// redirect at once if the user already granted the requested scope
+	if grant != nil && grant.Scope == req.Scope {
+		redirectWithAuthorizationCode(ctx, grant, req, form.Nonce, form.State)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("auth.authorize_title", req.App.Name)
+	ctx.Data["Application"] = req.App
+	ctx.Data["ApplicationOwner"] = req.App.Owner
+	ctx.Data["Scopes"] = oauth2ScopeOptions(req.Scope)
+	ctx.Data["ClientID"] = req.App.ClientID
+	ctx.Data["RedirectURI"] = req.RedirectURI
+	ctx.Data["State"] = form.State
+	ctx.Data["Scope"] = req.Scope
+	ctx.Data["Nonce"] = form.Nonce
+	ctx.Data["CodeChallenge"] = req.CodeChallenge
+	ctx.Data["CodeChallengeMethod"] = req.CodeChallengeMethod
+	if redirect, err := url.Parse(req.RedirectURI); err == nil {
+		ctx.Data["RedirectHost"] = redirect.Host
+	}
+	ctx.HTML(200, tplGrantAccess)
+}
+
+// GrantApplicationOAuth manages the post request submitted when a user grants access to an application
+func GrantApplicationOAuth(ctx *context.Context, form auth.GrantApplicationForm) {
+	// the parameters are validated again, since the form could be modified
+	req, authErr, err := validateAuthorizationRequest(form.ClientID, form.RedirectURI, form.Scope,
+		form.CodeChallenge, form.CodeChallengeMethod, form.State)
+	if err != nil {
+		ctx.Handle(500, "validateAuthorizationRequest", err)
+		return
+	} else if authErr != nil {
+		if req == nil {
+			handleAuthorizeError(ctx, *authErr, "")
+		} else {
+			handleAuthorizeError(ctx, *authErr, req.RedirectURI)
+		}
+		return
+	}
+
+	if !form.Granted {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeAccessDenied,
+			ErrorDescription: "The user denied the access.",
+			State:            form.State,
+		}, req.RedirectURI)
+		return
+	}
+
+	grant, err := req.App.GetGrantByUserID(ctx.User.ID)
+	if err == nil {
+		if grant == nil {
+			grant, err = req.App.CreateGrant(ctx.User.ID, req.Scope)
+		} else if grant.Scope != req.Scope {
+			err = grant.UpdateScope(req.Scope)
+		}
+	}
+	if err != nil {
+		log.Error(4, "Granting access to %s: %v", req.App.ClientID, err)
+		handleServerError(ctx, form.State, req.RedirectURI)
+		return
+	}
+	redirectWithAuthorizationCode(ctx, grant, req, form.Nonce, form.State)
+}
+
+// redirectWithAuthorizationCode sends the user back to the application with
+// a new authorization code of the grant
+func redirectWithAuthorizationCode(ctx *context.Context, grant *models.OAuth2Grant, req *authorizationRequest, nonce, state string) {
+	if err := grant.SetNonce(nonce); err != nil {
+		log.Error(4, "SetNonce: %v", err)
+		handleServerError(ctx, state, req.RedirectURI)
+		return
+	}
+	code, err := grant.GenerateNewAuthorizationCode(req.RedirectURI, req.CodeChallenge, req.CodeChallengeMethod)
+	if err != nil {
+		log.Error(4, "GenerateNewAuthorizationCode: %v", err)
+		handleServerError(ctx, state, req.RedirectURI)
+		return
+	}
+	redirect, err := code.GenerateRedirectURI(state)
+	if err != nil {
+		handleServerError(ctx, state, req.RedirectURI)
+		return
+	}
+	ctx.Redirect(redirect.String(), 302)
+}
+
+// OIDCWellKnown generates the OpenID Connect discovery document
+func OIDCWellKnown(ctx *context.Context) {
+	ctx.JSON(200, map[string]interface{}{
+		"issuer":                                setting.AppURL,
+		"authorization_endpoint":                setting.AppURL + "login/oauth/authorize",
+		"token_endpoint":                        setting.AppURL + "login/oauth/access_token",
+		"userinfo_endpoint":                     setting.AppURL + "login/oauth/userinfo",
+		"jwks_uri":                              setting.AppURL + "login/oauth/keys",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      models.OAuth2Scopes,
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+		"claims_supported": []string{
+			"aud", "exp", "iat", "iss", "sub",
+			"name", "preferred_username", "profile", "picture", "website", "updated_at",
+			"email", "email_verified",
+		},
+	})
+}
+
+// OIDCKeys generates the JSON Web Key Set of the keys signing the tokens
+func OIDCKeys(ctx *context.Context) {
+	ctx.JSON(200, map[string]interface{}{
+		"keys": []map[string]string{oauth2.SigningKeyJWK(oauth2.JWTSigningKey)},
+	})
+}
+
+// userInfoResponse the claims about the user returned by the userinfo
+// endpoint of OpenID Connect
+type userInfoResponse struct {
+	Sub               string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Website           string `json:"website,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+}
+
+// InfoOAuth returns the claims about the user authenticated by the access
+// token, see the UserInfo endpoint of OpenID Connect
+func InfoOAuth(ctx *context.Context) {
+	var grant *models.OAuth2Grant
+	if fields := strings.Fields(ctx.Req.Header.Get("Authorization")); len(fields) == 2 && strings.ToLower(fields[0]) == "bearer" {
+		grant = auth.CheckOAuthAccessToken(fields[1])
+	}
+	if grant == nil {
+		ctx.Resp.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		ctx.Error(401)
+		return
+	}
+
+	user, err := models.GetUserByID(grant.UserID)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(401)
+		} else {
+			ctx.Handle(500, "GetUserByID", err)
+		}
+		return
+	}
+
+	// a grant without scope allows the application to access everything
+	allScopes := len(strings.TrimSpace(grant.Scope)) == 0
+	response := &userInfoResponse{Sub: fmt.Sprint(user.ID)}
+	if allScopes || grant.ScopeContains(models.OAuth2ScopeProfile) {
+		response.Name = user.FullName
+		response.PreferredUsername = user.Name
+		response.Profile = user.HTMLURL()
+		response.Picture = user.AvatarLink()
+		response.Website = user.Website
+		response.UpdatedAt = user.UpdatedUnix
+	}
+	if allScopes || grant.ScopeContains(models.OAuth2ScopeEmail) {
+		response.Email = user.Email
+		response.EmailVerified = user.IsActive
+	}
+	ctx.JSON(200, response)
+}
+
+// AccessTokenOAuth manages all access token requests by the client
+func AccessTokenOAuth(ctx *context.Context, form auth.AccessTokenForm) {
+	// clients may authenticate with basic auth instead of the form
+	if len(form.ClientID) == 0 {
+		if fields := strings.Fields(ctx.Req.Header.Get("Authorization")); len(fields) == 2 && fields[0] == "Basic" {
+			clientID, clientSecret, err := base.BasicAuthDecode(fields[1])
+			if err != nil {
+				handleAccessTokenError(ctx, AccessTokenError{
+					ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+					ErrorDescription: "cannot parse basic auth header",
+				})
+				return
+			}
+			form.ClientID, form.ClientSecret = clientID, clientSecret
+		}
+	}
+
+	switch form.GrantType {
+	case "refresh_token":
+		handleRefreshToken(ctx, form)
+	case "authorization_code":
+		handleAuthorizationCode(ctx, form)
+	default:
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnsupportedGrantType,
+			ErrorDescription: "Only refresh_token or authorization_code grant type is supported",
+		})
+	}
+}
+
+// getAuthenticatedApplication returns the application of the client, the
+// client secret is checked unless allowPublicClient is true and no secret
+// was sent
+func getAuthenticatedApplication(form auth.AccessTokenForm, allowPublicClient bool) (*models.OAuth2Application, *AccessTokenError) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if !models.IsErrOAuthClientIDInvalid(err) {
+			log.Error(4, "GetOAuth2ApplicationByClientID: %v", err)
+		}
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+		}
+	}
+	if allowPublicClient && len(form.ClientSecret) == 0 {
+		return app, nil
+	}
+	if !app.ValidateClientSecret(form.ClientSecret) {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: "invalid client secret",
+		}
+	}
+	return app, nil
+}
+
+func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
+	token, err := models.ParseOAuth2Token(form.RefreshToken, oauth2.JWTSigningKey)
+	if err != nil || token.Type != models.TypeRefreshToken {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "invalid refresh token",
+		})
+		return
+	}
+
+	// public clients only identify themselves by the client id, to which the
+	// grant of the refresh token is bound
+	app, tokenErr := getAuthenticatedApplication(form, token.PublicClient)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil || grant.ApplicationID != app.ID {
+		if err != nil && !models.IsErrOAuthGrantNotFound(err) {
+			log.Error(4, "GetOAuth2GrantByID: %v", err)
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "grant does not exist",
+		})
+		return
+	}
+	grant.Application = app
+
+	// a refresh token can only be used once, the grant is revoked if it is
+	// used again as the token may have been stolen
+	if err = grant.IncreaseCounter(token.Counter); err != nil {
+		if !models.IsErrOAuthRefreshTokenReused(err) {
+			log.Error(4, "IncreaseCounter: %v", err)
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeServerError,
+				ErrorDescription: "cannot increase the grant counter",
+			})
+			return
+		}
+		if err = models.RevokeOAuth2Grant(grant.ID, grant.UserID); err != nil && !models.IsErrOAuthGrantNotFound(err) {
+			log.Error(4, "RevokeOAuth2Grant: %v", err)
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "refresh token has already been used",
+		})
+		return
+	}
+
+	resp, tokenErr := newAccessTokenResponse(grant, len(form.ClientSecret) == 0)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
+	authorizationCode, err := models.GetOAuth2AuthorizationByCode(form.Code)
+	if err != nil || authorizationCode.IsExpired() {
+		if err != nil && !models.IsErrOAuthAuthorizationCodeInvalid(err) {
+			log.Error(4, "GetOAuth2AuthorizationByCode: %v", err)
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "authorization code is invalid or has expired",
+		})
+		return
+	}
+
+	// public clients without a secret have to use PKCE
+	app, tokenErr := getAuthenticatedApplication(form, len(authorizationCode.CodeChallenge) > 0)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	if authorizationCode.Grant.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "authorization code was issued to another client",
+		})
+		return
+	}
+	if form.RedirectURI != authorizationCode.RedirectURI {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "unexpected redirect URI",
+		})
+		return
+	}
+	if !authorizationCode.ValidateCodeChallenge(form.CodeVerifier) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "failed PKCE code challenge",
+		})
+		return
+	}
+
+	// an authorization code can only be used once
+	if err = authorizationCode.Invalidate(); err != nil {
+		if models.IsErrOAuthAuthorizationCodeInvalid(err) {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+				ErrorDescription: "authorization code has already been used",
+			})
+			return
+		}
+		log.Error(4, "Invalidate: %v", err)
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeServerError,
+			ErrorDescription: "cannot invalidate the authorization code",
+		})
+		return
+	}
+
+	resp, tokenErr := newAccessTokenResponse(authorizationCode.Grant, len(form.ClientSecret) == 0)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+func handleAccessTokenError(ctx *context.Context, acErr AccessTokenError) {
+	status := 400
+	if acErr.ErrorCode == AccessTokenErrorCodeInvalidClient {
+		status = 401
+	} else if acErr.ErrorCode == AccessTokenErrorCodeServerError {
+		status = 500
+	}
+	ctx.JSON(status, acErr)
+}
+
+func handleServerError(ctx *context.Context, state string, redirectURI string) {
+	handleAuthorizeError(ctx, AuthorizeError{
+		ErrorCode:        ErrorCodeServerError,
+		ErrorDescription: "A server error occurred.",
+		State:            state,
+	}, redirectURI)
+}
+
+// handleAuthorizeError sends the error back to the application, or shows it
+// to the user if the redirect uri cannot be trusted
+func handleAuthorizeError(ctx *context.Context, authErr AuthorizeError, redirectURI string) {
+	if len(redirectURI) == 0 {
+		log.Warn("Authorization failed: %v", authErr)
+		ctx.Data["Title"] = ctx.Tr("auth.authorize_error")
+		ctx.Data["Error"] = authErr
+		ctx.HTML(400, tplGrantError)
+		return
+	}
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.Handle(500, "url.Parse", err)
+		return
+	}
+	q := redirect.Query()
+	q.Set("error", string(authErr.ErrorCode))
+	q.Set("error_description", authErr.ErrorDescription)
+	if len(authErr.State) > 0 {
+		q.Set("state", authErr.State)
+	}
+	redirect.RawQuery = q.Encode()
+	ctx.Redirect(redirect.String(), 302)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/Unknwon/com"
 	"github.com/pquerna/otp"
@@ -467,40 +468,66 @@ func DeleteKey(ctx *context.Context) {
 	})
 }
 
+// accessTokenScopeOption a scope which can be chosen for a new access token
+type accessTokenScopeOption struct {
+	Scope   models.AccessTokenScope
+	DescKey string
+	Checked bool
+}
+
+func accessTokenScopeOptions(checked []string) []accessTokenScopeOption {
+	options := make([]accessTokenScopeOption, len(models.AccessTokenScopes))
+	for i, scope := range models.AccessTokenScopes {
+		options[i] = accessTokenScopeOption{
+			Scope:   scope,
+			DescKey: "settings.token_scope_" + strings.Replace(string(scope), ":", "_", -1),
+			Checked: com.IsSliceContainsStr(checked, string(scope)),
+		}
+	}
+	return options
+}
+
 // SettingsApplications render user's access tokens page
 func SettingsApplications(ctx *context.Context) {
-	ctx.Data["Title"] = ctx.Tr("settings")
-	ctx.Data["PageIsSettingsApplications"] = true
-
-	tokens, err := models.ListAccessTokens(ctx.User.ID)
-	if err != nil {
-		ctx.Handle(500, "ListAccessTokens", err)
+	if !loadApplicationsData(ctx) {
 		return
 	}
-	ctx.Data["Tokens"] = tokens
 
 	ctx.HTML(200, tplSettingsApplications)
 }
 
 // SettingsApplicationsPost response for add user's access token
 func SettingsApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
-	ctx.Data["Title"] = ctx.Tr("settings")
-	ctx.Data["PageIsSettingsApplications"] = true
+	if !loadApplicationsData(ctx) {
+		return
+	}
+	ctx.Data["AccessTokenScopes"] = accessTokenScopeOptions(form.Scopes)
 
 	if ctx.HasError() {
-		tokens, err := models.ListAccessTokens(ctx.User.ID)
-		if err != nil {
-			ctx.Handle(500, "ListAccessTokens", err)
-			return
-		}
-		ctx.Data["Tokens"] = tokens
 		ctx.HTML(200, tplSettingsApplications)
 		return
 	}
 
+	scope, err := models.NewAccessTokenScope(form.Scopes)
+	if err != nil || len(scope) == 0 {
+		ctx.Data["Err_Scopes"] = true
+		ctx.RenderWithErr(ctx.Tr("settings.token_scopes_invalid"), tplSettingsApplications, &form)
+		return
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: scope,
+	}
+	if len(form.ExpiresAt) > 0 {
+		expires, err := time.ParseInLocation("2006-01-02", form.ExpiresAt, time.Local)
+		if err != nil || !expires.After(time.Now()) {
+			ctx.Data["Err_ExpiresAt"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.token_expires_invalid"), tplSettingsApplications, &form)
+			return
+		}
+		t.ExpiresUnix = expires.Unix()
 	}
 	if err := models.NewAccessToken(t); err != nil {
 		ctx.Handle(500, "NewAccessToken", err)
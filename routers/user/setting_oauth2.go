@@ -0,0 +1,262 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsOAuthApplicationEdit base.TplName = "user/settings/oauth2_application_edit"
+)
+
+// OAuth2ApplicationHandlers manages the OAuth2 applications of a user or an
+// organization in its settings
+type OAuth2ApplicationHandlers struct {
+	OwnerID int64
+	// BasePath link of the page listing the applications
+	BasePath string
+	// TplList template of the page listing the applications
+	TplList base.TplName
+	// TplEdit template of the page editing an application
+	TplEdit base.TplName
+	// LoadList loads the data of the page listing the applications,
+	// it returns false if it rendered an error
+	LoadList func(ctx *context.Context) bool
+}
+
+func (h *OAuth2ApplicationHandlers) editLink(app *models.OAuth2Application) string {
+	return fmt.Sprintf("%s/oauth2/%d", h.BasePath, app.ID)
+}
+
+// validateRedirectURIs returns false and renders an error if one of the
+// redirect uris is not an absolute url
+func validateRedirectURIs(ctx *context.Context, uris []string, tpl base.TplName, form interface{}) bool {
+	for _, uri := range uris {
+		if u, err := url.Parse(uri); err != nil || !u.IsAbs() || len(u.Fragment) > 0 {
+			ctx.Data["Err_RedirectURIs"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.oauth2_redirect_uri_invalid", uri), tpl, form)
+			return false
+		}
+	}
+	return true
+}
+
+// CreateApplication registers a new application and shows its client secret
+func (h *OAuth2ApplicationHandlers) CreateApplication(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	if !h.LoadList(ctx) {
+		return
+	}
+	// the list page is rendered again on errors, so the links of its other
+	// forms must not be relative to the url of this request
+	ctx.Data["Link"] = h.BasePath
+	ctx.Data["OAuth2FormError"] = true
+	if ctx.HasError() {
+		ctx.HTML(200, h.TplList)
+		return
+	}
+	uris := form.RedirectURIList()
+	if !validateRedirectURIs(ctx, uris, h.TplList, &form) {
+		return
+	}
+
+	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
+		Name:         form.Name,
+		UserID:       h.OwnerID,
+		RedirectURIs: uris,
+	})
+	if err != nil {
+		ctx.Handle(500, "CreateOAuth2Application", err)
+		return
+	}
+	secret, err := app.GenerateClientSecret()
+	if err != nil {
+		ctx.Handle(500, "GenerateClientSecret", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.create_oauth2_application_success"))
+	ctx.Flash.Info(ctx.Tr("settings.oauth2_client_secret_new", secret))
+	ctx.Redirect(h.editLink(app))
+}
+
+// getApplication returns the application of the url, or renders an error
+// if the owner has no such application
+func (h *OAuth2ApplicationHandlers) getApplication(ctx *context.Context) *models.OAuth2Application {
+	app, err := models.GetOAuth2ApplicationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrOAuthApplicationNotFound(err) {
+			ctx.Handle(404, "GetOAuth2ApplicationByID", err)
+		} else {
+			ctx.Handle(500, "GetOAuth2ApplicationByID", err)
+		}
+		return nil
+	} else if app.UID != h.OwnerID {
+		ctx.Handle(404, "GetOAuth2ApplicationByID", models.ErrOAuthApplicationNotFound{ID: app.ID})
+		return nil
+	}
+	ctx.Data["App"] = app
+	ctx.Data["AppLink"] = h.editLink(app)
+	ctx.Data["OAuth2BasePath"] = h.BasePath
+	return app
+}
+
+// EditShow shows the page editing an application
+func (h *OAuth2ApplicationHandlers) EditShow(ctx *context.Context) {
+	app := h.getApplication(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["application_name"] = app.Name
+	ctx.Data["redirect_uris"] = strings.Join(app.RedirectURIs, "\n")
+	ctx.HTML(200, h.TplEdit)
+}
+
+// EditSave updates the name and the redirect uris of an application
+func (h *OAuth2ApplicationHandlers) EditSave(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	app := h.getApplication(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(200, h.TplEdit)
+		return
+	}
+	uris := form.RedirectURIList()
+	if !validateRedirectURIs(ctx, uris, h.TplEdit, &form) {
+		return
+	}
+
+	if err := models.UpdateOAuth2Application(models.UpdateOAuth2ApplicationOptions{
+		ID:           app.ID,
+		Name:         form.Name,
+		UserID:       h.OwnerID,
+		RedirectURIs: uris,
+	}); err != nil {
+		ctx.Handle(500, "UpdateOAuth2Application", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("settings.update_oauth2_application_success"))
+	ctx.Redirect(h.editLink(app))
+}
+
+// RegenerateSecret replaces the client secret of an application
+func (h *OAuth2ApplicationHandlers) RegenerateSecret(ctx *context.Context) {
+	app := h.getApplication(ctx)
+	if ctx.Written() {
+		return
+	}
+	secret, err := app.GenerateClientSecret()
+	if err != nil {
+		ctx.Handle(500, "GenerateClientSecret", err)
+		return
+	}
+	ctx.Flash.Info(ctx.Tr("settings.oauth2_client_secret_new", secret))
+	ctx.Redirect(h.editLink(app))
+}
+
+// DeleteApplication deletes an application and revokes all of its grants
+func (h *OAuth2ApplicationHandlers) DeleteApplication(ctx *context.Context) {
+	if err := models.DeleteOAuth2Application(ctx.QueryInt64("id"), h.OwnerID); err != nil {
+		ctx.Flash.Error("DeleteOAuth2Application: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.remove_oauth2_application_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": h.BasePath,
+	})
+}
+
+// loadApplicationsData loads the OAuth2 applications and the grants of the
+// signed in user, shown on the applications settings page
+func loadApplicationsData(ctx *context.Context) bool {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	ctx.Data["AccessTokenScopes"] = accessTokenScopeOptions(nil)
+
+	tokens, err := models.ListAccessTokens(ctx.User.ID)
+	if err != nil {
+		ctx.Handle(500, "ListAccessTokens", err)
+		return false
+	}
+	ctx.Data["Tokens"] = tokens
+
+	if setting.OAuth2.Enable {
+		ctx.Data["EnableOAuth2"] = true
+		ctx.Data["OAuth2BasePath"] = setting.AppSubURL + "/user/settings/applications"
+		if ctx.Data["Applications"], err = models.GetOAuth2ApplicationsByUserID(ctx.User.ID); err != nil {
+			ctx.Handle(500, "GetOAuth2ApplicationsByUserID", err)
+			return false
+		}
+		if ctx.Data["Grants"], err = models.GetOAuth2GrantsByUserID(ctx.User.ID); err != nil {
+			ctx.Handle(500, "GetOAuth2GrantsByUserID", err)
+			return false
+		}
+	}
+	return true
+}
+
+func userOAuth2ApplicationHandlers(ctx *context.Context) *OAuth2ApplicationHandlers {
+	return &OAuth2ApplicationHandlers{
+		OwnerID:  ctx.User.ID,
+		BasePath: setting.AppSubURL + "/user/settings/applications",
+		TplList:  tplSettingsApplications,
+		TplEdit:  tplSettingsOAuthApplicationEdit,
+		LoadList: loadApplicationsData,
+	}
+}
+
+// SettingsOAuthApplicationsPost registers a new OAuth2 application of the user
+func SettingsOAuthApplicationsPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	userOAuth2ApplicationHandlers(ctx).CreateApplication(ctx, form)
+}
+
+// SettingsOAuthApplicationEdit shows an OAuth2 application of the user
+func SettingsOAuthApplicationEdit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2ApplicationHandlers(ctx).EditShow(ctx)
+}
+
+// SettingsOAuthApplicationEditPost updates an OAuth2 application of the user
+func SettingsOAuthApplicationEditPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2ApplicationHandlers(ctx).EditSave(ctx, form)
+}
+
+// SettingsOAuthApplicationRegenerateSecret replaces the client secret of an
+// OAuth2 application of the user
+func SettingsOAuthApplicationRegenerateSecret(ctx *context.Context) {
+	userOAuth2ApplicationHandlers(ctx).RegenerateSecret(ctx)
+}
+
+// SettingsDeleteOAuthApplication deletes an OAuth2 application of the user
+func SettingsDeleteOAuthApplication(ctx *context.Context) {
+	userOAuth2ApplicationHandlers(ctx).DeleteApplication(ctx)
+}
+
+// SettingsRevokeOAuth2Grant revokes the access of an application to the
+// account of the user
+func SettingsRevokeOAuth2Grant(ctx *context.Context) {
+	if err := models.RevokeOAuth2Grant(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
+		ctx.Flash.Error("RevokeOAuth2Grant: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.revoke_oauth2_grant_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/applications",
+	})
+}